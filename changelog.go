@@ -0,0 +1,17 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Repository files compiled into the binary
+
+// Package agen holds files from the repository root that the CLI ships
+// with. go:embed can't reach above a package's directory, so they're
+// embedded here rather than next to the code that reads them.
+package agen
+
+import _ "embed"
+
+// Changelog is the contents of CHANGELOG.md, shown by 'agen changelog'
+//
+//go:embed CHANGELOG.md
+var Changelog string
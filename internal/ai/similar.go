@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Closest-name lookup for mistyped agent and skill names
+
+package ai
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxSimilar is how many names Similar returns at most
+const maxSimilar = 3
+
+// Similar returns up to three agent and/or skill names close to name,
+// closest first. kind is "agent", "skill", or anything else for both.
+//
+// How it works:
+//  1. Names are compared lowercased with separators dropped, so
+//     "frontend_specialist" matches "frontend-specialist" exactly
+//  2. The edit distance between the two decides the ranking; a name that
+//     contains the query (or the other way round) counts as distance 1
+//  3. Anything further than a third of the query's length (at least 2)
+//     is too different to be what the user meant
+//  4. When a name differs only in case or separators, that's the one
+//     meant, and nothing else is returned
+func (s *Suggester) Similar(name, kind string) []string {
+	query := normalizeName(name)
+	if query == "" {
+		return nil
+	}
+
+	var candidates []string
+	if kind != "skill" {
+		for n := range s.templates.Agents {
+			candidates = append(candidates, n)
+		}
+	}
+	if kind != "agent" {
+		for n := range s.templates.Skills {
+			candidates = append(candidates, n)
+		}
+	}
+
+	maxDistance := max(len(query)/3, 2)
+
+	type match struct {
+		name     string
+		distance int
+	}
+	var matches []match
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if seen[candidate] {
+			continue // an agent and a skill can share a name
+		}
+		seen[candidate] = true
+
+		normalized := normalizeName(candidate)
+		distance := levenshtein(query, normalized)
+		if distance > 1 && (strings.Contains(normalized, query) || strings.Contains(query, normalized)) {
+			distance = 1
+		}
+		if distance <= maxDistance {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var names []string
+	for i := 0; i < len(matches) && i < maxSimilar; i++ {
+		if i > 0 && matches[0].distance == 0 && matches[i].distance > 0 {
+			break
+		}
+		names = append(names, matches[i].name)
+	}
+	return names
+}
+
+// normalizeName lowercases name and drops separators
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '.', ' ':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, strings.TrimSpace(name))
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for closest-name lookup
+
+package ai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/eshanized/agen/internal/templates"
+)
+
+func TestSimilar(t *testing.T) {
+	s := NewSuggesterFrom(&templates.Templates{
+		Agents: map[string]templates.Agent{
+			"frontend-specialist": {},
+			"backend-specialist":  {},
+			"debugger":            {},
+		},
+		Skills: map[string]templates.Skill{
+			"clean-code":       {},
+			"testing-patterns": {},
+		},
+	})
+
+	tests := []struct {
+		name, kind string
+		want       []string
+	}{
+		{"frontend_specialist", "auto", []string{"frontend-specialist"}},
+		{"FrontEnd Specialist", "agent", []string{"frontend-specialist"}},
+		{"debuger", "auto", []string{"debugger"}},
+		{"specialist", "auto", []string{"backend-specialist", "frontend-specialist"}},
+		{"clean-cod", "skill", []string{"clean-code"}},
+		{"clean-cod", "agent", nil},
+		{"kubernetes", "auto", nil},
+		{"", "auto", nil},
+	}
+
+	for _, tt := range tests {
+		if got := s.Similar(tt.name, tt.kind); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Similar(%q, %q) = %v, want %v", tt.name, tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"debuger", "debugger", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
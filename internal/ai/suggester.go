@@ -8,12 +8,15 @@ package ai
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/eshanized/agen/internal/config"
 	"github.com/eshanized/agen/internal/templates"
+	"gopkg.in/yaml.v3"
 )
 
 // Suggester analyzes projects and suggests appropriate agents
@@ -38,13 +41,32 @@ type ProjectAnalysis struct {
 	HasTests     bool            `json:"has_tests"`
 	HasDocker    bool            `json:"has_docker"`
 	HasCI        bool            `json:"has_ci"`
+	CISystems    []string        `json:"ci_systems,omitempty"` // e.g. "GitHub Actions", see detectCI
 	Dependencies map[string]bool `json:"dependencies"`
 	FileCount    int             `json:"file_count"`
+
+	// set for monorepos: the workspace file that marked it, and each
+	// package below the root that has its own manifest
+	WorkspaceFile string       `json:"workspace_file,omitempty"`
+	Subprojects   []Subproject `json:"subprojects,omitempty"`
 }
 
-// NewSuggester creates a new suggester
+// NewSuggester creates a new suggester.
+// It suggests from the templates last pulled by `agen update` when there
+// are any, so suggestions match what an update would install.
 func NewSuggester() (*Suggester, error) {
-	tmpl, err := templates.LoadEmbedded()
+	var tmpl *templates.Templates
+	var err error
+
+	cfg, cfgErr := config.Load()
+	if cfgErr != nil {
+		cfg = config.DefaultConfig()
+	}
+	if cacheDir, dirErr := cfg.GetCacheDir(); dirErr == nil {
+		tmpl, err = templates.LoadOffline(cacheDir)
+	} else {
+		tmpl, err = templates.LoadEmbedded()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -52,10 +74,16 @@ func NewSuggester() (*Suggester, error) {
 	return &Suggester{templates: tmpl}, nil
 }
 
+// NewSuggesterFrom creates a suggester over an already loaded template
+// set, e.g. what's installed in a project
+func NewSuggesterFrom(tmpl *templates.Templates) *Suggester {
+	return &Suggester{templates: tmpl}
+}
+
 // Suggest analyzes a project and suggests agents
 func (s *Suggester) Suggest(projectDir string) ([]Suggestion, error) {
 	analysis := s.analyzeProject(projectDir)
-	suggestions := s.generateSuggestions(analysis)
+	suggestions := s.resolveSuggestions(s.generateSuggestions(analysis), analysis)
 
 	// Sort by score descending
 	sort.Slice(suggestions, func(i, j int) bool {
@@ -65,6 +93,63 @@ func (s *Suggester) Suggest(projectDir string) ([]Suggestion, error) {
 	return suggestions, nil
 }
 
+// languageExts maps file extensions to the language they indicate
+var languageExts = map[string]string{
+	".js": "javascript", ".jsx": "javascript", ".mjs": "javascript",
+	".ts": "typescript", ".tsx": "typescript",
+	".py": "python", ".go": "go",
+	".rs": "rust", ".java": "java",
+	".rb": "ruby", ".php": "php",
+	".swift": "swift", ".kt": "kotlin",
+	".scala": "scala", ".cs": "csharp",
+	".c": "c", ".h": "c",
+	".cpp": "cpp", ".cc": "cpp", ".cxx": "cpp", ".hpp": "cpp",
+	".ex": "elixir", ".exs": "elixir",
+	".dart": "dart",
+}
+
+// languageThresholds is how many files of a language a project needs before
+// it counts. Most languages count from 2 files; JavaScript needs more
+// because nearly every project has a few .js config files.
+var languageThresholds = map[string]int{
+	"javascript": 5,
+}
+
+const defaultLanguageThreshold = 2
+
+// manifestLanguages maps project manifests to their language. A manifest
+// counts the language even below the file threshold, and one below the
+// project root marks a subproject.
+var manifestLanguages = map[string]string{
+	"package.json":     "javascript",
+	"go.mod":           "go",
+	"Cargo.toml":       "rust",
+	"pyproject.toml":   "python",
+	"requirements.txt": "python",
+	"pom.xml":          "java",
+	"build.sbt":        "scala",
+	"mix.exs":          "elixir",
+	"pubspec.yaml":     "dart",
+}
+
+// workspaceFiles mark a monorepo when found at the project root
+var workspaceFiles = []string{"pnpm-workspace.yaml", "go.work", "lerna.json"}
+
+// skippedDirs are never descended into
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	".git":         true,
+	"vendor":       true,
+}
+
+// Subproject is a package inside a monorepo, found by its manifest
+type Subproject struct {
+	Path      string `json:"path"` // relative to the project root
+	Manifest  string `json:"manifest"`
+	Language  string `json:"language"`
+	Framework string `json:"framework,omitempty"`
+}
+
 // analyzeProject scans the project to understand its nature
 func (s *Suggester) analyzeProject(dir string) *ProjectAnalysis {
 	analysis := &ProjectAnalysis{
@@ -74,22 +159,25 @@ func (s *Suggester) analyzeProject(dir string) *ProjectAnalysis {
 	}
 
 	// Detect languages by file extensions
-	extCount := make(map[string]int)
+	langCount := make(map[string]int)
+	manifestLangs := make(map[string]bool)
+	seenSubs := make(map[string]bool)
 
 	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
+		if err != nil {
 			return nil
 		}
 
-		// Skip hidden directories
-		if strings.Contains(path, "/node_modules/") ||
-			strings.Contains(path, "/.git/") ||
-			strings.Contains(path, "/vendor/") {
-			return filepath.SkipDir
+		if info.IsDir() {
+			if path != dir && skippedDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
 		}
 
-		ext := filepath.Ext(info.Name())
-		extCount[ext]++
+		if lang, ok := languageExts[filepath.Ext(info.Name())]; ok {
+			langCount[lang]++
+		}
 		analysis.FileCount++
 
 		// Check for specific files
@@ -105,42 +193,65 @@ func (s *Suggester) analyzeProject(dir string) *ProjectAnalysis {
 			analysis.Dependencies["rust"] = true
 		case "Dockerfile", "docker-compose.yml":
 			analysis.HasDocker = true
-		case ".github", ".gitlab-ci.yml", "Jenkinsfile":
-			analysis.HasCI = true
+		}
+
+		if lang, ok := manifestLanguages[name]; ok {
+			// package.json says Node, not whether the code is JS or TS
+			if lang != "javascript" {
+				manifestLangs[lang] = true
+			}
+			if sub := filepath.Dir(path); sub != dir && !seenSubs[sub] {
+				seenSubs[sub] = true
+				rel, _ := filepath.Rel(dir, sub)
+				analysis.Subprojects = append(analysis.Subprojects, Subproject{
+					Path:     filepath.ToSlash(rel),
+					Manifest: name,
+					Language: lang,
+				})
+			}
 		}
 
 		return nil
 	})
 
-	// Determine primary language
-	langMap := map[string]string{
-		".js": "javascript", ".ts": "typescript",
-		".py": "python", ".go": "go",
-		".rs": "rust", ".java": "java",
-		".rb": "ruby", ".php": "php",
-		".swift": "swift", ".kt": "kotlin",
+	// Determine languages, in a stable order
+	for lang, count := range langCount {
+		threshold, ok := languageThresholds[lang]
+		if !ok {
+			threshold = defaultLanguageThreshold
+		}
+		if count >= threshold || manifestLangs[lang] {
+			analysis.Languages = append(analysis.Languages, lang)
+		}
 	}
-
-	for ext, lang := range langMap {
-		if count, ok := extCount[ext]; ok && count > 5 {
+	for lang := range manifestLangs {
+		if langCount[lang] == 0 {
 			analysis.Languages = append(analysis.Languages, lang)
 		}
 	}
+	sort.Strings(analysis.Languages)
 
 	// Detect frameworks
-	if _, err := os.Stat(filepath.Join(dir, "next.config.js")); err == nil {
-		analysis.Frameworks = append(analysis.Frameworks, "nextjs")
-		analysis.ProjectType = "web"
-	}
-	if _, err := os.Stat(filepath.Join(dir, "vite.config.ts")); err == nil {
-		analysis.Frameworks = append(analysis.Frameworks, "vite")
-		analysis.ProjectType = "web"
+	analysis.Frameworks, analysis.ProjectType = detectFrameworks(dir)
+	for i, sub := range analysis.Subprojects {
+		if fws, _ := detectFrameworks(filepath.Join(dir, sub.Path)); len(fws) > 0 {
+			analysis.Subprojects[i].Framework = fws[0]
+		}
 	}
-	if _, err := os.Stat(filepath.Join(dir, "app.json")); err == nil {
-		analysis.Frameworks = append(analysis.Frameworks, "react-native")
-		analysis.ProjectType = "mobile"
+
+	// Workspace files win over the framework guess: the root of a
+	// monorepo is rarely one app
+	for _, f := range workspaceFiles {
+		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			analysis.ProjectType = "monorepo"
+			analysis.WorkspaceFile = f
+			break
+		}
 	}
 
+	analysis.CISystems = detectCI(dir)
+	analysis.HasCI = len(analysis.CISystems) > 0
+
 	// Check for tests
 	testDirs := []string{"test", "tests", "__tests__", "spec"}
 	for _, td := range testDirs {
@@ -153,6 +264,74 @@ func (s *Suggester) analyzeProject(dir string) *ProjectAnalysis {
 	return analysis
 }
 
+// detectFrameworks looks for framework config files directly in dir and
+// returns the frameworks found and the project type they imply
+func detectFrameworks(dir string) ([]string, string) {
+	frameworks := []string{}
+	projectType := ""
+	if _, err := os.Stat(filepath.Join(dir, "next.config.js")); err == nil {
+		frameworks = append(frameworks, "nextjs")
+		projectType = "web"
+	}
+	if _, err := os.Stat(filepath.Join(dir, "vite.config.ts")); err == nil {
+		frameworks = append(frameworks, "vite")
+		projectType = "web"
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app.json")); err == nil {
+		frameworks = append(frameworks, "react-native")
+		projectType = "mobile"
+	}
+	return frameworks, projectType
+}
+
+// ciConfigs maps each CI system to the glob patterns, relative to the
+// project root, of its pipeline config
+var ciConfigs = []struct {
+	System   string
+	Patterns []string
+}{
+	{"GitHub Actions", []string{".github/workflows/*.yml", ".github/workflows/*.yaml"}},
+	{"GitLab CI", []string{".gitlab-ci.yml"}},
+	{"CircleCI", []string{".circleci/config.yml"}},
+	{"Azure Pipelines", []string{"azure-pipelines.yml"}},
+	{"Jenkins", []string{"Jenkinsfile"}},
+}
+
+// detectCI returns the CI systems with a pipeline config in dir.
+// An empty .github/ (issue templates, say) doesn't count as CI.
+func detectCI(dir string) []string {
+	var systems []string
+	for _, ci := range ciConfigs {
+		for _, pattern := range ci.Patterns {
+			if matches, _ := filepath.Glob(filepath.Join(dir, pattern)); len(matches) > 0 {
+				systems = append(systems, ci.System)
+				break
+			}
+		}
+	}
+	return systems
+}
+
+// subprojectAgent picks the agent for one monorepo package
+func subprojectAgent(sub Subproject) string {
+	switch {
+	case sub.Framework == "react-native" || sub.Language == "dart":
+		return "mobile-developer"
+	case sub.Framework != "":
+		return "frontend-specialist"
+	default:
+		return "backend-specialist"
+	}
+}
+
+// summarizePaths lists up to three paths and counts the rest
+func summarizePaths(paths []string) string {
+	if len(paths) <= 3 {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:3], ", "), len(paths)-3)
+}
+
 // generateSuggestions creates recommendations based on analysis
 func (s *Suggester) generateSuggestions(analysis *ProjectAnalysis) []Suggestion {
 	var suggestions []Suggestion
@@ -209,6 +388,62 @@ func (s *Suggester) generateSuggestions(analysis *ProjectAnalysis) []Suggestion
 				Reason:      "Detected Go project",
 				Description: "Backend Development Architect",
 			})
+
+		case "rust", "java", "scala", "csharp", "elixir":
+			suggestions = append(suggestions, Suggestion{
+				Name:        "backend-specialist",
+				Type:        "agent",
+				Score:       0.8,
+				Reason:      fmt.Sprintf("Detected %s files", lang),
+				Description: "Backend Development Architect",
+			})
+
+		case "c", "cpp":
+			suggestions = append(suggestions, Suggestion{
+				Name:        "embedded-systems-developer",
+				Type:        "agent",
+				Score:       0.7,
+				Reason:      fmt.Sprintf("Detected %s files", lang),
+				Description: "Embedded and systems programming",
+			})
+
+		case "dart":
+			suggestions = append(suggestions, Suggestion{
+				Name:        "mobile-developer",
+				Type:        "agent",
+				Score:       0.85,
+				Reason:      "Detected Dart (Flutter) files",
+				Description: "Mobile development expert",
+			})
+		}
+	}
+
+	// Monorepo: one agent per kind of package, naming the packages
+	if analysis.ProjectType == "monorepo" {
+		suggestions = append(suggestions, Suggestion{
+			Name:        "monorepo-patterns",
+			Type:        "skill",
+			Score:       0.85,
+			Reason:      fmt.Sprintf("Workspace file %s found", analysis.WorkspaceFile),
+			Description: "Monorepo structure and tooling",
+		})
+
+		byAgent := make(map[string][]string)
+		var agents []string
+		for _, sub := range analysis.Subprojects {
+			agent := subprojectAgent(sub)
+			if _, ok := byAgent[agent]; !ok {
+				agents = append(agents, agent)
+			}
+			byAgent[agent] = append(byAgent[agent], sub.Path)
+		}
+		for _, agent := range agents {
+			suggestions = append(suggestions, Suggestion{
+				Name:   agent,
+				Type:   "agent",
+				Score:  0.85,
+				Reason: "Monorepo packages: " + summarizePaths(byAgent[agent]),
+			})
 		}
 	}
 
@@ -259,13 +494,21 @@ func (s *Suggester) generateSuggestions(analysis *ProjectAnalysis) []Suggestion
 
 	// CI/CD
 	if analysis.HasCI {
+		systems := strings.Join(analysis.CISystems, ", ")
 		suggestions = append(suggestions, Suggestion{
 			Name:        "deployment-procedures",
 			Type:        "skill",
 			Score:       0.7,
-			Reason:      "CI pipeline detected",
+			Reason:      fmt.Sprintf("CI pipeline detected (%s)", systems),
 			Description: "Deployment best practices",
 		})
+		suggestions = append(suggestions, Suggestion{
+			Name:        "devops-engineer",
+			Type:        "agent",
+			Score:       0.75,
+			Reason:      fmt.Sprintf("%s pipelines to maintain", systems),
+			Description: "DevOps and infrastructure",
+		})
 	}
 
 	// Always useful skills
@@ -288,6 +531,122 @@ func (s *Suggester) generateSuggestions(analysis *ProjectAnalysis) []Suggestion
 	return suggestions
 }
 
+// signalKeywords maps what analyzeProject detects to words that appear in
+// the names of skills relevant to it (skill names split on "-" and "/")
+var signalKeywords = map[string][]string{
+	"javascript":   {"nodejs", "react", "nextjs", "tailwind"},
+	"typescript":   {"nodejs", "react", "nextjs", "tailwind"},
+	"python":       {"python"},
+	"rust":         {"rust"},
+	"nextjs":       {"nextjs", "react", "frontend", "tailwind"},
+	"vite":         {"react", "frontend", "tailwind"},
+	"react-native": {"mobile"},
+	"docker":       {"deployment", "kubernetes", "server"},
+	"ci":           {"deployment"},
+}
+
+// signals lists the languages, frameworks and tooling detected in a project
+func (a *ProjectAnalysis) signals() []string {
+	signals := append(append([]string{}, a.Languages...), a.Frameworks...)
+	if a.HasDocker {
+		signals = append(signals, "docker")
+	}
+	if a.HasCI {
+		signals = append(signals, "ci")
+	}
+	return signals
+}
+
+// skillMatches reports whether a skill's name mentions any keyword for signal
+func skillMatches(skill, signal string) bool {
+	words := strings.FieldsFunc(skill, func(r rune) bool { return r == '-' || r == '/' })
+	for _, keyword := range signalKeywords[signal] {
+		for _, w := range words {
+			if w == keyword {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// resolveSuggestions checks generated suggestions against the templates
+// that actually exist and scores them by how well they fit the project.
+//
+// How it works:
+//  1. Merge duplicates (e.g. backend-specialist for both Python and Go),
+//     keeping the best score and every reason
+//  2. Drop names with no agent or skill template, so `agen init --agents`
+//     never gets a suggestion it can't install
+//  3. Add 0.05 per detected language/framework that one of the agent's
+//     declared skills (or the skill itself) covers, capped at 1.0
+//  4. Take descriptions from the templates rather than the hardcoded ones
+func (s *Suggester) resolveSuggestions(suggestions []Suggestion, analysis *ProjectAnalysis) []Suggestion {
+	index := make(map[string]int)
+	var merged []Suggestion
+	for _, sg := range suggestions {
+		key := sg.Type + "/" + sg.Name
+		if i, ok := index[key]; ok {
+			if sg.Score > merged[i].Score {
+				merged[i].Score = sg.Score
+			}
+			if !strings.Contains(merged[i].Reason, sg.Reason) {
+				merged[i].Reason += "; " + sg.Reason
+			}
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, sg)
+	}
+
+	signals := analysis.signals()
+	resolved := merged[:0]
+	for _, sg := range merged {
+		var skills []string
+		switch sg.Type {
+		case "agent":
+			agent, ok := s.templates.Agents[sg.Name]
+			if !ok {
+				continue
+			}
+			skills = agent.Skills
+			if agent.Description != "" {
+				sg.Description = agent.Description
+			}
+		case "skill":
+			skill, ok := s.templates.Skills[sg.Name]
+			if !ok {
+				continue
+			}
+			skills = []string{sg.Name}
+			if skill.Description != "" {
+				sg.Description = skill.Description
+			}
+		default:
+			continue
+		}
+
+		var matched []string
+		for _, signal := range signals {
+			for _, skill := range skills {
+				if skillMatches(skill, signal) {
+					matched = append(matched, signal)
+					break
+				}
+			}
+		}
+		if len(matched) > 0 {
+			// rounded so JSON output doesn't show 0.8500000000000001
+			sg.Score = math.Min(1, math.Round((sg.Score+0.05*float64(len(matched)))*100)/100)
+			sg.Reason += fmt.Sprintf(" (skills cover %s)", strings.Join(matched, ", "))
+		}
+
+		resolved = append(resolved, sg)
+	}
+
+	return resolved
+}
+
 // ExplainAgent provides detailed explanation of an agent
 func (s *Suggester) ExplainAgent(name string) (string, error) {
 	agent, ok := s.templates.Agents[name]
@@ -299,6 +658,7 @@ func (s *Suggester) ExplainAgent(name string) (string, error) {
 
 	sb.WriteString(fmt.Sprintf("# %s\n\n", name))
 	sb.WriteString(fmt.Sprintf("**Description:** %s\n\n", agent.Description))
+	sb.WriteString(fmt.Sprintf("**Source:** %s\n\n", agent.Source))
 
 	if len(agent.Skills) > 0 {
 		sb.WriteString("**Skills:**\n")
@@ -325,6 +685,7 @@ func (s *Suggester) ExplainSkill(name string) (string, error) {
 
 	sb.WriteString(fmt.Sprintf("# %s\n\n", name))
 	sb.WriteString(fmt.Sprintf("**Description:** %s\n\n", skill.Description))
+	sb.WriteString(fmt.Sprintf("**Source:** %s\n\n", skill.Source))
 	sb.WriteString("## Full Content\n\n")
 	sb.WriteString(skill.Content)
 
@@ -339,7 +700,26 @@ type ComposedAgent struct {
 	Content     string   `json:"content"`
 }
 
+// composedFrontmatter is the frontmatter of a composed agent, in the
+// field order the embedded agents use
+type composedFrontmatter struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Skills      string `yaml:"skills,omitempty"` // comma-separated, like the embedded agents
+}
+
 // Compose generates a custom agent based on user description
+//
+// How it works:
+//  1. Collect the skills of every base agent, deduplicated, in the order
+//     the agents list them
+//  2. Walk each base agent's "## " sections and merge them by heading, so
+//     two agents with a "Core Principles" section give one such section
+//  3. Within a merged section, drop any block (paragraph, table, code
+//     fence) that an earlier agent already contributed word for word
+//
+// The intro before each agent's first "## " heading is left out; the
+// composed agent gets its own title and description instead.
 func (s *Suggester) Compose(name, description string, baseAgents []string) (*ComposedAgent, error) {
 	composed := &ComposedAgent{
 		Name:        name,
@@ -347,56 +727,115 @@ func (s *Suggester) Compose(name, description string, baseAgents []string) (*Com
 		Skills:      []string{},
 	}
 
-	var sb strings.Builder
-
-	// Generate frontmatter
-	sb.WriteString("---\n")
-	sb.WriteString(fmt.Sprintf("name: %s\n", name))
-	sb.WriteString(fmt.Sprintf("description: %s\n", description))
+	agents := make([]templates.Agent, 0, len(baseAgents))
+	for _, baseAgent := range baseAgents {
+		agent, ok := s.templates.Agents[baseAgent]
+		if !ok {
+			return nil, fmt.Errorf("agent not found: %s", baseAgent)
+		}
+		if agent.Sections == nil {
+			// hand-built templates may not have been split yet
+			parsed, _ := templates.ParseAgent(baseAgent, agent.Content)
+			agent.Sections, agent.SectionOrder = parsed.Sections, parsed.SectionOrder
+		}
+		agents = append(agents, agent)
+	}
 
 	// Collect skills from base agents
-	skillSet := make(map[string]bool)
-	for _, baseAgent := range baseAgents {
-		if agent, ok := s.templates.Agents[baseAgent]; ok {
-			for _, skill := range agent.Skills {
-				skillSet[skill] = true
+	seenSkills := make(map[string]bool)
+	for _, agent := range agents {
+		for _, skill := range agent.Skills {
+			if !seenSkills[skill] {
+				seenSkills[skill] = true
+				composed.Skills = append(composed.Skills, skill)
 			}
 		}
 	}
 
-	if len(skillSet) > 0 {
-		sb.WriteString("skills: ")
-		var skills []string
-		for skill := range skillSet {
-			skills = append(skills, skill)
+	// Merge sections by heading, case-insensitively, keeping the first
+	// spelling and the order headings were first seen in
+	var headings []string
+	blocks := make(map[string][]string)
+	seenBlocks := make(map[string]map[string]bool)
+	for _, agent := range agents {
+		for _, heading := range agent.SectionOrder {
+			key := strings.ToLower(heading)
+			if seenBlocks[key] == nil {
+				seenBlocks[key] = make(map[string]bool)
+				headings = append(headings, heading)
+			}
+			for _, block := range splitBlocks(agent.Sections[heading]) {
+				if !seenBlocks[key][block] {
+					seenBlocks[key][block] = true
+					blocks[key] = append(blocks[key], block)
+				}
+			}
 		}
-		sb.WriteString(strings.Join(skills, ", "))
-		sb.WriteString("\n")
-		composed.Skills = skills
 	}
 
+	var sb strings.Builder
+
+	// Generate frontmatter. Marshalled rather than formatted, so a
+	// description containing ": " or " #" is quoted instead of breaking
+	// the YAML.
+	frontmatter, err := yaml.Marshal(composedFrontmatter{
+		Name:        name,
+		Description: description,
+		Skills:      strings.Join(composed.Skills, ", "),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write frontmatter: %w", err)
+	}
+	sb.WriteString("---\n")
+	sb.Write(frontmatter)
 	sb.WriteString("---\n\n")
 
 	// Generate content header
 	sb.WriteString(fmt.Sprintf("# %s\n\n", name))
 	sb.WriteString(fmt.Sprintf("> %s\n\n", description))
+	sb.WriteString(fmt.Sprintf("Composed from: %s\n", strings.Join(baseAgents, ", ")))
 
-	// Add composed rules from base agents
-	sb.WriteString("## Core Principles\n\n")
-
-	for _, baseAgent := range baseAgents {
-		if agent, ok := s.templates.Agents[baseAgent]; ok {
-			sb.WriteString(fmt.Sprintf("### From %s\n\n", baseAgent))
-			// Extract key points (simplified - just take first 500 chars)
-			content := agent.Content
-			if len(content) > 500 {
-				content = content[:500] + "..."
-			}
-			sb.WriteString(content)
-			sb.WriteString("\n\n")
+	for _, heading := range headings {
+		body := blocks[strings.ToLower(heading)]
+		if len(body) == 0 {
+			continue
 		}
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", heading))
+		sb.WriteString(strings.Join(body, "\n\n"))
+		sb.WriteString("\n")
 	}
 
 	composed.Content = sb.String()
 	return composed, nil
 }
+
+// splitBlocks splits a section body into blank-line separated blocks,
+// keeping fenced code blocks whole. Horizontal rules are dropped: they
+// only separate sections in the templates, and Compose reorders those.
+func splitBlocks(body string) []string {
+	var blocks []string
+	var buf []string
+	inFence := false
+
+	flush := func() {
+		if block := strings.TrimSpace(strings.Join(buf, "\n")); block != "" && block != "---" {
+			blocks = append(blocks, block)
+		}
+		buf = buf[:0]
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence && trimmed == "" {
+			flush()
+			continue
+		}
+		buf = append(buf, line)
+	}
+	flush()
+
+	return blocks
+}
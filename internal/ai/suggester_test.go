@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for suggestion resolution and scoring
+
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eshanized/agen/internal/templates"
+)
+
+func TestResolveSuggestions(t *testing.T) {
+	s := &Suggester{templates: &templates.Templates{
+		Agents: map[string]templates.Agent{
+			"backend-specialist": {Name: "backend-specialist", Description: "Backend", Skills: []string{"clean-code", "python-patterns"}},
+		},
+		Skills: map[string]templates.Skill{
+			"python-patterns": {Name: "python-patterns"},
+		},
+	}}
+	analysis := &ProjectAnalysis{Languages: []string{"python", "go"}}
+
+	got := s.resolveSuggestions([]Suggestion{
+		{Name: "backend-specialist", Type: "agent", Score: 0.85, Reason: "Detected Python project"},
+		{Name: "backend-specialist", Type: "agent", Score: 0.85, Reason: "Detected Go project"},
+		{Name: "python-patterns", Type: "skill", Score: 0.8, Reason: "Python best practices"},
+		{Name: "renamed-agent", Type: "agent", Score: 0.9, Reason: "gone upstream"},
+	}, analysis)
+
+	if len(got) != 2 {
+		t.Fatalf("resolveSuggestions() = %+v, want backend-specialist and python-patterns", got)
+	}
+
+	backend := got[0]
+	if backend.Name != "backend-specialist" || backend.Description != "Backend" {
+		t.Errorf("first suggestion = %+v", backend)
+	}
+	// duplicates merge, and the python skill lifts the score
+	if backend.Score != 0.9 || backend.Reason != "Detected Python project; Detected Go project (skills cover python)" {
+		t.Errorf("backend score/reason = %v %q", backend.Score, backend.Reason)
+	}
+	if got[1].Score != 0.85 {
+		t.Errorf("python-patterns score = %v, want 0.85", got[1].Score)
+	}
+}
+
+// writeFiles creates each path under dir with placeholder content
+func writeFiles(t *testing.T, dir string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		full := filepath.Join(dir, p)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAnalyzeProjectLanguages(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir,
+		"src/a.scala", "src/b.scala",
+		"lib/x.ex", "lib/y.exs",
+		"main.cs",              // one file: below the threshold
+		"a.js", "b.js", "c.js", // JS needs five
+		"node_modules/dep/d.dart", "node_modules/dep/e.dart", // skipped
+	)
+
+	got := (&Suggester{}).analyzeProject(dir).Languages
+	want := []string{"elixir", "scala"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Languages = %v, want %v", got, want)
+	}
+}
+
+func TestAnalyzeProjectMonorepo(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir,
+		"pnpm-workspace.yaml",
+		"package.json",
+		"apps/web/package.json", "apps/web/next.config.js",
+		"apps/mobile/pubspec.yaml",
+		"services/api/go.mod",
+		"services/ml/pyproject.toml", "services/ml/requirements.txt",
+	)
+
+	analysis := (&Suggester{}).analyzeProject(dir)
+	if analysis.ProjectType != "monorepo" || analysis.WorkspaceFile != "pnpm-workspace.yaml" {
+		t.Fatalf("ProjectType = %q (%q), want monorepo", analysis.ProjectType, analysis.WorkspaceFile)
+	}
+	if len(analysis.Subprojects) != 4 {
+		t.Fatalf("Subprojects = %+v, want 4", analysis.Subprojects)
+	}
+
+	agents := make(map[string]string)
+	for _, sg := range (&Suggester{}).generateSuggestions(analysis) {
+		if strings.HasPrefix(sg.Reason, "Monorepo packages: ") {
+			agents[sg.Name] = strings.TrimPrefix(sg.Reason, "Monorepo packages: ")
+		}
+	}
+	want := map[string]string{
+		"frontend-specialist": "apps/web",
+		"mobile-developer":    "apps/mobile",
+		"backend-specialist":  "services/api, services/ml",
+	}
+	if !reflect.DeepEqual(agents, want) {
+		t.Errorf("per-package agents = %v, want %v", agents, want)
+	}
+}
+
+func TestDetectCI(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, ".github/ISSUE_TEMPLATE/bug.md")
+	if got := detectCI(dir); len(got) != 0 {
+		t.Errorf("detectCI() with only issue templates = %v, want none", got)
+	}
+
+	writeFiles(t, dir, ".github/workflows/ci.yaml", ".circleci/config.yml", "Jenkinsfile")
+	want := []string{"GitHub Actions", "CircleCI", "Jenkins"}
+	if got := detectCI(dir); !reflect.DeepEqual(got, want) {
+		t.Errorf("detectCI() = %v, want %v", got, want)
+	}
+
+	analysis := (&Suggester{}).analyzeProject(dir)
+	if !analysis.HasCI || len(analysis.CISystems) != 3 {
+		t.Errorf("HasCI = %v, CISystems = %v", analysis.HasCI, analysis.CISystems)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	shared := "## Core Principles\n\n- Read before you write\n\n---\n\n"
+	s := &Suggester{templates: &templates.Templates{
+		Agents: map[string]templates.Agent{
+			"frontend": {Skills: []string{"clean-code", "frontend-design"}, Content: "---\nname: frontend\n---\n\n# Frontend\n\nIntro.\n\n" +
+				shared + "## Review\n\n```md\n## not a heading\n\nstill code\n```\n"},
+			"tester": {Skills: []string{"testing-patterns", "clean-code"}, Content: "# Tester\n\n" +
+				shared + "## core principles\n\n- Tests first\n"},
+		},
+	}}
+
+	got, err := s.Compose("my-reviewer", "Reviews things", []string{"frontend", "tester"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"clean-code", "frontend-design", "testing-patterns"}; !reflect.DeepEqual(got.Skills, want) {
+		t.Errorf("Skills = %v, want %v", got.Skills, want)
+	}
+	if !strings.Contains(got.Content, "skills: clean-code, frontend-design, testing-patterns\n") {
+		t.Errorf("frontmatter skills missing:\n%s", got.Content)
+	}
+	if n := strings.Count(got.Content, "Read before you write"); n != 1 {
+		t.Errorf("shared principle appears %d times, want 1", n)
+	}
+	if n := strings.Count(strings.ToLower(got.Content), "## core principles"); n != 1 {
+		t.Errorf("Core Principles heading appears %d times, want 1", n)
+	}
+	if !strings.Contains(got.Content, "- Read before you write\n\n- Tests first") {
+		t.Errorf("tester's principle not merged into the section:\n%s", got.Content)
+	}
+	if !strings.Contains(got.Content, "```md\n## not a heading\n\nstill code\n```") {
+		t.Errorf("code fence split or lost:\n%s", got.Content)
+	}
+	if strings.Contains(got.Content, "Intro.") {
+		t.Errorf("base agent intro should be left out:\n%s", got.Content)
+	}
+
+	fm, _, err := templates.ParseFrontmatter(got.Content)
+	if err != nil || fm["name"] != "my-reviewer" {
+		t.Errorf("frontmatter = %v, %v", fm, err)
+	}
+
+	if _, err := s.Compose("x", "y", []string{"missing"}); err == nil {
+		t.Error("Compose() with an unknown base agent should fail")
+	}
+}
+
+func TestComposeQuotesDescription(t *testing.T) {
+	s := &Suggester{templates: &templates.Templates{
+		Agents: map[string]templates.Agent{
+			"frontend": {Skills: []string{"clean-code"}, Content: "# Frontend\n\n## Review\n\n- Look closely\n"},
+		},
+	}}
+
+	desc := "Reviews PRs: checks style #1, then 'tests'"
+	got, err := s.Compose("my-reviewer", desc, []string{"frontend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fm, _, err := templates.ParseFrontmatter(got.Content)
+	if err != nil {
+		t.Fatalf("frontmatter doesn't parse: %v\n%s", err, got.Content)
+	}
+	if fm["description"] != desc {
+		t.Errorf("description = %q, want %q", fm["description"], desc)
+	}
+	if fm["skills"] != "clean-code" {
+		t.Errorf("skills = %q, want clean-code", fm["skills"])
+	}
+}
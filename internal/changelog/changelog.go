@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Parses CHANGELOG.md into per-version sections
+
+package changelog
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Release is one version's section of the changelog
+type Release struct {
+	Version string // without brackets or a "v" prefix, e.g. "2.0.0"
+	Date    string // as written after the version, may be empty
+	Body    string // everything up to the next release heading
+}
+
+// releaseHeading matches Keep a Changelog headings such as
+// "## [2.0.0] - 2026-01-29" and plain ones such as "## v1.0.0"
+var releaseHeading = regexp.MustCompile(`^##\s+\[?v?(\d+\.\d+\.\d+[^\]\s]*)\]?(?:\s+-\s+(.+))?\s*$`)
+
+// Parse splits a changelog into releases, in the order they're written
+// (newest first, by convention).
+//
+// Only "## " headings that carry a version start a release. Others,
+// like "## [Unreleased]", end the previous release and are skipped along
+// with their contents, as is everything before the first release.
+func Parse(text string) []Release {
+	var releases []Release
+	var current *Release
+	var body []string
+
+	flush := func() {
+		if current != nil {
+			current.Body = strings.TrimSpace(strings.Join(body, "\n"))
+			releases = append(releases, *current)
+		}
+		current = nil
+		body = nil
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if !strings.HasPrefix(line, "## ") {
+			if current != nil {
+				body = append(body, line)
+			}
+			continue
+		}
+
+		flush()
+		if m := releaseHeading.FindStringSubmatch(line); m != nil {
+			current = &Release{Version: m[1], Date: strings.TrimSpace(m[2])}
+		}
+	}
+	flush()
+
+	return releases
+}
+
+// Find returns the release for version, which may have a "v" prefix
+func Find(releases []Release, version string) (Release, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	for _, r := range releases {
+		if r.Version == version {
+			return r, true
+		}
+	}
+	return Release{}, false
+}
+
+// Versions lists the versions in releases, in order
+func Versions(releases []Release) []string {
+	versions := make([]string, len(releases))
+	for i, r := range releases {
+		versions[i] = r.Version
+	}
+	return versions
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for changelog parsing
+
+package changelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eshanized/agen"
+)
+
+const sample = `# Changelog
+
+Intro text.
+
+## [Unreleased]
+
+- Not shipped yet
+
+## [2.1.0-beta.1] - 2026-03-01
+
+### Added
+- Beta feature
+
+## v2.0.0
+
+### Fixed
+- A bug
+
+## [1.0.0] - 2026-01-28
+
+- Initial release
+`
+
+func TestParse(t *testing.T) {
+	releases := Parse(sample)
+
+	if got := strings.Join(Versions(releases), ","); got != "2.1.0-beta.1,2.0.0,1.0.0" {
+		t.Fatalf("versions = %s, want 2.1.0-beta.1,2.0.0,1.0.0", got)
+	}
+	if releases[0].Date != "2026-03-01" || releases[1].Date != "" {
+		t.Errorf("dates = %q, %q; want 2026-03-01 and none", releases[0].Date, releases[1].Date)
+	}
+	if releases[0].Body != "### Added\n- Beta feature" {
+		t.Errorf("body = %q", releases[0].Body)
+	}
+	if strings.Contains(releases[2].Body, "Unreleased") || strings.Contains(releases[0].Body, "Not shipped") {
+		t.Error("unreleased section leaked into a release")
+	}
+}
+
+func TestFind(t *testing.T) {
+	releases := Parse(sample)
+
+	for _, version := range []string{"2.0.0", "v2.0.0", " 2.0.0 "} {
+		if r, ok := Find(releases, version); !ok || r.Body != "### Fixed\n- A bug" {
+			t.Errorf("Find(%q) = %+v, %v", version, r, ok)
+		}
+	}
+	if _, ok := Find(releases, "3.0.0"); ok {
+		t.Error("Find(3.0.0) found a release that isn't there")
+	}
+}
+
+func TestEmbeddedChangelog(t *testing.T) {
+	releases := Parse(agen.Changelog)
+	if len(releases) == 0 {
+		t.Fatal("embedded CHANGELOG.md has no releases")
+	}
+	for _, r := range releases {
+		if r.Body == "" {
+			t.Errorf("release %s has an empty section", r.Version)
+		}
+	}
+}
@@ -7,12 +7,13 @@
 package cli
 
 import (
-	"crypto/md5"
-	"encoding/hex"
+	"archive/zip"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 // diffCmd shows differences between installed and latest templates
@@ -36,9 +38,15 @@ Shows files that are:
 - Removed (no longer in latest)
 - Unchanged
 
+With --json (or the global --output json) the result is printed as a
+JSON object listing the added, modified, removed and unchanged templates
+with their content hashes.
+
 Examples:
   agen diff             # Compare current directory
-  agen diff --detailed  # Show file-level diffs`,
+  agen diff --json      # Machine-readable result
+  agen diff --detailed  # Show line-level diffs
+  agen diff --detailed --context 1`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runDiff,
 }
@@ -53,9 +61,15 @@ Monitors:
 - .agent/ directory for local changes
 - GitHub for upstream updates (optional)
 
+With --sync, each agent, skill or workflow file is re-parsed when it
+changes and any frontmatter error is reported inline. Deleting a template
+that agen ships offers to restore it from the embedded templates.
+
 Examples:
   agen watch            # Watch current directory
-  agen watch --upstream # Also check for remote updates`,
+  agen watch --sync     # Re-parse edits, offer to restore deletions
+  agen watch --upstream # Also check for remote updates
+  agen watch --debounce 1s`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runWatch,
 }
@@ -69,12 +83,21 @@ var auditCmd = &cobra.Command{
 Checks:
 - Template integrity (checksums)
 - Suspicious patterns in agent rules
+- .env files missing from .gitignore
 - Known security issues
 - License compliance
 
+Extra patterns can be listed in .agen-audit.yml in the project root,
+as plain substrings or regular expressions, each with a severity
+(critical, warning or info). Info findings don't fail the audit.
+
+--fix adds .env to .gitignore and removes empty agent files. Suspicious
+patterns are never changed automatically; they're listed as skipped.
+
 Examples:
-  agen audit            # Audit current directory
-  agen audit --fix      # Attempt to fix issues`,
+  agen audit               # Audit current directory
+  agen audit --no-defaults # Only check the patterns in .agen-audit.yml
+  agen audit --fix         # Attempt to fix issues`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runAudit,
 }
@@ -85,15 +108,20 @@ var exportCmd = &cobra.Command{
 	Short: "Export templates",
 	Long: `Export installed templates to various formats.
 
+By default the templates installed in the project are exported, including
+local customisations in .agent/. Use --source embedded to export the
+templates shipped with agen instead (the default when nothing is installed).
+
 Formats:
 - json: Machine-readable JSON
 - yaml: YAML format
 - markdown: Human-readable documentation
-- zip: Compressed archive
+- zip: Archive in the .agent/ layout with a manifest.json (needs -o)
 
 Examples:
   agen export --format json > templates.json
-  agen export --format zip -o backup.zip`,
+  agen export --format zip -o backup.zip
+  agen export --source embedded -f yaml`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runExport,
 }
@@ -106,10 +134,14 @@ var validateCmd = &cobra.Command{
 
 Checks:
 - YAML frontmatter parsing
-- Required fields present
+- Required fields present (name, description)
 - Skill references valid
 - Workflow syntax correct
 
+Missing frontmatter, skill references that are neither installed nor
+embedded, and workflows with no body are warnings; --strict reports them
+as errors. A workflow's name: field, if set, must match its file name.
+
 Examples:
   agen validate         # Validate current directory
   agen validate --strict`,
@@ -119,16 +151,22 @@ Examples:
 
 func init() {
 	diffCmd.Flags().Bool("detailed", false, "show detailed file diffs")
+	diffCmd.Flags().Int("context", 3, "unchanged lines to show around each change with --detailed")
 	diffCmd.Flags().Bool("json", false, "output as JSON")
 
 	watchCmd.Flags().Bool("upstream", false, "also watch for remote updates")
 	watchCmd.Flags().Duration("interval", 30*time.Second, "check interval for upstream")
+	watchCmd.Flags().Duration("debounce", 500*time.Millisecond, "wait this long for a file to settle before reporting it (0 to disable)")
+	watchCmd.Flags().Bool("sync", false, "re-parse changed templates and offer to restore deleted ones")
 
-	auditCmd.Flags().Bool("fix", false, "attempt to fix issues")
+	auditCmd.Flags().Bool("fix", false, "fix what can be fixed safely (.gitignore entries, empty files)")
 	auditCmd.Flags().Bool("json", false, "output as JSON")
+	auditCmd.Flags().Bool("no-defaults", false, "skip the built-in suspicious patterns (use only .agen-audit.yml)")
 
 	exportCmd.Flags().StringP("format", "f", "json", "output format (json, yaml, markdown, zip)")
-	exportCmd.Flags().StringP("output", "o", "", "output file (default: stdout)")
+	exportCmd.Flags().StringP("out-file", "o", "", "output file (default: stdout)")
+	addOutputAlias(exportCmd, "out-file")
+	exportCmd.Flags().String("source", "", "templates to export: installed or embedded (default: installed if detected)")
 
 	validateCmd.Flags().Bool("strict", false, "strict validation mode")
 	validateCmd.Flags().Bool("json", false, "output as JSON")
@@ -143,10 +181,10 @@ func init() {
 // runDiff compares installed vs latest templates
 //
 // How it works:
-// 1. Load installed templates from project
-// 2. Load latest templates (embedded or network)
-// 3. Compare each file by content hash
-// 4. Display differences in a clear format
+// 1. Read the installed templates through the detected IDE adapter
+// 2. Render the latest templates with the same adapter
+// 3. Compare each agent, skill and workflow by content hash
+// 4. Display differences in a clear format, or as JSON with --json
 func runDiff(cmd *cobra.Command, args []string) error {
 	targetDir := "."
 	if len(args) > 0 {
@@ -155,10 +193,8 @@ func runDiff(cmd *cobra.Command, args []string) error {
 
 	absPath, _ := filepath.Abs(targetDir)
 	detailed, _ := cmd.Flags().GetBool("detailed")
-
-	cyan := color.New(color.FgCyan, color.Bold)
-	cyan.Println("\n📊 AGEN Diff")
-	fmt.Printf("Directory: %s\n\n", absPath)
+	diffContext, _ := cmd.Flags().GetInt("context")
+	jsonOut := wantJSON(cmd)
 
 	// detect IDE
 	ideAdapter := ide.Detect(absPath)
@@ -172,51 +208,150 @@ func runDiff(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load templates: %w", err)
 	}
 
-	// Compare agents
-	agentDir := filepath.Join(absPath, ".agent", "agents")
-	added, modified, removed, unchanged := 0, 0, 0, 0
+	// read both sides through the adapter so single-file formats compare
+	// like with like
+	installed, err := ideAdapter.GetInstalledContent(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to read installed templates: %w", err)
+	}
+	rendered, err := ide.RenderedContent(ideAdapter, latest)
+	if err != nil {
+		return fmt.Errorf("failed to render latest templates: %w", err)
+	}
 
-	green := color.New(color.FgGreen)
-	yellow := color.New(color.FgYellow)
-	red := color.New(color.FgRed)
+	result := diffOutput{
+		Directory: absPath,
+		IDE:       ideAdapter.Name(),
+		Agents:    compareContent(installed.Agents, rendered.Agents),
+		Skills:    compareContent(installed.Skills, rendered.Skills),
+		Workflows: compareContent(installed.Workflows, rendered.Workflows),
+	}
+
+	if jsonOut {
+		return printJSON(result)
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Println("\n📊 AGEN Diff")
+	fmt.Printf("Directory: %s\n\n", absPath)
 
 	fmt.Println("📦 Agents:")
-	for name, agent := range latest.Agents {
-		installedPath := filepath.Join(agentDir, name+".md")
-		if _, err := os.Stat(installedPath); os.IsNotExist(err) {
-			green.Printf("  + %s (new)\n", name)
-			added++
+	printDiffSection(result.Agents, rendered.Agents, detailed, diffContext)
+	fmt.Println("\n🧩 Skills:")
+	printDiffSection(result.Skills, rendered.Skills, detailed, diffContext)
+	fmt.Println("\n🔄 Workflows:")
+	printDiffSection(result.Workflows, rendered.Workflows, detailed, diffContext)
+
+	fmt.Println()
+	fmt.Println("Summary:")
+	for _, s := range []struct {
+		label   string
+		section diffSection
+	}{
+		{"Agents", result.Agents},
+		{"Skills", result.Skills},
+		{"Workflows", result.Workflows},
+	} {
+		added, modified, removed, unchanged := s.section.counts()
+		fmt.Printf("  %-10s +%d added, ~%d modified, -%d removed, %d unchanged\n",
+			s.label+":", added, modified, removed, unchanged)
+	}
+
+	return nil
+}
+
+// diffEntry is one template in agen diff output. Hashes are SHA-256 of the
+// file content; a side that doesn't exist has no hash.
+type diffEntry struct {
+	Name          string `json:"name"`
+	InstalledHash string `json:"installed_hash,omitempty"`
+	LatestHash    string `json:"latest_hash,omitempty"`
+
+	installed string // installed content, for --detailed
+}
+
+// diffSection holds the comparison for one kind of template, each list
+// sorted by name
+type diffSection struct {
+	Added     []diffEntry `json:"added"`
+	Modified  []diffEntry `json:"modified"`
+	Removed   []diffEntry `json:"removed"`
+	Unchanged []diffEntry `json:"unchanged"`
+}
+
+func (d diffSection) counts() (added, modified, removed, unchanged int) {
+	return len(d.Added), len(d.Modified), len(d.Removed), len(d.Unchanged)
+}
+
+// diffOutput is the agen diff --json document
+type diffOutput struct {
+	Directory string      `json:"directory"`
+	IDE       string      `json:"ide"`
+	Agents    diffSection `json:"agents"`
+	Skills    diffSection `json:"skills"`
+	Workflows diffSection `json:"workflows"`
+}
+
+// compareContent compares the latest rendering of each template with what's
+// installed. Both maps are keyed by template name.
+func compareContent(installed, latest map[string]string) diffSection {
+	section := diffSection{
+		Added:     []diffEntry{},
+		Modified:  []diffEntry{},
+		Removed:   []diffEntry{},
+		Unchanged: []diffEntry{},
+	}
+
+	names := make([]string, 0, len(latest))
+	for name := range latest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		entry := diffEntry{Name: name, LatestHash: templates.Hash([]byte(latest[name]))}
+		content, ok := installed[name]
+		if !ok {
+			section.Added = append(section.Added, entry)
+			continue
+		}
+		entry.InstalledHash = templates.Hash([]byte(content))
+		entry.installed = content
+		if entry.InstalledHash != entry.LatestHash {
+			section.Modified = append(section.Modified, entry)
 		} else {
-			// Compare content
-			installedContent, _ := os.ReadFile(installedPath)
-			if hashContent(installedContent) != hashContent([]byte(agent.Content)) {
-				yellow.Printf("  ~ %s (modified)\n", name)
-				modified++
-				if detailed {
-					showDiff(string(installedContent), agent.Content)
-				}
-			} else {
-				unchanged++
-			}
+			section.Unchanged = append(section.Unchanged, entry)
 		}
 	}
 
-	// Check for removed agents
-	if entries, err := os.ReadDir(agentDir); err == nil {
-		for _, entry := range entries {
-			name := strings.TrimSuffix(entry.Name(), ".md")
-			if _, ok := latest.Agents[name]; !ok {
-				red.Printf("  - %s (removed)\n", name)
-				removed++
-			}
+	for name, content := range installed {
+		if _, ok := latest[name]; !ok {
+			section.Removed = append(section.Removed, diffEntry{Name: name, InstalledHash: templates.Hash([]byte(content))})
 		}
 	}
+	sort.Slice(section.Removed, func(i, j int) bool { return section.Removed[i].Name < section.Removed[j].Name })
 
-	fmt.Println()
-	fmt.Printf("Summary: +%d added, ~%d modified, -%d removed, %d unchanged\n",
-		added, modified, removed, unchanged)
+	return section
+}
 
-	return nil
+// printDiffSection prints one section of the colored diff
+func printDiffSection(section diffSection, latest map[string]string, detailed bool, diffContext int) {
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+	red := color.New(color.FgRed)
+
+	for _, entry := range section.Added {
+		green.Printf("  + %s (new)\n", entry.Name)
+	}
+	for _, entry := range section.Modified {
+		yellow.Printf("  ~ %s (modified)\n", entry.Name)
+		if detailed {
+			showDiff(entry.installed, latest[entry.Name], diffContext)
+		}
+	}
+	for _, entry := range section.Removed {
+		red.Printf("  - %s (removed)\n", entry.Name)
+	}
 }
 
 // runWatch monitors for changes
@@ -229,11 +364,14 @@ func runWatch(cmd *cobra.Command, args []string) error {
 	absPath, _ := filepath.Abs(targetDir)
 	upstream, _ := cmd.Flags().GetBool("upstream")
 	interval, _ := cmd.Flags().GetDuration("interval")
+	debounce, _ := cmd.Flags().GetDuration("debounce")
+	syncTemplates, _ := cmd.Flags().GetBool("sync")
 
 	cyan := color.New(color.FgCyan, color.Bold)
 	cyan.Println("\n👁 AGEN Watch")
 	fmt.Printf("Directory: %s\n", absPath)
 	fmt.Printf("Upstream: %v (interval: %v)\n", upstream, interval)
+	fmt.Printf("Sync: %v\n", syncTemplates)
 	fmt.Println("\nWatching for changes... (Ctrl+C to stop)")
 
 	watcher, err := fsnotify.NewWatcher()
@@ -242,19 +380,34 @@ func runWatch(cmd *cobra.Command, args []string) error {
 	}
 	defer watcher.Close()
 
+	// fsnotify isn't recursive: watch .agent and each directory under it,
+	// including ones created while we run
 	agentDir := filepath.Join(absPath, ".agent")
-	if err := watcher.Add(agentDir); err != nil {
+	dirs := newDirWatches(watcher)
+	if err := dirs.AddTree(agentDir); err != nil {
 		return fmt.Errorf("failed to watch directory: %w", err)
 	}
 
-	// Also watch subdirectories
-	filepath.Walk(agentDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil || !info.IsDir() {
-			return nil
+	// editors fire several events per save; report each path once it
+	// settles (removes are reported immediately)
+	debouncer := newEventDebouncer(debounce)
+	defer debouncer.Stop()
+
+	var syncer *templateSyncer
+	if syncTemplates {
+		syncer = newTemplateSyncer(agentDir)
+	}
+
+	report := func(event fsnotify.Event) {
+		relPath, _ := filepath.Rel(absPath, event.Name)
+		printInfo("[%s] %s: %s",
+			time.Now().Format("15:04:05"),
+			event.Op.String(),
+			relPath)
+		if syncer != nil {
+			syncer.Sync(event.Name)
 		}
-		watcher.Add(path)
-		return nil
-	})
+	}
 
 	// Upstream ticker
 	var upstreamTicker *time.Ticker
@@ -269,12 +422,16 @@ func runWatch(cmd *cobra.Command, args []string) error {
 			if !ok {
 				return nil
 			}
-			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove) != 0 {
-				relPath, _ := filepath.Rel(absPath, event.Name)
-				printInfo("[%s] %s: %s",
-					time.Now().Format("15:04:05"),
-					event.Op.String(),
-					relPath)
+			dirs.Handle(event)
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
+				for _, e := range debouncer.Add(event, time.Now()) {
+					report(e)
+				}
+			}
+
+		case <-debouncer.C():
+			for _, e := range debouncer.Due(time.Now()) {
+				report(e)
 			}
 
 		case err, ok := <-watcher.Errors:
@@ -290,7 +447,7 @@ func runWatch(cmd *cobra.Command, args []string) error {
 			return nil
 		}():
 			printInfo("[%s] Checking upstream...", time.Now().Format("15:04:05"))
-			if release, err := updater.CheckForUpdate(Version); err == nil && release != nil {
+			if release, err := updater.CheckForUpdate(Version, updateChannel()); err == nil && release != nil {
 				color.Green("\n✨ New version available: %s", release.Version)
 				fmt.Printf("Run 'agen upgrade' to update\n\n")
 			} else if err != nil {
@@ -301,6 +458,14 @@ func runWatch(cmd *cobra.Command, args []string) error {
 }
 
 // runAudit performs security audit
+//
+// How it works:
+//  1. Collect findings: unreadable or empty agent files, templates whose
+//     sha256 no longer matches .agent/manifest.json, a .env that
+//     .gitignore doesn't cover, and suspicious patterns in .agent/
+//  2. With --fix, apply the safe fixes (see auditFinding.fix) and note
+//     why the rest were skipped
+//  3. Report; the audit passes when no unfixed finding is above info
 func runAudit(cmd *cobra.Command, args []string) error {
 	targetDir := "."
 	if len(args) > 0 {
@@ -308,48 +473,82 @@ func runAudit(cmd *cobra.Command, args []string) error {
 	}
 
 	absPath, _ := filepath.Abs(targetDir)
+	jsonOut := wantJSON(cmd)
 
-	cyan := color.New(color.FgCyan, color.Bold)
-	cyan.Println("\n🔒 AGEN Security Audit")
-	fmt.Printf("Directory: %s\n\n", absPath)
+	noDefaults, _ := cmd.Flags().GetBool("no-defaults")
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	rules, err := loadAuditRules(absPath, noDefaults)
+	if err != nil {
+		return err
+	}
 
-	issues := 0
+	report := auditOutput{Directory: absPath, Issues: []auditFinding{}}
+	add := func(check, severity, path, message string, fix func() (string, error)) {
+		report.Issues = append(report.Issues, auditFinding{
+			Check:    check,
+			Severity: severity,
+			Path:     path,
+			Message:  message,
+			fix:      fix,
+		})
+	}
 
 	// Check 1: Template integrity
-	fmt.Println("Checking template integrity...")
 	agentDir := filepath.Join(absPath, ".agent", "agents")
 	if entries, err := os.ReadDir(agentDir); err == nil {
 		for _, entry := range entries {
 			if entry.IsDir() {
 				continue
 			}
+			relPath := filepath.ToSlash(filepath.Join(".agent", "agents", entry.Name()))
+			fullPath := filepath.Join(agentDir, entry.Name())
 			// verify file is readable and valid markdown
-			content, err := os.ReadFile(filepath.Join(agentDir, entry.Name()))
+			content, err := os.ReadFile(fullPath)
 			if err != nil {
-				printWarning("  Cannot read: %s", entry.Name())
-				issues++
+				add("integrity", "warning", relPath, "Cannot read: "+entry.Name(), nil)
 			} else if len(content) == 0 {
-				printWarning("  Empty file: %s", entry.Name())
-				issues++
+				add("integrity", "warning", relPath, "Empty file: "+entry.Name(), func() (string, error) {
+					return "removed empty file", os.Remove(fullPath)
+				})
 			}
 		}
 	}
-	if issues == 0 {
-		printSuccess("Template integrity: OK")
+
+	// Check 1b: checksums against the manifest written at install time.
+	// Only Antigravity installs have one; a missing manifest is just a hint.
+	installDir := filepath.Join(absPath, ".agent")
+	if _, err := os.Stat(installDir); err == nil {
+		manifest, err := ide.LoadManifest(installDir)
+		switch {
+		case err != nil:
+			add("integrity", "warning", ".agent/"+ide.ManifestFile, "Cannot read manifest: "+err.Error(), nil)
+		case manifest == nil:
+			add("integrity", "info", ".agent/"+ide.ManifestFile,
+				"No checksum manifest; reinstall with 'agen init --force' to enable tamper detection", nil)
+		default:
+			for _, mismatch := range manifest.Verify(installDir) {
+				relPath := ".agent/" + mismatch.Path
+				if mismatch.Missing {
+					add("integrity", "warning", relPath, "Missing since install: "+mismatch.Path, nil)
+				} else {
+					add("integrity", "warning", relPath, "Checksum mismatch (modified since install): "+mismatch.Path, nil)
+				}
+			}
+		}
 	}
 
-	// Check 2: Suspicious patterns
-	fmt.Println("\nChecking for suspicious patterns...")
-	suspiciousPatterns := []string{
-		"rm -rf",
-		"sudo",
-		"curl | bash",
-		"wget | bash",
-		"eval(",
+	// Check 2: Secrets kept out of git
+	if _, err := os.Stat(filepath.Join(absPath, ".env")); err == nil && !gitignoreCovers(absPath, ".env") {
+		add("gitignore", "warning", ".gitignore", ".env file exists but is not in .gitignore", func() (string, error) {
+			return "added .env to .gitignore", appendGitignore(absPath, ".env")
+		})
 	}
 
-	filepath.Walk(filepath.Join(absPath, ".agent"), func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
+	// Check 3: Suspicious patterns (built-in defaults plus .agen-audit.yml)
+	manifestPath := filepath.Join(installDir, ide.ManifestFile)
+	filepath.Walk(installDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || path == manifestPath {
 			return nil
 		}
 		content, err := os.ReadFile(path)
@@ -357,40 +556,203 @@ func runAudit(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
-		for _, pattern := range suspiciousPatterns {
-			if strings.Contains(string(content), pattern) {
+		for _, rule := range rules {
+			if loc := rule.re.FindIndex(content); loc != nil {
 				relPath, _ := filepath.Rel(absPath, path)
-				printWarning("  Found '%s' in %s", pattern, relPath)
-				issues++
+				match := string(content[loc[0]:loc[1]])
+				add("pattern", rule.severity, filepath.ToSlash(relPath), rule.describe(match, relPath), nil)
 			}
 		}
 		return nil
 	})
 
-	if issues == 0 {
+	if fix {
+		for i := range report.Issues {
+			report.Issues[i].applyFix()
+		}
+	}
+
+	// info findings are reported but don't fail the audit
+	report.Passed = true
+	remaining := 0
+	for _, issue := range report.Issues {
+		if issue.Fixed {
+			continue
+		}
+		remaining++
+		if issue.Severity != "info" {
+			report.Passed = false
+		}
+	}
+	if jsonOut {
+		return printJSON(report)
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Println("\n🔒 AGEN Security Audit")
+	fmt.Printf("Directory: %s\n\n", absPath)
+
+	fmt.Println("Checking template integrity...")
+	if printAuditFindings(report.Issues, "integrity") == 0 {
+		printSuccess("Template integrity: OK")
+	}
+
+	fmt.Println("\nChecking .gitignore...")
+	if printAuditFindings(report.Issues, "gitignore") == 0 {
+		printSuccess("Secrets ignored: OK")
+	}
+
+	fmt.Println("\nChecking for suspicious patterns...")
+	if printAuditFindings(report.Issues, "pattern") == 0 {
 		printSuccess("No suspicious patterns found")
 	}
 
+	if fix && len(report.Issues) > 0 {
+		fmt.Println("\nFixes:")
+		for _, issue := range report.Issues {
+			if issue.Fixed {
+				printSuccess("  %s: %s", issue.Path, issue.FixNote)
+			} else {
+				printInfo("  Skipped %s: %s", issue.Path, issue.FixNote)
+			}
+		}
+	}
+
 	// Summary
 	fmt.Println()
-	if issues == 0 {
+	if report.Passed {
 		color.New(color.FgGreen, color.Bold).Println("✨ Audit passed!")
 	} else {
-		color.New(color.FgYellow).Printf("⚠ Found %d potential issue(s)\n", issues)
+		color.New(color.FgYellow).Printf("⚠ Found %d potential issue(s)\n", remaining)
 	}
 
 	return nil
 }
 
+// auditFinding is one problem runAudit found
+type auditFinding struct {
+	Check    string `json:"check"`    // integrity, gitignore or pattern
+	Severity string `json:"severity"` // critical, warning or info
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+
+	// Set by --fix: whether the finding was fixed, and what was done or
+	// why it was left alone
+	Fixed   bool   `json:"fixed,omitempty"`
+	FixNote string `json:"fix_note,omitempty"`
+
+	// fix applies the automated fix and describes it; nil when there's no
+	// safe one
+	fix func() (string, error)
+}
+
+// applyFix runs the finding's fix, or records why it was skipped
+func (f *auditFinding) applyFix() {
+	if f.fix == nil {
+		switch f.Check {
+		case "pattern":
+			f.FixNote = "suspicious patterns in agent rules need a human to review them"
+		default:
+			f.FixNote = "no safe automatic fix; check the file by hand"
+		}
+		return
+	}
+
+	note, err := f.fix()
+	if err != nil {
+		f.FixNote = "fix failed: " + err.Error()
+		return
+	}
+	f.Fixed = true
+	f.FixNote = note
+}
+
+// printAuditFindings prints the findings for one check and returns how
+// many there were
+func printAuditFindings(issues []auditFinding, check string) int {
+	count := 0
+	for _, issue := range issues {
+		if issue.Check == check {
+			printAuditFinding(issue)
+			count++
+		}
+	}
+	return count
+}
+
+// printAuditFinding prints a finding in its severity's colour
+func printAuditFinding(issue auditFinding) {
+	switch issue.Severity {
+	case "critical":
+		printError("  [critical] %s", issue.Message)
+	case "info":
+		printInfo("  [info] %s", issue.Message)
+	default:
+		printWarning("  %s", issue.Message)
+	}
+}
+
+// gitignoreCovers reports whether the project's .gitignore has a line
+// ignoring name (name, /name, or name/)
+func gitignoreCovers(projectDir, name string) bool {
+	content, err := os.ReadFile(filepath.Join(projectDir, ".gitignore"))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.Trim(strings.TrimSpace(line), "/")
+		if line == name {
+			return true
+		}
+	}
+	return false
+}
+
+// appendGitignore adds name on its own line at the end of .gitignore,
+// creating the file if needed
+func appendGitignore(projectDir, name string) error {
+	path := filepath.Join(projectDir, ".gitignore")
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	prefix := ""
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		prefix = "\n"
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(prefix + name + "\n")
+	return err
+}
+
+// auditOutput is the audit --output json document
+type auditOutput struct {
+	Directory string         `json:"directory"`
+	Passed    bool           `json:"passed"`
+	Issues    []auditFinding `json:"issues"`
+}
+
 // runExport exports templates to various formats
 func runExport(cmd *cobra.Command, args []string) error {
 	format, _ := cmd.Flags().GetString("format")
-	output, _ := cmd.Flags().GetString("output")
+	output, _ := cmd.Flags().GetString("out-file")
+	source, _ := cmd.Flags().GetString("source")
 
-	// load templates
-	tmpl, err := templates.LoadEmbedded()
+	targetDir := "."
+	if len(args) > 0 {
+		targetDir = args[0]
+	}
+	absPath, _ := filepath.Abs(targetDir)
+
+	tmpl, err := loadExportTemplates(absPath, source)
 	if err != nil {
-		return fmt.Errorf("failed to load templates: %w", err)
+		return err
 	}
 
 	var data []byte
@@ -399,18 +761,7 @@ func runExport(cmd *cobra.Command, args []string) error {
 	case "json":
 		data, err = json.MarshalIndent(tmpl, "", "  ")
 	case "yaml":
-		// Simple YAML-like output
-		var sb strings.Builder
-		sb.WriteString("# AGEN Templates Export\n\n")
-		sb.WriteString("agents:\n")
-		for name, agent := range tmpl.Agents {
-			sb.WriteString(fmt.Sprintf("  %s:\n    description: %s\n", name, agent.Description))
-		}
-		sb.WriteString("\nskills:\n")
-		for name, skill := range tmpl.Skills {
-			sb.WriteString(fmt.Sprintf("  %s:\n    description: %s\n", name, skill.Description))
-		}
-		data = []byte(sb.String())
+		data, err = exportYAML(tmpl)
 	case "markdown":
 		var sb strings.Builder
 		sb.WriteString("# AGEN Templates\n\n")
@@ -423,6 +774,12 @@ func runExport(cmd *cobra.Command, args []string) error {
 			sb.WriteString(fmt.Sprintf("### %s\n%s\n\n", name, skill.Description))
 		}
 		data = []byte(sb.String())
+	case "zip":
+		// binary on a terminal is no use to anyone
+		if output == "" {
+			return fmt.Errorf("zip export needs an output file (use -o backup.zip)")
+		}
+		data, err = exportZip(tmpl)
 	default:
 		return fmt.Errorf("unsupported format: %s", format)
 	}
@@ -439,7 +796,246 @@ func runExport(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// loadExportTemplates returns the templates agen export should write.
+//
+// How it works:
+//  1. source "embedded" exports the shipped templates
+//  2. source "installed" (the default when an install is detected) reads
+//     what the detected adapter installed in the project
+//  3. For the .agent/ tree that's the full, possibly customised, files.
+//     Single-file formats only keep names and summaries, so the installed
+//     names are exported with their embedded content, and a note says so
+//     on stderr (stdout may be the export itself)
+func loadExportTemplates(projectDir, source string) (*templates.Templates, error) {
+	adapter := ide.Detect(projectDir)
+
+	switch source {
+	case "":
+		if adapter == nil {
+			source = "embedded"
+		} else {
+			source = "installed"
+		}
+	case "installed":
+		if adapter == nil {
+			return nil, fmt.Errorf("no AGEN installation found in %s (use --source embedded)", projectDir)
+		}
+	case "embedded":
+	default:
+		return nil, fmt.Errorf("invalid --source %q (valid: installed, embedded)", source)
+	}
+
+	if source == "embedded" {
+		embedded, err := templates.LoadEmbedded()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load templates: %w", err)
+		}
+		return embedded, nil
+	}
+
+	if _, ok := adapter.(*ide.AntigravityAdapter); !ok {
+		fmt.Fprintf(os.Stderr, "Note: %s keeps only template summaries; exporting the installed templates with their embedded content\n", adapter.Name())
+	}
+	return installedTemplates(projectDir, adapter)
+}
+
+// installedTemplates returns the templates adapter installed in
+// projectDir. The .agent/ tree is read as is; for single-file formats,
+// which only keep names and summaries, the installed names are filled in
+// with their embedded content.
+func installedTemplates(projectDir string, adapter ide.Adapter) (*templates.Templates, error) {
+	if _, ok := adapter.(*ide.AntigravityAdapter); ok {
+		return templates.LoadFromDir(filepath.Join(projectDir, ".agent")), nil
+	}
+
+	embedded, err := templates.LoadEmbedded()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load templates: %w", err)
+	}
+
+	installed, err := adapter.GetInstalledContent(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installed templates: %w", err)
+	}
+
+	tmpl := &templates.Templates{
+		Version:   embedded.Version,
+		Agents:    make(map[string]templates.Agent),
+		Skills:    make(map[string]templates.Skill),
+		Workflows: make(map[string]templates.Workflow),
+	}
+	for name := range installed.Agents {
+		if agent, ok := embedded.Agents[name]; ok {
+			tmpl.Agents[name] = agent
+		}
+	}
+	for name := range installed.Skills {
+		if skill, ok := embedded.Skills[name]; ok {
+			tmpl.Skills[name] = skill
+		}
+	}
+	for name := range installed.Workflows {
+		if workflow, ok := embedded.Workflows[name]; ok {
+			tmpl.Workflows[name] = workflow
+		}
+	}
+	return tmpl, nil
+}
+
+// exportDocument is the agen export --format yaml document. It carries
+// every template's metadata and full content, so unmarshalling it gives
+// back exactly what was exported.
+type exportDocument struct {
+	Version   string                    `yaml:"version"`
+	Agents    map[string]exportAgent    `yaml:"agents"`
+	Skills    map[string]exportSkill    `yaml:"skills"`
+	Workflows map[string]exportWorkflow `yaml:"workflows"`
+}
+
+type exportAgent struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Skills      []string `yaml:"skills,omitempty"`
+	Tools       []string `yaml:"tools,omitempty"`
+	Source      string   `yaml:"source,omitempty"`
+	Content     string   `yaml:"content"`
+}
+
+type exportSkill struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Requires    []string `yaml:"requires,omitempty"`
+	Scripts     []string `yaml:"scripts,omitempty"`
+	Source      string   `yaml:"source,omitempty"`
+	Content     string   `yaml:"content"`
+}
+
+type exportWorkflow struct {
+	Name        string                  `yaml:"name"`
+	Description string                  `yaml:"description"`
+	Args        []templates.WorkflowArg `yaml:"args,omitempty"`
+	Source      string                  `yaml:"source,omitempty"`
+	Content     string                  `yaml:"content"`
+}
+
+func buildExportDocument(tmpl *templates.Templates) exportDocument {
+	doc := exportDocument{
+		Version:   tmpl.Version,
+		Agents:    make(map[string]exportAgent, len(tmpl.Agents)),
+		Skills:    make(map[string]exportSkill, len(tmpl.Skills)),
+		Workflows: make(map[string]exportWorkflow, len(tmpl.Workflows)),
+	}
+	for name, a := range tmpl.Agents {
+		doc.Agents[name] = exportAgent{
+			Name:        a.Name,
+			Description: a.Description,
+			Skills:      a.Skills,
+			Tools:       a.Tools,
+			Source:      a.Source,
+			Content:     a.Content,
+		}
+	}
+	for name, s := range tmpl.Skills {
+		doc.Skills[name] = exportSkill{
+			Name:        s.Name,
+			Description: s.Description,
+			Requires:    s.Requires,
+			Scripts:     s.Scripts,
+			Source:      s.Source,
+			Content:     s.Content,
+		}
+	}
+	for name, w := range tmpl.Workflows {
+		doc.Workflows[name] = exportWorkflow{
+			Name:        w.Name,
+			Description: w.Description,
+			Args:        w.Args,
+			Source:      w.Source,
+			Content:     w.Content,
+		}
+	}
+	return doc
+}
+
+// exportYAML marshals the export document with the two-space indent the
+// config files use
+func exportYAML(tmpl *templates.Templates) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(buildExportDocument(tmpl)); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeZipFile adds one deflated file to zw. Export and hub publish
+// both write their archives through here.
+func writeZipFile(zw *zip.Writer, name string, modified time.Time, content []byte) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// exportZip packs the templates into a zip laid out like .agent/:
+// agents/<name>.md, skills/<name>/SKILL.md, workflows/<name>.md, plus a
+// manifest.json with each file's sha256 (the same format agen init writes)
+func exportZip(tmpl *templates.Templates) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	manifest := ide.BuildManifest(tmpl)
+	write := func(name, content string) error {
+		return writeZipFile(zw, name, manifest.Generated, []byte(content))
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := write(ide.ManifestFile, string(manifestJSON)+"\n"); err != nil {
+		return nil, err
+	}
+
+	for _, name := range tmpl.AgentNames() {
+		if err := write("agents/"+name+".md", tmpl.Agents[name].Content); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range tmpl.SkillNames() {
+		if err := write("skills/"+name+"/SKILL.md", tmpl.Skills[name].Content); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range tmpl.WorkflowNames() {
+		if err := write("workflows/"+name+".md", tmpl.Workflows[name].Content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // runValidate validates template syntax
+//
+// How it works:
+//  1. Check every agent, skill and workflow file, collecting findings
+//  2. Check skill requirements for loops
+//  3. Print them (or the --json report) and fail if any is an error, so
+//     CI can gate on the exit code
 func runValidate(cmd *cobra.Command, args []string) error {
 	targetDir := "."
 	if len(args) > 0 {
@@ -448,88 +1044,318 @@ func runValidate(cmd *cobra.Command, args []string) error {
 
 	absPath, _ := filepath.Abs(targetDir)
 	strict, _ := cmd.Flags().GetBool("strict")
+	jsonOut := wantJSON(cmd)
 
-	cyan := color.New(color.FgCyan, color.Bold)
-	cyan.Println("\n✅ AGEN Validate")
-	fmt.Printf("Directory: %s\n", absPath)
-	fmt.Printf("Strict mode: %v\n\n", strict)
+	report := validateOutput{Directory: absPath, Strict: strict, Issues: []validateFinding{}}
+
+	// add records one finding; isError picks error over warning
+	add := func(isError bool, section, path, rule, format string, args ...interface{}) {
+		severity := "warning"
+		if isError {
+			severity = "error"
+		}
+		report.Issues = append(report.Issues, validateFinding{
+			Path:     path,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+			Rule:     rule,
+			section:  section,
+		})
+	}
+
+	// skill references may name an installed skill or an embedded one
+	// (init can still add it)
+	skillDir := filepath.Join(absPath, ".agent", "skills")
+	knownSkills := make(map[string]bool)
+	if entries, err := os.ReadDir(skillDir); err == nil {
+		for _, entry := range entries {
+			if _, err := os.Stat(filepath.Join(skillDir, entry.Name(), "SKILL.md")); err == nil {
+				knownSkills[entry.Name()] = true
+			}
+		}
+	}
+	if embedded, err := templates.LoadEmbedded(); err == nil {
+		for name := range embedded.Skills {
+			knownSkills[name] = true
+		}
+	}
 
-	errors := 0
-	warnings := 0
+	var sections []string
 
 	// Validate agents
 	agentDir := filepath.Join(absPath, ".agent", "agents")
 	if entries, err := os.ReadDir(agentDir); err == nil {
-		fmt.Println("Validating agents...")
+		sections = append(sections, "agents")
 		for _, entry := range entries {
 			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
 				continue
 			}
+			path := filepath.ToSlash(filepath.Join(".agent", "agents", entry.Name()))
 
 			content, err := os.ReadFile(filepath.Join(agentDir, entry.Name()))
 			if err != nil {
-				printError("  %s: cannot read", entry.Name())
-				errors++
+				add(true, "agents", path, "file/unreadable", "%s: cannot read", entry.Name())
 				continue
 			}
 
-			// Check for frontmatter
-			if !strings.HasPrefix(string(content), "---") {
-				if strict {
-					printWarning("  %s: missing frontmatter", entry.Name())
-					warnings++
+			fields, body, err := templates.ParseFrontmatter(string(content))
+			if err != nil {
+				add(true, "agents", path, "frontmatter/invalid", "%s: %v", entry.Name(), err)
+				continue
+			}
+			if fields == nil {
+				add(strict, "agents", path, "frontmatter/missing", "%s: missing frontmatter", entry.Name())
+			} else {
+				for _, key := range missingFrontmatterKeys(fields, "name", "description") {
+					add(true, "agents", path, "frontmatter/required-key",
+						"%s: frontmatter is missing required key %q", entry.Name(), key)
+				}
+				agent, _ := templates.ParseAgent(strings.TrimSuffix(entry.Name(), ".md"), string(content))
+				for _, skill := range agent.Skills {
+					if !knownSkills[skill] {
+						add(strict, "agents", path, "skills/unknown-reference",
+							"%s: skills references unknown skill %q", entry.Name(), skill)
+					}
 				}
 			}
 
 			// Check for heading
-			if !strings.Contains(string(content), "#") {
-				printWarning("  %s: no markdown heading", entry.Name())
-				warnings++
+			if !strings.Contains(body, "#") {
+				add(false, "agents", path, "markdown/no-heading", "%s: no markdown heading", entry.Name())
 			}
 		}
 	}
 
 	// Validate skills
-	skillDir := filepath.Join(absPath, ".agent", "skills")
 	if entries, err := os.ReadDir(skillDir); err == nil {
-		fmt.Println("Validating skills...")
+		sections = append(sections, "skills")
 		for _, entry := range entries {
 			if !entry.IsDir() {
 				continue
 			}
+			path := filepath.ToSlash(filepath.Join(".agent", "skills", entry.Name(), "SKILL.md"))
+
+			content, err := os.ReadFile(filepath.Join(skillDir, entry.Name(), "SKILL.md"))
+			if os.IsNotExist(err) {
+				add(true, "skills", path, "skills/missing-file", "%s: missing SKILL.md", entry.Name())
+				continue
+			} else if err != nil {
+				add(true, "skills", path, "file/unreadable", "%s: cannot read SKILL.md", entry.Name())
+				continue
+			}
 
-			skillFile := filepath.Join(skillDir, entry.Name(), "SKILL.md")
-			if _, err := os.Stat(skillFile); os.IsNotExist(err) {
-				printError("  %s: missing SKILL.md", entry.Name())
-				errors++
+			fields, _, err := templates.ParseFrontmatter(string(content))
+			switch {
+			case err != nil:
+				add(true, "skills", path, "frontmatter/invalid", "%s/SKILL.md: %v", entry.Name(), err)
+			case fields == nil:
+				add(strict, "skills", path, "frontmatter/missing", "%s/SKILL.md: missing frontmatter", entry.Name())
+			default:
+				for _, key := range missingFrontmatterKeys(fields, "name", "description") {
+					add(true, "skills", path, "frontmatter/required-key",
+						"%s/SKILL.md: frontmatter is missing required key %q", entry.Name(), key)
+				}
+			}
+		}
+	}
+
+	// Validate workflows
+	workflowDir := filepath.Join(absPath, ".agent", "workflows")
+	if entries, err := os.ReadDir(workflowDir); err == nil {
+		sections = append(sections, "workflows")
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+				continue
+			}
+			path := filepath.ToSlash(filepath.Join(".agent", "workflows", entry.Name()))
+
+			content, err := os.ReadFile(filepath.Join(workflowDir, entry.Name()))
+			if err != nil {
+				add(true, "workflows", path, "file/unreadable", "%s: cannot read", entry.Name())
+				continue
+			}
+
+			fields, body, err := templates.ParseFrontmatter(string(content))
+			if err != nil {
+				add(true, "workflows", path, "frontmatter/invalid", "%s: %v", entry.Name(), err)
+				continue
+			}
+			if fields == nil {
+				add(strict, "workflows", path, "frontmatter/missing", "%s: missing frontmatter", entry.Name())
+			} else {
+				for _, key := range missingFrontmatterKeys(fields, "description") {
+					add(true, "workflows", path, "frontmatter/required-key",
+						"%s: frontmatter is missing required key %q", entry.Name(), key)
+				}
+				// the filename is the slash command, so a name: must agree
+				fileName := strings.TrimSuffix(entry.Name(), ".md")
+				if name, ok := fields["name"]; ok && strings.TrimPrefix(fmt.Sprint(name), "/") != fileName {
+					add(true, "workflows", path, "workflows/name-mismatch",
+						"%s: name %q does not match the file name", entry.Name(), fmt.Sprint(name))
+				}
+			}
+
+			if strings.TrimSpace(body) == "" {
+				add(strict, "workflows", path, "workflows/empty-body", "%s: no content after the frontmatter", entry.Name())
+			}
+		}
+	}
+
+	// Check skill requirements for loops - they'd make init expand forever
+	installed := templates.LoadFromDir(filepath.Join(absPath, ".agent"))
+	for _, cycle := range installed.FindCycles() {
+		message := cycle.Error()
+		if len(cycle.Agents) > 0 {
+			message += " (used by: " + strings.Join(cycle.Agents, ", ") + ")"
+		}
+		add(true, "cycles", ".agent/skills", "skills/cycle", "%s", message)
+	}
+
+	for _, issue := range report.Issues {
+		if issue.Severity == "error" {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	report.Valid = report.Errors == 0
+
+	var failure error
+	if !report.Valid {
+		failure = fmt.Errorf("validation failed with %d error(s)", report.Errors)
+	}
+
+	if jsonOut {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+		return failure
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Println("\n✅ AGEN Validate")
+	fmt.Printf("Directory: %s\n", absPath)
+	fmt.Printf("Strict mode: %v\n\n", strict)
+
+	for _, section := range append(sections, "cycles") {
+		if section != "cycles" {
+			fmt.Printf("Validating %s...\n", section)
+		}
+		for _, issue := range report.Issues {
+			if issue.section != section {
+				continue
+			}
+			if issue.Severity == "error" {
+				printError("  %s", issue.Message)
+			} else {
+				printWarning("  %s", issue.Message)
 			}
 		}
 	}
 
 	fmt.Println()
-	if errors == 0 && warnings == 0 {
+	if report.Errors == 0 && report.Warnings == 0 {
 		color.New(color.FgGreen, color.Bold).Println("✨ All templates valid!")
-	} else if errors == 0 {
-		color.Yellow("⚠ Valid with %d warning(s)", warnings)
+	} else if report.Errors == 0 {
+		color.Yellow("⚠ Valid with %d warning(s)", report.Warnings)
 	} else {
-		color.Red("❌ Found %d error(s), %d warning(s)", errors, warnings)
+		color.Red("❌ Found %d error(s), %d warning(s)", report.Errors, report.Warnings)
 	}
 
-	return nil
+	return failure
+}
+
+// validateFinding is one problem runValidate found
+type validateFinding struct {
+	Path     string `json:"path"`     // relative to the project
+	Severity string `json:"severity"` // error or warning
+	Message  string `json:"message"`
+	Rule     string `json:"rule"` // e.g. frontmatter/invalid
+
+	section string // agents, skills, workflows or cycles, for text output
+}
+
+// validateOutput is the validate --json document
+type validateOutput struct {
+	Directory string            `json:"directory"`
+	Strict    bool              `json:"strict"`
+	Valid     bool              `json:"valid"`
+	Errors    int               `json:"errors"`
+	Warnings  int               `json:"warnings"`
+	Issues    []validateFinding `json:"issues"`
 }
 
 // Helper functions
 
-func hashContent(content []byte) string {
-	hash := md5.Sum(content)
-	return hex.EncodeToString(hash[:])
+// missingFrontmatterKeys returns the keys that are absent or blank in fields
+func missingFrontmatterKeys(fields map[string]interface{}, keys ...string) []string {
+	var missing []string
+	for _, key := range keys {
+		value, ok := fields[key]
+		if !ok || value == nil || strings.TrimSpace(fmt.Sprint(value)) == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
 }
 
-func showDiff(old, new string) {
-	// Simple diff display
-	oldLines := strings.Split(old, "\n")
-	newLines := strings.Split(new, "\n")
+// showDiff prints a colored unified diff of an installed file against the
+// latest template, with up to context unchanged lines around each change.
+//
+// Line endings are normalized before comparing. When that, or trimming
+// trailing whitespace, makes the files equal, a note says so instead of
+// printing a diff that looks empty.
+func showDiff(old, new string, context int) {
+	dim := color.New(color.Faint)
+
+	crlf := strings.Contains(old, "\r\n") != strings.Contains(new, "\r\n")
+	hunks := templates.DiffLines(old, new, context)
+	if len(hunks) == 0 {
+		if crlf {
+			dim.Println("    only line endings differ (CRLF vs LF)")
+		}
+		return
+	}
+	if crlf {
+		dim.Println("    line endings differ (CRLF vs LF), ignored below")
+	}
+	if onlyTrailingWhitespace(old, new) {
+		dim.Println("    only trailing whitespace differs")
+		return
+	}
 
-	fmt.Printf("    --- installed (%d lines)\n", len(oldLines))
-	fmt.Printf("    +++ latest (%d lines)\n", len(newLines))
+	cyan := color.New(color.FgCyan)
+	red := color.New(color.FgRed)
+	green := color.New(color.FgGreen)
+
+	fmt.Println("    --- installed")
+	fmt.Println("    +++ latest")
+	for _, h := range hunks {
+		cyan.Printf("    @@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		for _, line := range h.Lines {
+			switch line.Op {
+			case templates.DiffDelete:
+				red.Printf("    -%s\n", line.Text)
+			case templates.DiffInsert:
+				green.Printf("    +%s\n", line.Text)
+			default:
+				fmt.Printf("     %s\n", line.Text)
+			}
+		}
+	}
+}
+
+// onlyTrailingWhitespace reports whether old and new match once trailing
+// whitespace is trimmed from every line
+func onlyTrailingWhitespace(old, new string) bool {
+	oldLines, newLines := templates.SplitLines(old), templates.SplitLines(new)
+	if len(oldLines) != len(newLines) {
+		return false
+	}
+	for i := range oldLines {
+		if strings.TrimRight(oldLines[i], " \t") != strings.TrimRight(newLines[i], " \t") {
+			return false
+		}
+	}
+	return true
 }
@@ -9,8 +9,13 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/eshanized/agen/internal/ai"
+	"github.com/eshanized/agen/internal/ide"
+	"github.com/eshanized/agen/internal/plugin"
+	"github.com/eshanized/agen/internal/templates"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -44,9 +49,15 @@ Provides:
 - Complete content/rules
 - Usage recommendations
 
+By default a template installed in the current project wins,
+so a customized agent is explained as it is. Next come
+installed plugins, then the shipped templates. Use --source
+to pick one side when they differ.
+
 Examples:
   agen explain frontend-specialist
-  agen explain clean-code`,
+  agen explain clean-code
+  agen explain frontend-specialist --source embedded`,
 	Args: cobra.ExactArgs(1),
 	RunE: runExplain,
 }
@@ -59,9 +70,14 @@ var composeCmd = &cobra.Command{
 Generates a new agent that merges skills and rules
 from multiple base agents.
 
+With --save the agent is installed into the current project
+through the detected IDE, along with any of its skills the
+project doesn't have yet.
+
 Examples:
   agen compose fullstack --from frontend-specialist,backend-specialist
-  agen compose security-dev --from security-auditor,debugger`,
+  agen compose security-dev --from security-auditor,debugger
+  agen compose fullstack --from frontend-specialist,backend-specialist --save`,
 	Args: cobra.ExactArgs(1),
 	RunE: runCompose,
 }
@@ -71,10 +87,14 @@ func init() {
 	suggestCmd.Flags().Int("top", 10, "number of suggestions to show")
 
 	explainCmd.Flags().String("type", "auto", "type (agent, skill, auto)")
+	explainCmd.Flags().String("source", "", "where to read the template: installed or embedded (default: installed, then plugins, then embedded)")
 
 	composeCmd.Flags().StringSlice("from", []string{}, "base agents to compose from")
 	composeCmd.Flags().StringP("description", "d", "", "agent description")
-	composeCmd.Flags().StringP("output", "o", "", "output file")
+	composeCmd.Flags().StringP("out-file", "o", "", "output file")
+	addOutputAlias(composeCmd, "out-file")
+	composeCmd.Flags().Bool("save", false, "install the agent into the current project's IDE setup")
+	composeCmd.Flags().BoolP("force", "f", false, "with --save, replace an installed agent of the same name")
 
 	rootCmd.AddCommand(suggestCmd)
 	rootCmd.AddCommand(explainCmd)
@@ -88,10 +108,13 @@ func runSuggest(cmd *cobra.Command, args []string) error {
 	}
 
 	top, _ := cmd.Flags().GetInt("top")
+	jsonOut := wantJSON(cmd)
 
-	cyan := color.New(color.FgCyan, color.Bold)
-	cyan.Println("\n🤖 AGEN Suggest")
-	fmt.Printf("Analyzing: %s\n\n", targetDir)
+	if !jsonOut {
+		cyan := color.New(color.FgCyan, color.Bold)
+		cyan.Println("\n🤖 AGEN Suggest")
+		fmt.Printf("Analyzing: %s\n\n", targetDir)
+	}
 
 	suggester, err := ai.NewSuggester()
 	if err != nil {
@@ -103,16 +126,23 @@ func runSuggest(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if len(suggestions) == 0 {
-		fmt.Println("No specific suggestions for this project.")
-		return nil
-	}
-
 	// Limit to top N
 	if len(suggestions) > top {
 		suggestions = suggestions[:top]
 	}
 
+	if jsonOut {
+		if suggestions == nil {
+			suggestions = []ai.Suggestion{}
+		}
+		return printJSON(suggestions)
+	}
+
+	if len(suggestions) == 0 {
+		fmt.Println("No specific suggestions for this project.")
+		return nil
+	}
+
 	fmt.Println("Recommended:")
 	fmt.Println()
 
@@ -134,8 +164,7 @@ func runSuggest(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println("Install all recommended:")
-	fmt.Printf("  agen init --agents %s\n",
-		joinNames(suggestions[:min(5, len(suggestions))]))
+	fmt.Printf("  agen init%s\n", initFlagsFor(suggestions[:min(5, len(suggestions))]))
 
 	return nil
 }
@@ -143,8 +172,9 @@ func runSuggest(cmd *cobra.Command, args []string) error {
 func runExplain(cmd *cobra.Command, args []string) error {
 	name := args[0]
 	typeHint, _ := cmd.Flags().GetString("type")
+	source, _ := cmd.Flags().GetString("source")
 
-	suggester, err := ai.NewSuggester()
+	suggester, err := explainSuggester(".", source)
 	if err != nil {
 		return err
 	}
@@ -164,6 +194,12 @@ func runExplain(cmd *cobra.Command, args []string) error {
 	}
 
 	if err != nil {
+		// errors aren't printed by the root command, so say it here
+		if similar := suggester.Similar(name, typeHint); len(similar) > 0 {
+			printError("%s not found, did you mean %s?", name, strings.Join(similar, ", "))
+		} else {
+			printError("%s not found (see agen list or agen search)", name)
+		}
 		return fmt.Errorf("not found: %s", name)
 	}
 
@@ -171,11 +207,77 @@ func runExplain(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// explainSuggester returns a suggester over the templates agen explain
+// should read, picked by --source.
+//
+// How it works:
+//  1. "embedded" is the shipped set, as last fetched by agen update
+//  2. "installed" is what the detected IDE installed in dir, and fails
+//     without an install
+//  3. The default layers them: installed first, then plugin templates,
+//     then the shipped set, so the first copy of a name found wins
+func explainSuggester(dir, source string) (*ai.Suggester, error) {
+	switch source {
+	case "", "installed", "embedded":
+	default:
+		return nil, fmt.Errorf("invalid --source %q (valid: installed, embedded)", source)
+	}
+	if source == "embedded" {
+		return ai.NewSuggester()
+	}
+
+	absPath, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	adapter := ide.Detect(absPath)
+	if adapter == nil {
+		if source == "installed" {
+			return nil, fmt.Errorf("no AGEN installation found in %s (use --source embedded)", absPath)
+		}
+		tmpl := &templates.Templates{
+			Agents:    make(map[string]templates.Agent),
+			Skills:    make(map[string]templates.Skill),
+			Workflows: make(map[string]templates.Workflow),
+		}
+		return layerExplainTemplates(tmpl)
+	}
+
+	tmpl, err := installedTemplates(absPath, adapter)
+	if err != nil {
+		return nil, err
+	}
+	if source == "installed" {
+		return ai.NewSuggesterFrom(tmpl), nil
+	}
+	return layerExplainTemplates(tmpl)
+}
+
+// layerExplainTemplates adds plugin templates and then the shipped ones
+// under tmpl. Name clashes are expected here (that's the point of
+// layering), so they aren't reported.
+func layerExplainTemplates(tmpl *templates.Templates) (*ai.Suggester, error) {
+	if mgr, err := plugin.NewManager(); err == nil {
+		plugins, _ := mgr.LoadPlugins()
+		tmpl.Merge(plugins)
+	}
+
+	shipped, _, err := loadOfflineTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load templates: %w", err)
+	}
+	tmpl.Merge(shipped)
+	return ai.NewSuggesterFrom(tmpl), nil
+}
+
 func runCompose(cmd *cobra.Command, args []string) error {
 	name := args[0]
 	baseAgents, _ := cmd.Flags().GetStringSlice("from")
 	description, _ := cmd.Flags().GetString("description")
-	output, _ := cmd.Flags().GetString("output")
+	output, _ := cmd.Flags().GetString("out-file")
+	save, _ := cmd.Flags().GetBool("save")
+	force, _ := cmd.Flags().GetBool("force")
 
 	if len(baseAgents) == 0 {
 		return fmt.Errorf("--from flag required (e.g., --from agent1,agent2)")
@@ -200,6 +302,10 @@ func runCompose(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if save {
+		return saveAgent(".", composed.Name, composed.Content, composed.Skills, force)
+	}
+
 	if output != "" {
 		if err := os.WriteFile(output, []byte(composed.Content), 0644); err != nil {
 			return err
@@ -218,14 +324,105 @@ func runCompose(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// saveAgent installs a new agent (name, with markdown content) into the
+// project at dir through the detected IDE adapter. It's how compose --save
+// and create write their agents.
+//
+// How it works:
+//  1. Read what the adapter already installed (see installedTemplates)
+//  2. Add the agent and any of its skills that are missing
+//  3. Run the adapter's Update over the whole set. The .agent/ tree only
+//     gains the new files; single-file formats are rewritten with the
+//     installed templates plus the new agent, so nothing else drops out
+func saveAgent(dir, name, content string, skills []string, force bool) error {
+	absPath, adapter, tmpl, err := projectAgents(dir)
+	if err != nil {
+		return err
+	}
+	if _, exists := tmpl.Agents[name]; exists && !force {
+		return fmt.Errorf("agent %s is already installed (use --force to replace it)", name)
+	}
+
+	agent, err := templates.ParseAgent(name, content)
+	if err != nil {
+		return fmt.Errorf("agent %s is invalid: %w", name, err)
+	}
+	tmpl.Agents[name] = agent
+
+	available, _, err := loadOfflineTemplates()
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+	var addedSkills []string
+	for _, skillName := range skills {
+		if _, ok := tmpl.Skills[skillName]; ok {
+			continue
+		}
+		if skill, ok := available.Skills[skillName]; ok {
+			tmpl.Skills[skillName] = skill
+			addedSkills = append(addedSkills, skillName)
+		}
+	}
+
+	// the rest of the set is what's installed, so forcing only replaces
+	// the new agent (or re-renders a single-file format)
+	changes, err := adapter.Update(tmpl, ide.UpdateOptions{TargetDir: absPath, Force: true})
+	if err != nil {
+		return fmt.Errorf("failed to save agent: %w", err)
+	}
+
+	printSuccess("Saved %s for %s", name, adapter.Name())
+	for _, f := range append(changes.Added, changes.Updated...) {
+		fmt.Printf("  %s\n", f)
+	}
+	if len(addedSkills) > 0 {
+		printInfo("Added skills: %s", strings.Join(addedSkills, ", "))
+	}
+	return nil
+}
+
+// projectAgents detects the IDE set up in dir and reads the templates
+// installed there
+func projectAgents(dir string) (string, ide.Adapter, *templates.Templates, error) {
+	absPath, err := filepath.Abs(dir)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	adapter := ide.Detect(absPath)
+	if adapter == nil {
+		return "", nil, nil, fmt.Errorf("no AGEN installation found in %s. Run 'agen init' first, or write the agent to a file instead", absPath)
+	}
+
+	tmpl, err := installedTemplates(absPath, adapter)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return absPath, adapter, tmpl, nil
+}
+
 // Helper functions
 
-func joinNames(suggestions []ai.Suggestion) string {
-	names := make([]string, len(suggestions))
-	for i, s := range suggestions {
-		names[i] = s.Name
+// initFlagsFor builds the --agents/--skills flags that install suggestions
+func initFlagsFor(suggestions []ai.Suggestion) string {
+	var agents, skills []string
+	for _, s := range suggestions {
+		switch s.Type {
+		case "agent":
+			agents = append(agents, s.Name)
+		case "skill":
+			skills = append(skills, s.Name)
+		}
+	}
+
+	flags := ""
+	if len(agents) > 0 {
+		flags += " --agents " + joinStrings(agents)
+	}
+	if len(skills) > 0 {
+		flags += " --skills " + joinStrings(skills)
 	}
-	return joinStrings(names)
+	return flags
 }
 
 func joinStrings(s []string) string {
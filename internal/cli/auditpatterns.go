@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Suspicious-pattern rules for agen audit
+
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/eshanized/agen/internal/config"
+	"github.com/eshanized/agen/internal/verify"
+)
+
+// defaultAuditPatterns are checked unless --no-defaults is given
+var defaultAuditPatterns = []config.AuditPattern{
+	{Pattern: "rm -rf", Severity: "warning"},
+	{Pattern: "sudo", Severity: "warning"},
+	{Pattern: "curl | bash", Severity: "critical"},
+	{Pattern: "wget | bash", Severity: "critical"},
+	{Pattern: "eval(", Severity: "warning"},
+}
+
+// auditRule is a compiled audit pattern. Plain substrings are compiled
+// too (quoted), so every rule matches the same way.
+type auditRule struct {
+	pattern  string
+	severity string
+	message  string
+	re       *regexp.Regexp
+}
+
+// compileAuditRules turns patterns into rules, checking regexes and
+// severities up front so a typo in .agen-audit.yml fails loudly instead of
+// silently matching nothing
+func compileAuditRules(patterns []config.AuditPattern) ([]auditRule, error) {
+	rules := make([]auditRule, 0, len(patterns))
+	for _, p := range patterns {
+		severity := p.Severity
+		if severity == "" {
+			severity = "warning"
+		}
+		if !verify.ValidSeverity(severity) {
+			return nil, fmt.Errorf("invalid severity %q for audit pattern %q (valid: %s)",
+				p.Severity, p.Pattern, strings.Join(verify.Severities, ", "))
+		}
+
+		expr := regexp.QuoteMeta(p.Pattern)
+		if p.Regex {
+			expr = p.Pattern
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit pattern %q: %w", p.Pattern, err)
+		}
+
+		rules = append(rules, auditRule{
+			pattern:  p.Pattern,
+			severity: severity,
+			message:  p.Message,
+			re:       re,
+		})
+	}
+	return rules, nil
+}
+
+// loadAuditRules combines the built-in patterns (unless noDefaults) with
+// the project's .agen-audit.yml
+func loadAuditRules(projectDir string, noDefaults bool) ([]auditRule, error) {
+	var patterns []config.AuditPattern
+	if !noDefaults {
+		patterns = append(patterns, defaultAuditPatterns...)
+	}
+
+	project, err := config.LoadAuditConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if project != nil {
+		patterns = append(patterns, project.Patterns...)
+	}
+
+	return compileAuditRules(patterns)
+}
+
+// describe builds the finding message for a match in relPath
+func (r auditRule) describe(match, relPath string) string {
+	if r.message != "" {
+		return fmt.Sprintf("%s: found '%s' in %s", r.message, match, relPath)
+	}
+	return fmt.Sprintf("Found '%s' in %s", match, relPath)
+}
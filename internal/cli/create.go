@@ -9,12 +9,15 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
-	"github.com/eshanized/agen/internal/templates"
+	"github.com/eshanized/agen/internal/config"
 	"github.com/eshanized/agen/internal/tui"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var createCmd = &cobra.Command{
@@ -27,30 +30,66 @@ Collects:
 - Role Description
 - Required Skills
 
-Generates a standard Markdown file with frontmatter headers.
+Generates a Markdown agent with frontmatter headers and a body skeleton,
+and installs it into the current project through the detected IDE, along
+with any of its skills that aren't installed yet. With --out-dir the file
+is written to that directory instead.
+
+The name must not be taken by an installed agent (or a file in --out-dir);
+--force replaces it.
 
 Examples:
   agen create
-  agen create --output ./my-agents/`,
+  agen create --out-dir ./my-agents/`,
 	RunE: runCreate,
 }
 
 func init() {
-	createCmd.Flags().StringP("output", "o", ".", "Directory to save the agent file")
+	createCmd.Flags().StringP("out-dir", "o", "", "write the agent file to this directory instead of installing it")
+	addOutputAlias(createCmd, "out-dir")
+	createCmd.Flags().BoolP("force", "f", false, "replace an existing agent of the same name")
 	rootCmd.AddCommand(createCmd)
 }
 
 func runCreate(cmd *cobra.Command, args []string) error {
-	outputDir, _ := cmd.Flags().GetString("output")
+	outputDir, _ := cmd.Flags().GetString("out-dir")
+	force, _ := cmd.Flags().GetBool("force")
 
 	// 1. Load templates (needed for skill list)
-	tmpl, err := templates.LoadEmbedded()
+	tmpl, _, err := loadOfflineTemplates()
 	if err != nil {
 		return fmt.Errorf("failed to load templates: %w", err)
 	}
 
-	// 2. Run TUI Wizard
-	result, err := tui.RunCreator(tmpl)
+	// 2. Work out what the name must not collide with, so the wizard can
+	// catch it on the first step
+	taken := func(name string) bool {
+		_, err := os.Stat(filepath.Join(outputDir, name+".md"))
+		return err == nil
+	}
+	if outputDir == "" {
+		_, _, installed, err := projectAgents(".")
+		if err != nil {
+			printError("%v", err)
+			return err
+		}
+		taken = func(name string) bool {
+			_, ok := installed.Agents[name]
+			return ok
+		}
+	}
+	validateName := func(name string) error {
+		if err := config.ValidateName("agent", name); err != nil {
+			return err
+		}
+		if taken(name) && !force {
+			return fmt.Errorf("agent %s already exists (use --force to replace it)", name)
+		}
+		return nil
+	}
+
+	// 3. Run TUI Wizard
+	result, err := tui.RunCreator(tmpl, validateName)
 	if err != nil {
 		return fmt.Errorf("wizard failed: %w", err)
 	}
@@ -59,43 +98,70 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		color.Yellow("Operation cancelled.")
 		return nil
 	}
-
-	// 3. Generate Content
-	var sb strings.Builder
-	sb.WriteString("---\n")
-	sb.WriteString(fmt.Sprintf("name: %s\n", result.Name))
-	sb.WriteString(fmt.Sprintf("description: %s\n", result.Description))
-
-	if len(result.Skills) > 0 {
-		sb.WriteString("skills: " + strings.Join(result.Skills, ", ") + "\n")
+	if err := validateName(result.Name); err != nil {
+		printError("%v", err)
+		return err
 	}
-	sb.WriteString("---\n\n")
 
-	sb.WriteString(fmt.Sprintf("# %s\n\n", result.Name))
-	sb.WriteString(fmt.Sprintf("> %s\n\n", result.Description))
-	sb.WriteString("## Core Responsibilities\n\n")
-	sb.WriteString("- [ ] responsibility 1\n")
-	sb.WriteString("- [ ] responsibility 2\n\n")
-	sb.WriteString("## Guidelines\n\n")
-	sb.WriteString("1. First guideline\n")
-	sb.WriteString("2. Second guideline\n")
+	// 4. Generate Content
+	content, err := agentMarkdown(result)
+	if err != nil {
+		return err
+	}
 
-	// 4. Save to file
-	filename := strings.ToLower(strings.ReplaceAll(result.Name, " ", "-")) + ".md"
-	if !strings.HasSuffix(filename, ".md") {
-		filename += ".md"
+	// 5. Install, or save to a file
+	if outputDir == "" {
+		if err := saveAgent(".", result.Name, content, result.Skills, force); err != nil {
+			printError("%v", err)
+			return err
+		}
+		return nil
 	}
 
-	// Ensure output dir exists
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
-
-	path := fmt.Sprintf("%s/%s", strings.TrimRight(outputDir, "/"), filename)
-	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+	path := filepath.Join(outputDir, result.Name+".md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to save file: %w", err)
 	}
 
 	printSuccess("Created agent: %s", path)
 	return nil
 }
+
+// createdFrontmatter is the frontmatter of an agent made by agen create
+type createdFrontmatter struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Skills      string `yaml:"skills,omitempty"` // comma-separated, like the embedded agents
+}
+
+// agentMarkdown renders the creator's answers as an agent: frontmatter
+// plus a skeleton body to fill in
+func agentMarkdown(result tui.CreatorResult) (string, error) {
+	sort.Strings(result.Skills)
+	frontmatter, err := yaml.Marshal(createdFrontmatter{
+		Name:        result.Name,
+		Description: result.Description,
+		Skills:      strings.Join(result.Skills, ", "),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to write frontmatter: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.Write(frontmatter)
+	sb.WriteString("---\n\n")
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", result.Name))
+	sb.WriteString(fmt.Sprintf("> %s\n\n", result.Description))
+	sb.WriteString("## Core Responsibilities\n\n")
+	sb.WriteString("- [ ] responsibility 1\n")
+	sb.WriteString("- [ ] responsibility 2\n\n")
+	sb.WriteString("## Guidelines\n\n")
+	sb.WriteString("1. First guideline\n")
+	sb.WriteString("2. Second guideline\n")
+	return sb.String(), nil
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for the agent markdown written by agen create
+
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eshanized/agen/internal/templates"
+	"github.com/eshanized/agen/internal/tui"
+)
+
+func TestAgentMarkdown(t *testing.T) {
+	content, err := agentMarkdown(tui.CreatorResult{
+		Name:        "api-reviewer",
+		Description: "Reviews APIs: naming, versioning # and errors",
+		Skills:      []string{"testing-patterns", "clean-code"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agent, err := templates.ParseAgent("api-reviewer", content)
+	if err != nil {
+		t.Fatalf("ParseAgent() error = %v\n%s", err, content)
+	}
+	if agent.Description != "Reviews APIs: naming, versioning # and errors" {
+		t.Errorf("Description = %q", agent.Description)
+	}
+	if want := []string{"clean-code", "testing-patterns"}; !reflect.DeepEqual(agent.Skills, want) {
+		t.Errorf("Skills = %v, want %v", agent.Skills, want)
+	}
+	if !strings.Contains(content, "## Core Responsibilities") {
+		t.Errorf("body skeleton missing:\n%s", content)
+	}
+}
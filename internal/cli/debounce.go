@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Coalescing filesystem events for agen watch
+
+package cli
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// eventDebouncer coalesces bursts of fsnotify events per path.
+//
+// Editors often save with write-temp-then-rename, which shows up as three
+// or four events for one save. Events for a path are merged (ops OR'ed
+// together) until the path has been quiet for the window, then released
+// as one event. Removes are released straight away so a deletion is never
+// held back or lost behind a later event.
+//
+// Not safe for concurrent use; runWatch drives it from its select loop.
+type eventDebouncer struct {
+	window  time.Duration
+	pending map[string]*pendingEvent
+	timer   *time.Timer
+}
+
+// pendingEvent is the merged state of one path waiting to be released
+type pendingEvent struct {
+	op   fsnotify.Op
+	last time.Time
+}
+
+func newEventDebouncer(window time.Duration) *eventDebouncer {
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	return &eventDebouncer{
+		window:  window,
+		pending: make(map[string]*pendingEvent),
+		timer:   timer,
+	}
+}
+
+// C fires when some pending path may have gone quiet; call Due then
+func (d *eventDebouncer) C() <-chan time.Time {
+	return d.timer.C
+}
+
+// Add records an event and returns any events to act on right away:
+// the path itself for a Remove (merged with whatever was pending for it),
+// or everything when there's no debounce window.
+func (d *eventDebouncer) Add(event fsnotify.Event, now time.Time) []fsnotify.Event {
+	p, ok := d.pending[event.Name]
+	if !ok {
+		p = &pendingEvent{}
+		d.pending[event.Name] = p
+	}
+	p.op |= event.Op
+	p.last = now
+
+	if event.Op.Has(fsnotify.Remove) || d.window <= 0 {
+		delete(d.pending, event.Name)
+		return []fsnotify.Event{{Name: event.Name, Op: p.op}}
+	}
+
+	if !ok && len(d.pending) == 1 {
+		d.timer.Reset(d.window)
+	}
+	return nil
+}
+
+// Due releases the paths that have been quiet for the whole window, sorted
+// by path, and re-arms the timer for the rest
+func (d *eventDebouncer) Due(now time.Time) []fsnotify.Event {
+	var due []fsnotify.Event
+	next := time.Duration(-1)
+
+	for name, p := range d.pending {
+		wait := d.window - now.Sub(p.last)
+		if wait <= 0 {
+			due = append(due, fsnotify.Event{Name: name, Op: p.op})
+			delete(d.pending, name)
+		} else if next < 0 || wait < next {
+			next = wait
+		}
+	}
+	if next >= 0 {
+		d.timer.Reset(next)
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].Name < due[j].Name })
+	return due
+}
+
+// Stop releases the timer
+func (d *eventDebouncer) Stop() {
+	d.timer.Stop()
+}
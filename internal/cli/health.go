@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/eshanized/agen/internal/config"
 	"github.com/eshanized/agen/internal/ide"
 	"github.com/eshanized/agen/internal/templates"
 	"github.com/fatih/color"
@@ -66,6 +67,10 @@ func runHealth(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("directory does not exist: %s", absPath)
 	}
 
+	if wantJSON(cmd) {
+		return printJSON(buildHealthOutput(absPath))
+	}
+
 	cyan := color.New(color.FgCyan, color.Bold)
 	cyan.Println("\n📊 AGEN Health Dashboard")
 	fmt.Printf("Directory: %s\n\n", absPath)
@@ -83,7 +88,7 @@ func runHealth(cmd *cobra.Command, args []string) error {
 
 	// Step 2: Get installed info
 	installed, err := ide.GetInstalledInfo(absPath, ideAdapter)
-	if err != nil && verbose {
+	if err != nil {
 		printWarning("Could not read installed info: %v", err)
 	}
 
@@ -114,6 +119,11 @@ func runHealth(cmd *cobra.Command, args []string) error {
 	// Step 5: Agent recommendations based on project type
 	fmt.Println("\n🎯 Agent Recommendations:")
 	recommendations := getRecommendedAgents(projectType)
+	if cfg, err := config.LoadForProject(absPath); err == nil {
+		recommendations = mergeConfiguredAgents(recommendations, cfg.DefaultAgents)
+	} else if verbose {
+		printWarning("Could not load config: %v", err)
+	}
 
 	for _, rec := range recommendations {
 		// Check if agent is installed
@@ -162,6 +172,64 @@ func runHealth(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// healthRecommendation is one recommended agent in health --output json
+type healthRecommendation struct {
+	Name      string `json:"name"`
+	Critical  bool   `json:"critical"`
+	Reason    string `json:"reason,omitempty"`
+	Installed bool   `json:"installed"`
+}
+
+// healthOutput is the health --output json document
+type healthOutput struct {
+	Directory       string                 `json:"directory"`
+	IDE             string                 `json:"ide,omitempty"`
+	ProjectType     string                 `json:"project_type"`
+	Version         string                 `json:"version,omitempty"`
+	LatestVersion   string                 `json:"latest_version"`
+	UpToDate        bool                   `json:"up_to_date"`
+	ModifiedFiles   int                    `json:"modified_files"`
+	Recommendations []healthRecommendation `json:"recommendations"`
+	Score           int                    `json:"score"`
+}
+
+// buildHealthOutput computes the same dashboard runHealth prints
+func buildHealthOutput(absPath string) healthOutput {
+	out := healthOutput{
+		Directory:       absPath,
+		ProjectType:     analyzeProjectType(absPath),
+		LatestVersion:   templates.GetLatestVersion(),
+		Recommendations: []healthRecommendation{},
+	}
+
+	var installed *ide.InstalledInfo
+	if ideAdapter := ide.Detect(absPath); ideAdapter != nil {
+		out.IDE = ideAdapter.Name()
+		installed, _ = ide.GetInstalledInfo(absPath, ideAdapter)
+	}
+	if installed != nil {
+		out.Version = installed.Version
+		out.UpToDate = !latestOutdated(installed)
+		out.ModifiedFiles = installed.ModifiedFiles
+	}
+
+	recommendations := getRecommendedAgents(out.ProjectType)
+	if cfg, err := config.LoadForProject(absPath); err == nil {
+		recommendations = mergeConfiguredAgents(recommendations, cfg.DefaultAgents)
+	}
+	for _, rec := range recommendations {
+		out.Recommendations = append(out.Recommendations, healthRecommendation{
+			Name:      rec.Name,
+			Critical:  rec.Critical,
+			Reason:    rec.Reason,
+			Installed: installed != nil && hasAgent(installed, rec.Name),
+		})
+	}
+	out.Score = calculateHealthScore(installed, recommendations)
+
+	return out
+}
+
 // AgentRecommendation represents a recommended agent for a project type
 type AgentRecommendation struct {
 	Name     string
@@ -239,6 +307,26 @@ func getRecommendedAgents(projectType string) []AgentRecommendation {
 	}
 }
 
+// mergeConfiguredAgents marks the project's default agents as critical
+// recommendations, adding any the project type didn't already suggest.
+func mergeConfiguredAgents(recs []AgentRecommendation, agents []string) []AgentRecommendation {
+	for _, name := range agents {
+		found := false
+		for i := range recs {
+			if recs[i].Name == name {
+				recs[i].Critical = true
+				recs[i].Reason = "Listed in project config"
+				found = true
+				break
+			}
+		}
+		if !found {
+			recs = append(recs, AgentRecommendation{Name: name, Critical: true, Reason: "Listed in project config"})
+		}
+	}
+	return recs
+}
+
 func hasAgent(info *ide.InstalledInfo, name string) bool {
 	for _, a := range info.Agents {
 		if a == name {
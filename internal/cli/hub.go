@@ -0,0 +1,493 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Hub commands for finding and sharing plugins
+
+package cli
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eshanized/agen/internal/config"
+	"github.com/eshanized/agen/internal/hub"
+	"github.com/eshanized/agen/internal/plugin"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// hubTimeout bounds a single request to a hub
+const hubTimeout = 30 * time.Second
+
+// hubTokenEnv names the environment variable hub publish reads its
+// token from
+const hubTokenEnv = "AGEN_HUB_TOKEN"
+
+// hubCmd groups the hub subcommands
+var hubCmd = &cobra.Command{
+	Use:   "hub",
+	Short: "Find and share plugins on a template hub",
+	Long: `Find and share plugins on a template hub.
+
+The hub is hub_url in the config. When that isn't set, the remotes added
+with --type http are used as hubs instead.
+
+Examples:
+  agen hub search react
+  agen hub install acme/react-native
+  agen hub publish ./my-plugin`,
+}
+
+var hubSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the hub for plugins",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runHubSearch,
+}
+
+var hubInstallCmd = &cobra.Command{
+	Use:   "install <author/name>",
+	Short: "Install a plugin from the hub",
+	Long: `Install a plugin from the hub.
+
+The hub says where the plugin is downloaded from, and the plugin manager
+installs it from there exactly like 'agen plugin install' would. When the
+hub publishes the archive's sha256, the download is checked against it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHubInstall,
+}
+
+var hubPublishCmd = &cobra.Command{
+	Use:   "publish [dir]",
+	Short: "Package a plugin and upload it to the hub",
+	Long: `Package a plugin and upload it to the hub.
+
+The plugin directory (default: the current one) must have a plugin.json
+(or plugin.yaml) with name, version and author set. It is zipped, hashed
+with sha256 and uploaded with the token in $AGEN_HUB_TOKEN.
+
+With --dry-run, or when no token is set, nothing is uploaded: the archive
+and its .sha256 are written to the current directory (or --out-dir) and the
+command that would upload them is printed.
+
+Examples:
+  agen hub publish ./my-plugin
+  agen hub publish --dry-run
+  agen hub publish --hub community`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHubPublish,
+}
+
+func init() {
+	hubSearchCmd.Flags().Bool("json", false, "output as JSON")
+	hubInstallCmd.Flags().Duration("timeout", plugin.DefaultDownloadTimeout, "time limit for each download (at most 2m)")
+
+	hubCmd.AddCommand(hubSearchCmd)
+	hubPublishCmd.Flags().Bool("dry-run", false, "package the plugin without uploading it")
+	hubPublishCmd.Flags().String("out-dir", ".", "where to write the archive when not uploading")
+	hubPublishCmd.Flags().String("hub", "", "hub to publish to, when several remotes are hubs")
+
+	hubCmd.AddCommand(hubInstallCmd)
+	hubCmd.AddCommand(hubPublishCmd)
+	rootCmd.AddCommand(hubCmd)
+}
+
+// hubSource is a hub that hub commands query
+type hubSource struct {
+	Name string // "hub" for hub_url, otherwise the remote's name
+	URL  string
+}
+
+// hubSources returns the hubs to use: hub_url from the config, or the
+// http remotes when it isn't set. Git remotes hold templates, not a
+// package index, so they're never searched.
+func hubSources() ([]hubSource, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.HubURL != "" {
+		return []hubSource{{Name: "hub", URL: cfg.HubURL}}, nil
+	}
+
+	remotes, err := loadRemotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remotes: %w", err)
+	}
+	var sources []hubSource
+	for _, r := range remotes {
+		if r.Type == "http" {
+			sources = append(sources, hubSource{Name: r.Name, URL: r.URL})
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no hub configured: set hub_url in the config, or add one with 'agen remote add <name> <url> --type http'")
+	}
+	return sources, nil
+}
+
+// hubSearchResult is what one hub returned for hub search
+type hubSearchResult struct {
+	Hub      string        `json:"hub"`
+	URL      string        `json:"url"`
+	Packages []hub.Package `json:"packages"`
+	Error    string        `json:"error,omitempty"` // the hub couldn't be searched
+}
+
+// runHubSearch queries every hub source. A hub that can't be reached is
+// reported and skipped; the command only fails when none could be
+// searched, so "no matches" and "no answer" never look the same.
+func runHubSearch(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+
+	sources, err := hubSources()
+	if err != nil {
+		printError("%v", err)
+		return err
+	}
+
+	var results []hubSearchResult
+	failed, found := 0, 0
+	for _, src := range sources {
+		ctx, cancel := context.WithTimeout(context.Background(), hubTimeout)
+		packages, err := hub.New(src.URL).Search(ctx, query)
+		cancel()
+
+		result := hubSearchResult{Hub: src.Name, URL: src.URL, Packages: packages}
+		if err != nil {
+			result.Error = err.Error()
+			result.Packages = []hub.Package{}
+			failed++
+		}
+		found += len(result.Packages)
+		results = append(results, result)
+	}
+
+	if wantJSON(cmd) {
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		printHubSearch(query, results, found)
+	}
+
+	if failed == len(sources) {
+		return fmt.Errorf("could not search any hub")
+	}
+	return nil
+}
+
+// printHubSearch prints hub search results grouped by hub
+func printHubSearch(query string, results []hubSearchResult, found int) {
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("\n🔍 Hub search: %q\n\n", query)
+
+	for _, r := range results {
+		if r.Error != "" {
+			printError("Could not search %s (%s): %s", r.Hub, r.URL, r.Error)
+			continue
+		}
+		if len(results) > 1 && len(r.Packages) > 0 {
+			fmt.Printf("From %s (%s):\n", r.Hub, r.URL)
+		}
+		for _, p := range r.Packages {
+			name := p.ID()
+			if p.Version != "" {
+				name += " v" + p.Version
+			}
+			fmt.Printf("  %s  ⬇ %d\n", color.GreenString(name), p.Downloads)
+			if p.Description != "" {
+				fmt.Printf("    %s\n", p.Description)
+			}
+		}
+		if len(results) > 1 && len(r.Packages) > 0 {
+			fmt.Println()
+		}
+	}
+
+	if found == 0 {
+		printInfo("No packages match %q", query)
+		return
+	}
+	fmt.Printf("\n%d package(s). Install one with: agen hub install <author/name>\n", found)
+}
+
+// runHubInstall resolves author/name on the hubs and hands the source to
+// the plugin manager
+func runHubInstall(cmd *cobra.Command, args []string) error {
+	author, name, ok := strings.Cut(args[0], "/")
+	if !ok || author == "" || name == "" || strings.Contains(name, "/") {
+		err := fmt.Errorf("invalid package %q: want author/name", args[0])
+		printError("%v", err)
+		return err
+	}
+
+	sources, err := hubSources()
+	if err != nil {
+		printError("%v", err)
+		return err
+	}
+
+	pkg, src, err := lookupPackage(sources, author, name)
+	if err != nil {
+		printError("%v", err)
+		return err
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Println("\n📦 Installing from hub")
+	fmt.Printf("Package: %s (%s)\n", pkg.ID(), src.Name)
+	fmt.Printf("Source: %s\n\n", pkg.Source)
+
+	manager, err := newDownloadingManager(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to initialize plugin manager: %w", err)
+	}
+	p, err := manager.Install(pkg.InstallSource())
+	if err != nil {
+		err = fmt.Errorf("installation failed: %w", err)
+		printError("%v", err)
+		return err
+	}
+
+	printInstalledPlugin(p)
+	return nil
+}
+
+// lookupPackage asks each hub in turn for author/name and returns the
+// first answer. Hubs that don't have it are skipped quietly; if none has
+// it, the errors from hubs that couldn't be asked are reported instead of
+// a plain "not found".
+func lookupPackage(sources []hubSource, author, name string) (*hub.Package, hubSource, error) {
+	var failures []string
+	for _, src := range sources {
+		ctx, cancel := context.WithTimeout(context.Background(), hubTimeout)
+		pkg, err := hub.New(src.URL).Lookup(ctx, author, name)
+		cancel()
+
+		switch {
+		case err == nil:
+			return pkg, src, nil
+		case !errors.Is(err, hub.ErrNotFound):
+			failures = append(failures, fmt.Sprintf("%s: %v", src.Name, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return nil, hubSource{}, fmt.Errorf("could not look up %s/%s (%s)", author, name, strings.Join(failures, "; "))
+	}
+	return nil, hubSource{}, fmt.Errorf("%s/%s is not on any hub", author, name)
+}
+
+// runHubPublish validates and packages a plugin, then uploads it, or
+// leaves the archive on disk when it can't or shouldn't upload
+func runHubPublish(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	outDir, _ := cmd.Flags().GetString("out-dir")
+	hubName, _ := cmd.Flags().GetString("hub")
+
+	meta, err := loadPublishMetadata(dir)
+	if err != nil {
+		printError("%v", err)
+		return err
+	}
+
+	archive, err := packPlugin(dir, meta.Name)
+	if err != nil {
+		err = fmt.Errorf("failed to package %s: %w", dir, err)
+		printError("%v", err)
+		return err
+	}
+	digest := sha256.Sum256(archive)
+	sum := hex.EncodeToString(digest[:])
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Println("\n📤 Publishing to hub")
+	fmt.Printf("Package: %s/%s v%s\n", meta.Author, meta.Name, meta.Version)
+	fmt.Printf("Archive: %d bytes, sha256 %s\n\n", len(archive), sum)
+
+	// A dry run is useful before any hub is configured, so only an
+	// actual upload needs one
+	src, srcErr := publishTarget(hubName)
+	token := os.Getenv(hubTokenEnv)
+
+	if dryRun || token == "" || srcErr != nil {
+		if srcErr != nil && !dryRun && token != "" {
+			printError("%v", srcErr)
+			return srcErr
+		}
+		path, err := writePublishArchive(outDir, meta, archive, sum)
+		if err != nil {
+			printError("%v", err)
+			return err
+		}
+		printSuccess("Wrote %s", path)
+
+		switch {
+		case dryRun:
+			printInfo("Dry run: nothing was uploaded")
+		default:
+			printWarning("$%s is not set, so nothing was uploaded", hubTokenEnv)
+		}
+		if srcErr != nil {
+			printInfo("%v", srcErr)
+			return nil
+		}
+		fmt.Println("\nUpload it with:")
+		fmt.Printf("  curl -X POST -H \"Authorization: Bearer $%s\" -H \"Content-Type: application/zip\" \\\n", hubTokenEnv)
+		fmt.Printf("    -H \"X-Agen-Sha256: %s\" --data-binary @%s %s%s\n", sum, path, strings.TrimRight(src.URL, "/"), hub.PublishPath)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hubTimeout)
+	defer cancel()
+	pkg, err := hub.New(src.URL).Publish(ctx, token, archive, sum)
+	if err != nil {
+		err = fmt.Errorf("failed to publish to %s: %w", src.Name, err)
+		printError("%v", err)
+		return err
+	}
+
+	printSuccess("Published %s v%s to %s", pkg.ID(), pkg.Version, src.Name)
+	fmt.Printf("\nInstall it with: agen hub install %s/%s\n", meta.Author, meta.Name)
+	return nil
+}
+
+// loadPublishMetadata reads a plugin's metadata file and checks the
+// fields a hub listing needs. Unlike installing, publishing never infers
+// metadata from the directory: the hub needs an author to file it under.
+func loadPublishMetadata(dir string) (*plugin.Plugin, error) {
+	path := config.FindFile(filepath.Join(dir, "plugin"))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s has no plugin.json", dir)
+		}
+		return nil, err
+	}
+
+	var meta plugin.Plugin
+	if err := config.Unmarshal(path, data, &meta); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Base(path), err)
+	}
+
+	var missing []string
+	for _, f := range []struct{ field, value string }{
+		{"name", meta.Name}, {"version", meta.Version}, {"author", meta.Author},
+	} {
+		if strings.TrimSpace(f.value) == "" {
+			missing = append(missing, f.field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s is missing %s", filepath.Base(path), strings.Join(missing, ", "))
+	}
+	if err := config.ValidateName("plugin", meta.Name); err != nil {
+		return nil, err
+	}
+	if err := config.ValidateName("author", meta.Author); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// packPlugin zips dir with every file under a top-level <name>/ folder,
+// the layout the plugin manager unpacks. Hidden files (.git and the like)
+// and earlier publish archives are left out, and entries carry a fixed
+// time so the same files always give the same checksum.
+func packPlugin(dir, name string) ([]byte, error) {
+	modified := time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasSuffix(path, ".zip") || strings.HasSuffix(path, ".zip.sha256") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return writeZipFile(zw, name+"/"+filepath.ToSlash(rel), modified, content)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writePublishArchive saves archive as <name>-<version>.zip in dir, with a
+// sha256sum-style <archive>.sha256 beside it so the archive can be
+// installed from a URL with its checksum checked
+func writePublishArchive(dir string, meta *plugin.Plugin, archive []byte, sum string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := meta.Name + "-" + meta.Version + ".zip"
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, archive, 0644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path+".sha256", []byte(sum+"  "+name+"\n"), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// publishTarget picks the hub to publish to: the one named by --hub, or
+// the only configured hub. Several http remotes and no --hub is an error
+// rather than a guess.
+func publishTarget(name string) (hubSource, error) {
+	sources, err := hubSources()
+	if err != nil {
+		return hubSource{}, err
+	}
+
+	if name != "" {
+		for _, src := range sources {
+			if src.Name == name {
+				return src, nil
+			}
+		}
+		return hubSource{}, fmt.Errorf("no hub named %q", name)
+	}
+	if len(sources) > 1 {
+		names := make([]string, len(sources))
+		for i, src := range sources {
+			names[i] = src.Name
+		}
+		return hubSource{}, fmt.Errorf("several hubs are configured (%s): choose one with --hub", strings.Join(names, ", "))
+	}
+	return sources[0], nil
+}
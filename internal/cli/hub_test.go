@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for packaging plugins for hub publish
+
+package cli
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadPublishMetadata(t *testing.T) {
+	tests := []struct {
+		manifest string
+		wantErr  string
+	}{
+		{`{"name": "rn-agents", "version": "1.0.0", "author": "acme"}`, ""},
+		{`{"name": "rn-agents"}`, "missing version, author"},
+		{`{"name": "../x", "version": "1.0.0", "author": "acme"}`, "invalid plugin name"},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"plugin.json": tt.manifest})
+
+		_, err := loadPublishMetadata(dir)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("loadPublishMetadata(%s) = %v", tt.manifest, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("loadPublishMetadata(%s) = %v, want %q", tt.manifest, err, tt.wantErr)
+		}
+	}
+
+	if _, err := loadPublishMetadata(t.TempDir()); err == nil {
+		t.Error("loadPublishMetadata() without plugin.json should fail")
+	}
+}
+
+func TestPackPlugin(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"plugin.json":                `{"name": "rn-agents"}`,
+		"agents/mobile.md":           "# Mobile",
+		".git/config":                "[core]",
+		"rn-agents-0.9.0.zip":        "old archive",
+		"rn-agents-0.9.0.zip.sha256": "abc",
+	})
+
+	first, err := packPlugin(dir, "rn-agents")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(first), int64(len(first)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	want := []string{"rn-agents/agents/mobile.md", "rn-agents/plugin.json"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("archive holds %v, want %v", names, want)
+	}
+
+	second, err := packPlugin(dir, "rn-agents")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("packing the same files twice gave different archives")
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/eshanized/agen/internal/config"
 	"github.com/eshanized/agen/internal/ide"
 	"github.com/eshanized/agen/internal/templates"
 	"github.com/eshanized/agen/internal/tui"
@@ -30,25 +32,36 @@ By default, this will:
 2. Install the appropriate configuration files
 3. Copy agent templates to your project
 
-If no IDE is detected, you'll be prompted to choose one.
+If no IDE is detected, you'll be prompted to choose one. When the wizard
+is skipped, the --fallback IDE is used (or default_ide from the config,
+or Antigravity if neither is set).
 
 Examples:
   agen init                           # Initialize in current directory
   agen init /path/to/project          # Initialize in specific directory
   agen init --ide cursor              # Force Cursor format
-  agen init --agents frontend,backend # Only install specific agents`,
+  agen init --ide auto --fallback cursor --no-wizard  # Detect, else Cursor
+  agen init --agents frontend,backend # Only install specific agents
+  agen init --with-plugins            # Include templates from installed plugins
+  agen init --resume                  # Pick up an interrupted wizard session`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
 
 func init() {
 	// Flags for the init command
-	initCmd.Flags().StringP("ide", "i", "", "force specific IDE (antigravity, cursor, windsurf, zed)")
+	initCmd.Flags().StringP("ide", "i", "", "force specific IDE (antigravity, cursor, windsurf, zed) or 'auto' to detect")
+	initCmd.Flags().String("fallback", "", "IDE to use when detection fails (default: config default_ide, then antigravity)")
 	initCmd.Flags().StringSliceP("agents", "a", nil, "comma-separated list of agents to install")
 	initCmd.Flags().StringSliceP("skills", "s", nil, "comma-separated list of skills to install")
 	initCmd.Flags().BoolP("force", "f", false, "overwrite existing files without prompting")
 	initCmd.Flags().Bool("dry-run", false, "show what would be done without making changes")
 	initCmd.Flags().Bool("no-wizard", false, "skip interactive wizard even if no flags provided")
+	initCmd.Flags().BoolP("quiet", "q", false, "suppress all output except errors (implies --no-wizard)")
+	initCmd.Flags().Bool("json", false, "print the install summary as JSON (implies --no-wizard)")
+	initCmd.Flags().String("output-summary", "", "also write the install summary to this file (JSON, or YAML by extension)")
+	initCmd.Flags().Bool("with-plugins", false, "also offer templates from installed plugins")
+	initCmd.Flags().Bool("resume", false, "reload the selections of this project's last wizard session")
 }
 
 // runInit is the main logic for the init command.
@@ -65,6 +78,26 @@ func init() {
 // Cursor uses .cursorrules, Windsurf uses .windsurfrules, etc.
 func runInit(cmd *cobra.Command, args []string) error {
 	verbose := checkVerbose(cmd)
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	jsonOutput := wantJSON(cmd)
+	summaryFile, _ := cmd.Flags().GetString("output-summary")
+	withPlugins, _ := cmd.Flags().GetBool("with-plugins")
+
+	// --quiet and --json keep stdout for the final summary only
+	info, printWarn := printInfo, printWarning
+	if quiet || jsonOutput {
+		info = func(string, ...interface{}) {}
+		printWarn = func(format string, args ...interface{}) {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", fmt.Sprintf(format, args...))
+		}
+	}
+
+	// warnings are kept for the summary as well as printed
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+		printWarn(format, args...)
+	}
 
 	// Step 1: determine target directory
 	targetDir := "."
@@ -83,43 +116,105 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 
 	if verbose {
-		printInfo("Target directory: %s", absPath)
+		info("Target directory: %s", absPath)
+	}
+
+	// global config with the project's .agenrc merged over it
+	cfg, err := config.LoadForProject(absPath)
+	if err != nil {
+		warn("Ignoring config: %v", err)
+		cfg = config.DefaultConfig()
 	}
 
 	// Step 2: detect or get IDE
 	ideName, _ := cmd.Flags().GetString("ide")
 	var ideAdapter ide.Adapter
 
-	if ideName != "" {
+	fallbackName, explicitFallback, err := resolveFallbackIDE(cmd, cfg)
+	if err != nil {
+		return err
+	}
+
+	if ideName != "" && ideName != "auto" {
 		// User specified IDE explicitly
 		ideAdapter = ide.GetAdapter(ideName)
 		if ideAdapter == nil {
 			return fmt.Errorf("unknown IDE: %s (supported: antigravity, cursor, windsurf, zed)", ideName)
 		}
-		printInfo("Using IDE: %s (specified via --ide)", ideAdapter.Name())
+		info("Using IDE: %s (specified via --ide)", ideAdapter.Name())
 	} else {
 		// Try to auto-detect
 		ideAdapter = ide.Detect(absPath)
 		if ideAdapter != nil {
-			printInfo("Detected IDE: %s", ideAdapter.Name())
+			info("Detected IDE: %s", ideAdapter.Name())
 		}
 	}
 
 	// Step 3: Check if we should launch interactive wizard
 	noWizard, _ := cmd.Flags().GetBool("no-wizard")
+	noWizard = noWizard || quiet || jsonOutput
 	agents, _ := cmd.Flags().GetStringSlice("agents")
 	skills, _ := cmd.Flags().GetStringSlice("skills")
 
-	// Launch wizard if: no IDE detected AND no flags provided AND not disabled
-	if ideAdapter == nil && len(agents) == 0 && len(skills) == 0 && !noWizard {
+	// fall back to the configured defaults when no selection was passed
+	if len(agents) == 0 && len(skills) == 0 {
+		agents = cfg.DefaultAgents
+		skills = cfg.DefaultSkills
+		if verbose && (len(agents) > 0 || len(skills) > 0) {
+			info("Using default agents/skills from config")
+		}
+	}
+
+	// the wizard saves its progress here, for --resume
+	checkpointPath := tui.CheckpointPath(absPath)
+	var resume *tui.Checkpoint
+	if resumeFlag, _ := cmd.Flags().GetBool("resume"); resumeFlag {
+		resume, err = tui.LoadCheckpoint(checkpointPath)
+		if err == nil && resume == nil {
+			err = fmt.Errorf("no wizard session to resume for %s", absPath)
+		} else if err != nil {
+			err = fmt.Errorf("failed to read wizard session: %w", err)
+		}
+		if err != nil {
+			printError("%v", err)
+			return err
+		}
+
+		if resume.Confirmed {
+			// the wizard was finished; only the install is left
+			if resume.IDE != "" {
+				ideAdapter = ide.GetAdapter(resume.IDE)
+			}
+			agents, skills = resume.Agents, resume.Skills
+			info("Resuming wizard selection from %s: IDE=%s, Agents=%d, Skills=%d",
+				resume.SavedAt.Local().Format("2006-01-02 15:04"), resume.IDE, len(agents), len(skills))
+			resume = nil
+		} else if noWizard {
+			err := fmt.Errorf("the saved wizard session wasn't finished; run 'agen init --resume' without --no-wizard, --quiet or --json to complete it")
+			printError("%v", err)
+			return err
+		}
+	}
+
+	// Launch wizard if: no IDE detected AND no flags provided AND not
+	// disabled, or to finish a resumed session
+	if resume != nil || (ideAdapter == nil && len(agents) == 0 && len(skills) == 0 && !noWizard) {
 		// Launch interactive wizard
 		// We need to load templates first for the wizard
 		tmpl, err := templates.LoadEmbedded()
 		if err != nil {
 			return fmt.Errorf("failed to load templates for wizard: %w", err)
 		}
+		if withPlugins {
+			if err := mergePluginTemplates(tmpl, warn); err != nil {
+				return err
+			}
+		}
 
-		result, err := tui.RunWizard(tmpl)
+		result, err := tui.RunWizard(tmpl, fallbackName, tui.WizardOptions{
+			Checkpoint: checkpointPath,
+			Resume:     resume,
+		})
 		if err != nil {
 			return fmt.Errorf("wizard failed: %w", err)
 		}
@@ -137,11 +232,15 @@ func runInit(cmd *cobra.Command, args []string) error {
 		agents = result.Agents
 		skills = result.Skills
 
-		printInfo("Selected from wizard: IDE=%s, Agents=%d, Skills=%d",
+		info("Selected from wizard: IDE=%s, Agents=%d, Skills=%d",
 			result.IDE, len(agents), len(skills))
-	} else if ideAdapter == nil && len(agents) == 0 && len(skills) == 0 {
+	} else if ideAdapter == nil && len(agents) == 0 && len(skills) == 0 && !explicitFallback {
+		if quiet || jsonOutput {
+			return fmt.Errorf("no IDE detected (use --ide or --fallback)")
+		}
+
 		// Only show warning if wizard was explicitly disabled or not applicable
-		printWarning("No IDE detected. Use --ide flag or run without --no-wizard for interactive mode.")
+		warn("No IDE detected. Use --ide flag or run without --no-wizard for interactive mode.")
 		fmt.Println("\nSupported IDEs:")
 		fmt.Println("  antigravity  - Claude Code / Antigravity (full .agent/ folder)")
 		fmt.Println("  cursor       - Cursor IDE (.cursorrules file)")
@@ -150,63 +249,268 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// If still no IDE, default to Antigravity
+	// If still no IDE, use the configured fallback
 	if ideAdapter == nil {
-		ideAdapter = ide.GetAdapter("antigravity")
-		printInfo("Defaulting to Antigravity format")
+		ideAdapter = ide.GetAdapter(fallbackName)
+		info("Defaulting to %s format", ideAdapter.Name())
 	}
 
-	// Step 4: Load templates
+	// Steps 4-6: load, filter and install
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	force, _ := cmd.Flags().GetBool("force")
 
 	if dryRun {
-		printWarning("DRY RUN: No changes will be made")
+		printWarn("DRY RUN: No changes will be made")
 	}
 
+	summary, err := applyInit(initRequest{
+		Adapter:     ideAdapter,
+		TargetDir:   absPath,
+		Agents:      agents,
+		Skills:      skills,
+		DryRun:      dryRun,
+		Force:       force,
+		Verbose:     verbose,
+		WithPlugins: withPlugins,
+		// hashing the project is only worth it when someone reads the report
+		TrackFiles: jsonOutput || summaryFile != "",
+	}, info, warn)
+	if err != nil {
+		return err
+	}
+
+	// installed, so there's nothing left to resume
+	if !dryRun {
+		tui.RemoveCheckpoint(checkpointPath)
+	}
+
+	// Step 7: Report what actually got installed (after filtering)
+	summary.Warnings = warnings
+
+	if summaryFile != "" {
+		if err := writeSummaryFile(summaryFile, summary); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
+		}
+	}
+
+	switch {
+	case jsonOutput:
+		if err := printJSON(summary); err != nil {
+			return err
+		}
+	case quiet:
+		// nothing - the exit code says it all
+	default:
+		printInitSummary(summary)
+	}
+
+	return nil
+}
+
+// initRequest is a resolved init: the IDE and selection are already
+// settled, whether by flags, the wizard or a saved profile
+type initRequest struct {
+	Adapter     ide.Adapter
+	TargetDir   string // absolute path of the project
+	Agents      []string
+	Skills      []string
+	Workflows   []string // none means every workflow
+	DryRun      bool
+	Force       bool
+	Verbose     bool
+	WithPlugins bool
+	TrackFiles  bool // fill in the summary's Files
+}
+
+// applyInit installs templates for req and returns what was installed.
+// It's the part of init shared with 'agen profile load'.
+//
+// How it works:
+//  1. Load embedded templates, plus plugin templates if asked for
+//  2. Expand the requested skills with the skills they require
+//  3. Filter to the requested agents, skills and workflows (none means
+//     everything)
+//  4. Install with the adapter
+func applyInit(req initRequest, info, warn func(string, ...interface{})) (initSummary, error) {
 	// load from embedded templates first
 	tmpl, err := templates.LoadEmbedded()
 	if err != nil {
-		return fmt.Errorf("failed to load templates: %w", err)
+		return initSummary{}, fmt.Errorf("failed to load templates: %w", err)
 	}
 
-	if verbose {
-		printInfo("Loaded %d agents, %d skills, %d workflows",
+	// then plugins, which can add templates but never replace embedded ones
+	if req.WithPlugins {
+		if err := mergePluginTemplates(tmpl, warn); err != nil {
+			return initSummary{}, err
+		}
+	}
+
+	if req.Verbose {
+		info("Loaded %d agents, %d skills, %d workflows",
 			len(tmpl.Agents), len(tmpl.Skills), len(tmpl.Workflows))
 	}
 
-	// Step 5: Filter if specific agents/skills requested
-	if len(agents) > 0 || len(skills) > 0 {
-		tmpl = tmpl.Filter(agents, skills)
-		if verbose {
-			printInfo("Filtered to %d agents, %d skills",
+	// Requested skills bring the skills they require along with them
+	skills := req.Skills
+	if len(skills) > 0 {
+		resolved, err := tmpl.ResolveSkills(skills)
+		if err != nil {
+			return initSummary{}, fmt.Errorf("cannot resolve skills: %w", err)
+		}
+		if added := len(resolved) - len(skills); added > 0 {
+			info("Including %d required skill(s): %s", added, strings.Join(resolved[len(skills):], ", "))
+		}
+		skills = resolved
+	}
+
+	if len(req.Agents) > 0 || len(skills) > 0 {
+		tmpl = tmpl.Filter(req.Agents, skills)
+		if req.Verbose {
+			info("Filtered to %d agents, %d skills",
 				len(tmpl.Agents), len(tmpl.Skills))
 		}
 	}
 
-	// Step 6: Install!
+	tmpl = tmpl.FilterWorkflows(req.Workflows)
+
 	opts := ide.InstallOptions{
-		TargetDir: absPath,
-		DryRun:    dryRun,
-		Force:     force,
-		Verbose:   verbose,
+		TargetDir: req.TargetDir,
+		DryRun:    req.DryRun,
+		Force:     req.Force,
+		Verbose:   req.Verbose,
 	}
 
-	if err := ideAdapter.Install(tmpl, opts); err != nil {
-		return fmt.Errorf("installation failed: %w", err)
+	var before map[string]string
+	if req.TrackFiles {
+		before = snapshotProject(req.TargetDir)
 	}
 
-	// Success!
-	if !dryRun {
-		green := color.New(color.FgGreen, color.Bold)
+	if err := req.Adapter.Install(tmpl, opts); err != nil {
+		return initSummary{}, fmt.Errorf("installation failed: %w", err)
+	}
+
+	summary := newInitSummary(req.Adapter, req.TargetDir, req.DryRun, tmpl, req.Agents, skills)
+	for _, name := range req.Workflows {
+		if _, ok := tmpl.Workflows[name]; !ok {
+			summary.NotFound = append(summary.NotFound, "workflow:"+name)
+		}
+	}
+	if req.TrackFiles {
+		summary.Files = diffSnapshots(before, snapshotProject(req.TargetDir))
+	}
+	return summary, nil
+}
+
+// resolveFallbackIDE picks the IDE used when detection comes up empty.
+//
+// Resolution order: --fallback flag > config default_ide (project .agenrc
+// over global) > antigravity.
+// The name is validated up front so a typo fails before anything is written,
+// and returned as its registry key ("Cursor" → cursor) so the wizard can
+// pre-select it.
+// explicit reports whether the user chose it (flag or config) rather than
+// us falling back to the built-in default.
+func resolveFallbackIDE(cmd *cobra.Command, cfg *config.Config) (name string, explicit bool, err error) {
+	name, _ = cmd.Flags().GetString("fallback")
+	source := "--fallback"
+
+	if name == "" && cfg.DefaultIDE != "" {
+		name = cfg.DefaultIDE
+		source = "config default_ide"
+	}
+
+	if name == "" {
+		return "antigravity", false, nil
+	}
+
+	key, ok := ide.ResolveAdapterName(name)
+	if !ok {
+		return "", false, fmt.Errorf("unknown IDE in %s: %s", source, name)
+	}
+
+	return key, true, nil
+}
+
+// initSummary describes what an init run installed.
+// It's what --json prints and --output-summary writes.
+type initSummary struct {
+	IDE              string       `json:"ide"`
+	Location         string       `json:"location"`
+	DryRun           bool         `json:"dry_run"`
+	AgenVersion      string       `json:"agen_version"`
+	TemplatesVersion string       `json:"templates_version"`
+	Agents           []string     `json:"agents"`
+	Skills           []string     `json:"skills"`
+	Workflows        int          `json:"workflows"`
+	NotFound         []string     `json:"not_found,omitempty"`
+	Files            []fileChange `json:"files,omitempty"`
+	Warnings         []string     `json:"warnings,omitempty"`
+}
+
+// newInitSummary builds the summary from the final (filtered) templates.
+// Requested names that Filter dropped are listed in NotFound so a typo in
+// --agents/--skills doesn't go unnoticed.
+func newInitSummary(adapter ide.Adapter, location string, dryRun bool, tmpl *templates.Templates, agents, skills []string) initSummary {
+	summary := initSummary{
+		IDE:              adapter.Name(),
+		Location:         location,
+		DryRun:           dryRun,
+		AgenVersion:      Version,
+		TemplatesVersion: tmpl.Version,
+		Agents:           tmpl.AgentNames(),
+		Skills:           tmpl.SkillNames(),
+		Workflows:        len(tmpl.Workflows),
+	}
+
+	for _, name := range agents {
+		if _, ok := tmpl.Agents[name]; !ok {
+			summary.NotFound = append(summary.NotFound, "agent:"+name)
+		}
+	}
+	for _, name := range skills {
+		if _, ok := tmpl.Skills[name]; !ok {
+			summary.NotFound = append(summary.NotFound, "skill:"+name)
+		}
+	}
+
+	return summary
+}
+
+// printInitSummary prints the human-readable result of init
+func printInitSummary(summary initSummary) {
+	green := color.New(color.FgGreen, color.Bold)
+	dim := color.New(color.Faint)
+
+	if summary.DryRun {
+		fmt.Printf("\nWould install for: %s\n", summary.IDE)
+	} else {
 		green.Println("\n✨ AGEN initialized successfully!")
-		fmt.Printf("\nInstalled for: %s\n", ideAdapter.Name())
-		fmt.Printf("Location: %s\n", absPath)
-		fmt.Println("\nNext steps:")
-		fmt.Println("  agen status   - Check installation status")
-		fmt.Println("  agen list     - See available agents and skills")
-		fmt.Println("  agen verify   - Run verification scripts")
+		fmt.Printf("\nInstalled for: %s\n", summary.IDE)
 	}
+	fmt.Printf("Location: %s\n", summary.Location)
 
-	return nil
+	fmt.Printf("\nAgents (%d):\n", len(summary.Agents))
+	for _, name := range summary.Agents {
+		fmt.Printf("  %s\n", color.GreenString(name))
+	}
+
+	fmt.Printf("\nSkills (%d):\n", len(summary.Skills))
+	for _, name := range summary.Skills {
+		fmt.Printf("  %s\n", color.BlueString(name))
+	}
+
+	dim.Printf("\nWorkflows: %d\n", summary.Workflows)
+
+	for _, name := range summary.NotFound {
+		printWarning("Not found, skipped: %s", name)
+	}
+
+	if summary.DryRun {
+		return
+	}
+
+	fmt.Println("\nNext steps:")
+	fmt.Println("  agen status   - Check installation status")
+	fmt.Println("  agen list     - See available agents and skills")
+	fmt.Println("  agen verify   - Run verification scripts")
 }
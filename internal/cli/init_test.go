@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for picking the IDE agen init falls back to
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/eshanized/agen/internal/config"
+)
+
+func TestResolveFallbackIDE(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		defaultIDE string
+		want       string
+		explicit   bool
+		wantErr    bool
+	}{
+		{"built-in default", nil, "", "antigravity", false, false},
+		{"flag", []string{"--fallback", "cursor"}, "", "cursor", true, false},
+		{"flag in another case", []string{"--fallback", "Cursor"}, "", "cursor", true, false},
+		{"config alias", nil, "claude-code", "claudecode", true, false},
+		{"flag over config", []string{"--fallback", "zed"}, "windsurf", "zed", true, false},
+		{"unknown", []string{"--fallback", "notepad"}, "", "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := parse(t, append([]string{"init"}, tt.args...)...)
+			cfg := config.DefaultConfig()
+			cfg.DefaultIDE = tt.defaultIDE
+
+			got, explicit, err := resolveFallbackIDE(cmd, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveFallbackIDE() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || explicit != tt.explicit {
+				t.Errorf("resolveFallbackIDE() = %q, %v; want %q, %v", got, explicit, tt.want, tt.explicit)
+			}
+		})
+	}
+}
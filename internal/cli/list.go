@@ -8,6 +8,7 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"sort"
 	"strings"
 
@@ -29,7 +30,9 @@ Examples:
   agen list              # List everything
   agen list --agents     # Only show agents
   agen list --skills     # Only show skills
-  agen list --workflows  # Only show workflows`,
+  agen list --workflows  # Only show workflows
+  agen list --verbose    # Also show where each template was loaded from
+  agen list --with-plugins  # Include templates from installed plugins`,
 	RunE: runList,
 }
 
@@ -38,6 +41,7 @@ func init() {
 	listCmd.Flags().BoolP("skills", "s", false, "only show skills")
 	listCmd.Flags().BoolP("workflows", "w", false, "only show workflows")
 	listCmd.Flags().Bool("json", false, "output as JSON")
+	listCmd.Flags().Bool("with-plugins", false, "include templates from installed plugins")
 }
 
 // runList is the main logic for the list command.
@@ -57,16 +61,42 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load templates: %w", err)
 	}
 
+	if withPlugins, _ := cmd.Flags().GetBool("with-plugins"); withPlugins {
+		// keep stdout clean for --json
+		warn := printWarning
+		if wantJSON(cmd) {
+			warn = func(format string, args ...interface{}) {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", fmt.Sprintf(format, args...))
+			}
+		}
+		if err := mergePluginTemplates(tmpl, warn); err != nil {
+			return err
+		}
+	}
+
 	showAgents, _ := cmd.Flags().GetBool("agents")
 	showSkills, _ := cmd.Flags().GetBool("skills")
 	showWorkflows, _ := cmd.Flags().GetBool("workflows")
+	verbose := checkVerbose(cmd)
 
 	// if no specific flags, show everything
 	showAll := !showAgents && !showSkills && !showWorkflows
 
+	if wantJSON(cmd) {
+		return printJSON(buildListOutput(tmpl, showAll || showAgents, showAll || showSkills, showAll || showWorkflows))
+	}
+
 	cyan := color.New(color.FgCyan, color.Bold)
 	dim := color.New(color.Faint)
 
+	// with --verbose every line ends with the template's source
+	printSource := func(source string) {
+		if verbose {
+			dim.Printf(" [%s]", source)
+		}
+		fmt.Println()
+	}
+
 	if showAll || showAgents {
 		fmt.Println()
 		cyan.Println("📦 AGENTS")
@@ -82,7 +112,8 @@ func runList(cmd *cobra.Command, args []string) error {
 
 		for _, name := range names {
 			agent := tmpl.Agents[name]
-			fmt.Printf("  %-25s %s\n", color.GreenString(name), agent.Description)
+			fmt.Printf("  %-25s %s", color.GreenString(name), agent.Description)
+			printSource(agent.Source)
 		}
 		fmt.Printf("\n  Total: %d agents\n", len(tmpl.Agents))
 	}
@@ -101,7 +132,8 @@ func runList(cmd *cobra.Command, args []string) error {
 
 		for _, name := range names {
 			skill := tmpl.Skills[name]
-			fmt.Printf("  %-25s %s\n", color.BlueString(name), skill.Description)
+			fmt.Printf("  %-25s %s", color.BlueString(name), skill.Description)
+			printSource(skill.Source)
 		}
 		fmt.Printf("\n  Total: %d skills\n", len(tmpl.Skills))
 	}
@@ -125,7 +157,8 @@ func runList(cmd *cobra.Command, args []string) error {
 			if !strings.HasPrefix(name, "/") {
 				displayName = "/" + name
 			}
-			fmt.Printf("  %-25s %s\n", color.MagentaString(displayName), workflow.Description)
+			fmt.Printf("  %-25s %s", color.MagentaString(displayName), workflow.Description)
+			printSource(workflow.Source)
 		}
 		fmt.Printf("\n  Total: %d workflows\n", len(tmpl.Workflows))
 	}
@@ -133,3 +166,42 @@ func runList(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	return nil
 }
+
+// listEntry is one template in list --json output
+type listEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Source      string `json:"source"`
+}
+
+// listOutput is the list --json document. Sections that weren't asked
+// for are left out rather than printed empty.
+type listOutput struct {
+	Agents    []listEntry `json:"agents,omitempty"`
+	Skills    []listEntry `json:"skills,omitempty"`
+	Workflows []listEntry `json:"workflows,omitempty"`
+}
+
+// buildListOutput collects the requested sections, sorted by name
+func buildListOutput(tmpl *templates.Templates, agents, skills, workflows bool) listOutput {
+	var out listOutput
+	if agents {
+		for _, name := range tmpl.AgentNames() {
+			a := tmpl.Agents[name]
+			out.Agents = append(out.Agents, listEntry{Name: name, Description: a.Description, Source: a.Source})
+		}
+	}
+	if skills {
+		for _, name := range tmpl.SkillNames() {
+			s := tmpl.Skills[name]
+			out.Skills = append(out.Skills, listEntry{Name: name, Description: s.Description, Source: s.Source})
+		}
+	}
+	if workflows {
+		for _, name := range tmpl.WorkflowNames() {
+			w := tmpl.Workflows[name]
+			out.Workflows = append(out.Workflows, listEntry{Name: name, Description: w.Description, Source: w.Source})
+		}
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Open command implementation
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/eshanized/agen/internal/ide"
+	"github.com/spf13/cobra"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open [path]",
+	Short: "Open the rules file for the detected IDE",
+	Long: `Open the main rules file AGEN generated for this project.
+
+Every IDE keeps its rules somewhere different (.cursorrules, CLAUDE.md,
+.agent/rules/GEMINI.md, ...). This detects the IDE and opens the right
+file in $VISUAL or $EDITOR, or with the OS default application if
+neither is set.
+
+Examples:
+  agen open                  # Open rules for current project
+  agen open --print          # Just print the absolute path
+  agen open --ide cursor     # Open .cursorrules regardless of detection
+  $EDITOR $(agen open -p)    # Use the path in scripts`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runOpen,
+}
+
+func init() {
+	openCmd.Flags().BoolP("print", "p", false, "print the absolute path instead of opening it")
+	openCmd.Flags().StringP("ide", "i", "", "use this IDE's rules file instead of detecting")
+	rootCmd.AddCommand(openCmd)
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	targetDir := "."
+	if len(args) > 0 {
+		targetDir = args[0]
+	}
+
+	absPath, err := filepath.Abs(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	var adapter ide.Adapter
+	if ideName, _ := cmd.Flags().GetString("ide"); ideName != "" {
+		adapter = ide.GetAdapter(ideName)
+		if adapter == nil {
+			return fmt.Errorf("unknown IDE: %s", ideName)
+		}
+	} else {
+		adapter = ide.Detect(absPath)
+		if adapter == nil {
+			return fmt.Errorf("no AGEN installation found. Run 'agen init' first")
+		}
+	}
+
+	rulesPath := filepath.Join(absPath, filepath.FromSlash(adapter.GetRulesPath()))
+	if _, err := os.Stat(rulesPath); err != nil {
+		return fmt.Errorf("%s rules file not found: %s", adapter.Name(), rulesPath)
+	}
+
+	if printOnly, _ := cmd.Flags().GetBool("print"); printOnly {
+		fmt.Println(rulesPath)
+		return nil
+	}
+
+	return openFile(rulesPath)
+}
+
+// openFile opens path in the user's editor, or the OS default app.
+//
+// $VISUAL wins over $EDITOR (the usual Unix convention). The editor runs
+// attached to our terminal so terminal editors like vim work. The value
+// may include arguments, e.g. "code --wait".
+func openFile(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if editor != "" {
+		parts := strings.Fields(editor)
+		c := exec.Command(parts[0], append(parts[1:], path)...)
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		return c.Run()
+	}
+
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("open", path)
+	case "windows":
+		c = exec.Command("cmd", "/c", "start", "", path)
+	default:
+		c = exec.Command("xdg-open", path)
+	}
+
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("failed to open %s (set $EDITOR or use --print): %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Shared machine-readable output helpers
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Values for the global --output flag
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// outputFormat holds the global --output flag value.
+// verify has its own --output for its report format (text, json or
+// markdown), which shadows this one there. Commands that write to a file
+// use --out-file or --out-dir instead, so the two never get mixed up;
+// see addOutputAlias for the --output they used to take.
+var outputFormat = outputText
+
+// outputAliasAnnotation marks a hidden --output flag as the old name of
+// the flag stored in the annotation
+const outputAliasAnnotation = "agen_output_alias_for"
+
+// addOutputAlias keeps --output working on a command whose file or
+// directory flag was renamed to target (out-file or out-dir). The hidden
+// flag shadows the global --output there, so applyOutputAlias sorts the
+// value out: text and json still set the output format, anything else is
+// the old file or directory meaning.
+func addOutputAlias(cmd *cobra.Command, target string) {
+	cmd.Flags().String("output", "", "deprecated alias for --"+target)
+	cmd.Flags().MarkHidden("output")
+	cmd.Flags().SetAnnotation("output", outputAliasAnnotation, []string{target})
+}
+
+// applyOutputAlias resolves a command's --output alias (see addOutputAlias)
+// before the command runs
+func applyOutputAlias(cmd *cobra.Command) error {
+	flag := cmd.Flags().Lookup("output")
+	if flag == nil || !flag.Changed || len(flag.Annotations[outputAliasAnnotation]) == 0 {
+		return nil
+	}
+	target := flag.Annotations[outputAliasAnnotation][0]
+
+	value := flag.Value.String()
+	if value == outputText || value == outputJSON {
+		outputFormat = value
+		return nil
+	}
+
+	if cmd.Flags().Changed(target) {
+		err := fmt.Errorf("--output %q and --%s both set where to write; use --%s", value, target, target)
+		printError("%v", err)
+		return err
+	}
+	printWarning("--output for a file or directory is deprecated; use --%s", target)
+	return cmd.Flags().Set(target, value)
+}
+
+// validateOutputFormat rejects unknown --output values before a command runs
+func validateOutputFormat() error {
+	switch outputFormat {
+	case outputText, outputJSON:
+		return nil
+	}
+	err := fmt.Errorf("invalid --output %q (want %s or %s)", outputFormat, outputText, outputJSON)
+	printError("%v", err)
+	return err
+}
+
+// wantJSON reports whether cmd should print JSON instead of text.
+// That's the case with the global --output json, or with the command's own
+// --json flag, which is kept as a shorthand.
+func wantJSON(cmd *cobra.Command) bool {
+	if outputFormat == outputJSON {
+		return true
+	}
+	if cmd.Flags().Lookup("json") == nil {
+		return false
+	}
+	enabled, _ := cmd.Flags().GetBool("json")
+	return enabled
+}
+
+// printJSON writes v to stdout as indented JSON.
+// Every command's JSON mode goes through here so the shape stays consistent.
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
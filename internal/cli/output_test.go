@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for the global --output flag
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// parse resolves args to a command and parses its flags, without running it.
+// The flags go back to their defaults when the test ends.
+func parse(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	outputFormat = outputText
+	t.Cleanup(func() { outputFormat = outputText })
+
+	cmd, flags, err := rootCmd.Find(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resetFlags(cmd)
+	t.Cleanup(func() { resetFlags(cmd) })
+	if err := cmd.ParseFlags(flags); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+// resetFlags puts cmd's flags back to their defaults, unset
+func resetFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+func TestGlobalOutputNotShadowed(t *testing.T) {
+	tests := []struct {
+		args []string
+		flag string
+	}{
+		{[]string{"--output", "json", "export", "-o", "templates.json"}, "out-file"},
+		{[]string{"compose", "my-agent", "--output", "json", "--out-file", "my-agent.md"}, "out-file"},
+		{[]string{"create", "--output", "json", "-o", "agents"}, "out-dir"},
+	}
+
+	for _, tt := range tests {
+		cmd := parse(t, tt.args...)
+		if err := applyOutputAlias(cmd); err != nil {
+			t.Fatalf("%s: applyOutputAlias() failed: %v", cmd.Name(), err)
+		}
+		if outputFormat != outputJSON {
+			t.Errorf("%s: --output = %q, want json", cmd.Name(), outputFormat)
+		}
+		if got, _ := cmd.Flags().GetString(tt.flag); got != tt.args[len(tt.args)-1] {
+			t.Errorf("%s: --%s = %q, want %q", cmd.Name(), tt.flag, got, tt.args[len(tt.args)-1])
+		}
+	}
+}
+
+func TestOnlyVerifyHasLocalOutput(t *testing.T) {
+	var walk func(*cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		flag := cmd.LocalNonPersistentFlags().Lookup("output")
+		if cmd != rootCmd && flag != nil && cmd != verifyCmd && len(flag.Annotations[outputAliasAnnotation]) == 0 {
+			t.Errorf("%q defines its own --output, which hides the global one", cmd.CommandPath())
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
+
+func TestOutputAlias(t *testing.T) {
+	tests := []struct {
+		args   []string
+		flag   string
+		want   string // value of flag after the alias is applied
+		format string // global output format after the alias is applied
+	}{
+		{[]string{"export", "--output", "templates.json"}, "out-file", "templates.json", outputText},
+		{[]string{"compose", "my-agent", "--output", "my-agent.md"}, "out-file", "my-agent.md", outputText},
+		{[]string{"create", "--output", "./agents"}, "out-dir", "./agents", outputText},
+		{[]string{"export", "--output", "json", "-o", "templates.json"}, "out-file", "templates.json", outputJSON},
+		{[]string{"--output", "json", "create"}, "out-dir", "", outputJSON},
+	}
+
+	for _, tt := range tests {
+		cmd := parse(t, tt.args...)
+		if err := applyOutputAlias(cmd); err != nil {
+			t.Fatalf("%v: applyOutputAlias() failed: %v", tt.args, err)
+		}
+		if got, _ := cmd.Flags().GetString(tt.flag); got != tt.want {
+			t.Errorf("%v: --%s = %q, want %q", tt.args, tt.flag, got, tt.want)
+		}
+		if outputFormat != tt.format {
+			t.Errorf("%v: --output = %q, want %q", tt.args, outputFormat, tt.format)
+		}
+		if err := validateOutputFormat(); err != nil {
+			t.Errorf("%v: %v", tt.args, err)
+		}
+	}
+
+	cmd := parse(t, "export", "--output", "a.json", "--out-file", "b.json")
+	if err := applyOutputAlias(cmd); err == nil {
+		t.Error("--output and --out-file together should fail")
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	t.Cleanup(func() { outputFormat = outputText })
+
+	for _, format := range []string{outputText, outputJSON} {
+		outputFormat = format
+		if err := validateOutputFormat(); err != nil {
+			t.Errorf("validateOutputFormat(%q) = %v", format, err)
+		}
+	}
+
+	outputFormat = "yaml"
+	if err := validateOutputFormat(); err == nil {
+		t.Error("validateOutputFormat(\"yaml\") should fail")
+	}
+}
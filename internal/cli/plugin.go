@@ -8,8 +8,11 @@ package cli
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/eshanized/agen/internal/httpclient"
 	"github.com/eshanized/agen/internal/plugin"
+	"github.com/eshanized/agen/internal/templates"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -38,10 +41,21 @@ Supported sources:
 - Local: ./path/to/plugin
 - URL: https://example.com/plugin.zip
 
+Checksums:
+Append @sha256:<hash> to a URL or GitHub source to pin the archive. It is
+hashed after download and nothing is installed if the hash differs. A
+pinned GitHub source is downloaded as the zip GitHub serves for that
+version instead of being cloned with git.
+
+Without a pin, a URL plugin is checked against <url>.sha256 if the server
+publishes one (either a bare hash or sha256sum output). Unpinned GitHub
+sources are cloned with git and not checksummed.
+
 Examples:
   agen plugin install github.com/eshanized/agen-plugins
   agen plugin install ./my-local-plugin
-  agen plugin install github.com/user/repo@v1.0.0`,
+  agen plugin install github.com/user/repo@v1.0.0
+  agen plugin install https://example.com/plugin.zip@sha256:9f86d08...`,
 	Args: cobra.ExactArgs(1),
 	RunE: runPluginInstall,
 }
@@ -53,6 +67,42 @@ var pluginUninstallCmd = &cobra.Command{
 	RunE:  runPluginUninstall,
 }
 
+var pluginUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [name]",
+	Short: "Upgrade installed plugins",
+	Long: `Fetch a plugin again from the source it was installed from and
+record its new version.
+
+GitHub plugins are pulled with git, URL plugins are downloaded again
+(and checked against their pinned or published sha256), and local
+plugins are re-read from their directory. A plugin whose source has
+nothing new is reported as up to date.
+
+Examples:
+  agen plugin upgrade security-pack
+  agen plugin upgrade --all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPluginUpgrade,
+}
+
+var pluginVerifyCmd = &cobra.Command{
+	Use:   "verify <name>",
+	Short: "Check a plugin's registry entry against its files",
+	Long: `Re-scan a plugin's directory and report agents, skills and workflows
+that are on disk but missing from its registry entry, or listed in the
+entry but missing on disk.
+
+This happens after editing an installed plugin by hand or after an
+interrupted install. With --fix, the entry is rewritten to match the
+directory.
+
+Examples:
+  agen plugin verify security-pack
+  agen plugin verify security-pack --fix`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPluginVerify,
+}
+
 var pluginListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List installed plugins",
@@ -85,12 +135,19 @@ var pluginInfoCmd = &cobra.Command{
 
 func init() {
 	pluginCreateCmd.Flags().String("type", "bundle", "plugin type (agent, skill, workflow, bundle)")
+	pluginUpgradeCmd.Flags().Bool("all", false, "upgrade every installed plugin")
+	pluginVerifyCmd.Flags().Bool("fix", false, "rewrite the registry entry from the plugin directory")
+	for _, c := range []*cobra.Command{pluginInstallCmd, pluginUpgradeCmd} {
+		c.Flags().Duration("timeout", plugin.DefaultDownloadTimeout, "time limit for each download (at most 2m)")
+	}
 
 	pluginCmd.AddCommand(pluginInstallCmd)
 	pluginCmd.AddCommand(pluginUninstallCmd)
+	pluginCmd.AddCommand(pluginUpgradeCmd)
 	pluginCmd.AddCommand(pluginListCmd)
 	pluginCmd.AddCommand(pluginCreateCmd)
 	pluginCmd.AddCommand(pluginInfoCmd)
+	pluginCmd.AddCommand(pluginVerifyCmd)
 
 	rootCmd.AddCommand(pluginCmd)
 }
@@ -102,7 +159,7 @@ func runPluginInstall(cmd *cobra.Command, args []string) error {
 	cyan.Println("\n🔌 Installing Plugin")
 	fmt.Printf("Source: %s\n\n", source)
 
-	manager, err := plugin.NewManager()
+	manager, err := newDownloadingManager(cmd)
 	if err != nil {
 		return fmt.Errorf("failed to initialize plugin manager: %w", err)
 	}
@@ -112,6 +169,13 @@ func runPluginInstall(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("installation failed: %w", err)
 	}
 
+	printInstalledPlugin(p)
+	return nil
+}
+
+// printInstalledPlugin reports a plugin that was just installed and the
+// templates it brought
+func printInstalledPlugin(p *plugin.Plugin) {
 	printSuccess("Installed: %s v%s", p.Name, p.Version)
 	if len(p.Agents) > 0 {
 		fmt.Printf("  Agents: %v\n", p.Agents)
@@ -122,8 +186,23 @@ func runPluginInstall(cmd *cobra.Command, args []string) error {
 	if len(p.Workflows) > 0 {
 		fmt.Printf("  Workflows: %v\n", p.Workflows)
 	}
+}
 
-	return nil
+// newDownloadingManager returns a plugin manager using the command's
+// --timeout for downloads
+func newDownloadingManager(cmd *cobra.Command) (*plugin.Manager, error) {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	// the shared HTTP client gives up at its own limit regardless
+	if timeout <= 0 || timeout > httpclient.DefaultTimeout {
+		return nil, fmt.Errorf("--timeout must be between 0 and %s", httpclient.DefaultTimeout)
+	}
+
+	manager, err := plugin.NewManager()
+	if err != nil {
+		return nil, err
+	}
+	manager.SetTimeout(timeout)
+	return manager, nil
 }
 
 func runPluginUninstall(cmd *cobra.Command, args []string) error {
@@ -142,6 +221,58 @@ func runPluginUninstall(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runPluginUpgrade(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	if all == (len(args) == 1) {
+		return fmt.Errorf("specify a plugin name or --all")
+	}
+
+	manager, err := newDownloadingManager(cmd)
+	if err != nil {
+		return err
+	}
+
+	names := args
+	if all {
+		for _, p := range manager.List() {
+			names = append(names, p.Name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			fmt.Println("No plugins installed.")
+			return nil
+		}
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Println("\n🔌 Upgrading Plugins")
+	fmt.Println()
+
+	// with --all, one broken source shouldn't stop the rest
+	failed := 0
+	for _, name := range names {
+		result, err := manager.Upgrade(name)
+		if err != nil {
+			printError("%s: %v", name, err)
+			failed++
+			continue
+		}
+
+		if !result.Changed {
+			printInfo("%s is up to date (v%s)", name, result.NewVersion)
+		} else if result.OldVersion != result.NewVersion {
+			printSuccess("%s: v%s → v%s", name, result.OldVersion, result.NewVersion)
+		} else {
+			printSuccess("%s: updated, still v%s", name, result.NewVersion)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d plugin(s) failed to upgrade", failed)
+	}
+	return nil
+}
+
 func runPluginList(cmd *cobra.Command, args []string) error {
 	manager, err := plugin.NewManager()
 	if err != nil {
@@ -203,7 +334,7 @@ func runPluginInfo(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	p, err := manager.Get(name)
+	p, contents, issues, err := manager.Inspect(name)
 	if err != nil {
 		return err
 	}
@@ -215,24 +346,88 @@ func runPluginInfo(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Type:        %s\n", p.Type)
 	fmt.Printf("Author:      %s\n", p.Author)
 	fmt.Printf("Source:      %s\n", p.Source)
+	fmt.Printf("Location:    %s\n", manager.Path(p))
 
 	if p.Description != "" {
 		fmt.Printf("\n%s\n", p.Description)
 	}
 
-	if len(p.Agents) > 0 {
-		fmt.Printf("\nAgents:\n")
-		for _, a := range p.Agents {
-			fmt.Printf("  - %s\n", a)
+	// show what's actually on disk, not what the manifest claims
+	printPluginSection("Agents", contents.Agents)
+	printPluginSection("Skills", contents.Skills)
+	printPluginSection("Workflows", contents.Workflows)
+
+	if len(issues) > 0 {
+		fmt.Println()
+		printWarning("Manifest does not match plugin contents:")
+		for _, issue := range issues {
+			fmt.Printf("  - %s\n", issue)
 		}
 	}
 
-	if len(p.Skills) > 0 {
-		fmt.Printf("\nSkills:\n")
-		for _, s := range p.Skills {
-			fmt.Printf("  - %s\n", s)
-		}
+	return nil
+}
+
+func runPluginVerify(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	manager, err := plugin.NewManager()
+	if err != nil {
+		return err
 	}
 
+	issues, err := manager.Verify(name)
+	if err != nil {
+		return err
+	}
+
+	if len(issues) == 0 {
+		printSuccess("%s: registry entry matches its files", name)
+		return nil
+	}
+
+	printWarning("%s: registry entry does not match its files:", name)
+	for _, issue := range issues {
+		fmt.Printf("  - %s\n", issue)
+	}
+
+	if !fix {
+		fmt.Printf("\nRun 'agen plugin verify %s --fix' to update the registry entry.\n", name)
+		return fmt.Errorf("plugin %s has %d mismatch(es)", name, len(issues))
+	}
+
+	if err := manager.Fix(name); err != nil {
+		return fmt.Errorf("failed to fix %s: %w", name, err)
+	}
+	printSuccess("Updated the registry entry for %s", name)
+	return nil
+}
+
+// printPluginSection prints a titled list with its count, skipping empty ones
+func printPluginSection(title string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	fmt.Printf("\n%s (%d):\n", title, len(names))
+	for _, n := range names {
+		fmt.Printf("  - %s\n", n)
+	}
+}
+
+// mergePluginTemplates adds every installed plugin's templates to tmpl for
+// --with-plugins. Templates already in tmpl are kept; each name clash is
+// passed to warn rather than silently overwriting.
+func mergePluginTemplates(tmpl *templates.Templates, warn func(format string, args ...interface{})) error {
+	mgr, err := plugin.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to load plugins: %w", err)
+	}
+
+	plugins, collisions := mgr.LoadPlugins()
+	collisions = append(collisions, tmpl.Merge(plugins)...)
+	for _, c := range collisions {
+		warn("%s", c)
+	}
 	return nil
 }
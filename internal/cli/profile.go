@@ -11,9 +11,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/eshanized/agen/internal/config"
+	"github.com/eshanized/agen/internal/ide"
+	"github.com/eshanized/agen/internal/templates"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -31,6 +35,7 @@ and quickly apply them to new projects.
 Examples:
   agen profile save frontend              # Save current setup as "frontend"
   agen profile load backend-api           # Apply saved "backend-api" profile
+  agen profile diff frontend              # Compare "frontend" with this project
   agen profile list                       # Show all saved profiles
   agen profile delete old-profile         # Delete a profile
   agen profile export frontend > f.json   # Export to file`,
@@ -57,8 +62,34 @@ var saveProfileCmd = &cobra.Command{
 var loadProfileCmd = &cobra.Command{
 	Use:   "load <name>",
 	Short: "Load and apply a saved profile",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runProfileLoad,
+	Long: `Install a saved profile's agents and skills into the current directory.
+
+The IDE is --ide when given, else the profile's, else the one detected
+in the project, then default_ide from the config, then Antigravity.
+
+Examples:
+  agen profile load frontend
+  agen profile load frontend --dry-run
+  agen profile load frontend --ide cursor --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProfileLoad,
+}
+
+var diffProfileCmd = &cobra.Command{
+	Use:   "diff <name>",
+	Short: "Compare a saved profile with the current project",
+	Long: `Show what loading a profile would change in the current project.
+
+Lines starting with + are in the profile but not installed; lines
+starting with - are installed but not in the profile. A profile that
+lists no agents (or skills, or workflows) stands for all of them, as it
+does on load.
+
+Examples:
+  agen profile diff frontend
+  agen profile diff frontend --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProfileDiff,
 }
 
 var listProfileCmd = &cobra.Command{
@@ -83,7 +114,7 @@ var exportProfileCmd = &cobra.Command{
 
 var importProfileCmd = &cobra.Command{
 	Use:   "import <file>",
-	Short: "Import a profile from JSON file",
+	Short: "Import a profile from a JSON or YAML file",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runProfileImport,
 }
@@ -91,10 +122,17 @@ var importProfileCmd = &cobra.Command{
 func init() {
 	profileCmd.AddCommand(saveProfileCmd)
 	profileCmd.AddCommand(loadProfileCmd)
+	profileCmd.AddCommand(diffProfileCmd)
 	profileCmd.AddCommand(listProfileCmd)
 	profileCmd.AddCommand(deleteProfileCmd)
 	profileCmd.AddCommand(exportProfileCmd)
 	profileCmd.AddCommand(importProfileCmd)
+
+	loadProfileCmd.Flags().StringP("ide", "i", "", "install for this IDE instead of the profile's")
+	loadProfileCmd.Flags().BoolP("force", "f", false, "overwrite existing files without prompting")
+	loadProfileCmd.Flags().Bool("dry-run", false, "show what would be installed without making changes")
+
+	diffProfileCmd.Flags().Bool("json", false, "output as JSON")
 }
 
 // getProfilesDir returns the directory where profiles are stored.
@@ -107,10 +145,51 @@ func getProfilesDir() (string, error) {
 	return filepath.Join(configDir, "agen", "profiles"), nil
 }
 
+// getProfilePath returns the file for a named profile.
+// Profiles can be .json, .yaml or .yml; an existing file keeps its format
+// and new profiles are written as JSON.
+//
+// Every profile command goes through here, so this is where names are
+// validated: a name like "../../evil" would otherwise point outside
+// profilesDir.
+func getProfilePath(profilesDir, name string) (string, error) {
+	if err := config.ValidateName("profile", name); err != nil {
+		printError("%v", err)
+		return "", err
+	}
+	return config.FindFile(filepath.Join(profilesDir, name)), nil
+}
+
+// readProfile loads a saved profile by name
+func readProfile(name string) (*Profile, error) {
+	profilesDir, err := getProfilesDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profiles directory: %w", err)
+	}
+
+	profilePath, err := getProfilePath(profilesDir, name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(profilePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("profile '%s' not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+
+	var profile Profile
+	if err := config.Unmarshal(profilePath, data, &profile); err != nil {
+		return nil, fmt.Errorf("invalid profile format: %w", err)
+	}
+	return &profile, nil
+}
+
 // runProfileSave saves the current project's configuration as a named profile.
 //
 // How it works:
-// 1. Detect current IDE and installed agents/skills
+// 1. Detect current IDE and installed agents/skills/workflows
 // 2. Create a Profile struct with all the info
 // 3. Serialize to JSON and save to profiles directory
 //
@@ -124,6 +203,7 @@ func runProfileSave(cmd *cobra.Command, args []string) error {
 	cwd, _ := os.Getwd()
 	agentDir := filepath.Join(cwd, ".agent", "agents")
 	skillDir := filepath.Join(cwd, ".agent", "skills")
+	workflowDir := filepath.Join(cwd, ".agent", "workflows")
 
 	profile := Profile{
 		Name:      profileName,
@@ -152,6 +232,15 @@ func runProfileSave(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Detect installed workflows, so loading restores the same slash commands
+	if entries, err := os.ReadDir(workflowDir); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() && filepath.Ext(e.Name()) == ".md" {
+				profile.Workflows = append(profile.Workflows, strings.TrimSuffix(e.Name(), ".md"))
+			}
+		}
+	}
+
 	// Detect IDE
 	if _, err := os.Stat(filepath.Join(cwd, ".cursorrules")); err == nil {
 		profile.IDE = "cursor"
@@ -169,19 +258,23 @@ func runProfileSave(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get profiles directory: %w", err)
 	}
 
+	// check the name before touching the filesystem
+	profilePath, err := getProfilePath(profilesDir, profileName)
+	if err != nil {
+		return err
+	}
+
 	if err := os.MkdirAll(profilesDir, 0755); err != nil {
 		return fmt.Errorf("failed to create profiles directory: %w", err)
 	}
-
-	data, _ := json.MarshalIndent(profile, "", "  ")
-	profilePath := filepath.Join(profilesDir, profileName+".json")
-	if err := os.WriteFile(profilePath, data, 0644); err != nil {
+	if err := config.WriteFile(profilePath, profile); err != nil {
 		return fmt.Errorf("failed to save profile: %w", err)
 	}
 
 	printSuccess("Profile '%s' saved", profileName)
 	fmt.Printf("  Agents: %d\n", len(profile.Agents))
 	fmt.Printf("  Skills: %d\n", len(profile.Skills))
+	fmt.Printf("  Workflows: %d\n", len(profile.Workflows))
 	fmt.Printf("  IDE: %s\n", profile.IDE)
 	fmt.Println("\nTo use this profile later:")
 	fmt.Printf("  agen profile load %s\n", profileName)
@@ -189,62 +282,242 @@ func runProfileSave(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runProfileLoad applies a saved profile to the current directory.
+//
+// How it works:
+//  1. Read the profile
+//  2. Pick the IDE: --ide, else the profile's, else the one detected in
+//     the project, else default_ide from the config, else Antigravity
+//  3. Install the profile's agents and skills the way init does
+//
+// A profile with no agents or skills installs everything, like a plain
+// 'agen init'. Likewise, one without workflows (saved before they were
+// recorded) gets every workflow.
 func runProfileLoad(cmd *cobra.Command, args []string) error {
 	profileName := args[0]
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
 
-	// Load profile
-	profilesDir, err := getProfilesDir()
+	profile, err := readProfile(profileName)
 	if err != nil {
-		return fmt.Errorf("failed to get profiles directory: %w", err)
+		return err
 	}
 
-	profilePath := filepath.Join(profilesDir, profileName+".json")
-	data, err := os.ReadFile(profilePath)
-	if os.IsNotExist(err) {
-		return fmt.Errorf("profile '%s' not found", profileName)
-	}
+	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to read profile: %w", err)
+		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
-	var profile Profile
-	if err := json.Unmarshal(data, &profile); err != nil {
-		return fmt.Errorf("invalid profile format: %w", err)
+	adapter := ide.Detect(cwd)
+	if ideName, _ := cmd.Flags().GetString("ide"); ideName != "" {
+		if adapter = ide.GetAdapter(ideName); adapter == nil {
+			return fmt.Errorf("unknown IDE: %s", ideName)
+		}
+	} else if profile.IDE != "" {
+		if adapter = ide.GetAdapter(profile.IDE); adapter == nil {
+			return fmt.Errorf("profile '%s' uses an unknown IDE: %s", profileName, profile.IDE)
+		}
+	}
+	if adapter == nil {
+		name := "antigravity"
+		if cfg, err := config.LoadForProject(cwd); err == nil && cfg.DefaultIDE != "" {
+			name = cfg.DefaultIDE
+		}
+		if adapter = ide.GetAdapter(name); adapter == nil {
+			return fmt.Errorf("unknown IDE in config default_ide: %s", name)
+		}
 	}
 
 	fmt.Printf("Loading profile: %s\n", profile.Name)
-	fmt.Printf("  IDE: %s\n", profile.IDE)
+	fmt.Printf("  IDE: %s\n", adapter.Name())
 	fmt.Printf("  Agents: %d\n", len(profile.Agents))
 	fmt.Printf("  Skills: %d\n", len(profile.Skills))
+	if len(profile.Workflows) > 0 {
+		fmt.Printf("  Workflows: %d\n", len(profile.Workflows))
+	}
 
-	// Apply the profile by running init with the profile's settings
-	cwd, _ := os.Getwd()
+	if dryRun {
+		printWarning("DRY RUN: No changes will be made")
+	}
+	if len(profile.Agents) == 0 && len(profile.Skills) == 0 {
+		printInfo("Profile lists no agents or skills, installing all templates")
+	}
+
+	summary, err := applyInit(initRequest{
+		Adapter:   adapter,
+		TargetDir: cwd,
+		Agents:    profile.Agents,
+		Skills:    profile.Skills,
+		Workflows: profile.Workflows,
+		DryRun:    dryRun,
+		Force:     force,
+		Verbose:   checkVerbose(cmd),
+	}, printInfo, printWarning)
+	if err != nil {
+		return err
+	}
+
+	printInitSummary(summary)
+	if !dryRun {
+		printSuccess("Profile '%s' applied", profileName)
+	}
+
+	return nil
+}
+
+// profileDiffSection is what a profile would change for one kind of
+// template, each list sorted by name
+type profileDiffSection struct {
+	Add    []string `json:"add"`    // in the profile, not installed
+	Remove []string `json:"remove"` // installed, not in the profile
+}
+
+// profileDiff is the agen profile diff --json document
+type profileDiff struct {
+	Profile    string             `json:"profile"`
+	Directory  string             `json:"directory"`
+	ProfileIDE string             `json:"profile_ide,omitempty"`
+	ProjectIDE string             `json:"project_ide,omitempty"`
+	IDEMatches bool               `json:"ide_matches"`
+	Agents     profileDiffSection `json:"agents"`
+	Skills     profileDiffSection `json:"skills"`
+	Workflows  profileDiffSection `json:"workflows"`
+	Matches    bool               `json:"matches"`
+}
+
+// runProfileDiff compares a saved profile with the installation in the
+// current directory.
+//
+// How it works:
+//  1. Read the profile, and the installed names through the detected
+//     adapter (a project without an installation has none)
+//  2. Expand empty profile lists to every embedded template, since that's
+//     what load would install
+//  3. Diff each kind of template by name, and the IDE by adapter
+func runProfileDiff(cmd *cobra.Command, args []string) error {
+	profile, err := readProfile(args[0])
+	if err != nil {
+		return err
+	}
 
-	// Create init command with profile's agents/skills
-	initArgs := []string{"init"}
-	if profile.IDE != "" {
-		initArgs = append(initArgs, "--ide", profile.IDE)
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	embedded, err := templates.LoadEmbedded()
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
 	}
-	if len(profile.Agents) > 0 {
-		initArgs = append(initArgs, "--agents", strings.Join(profile.Agents, ","))
+
+	result := profileDiff{
+		Profile:    profile.Name,
+		Directory:  cwd,
+		ProfileIDE: profile.IDE,
+	}
+
+	installed := &ide.InstalledContent{}
+	if adapter := ide.Detect(cwd); adapter != nil {
+		result.ProjectIDE = adapter.Name()
+		if installed, err = adapter.GetInstalledContent(cwd); err != nil {
+			return fmt.Errorf("failed to read installed templates: %w", err)
+		}
+		if want := ide.GetAdapter(profile.IDE); want != nil {
+			result.IDEMatches = want.Name() == adapter.Name()
+		}
 	}
-	if len(profile.Skills) > 0 {
-		initArgs = append(initArgs, "--skills", strings.Join(profile.Skills, ","))
+	// a profile without an IDE takes whatever the project has
+	if profile.IDE == "" {
+		result.IDEMatches = result.ProjectIDE != ""
 	}
 
-	fmt.Println("\nApplying configuration...")
-	fmt.Printf("  agen %s\n", strings.Join(initArgs, " "))
+	result.Agents = diffNames(profileNames(profile.Agents, embedded.AgentNames()), installed.Agents)
+	result.Skills = diffNames(profileNames(profile.Skills, embedded.SkillNames()), installed.Skills)
+	result.Workflows = diffNames(profileNames(profile.Workflows, embedded.WorkflowNames()), installed.Workflows)
+
+	result.Matches = result.IDEMatches
+	for _, section := range []profileDiffSection{result.Agents, result.Skills, result.Workflows} {
+		if len(section.Add) > 0 || len(section.Remove) > 0 {
+			result.Matches = false
+		}
+	}
 
-	// Since we can't call cobra commands directly, just print instructions
-	printSuccess("Profile '%s' loaded", profileName)
-	fmt.Println("\nRun the following to apply:")
-	fmt.Printf("  agen %s\n", strings.Join(initArgs, " "))
+	if wantJSON(cmd) {
+		return printJSON(result)
+	}
 
-	_ = cwd // for context
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("\n🔍 Profile '%s' vs %s\n\n", profile.Name, cwd)
+
+	switch {
+	case result.IDEMatches:
+		fmt.Printf("IDE: %s\n", result.ProjectIDE)
+	case result.ProjectIDE == "" && profile.IDE == "":
+		fmt.Println("IDE: nothing installed, and the profile has no IDE")
+	case result.ProjectIDE == "":
+		fmt.Printf("IDE: %s (nothing installed yet)\n", color.GreenString("+ "+profile.IDE))
+	default:
+		fmt.Printf("IDE: %s %s\n", color.RedString("- "+result.ProjectIDE), color.GreenString("+ "+profile.IDE))
+	}
+
+	for _, s := range []struct {
+		label   string
+		section profileDiffSection
+	}{
+		{"Agents", result.Agents},
+		{"Skills", result.Skills},
+		{"Workflows", result.Workflows},
+	} {
+		if len(s.section.Add) == 0 && len(s.section.Remove) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s:\n", s.label)
+		for _, name := range s.section.Add {
+			fmt.Println(color.GreenString("  + %s", name))
+		}
+		for _, name := range s.section.Remove {
+			fmt.Println(color.RedString("  - %s", name))
+		}
+	}
+	fmt.Println()
 
+	if result.Matches {
+		printSuccess("Project matches profile '%s'", profile.Name)
+	}
 	return nil
 }
 
+// profileNames is the names a profile list stands for: itself, or every
+// template when it's empty
+func profileNames(listed, all []string) []string {
+	if len(listed) == 0 {
+		return all
+	}
+	return listed
+}
+
+// diffNames compares the names a profile wants with the installed ones
+func diffNames(want []string, installed map[string]string) profileDiffSection {
+	section := profileDiffSection{Add: []string{}, Remove: []string{}}
+
+	wanted := make(map[string]bool, len(want))
+	for _, name := range want {
+		wanted[name] = true
+		if _, ok := installed[name]; !ok {
+			section.Add = append(section.Add, name)
+		}
+	}
+	for name := range installed {
+		if !wanted[name] {
+			section.Remove = append(section.Remove, name)
+		}
+	}
+
+	sort.Strings(section.Add)
+	sort.Strings(section.Remove)
+	return section
+}
+
 func runProfileList(cmd *cobra.Command, args []string) error {
 	profilesDir, err := getProfilesDir()
 	if err != nil {
@@ -267,8 +540,9 @@ func runProfileList(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
-			name := strings.TrimSuffix(entry.Name(), ".json")
+		ext := filepath.Ext(entry.Name())
+		if !entry.IsDir() && (ext == ".json" || config.IsYAML(entry.Name())) {
+			name := strings.TrimSuffix(entry.Name(), ext)
 			fmt.Printf("  • %s\n", color.GreenString(name))
 		}
 	}
@@ -285,7 +559,10 @@ func runProfileDelete(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get profiles directory: %w", err)
 	}
 
-	profilePath := filepath.Join(profilesDir, profileName+".json")
+	profilePath, err := getProfilePath(profilesDir, profileName)
+	if err != nil {
+		return err
+	}
 	if _, err := os.Stat(profilePath); os.IsNotExist(err) {
 		return fmt.Errorf("profile '%s' not found", profileName)
 	}
@@ -301,25 +578,12 @@ func runProfileDelete(cmd *cobra.Command, args []string) error {
 func runProfileExport(cmd *cobra.Command, args []string) error {
 	profileName := args[0]
 
-	profilesDir, err := getProfilesDir()
-	if err != nil {
-		return fmt.Errorf("failed to get profiles directory: %w", err)
-	}
-
-	profilePath := filepath.Join(profilesDir, profileName+".json")
-	data, err := os.ReadFile(profilePath)
-	if os.IsNotExist(err) {
-		return fmt.Errorf("profile '%s' not found", profileName)
-	}
+	profile, err := readProfile(profileName)
 	if err != nil {
-		return fmt.Errorf("failed to read profile: %w", err)
+		return err
 	}
 
-	// Pretty print to stdout
-	var profile Profile
-	if err := json.Unmarshal(data, &profile); err != nil {
-		return fmt.Errorf("failed to parse profile: %w", err)
-	}
+	// Pretty print to stdout (always JSON, whatever the stored format)
 
 	pretty, _ := json.MarshalIndent(profile, "", "  ")
 	fmt.Println(string(pretty))
@@ -336,7 +600,7 @@ func runProfileImport(cmd *cobra.Command, args []string) error {
 	}
 
 	var profile Profile
-	if err := json.Unmarshal(data, &profile); err != nil {
+	if err := config.Unmarshal(filePath, data, &profile); err != nil {
 		return fmt.Errorf("invalid profile format: %w", err)
 	}
 
@@ -350,12 +614,21 @@ func runProfileImport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get profiles directory: %w", err)
 	}
 
+	// check the name before touching the filesystem
+	profilePath, err := getProfilePath(profilesDir, profile.Name)
+	if err != nil {
+		return err
+	}
+
 	if err := os.MkdirAll(profilesDir, 0755); err != nil {
 		return fmt.Errorf("failed to create profiles directory: %w", err)
 	}
 
-	profilePath := filepath.Join(profilesDir, profile.Name+".json")
-	if err := os.WriteFile(profilePath, data, 0644); err != nil {
+	// keep the file's format unless a profile with this name already exists
+	if _, err := os.Stat(profilePath); os.IsNotExist(err) && config.IsYAML(filePath) {
+		profilePath = filepath.Join(profilesDir, profile.Name+filepath.Ext(filePath))
+	}
+	if err := config.WriteFile(profilePath, profile); err != nil {
 		return fmt.Errorf("failed to save profile: %w", err)
 	}
 
@@ -7,16 +7,27 @@
 package cli
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/eshanized/agen"
+	"github.com/eshanized/agen/internal/changelog"
 	"github.com/eshanized/agen/internal/config"
+	"github.com/eshanized/agen/internal/httpclient"
 	"github.com/eshanized/agen/internal/ide"
+	"github.com/eshanized/agen/internal/plugin"
 	"github.com/eshanized/agen/internal/templates"
+	"github.com/eshanized/agen/internal/updater"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -29,6 +40,7 @@ var doctorCmd = &cobra.Command{
 
 Checks:
 - Configuration file validity
+- Remotes, profiles, aliases and plugin registry files
 - Template integrity
 - IDE detection
 - Network connectivity
@@ -46,10 +58,23 @@ var cleanCmd = &cobra.Command{
 	Short: "Remove cached files",
 	Long: `Clean up cached templates, temporary files, and build artifacts.
 
+Lists what will be removed and asks before deleting. Paths outside the
+user cache and temp directories are never removed.
+
+--plugins removes plugin directories that registry.json doesn't list,
+such as leftovers from an interrupted install. Registered plugins are
+kept unless --all is given as well, which uninstalls every plugin
+stored in the plugins folder (plugins installed from a local path are
+never deleted).
+
 Examples:
-  agen clean           # Clean all caches
-  agen clean --cache   # Only template cache
-  agen clean --temp    # Only temp files`,
+  agen clean                   # Clean all caches
+  agen clean --cache           # Only template cache
+  agen clean --temp            # Only temp files
+  agen clean --plugins         # Only orphaned plugin directories
+  agen clean --plugins --all   # Caches and every downloaded plugin
+  agen clean --dry-run         # Show what would be removed
+  agen clean --yes             # Skip the confirmation prompt`,
 	RunE: runClean,
 }
 
@@ -60,10 +85,24 @@ var statsCmd = &cobra.Command{
 	Long: `Display AGEN usage statistics and metrics.
 
 Shows:
-- Number of projects using AGEN
-- Most used agents and skills
-- Template versions in use
-- Command history summary`,
+- Embedded template counts and cache size
+- Commands run, and when AGEN was first and last used
+- Most used commands
+- Most installed agents across known projects
+
+Usage comes from a local history (history.jsonl in the config dir) that
+records each command's name, time and project directory. It never leaves
+your machine. Turn it off with "record_usage": false in the config or
+DO_NOT_TRACK=1, or skip one command with --no-telemetry.
+
+With --project, shows a compact summary for one project instead:
+installed counts, template version, drift, health score and when the
+templates were last written.
+
+Examples:
+  agen stats                      # Global statistics
+  agen stats --project            # Current project
+  agen stats --project ./my-app --json`,
 	RunE: runStats,
 }
 
@@ -71,11 +110,15 @@ Shows:
 var changelogCmd = &cobra.Command{
 	Use:   "changelog",
 	Short: "Show what's new",
-	Long: `Display the changelog for the current or specified version.
+	Long: `Display the changelog for a version, read from the CHANGELOG.md
+built into the binary.
+
+Without a version, shows the newest release. An unknown version lists
+the ones available.
 
 Examples:
-  agen changelog        # Show current version changes
-  agen changelog v1.0.0 # Show specific version`,
+  agen changelog        # Show the latest release
+  agen changelog v1.0.0 # Show a specific version`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runChangelog,
 }
@@ -84,8 +127,13 @@ func init() {
 	doctorCmd.Flags().Bool("fix", false, "attempt to fix issues automatically")
 	cleanCmd.Flags().Bool("cache", false, "only clean template cache")
 	cleanCmd.Flags().Bool("temp", false, "only clean temporary files")
-	cleanCmd.Flags().Bool("all", false, "clean everything")
+	cleanCmd.Flags().Bool("all", false, "clean everything; with --plugins, registered plugins too")
+	cleanCmd.Flags().Bool("plugins", false, "remove orphaned plugin directories")
+	cleanCmd.Flags().Bool("dry-run", false, "list what would be removed without deleting anything")
+	cleanCmd.Flags().BoolP("yes", "y", false, "don't ask for confirmation")
 	statsCmd.Flags().Bool("json", false, "output as JSON")
+	statsCmd.Flags().String("project", "", "show metrics for a project instead of global stats")
+	statsCmd.Flags().Lookup("project").NoOptDefVal = "."
 
 	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(cleanCmd)
@@ -96,11 +144,13 @@ func init() {
 // runDoctor performs diagnostic checks
 //
 // How it works:
-// 1. Check if config file exists and is valid
-// 2. Verify embedded templates load correctly
-// 3. Test IDE detection capabilities
-// 4. Check network connectivity to GitHub
-// 5. Verify cache directory is writable
+//  1. Check if config file exists and is valid
+//  2. Check the other JSON state files (remotes, profiles, aliases, plugins)
+//  3. Verify embedded templates load correctly, and compare the cache
+//  4. Test IDE detection capabilities, and in a project check that every
+//     skill an installed agent lists is installed too
+//  5. Verify cache directory is writable
+//  6. Reach the GitHub API the updater uses, with latency and rate limit
 //
 // Why a doctor command? Helps users troubleshoot issues without
 // digging through logs or configuration files manually.
@@ -118,26 +168,16 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	fixed := 0
 
 	// Check 1: Configuration
-	fmt.Print("Checking configuration... ")
-	cfg, err := config.Load()
-	if err != nil {
-		red.Println("❌ FAILED")
-		fmt.Printf("  Error: %v\n", err)
-		issues++
-		if fix {
-			// Try to create default config
-			if err := config.DefaultConfig().Save(); err == nil {
-				green.Println("  ✓ Created default config")
-				fixed++
-			}
-		}
-	} else {
-		green.Println("✓ OK")
-		fmt.Printf("  Config loaded successfully\n")
-		_ = cfg // use cfg
-	}
+	configIssues, configFixed := checkConfig(fix)
+	issues += configIssues
+	fixed += configFixed
+
+	// Check 2: Local state files
+	stateIssues, stateFixed := checkStateFiles(fix)
+	issues += stateIssues
+	fixed += stateFixed
 
-	// Check 2: Templates
+	// Check 3: Templates
 	fmt.Print("Checking embedded templates... ")
 	tmpl, err := templates.LoadEmbedded()
 	if err != nil {
@@ -148,21 +188,34 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		green.Println("✓ OK")
 		fmt.Printf("  %d agents, %d skills, %d workflows\n",
 			len(tmpl.Agents), len(tmpl.Skills), len(tmpl.Workflows))
+		fmt.Printf("  Sources: %s\n", formatSources(tmpl.Sources()))
+
+		// update reads the cache, so a stale one explains old templates
+		if userCache, err := os.UserCacheDir(); err == nil {
+			if cached, err := templates.LoadFromCache(filepath.Join(userCache, "agen")); err == nil {
+				fmt.Printf("  Cache: %s, %d differ from embedded\n",
+					formatSources(cached.Sources()), countChanged(tmpl, cached))
+			}
+		}
 	}
 
-	// Check 3: IDE detection
+	// Check 4: IDE detection
 	fmt.Print("Checking IDE detection... ")
 	cwd, _ := os.Getwd()
 	detectedIDE := ide.Detect(cwd)
 	if detectedIDE != nil {
 		green.Println("✓ OK")
 		fmt.Printf("  Detected: %s\n", detectedIDE.Name())
+
+		skillIssues, skillFixed := checkSkillReferences(cwd, detectedIDE, fix)
+		issues += skillIssues
+		fixed += skillFixed
 	} else {
 		color.Yellow("⚠ No IDE detected")
 		fmt.Println("  (This is OK if not in a project directory)")
 	}
 
-	// Check 4: Cache directory
+	// Check 5: Cache directory
 	fmt.Print("Checking cache directory... ")
 	cacheDir, err := os.UserCacheDir()
 	if err != nil {
@@ -190,7 +243,10 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Check 5: Go runtime
+	// Check 6: Network
+	issues += checkNetwork(fix)
+
+	// Check 7: Go runtime
 	fmt.Print("Checking runtime... ")
 	green.Println("✓ OK")
 	fmt.Printf("  Go version: %s\n", runtime.Version())
@@ -211,30 +267,431 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkConfig loads the global config file.
+//
+// A file that doesn't parse is repaired with --fix: it's backed up to
+// *.bak (reported, so the settings can be copied back by hand) and a
+// default config is written in its place. A file that can't be read at
+// all (permissions, say) is only reported, since overwriting it could
+// lose settings that are fine.
+func checkConfig(fix bool) (issues, fixed int) {
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+
+	fmt.Print("Checking configuration... ")
+	path, _ := config.GetConfigPath()
+
+	_, err := config.Load()
+	var parseErr *config.ParseError
+	switch {
+	case err == nil:
+		green.Println("✓ OK")
+		if _, statErr := os.Stat(path); os.IsNotExist(statErr) {
+			fmt.Printf("  No config file, using defaults (%s)\n", path)
+		} else {
+			fmt.Printf("  Loaded %s\n", path)
+		}
+		return 0, 0
+
+	case errors.As(err, &parseErr):
+		red.Println("❌ INVALID")
+		fmt.Printf("  Error: %v\n", err)
+		if !fix {
+			fmt.Println("  Run 'agen doctor --fix' to back it up and write a default config")
+			return 1, 0
+		}
+
+		backup, err := backupStateFile(parseErr.Path)
+		if err != nil {
+			fmt.Printf("  Backup failed, config left as is: %v\n", err)
+			return 1, 0
+		}
+		if err := config.DefaultConfig().Save(); err != nil {
+			fmt.Printf("  Failed to write default config: %v\n", err)
+			return 1, 0
+		}
+		green.Println("  ✓ Wrote a default config")
+		fmt.Printf("  Your old settings are in %s\n", backup)
+		return 1, 1
+
+	default:
+		red.Println("❌ FAILED")
+		fmt.Printf("  Error: %v\n", err)
+		if fix {
+			fmt.Println("  (the file isn't corrupt but can't be read; check its permissions)")
+		}
+		return 1, 0
+	}
+}
+
+// checkSkillReferences finds skills that installed agents list in their
+// frontmatter but that aren't installed, which leaves the agent pointing
+// at nothing. With --fix it offers to copy the missing skills from the
+// embedded templates, installing them the way compose --save does: the
+// installed set plus the new skills, through the adapter's Update.
+func checkSkillReferences(projectDir string, adapter ide.Adapter, fix bool) (issues, fixed int) {
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+
+	fmt.Print("Checking agent skill references... ")
+	tmpl, err := installedTemplates(projectDir, adapter)
+	if err != nil {
+		red.Println("❌ FAILED")
+		fmt.Printf("  Error: %v\n", err)
+		return 1, 0
+	}
+
+	// missing skill -> agents that reference it
+	orphans := make(map[string][]string)
+	for name, agent := range tmpl.Agents {
+		for _, skill := range agent.Skills {
+			if _, ok := tmpl.Skills[skill]; !ok {
+				orphans[skill] = append(orphans[skill], name)
+			}
+		}
+	}
+	if len(orphans) == 0 {
+		green.Println("✓ OK")
+		fmt.Printf("  %d agent(s), every referenced skill is installed\n", len(tmpl.Agents))
+		return 0, 0
+	}
+
+	missing := make([]string, 0, len(orphans))
+	for skill := range orphans {
+		missing = append(missing, skill)
+	}
+	sort.Strings(missing)
+
+	embedded, err := templates.LoadEmbedded()
+	if err != nil {
+		embedded = &templates.Templates{}
+	}
+
+	color.Yellow("⚠ %d missing skill(s)", len(missing))
+	var copyable []string
+	for _, skill := range missing {
+		agents := orphans[skill]
+		sort.Strings(agents)
+		note := ""
+		if _, ok := embedded.Skills[skill]; ok {
+			copyable = append(copyable, skill)
+		} else {
+			note = " - not in the embedded templates either"
+		}
+		fmt.Printf("  %s, referenced by %s%s\n", skill, strings.Join(agents, ", "), note)
+	}
+
+	if !fix || len(copyable) == 0 {
+		if len(copyable) > 0 {
+			fmt.Println("  Run 'agen doctor --fix' to copy them from the embedded templates")
+		}
+		return len(missing), 0
+	}
+	if !confirm(fmt.Sprintf("  Copy %d missing skill(s) from the embedded templates?", len(copyable))) {
+		return len(missing), 0
+	}
+
+	for _, skill := range copyable {
+		tmpl.Skills[skill] = embedded.Skills[skill]
+	}
+	// everything else in the set is what's installed, so forcing only
+	// writes the new skills (or re-renders a single-file format)
+	if _, err := adapter.Update(tmpl, ide.UpdateOptions{TargetDir: projectDir, Force: true}); err != nil {
+		fmt.Printf("  Failed to install skills: %v\n", err)
+		return len(missing), 0
+	}
+	green.Printf("  ✓ Installed %s\n", strings.Join(copyable, ", "))
+	return len(missing), len(copyable)
+}
+
+// doctorNetworkTimeout keeps doctor quick when the network is down
+const doctorNetworkTimeout = 5 * time.Second
+
+// checkNetwork tells whether agen update can reach GitHub: it's
+// reachable (with latency and rate limit left), reachable but out of API
+// requests, or unreachable. It returns the number of issues found; none
+// of them are something --fix can repair.
+func checkNetwork(fix bool) int {
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+
+	fmt.Print("Checking network connectivity... ")
+	conn, err := updater.CheckConnectivity(doctorNetworkTimeout)
+
+	var rateErr *httpclient.RateLimitError
+	switch {
+	case err == nil:
+		green.Println("✓ OK")
+		fmt.Printf("  %s reachable in %s\n", conn.Host, conn.Latency.Round(time.Millisecond))
+		if conn.Limit > 0 {
+			auth := "unauthenticated"
+			if conn.Authenticated {
+				auth = "authenticated"
+			}
+			fmt.Printf("  Rate limit: %d/%d requests left (%s)\n", conn.Remaining, conn.Limit, auth)
+		}
+		return 0
+
+	case errors.As(err, &rateErr):
+		color.Yellow("⚠ RATE LIMITED")
+		fmt.Printf("  %s reachable in %s, but: %v\n", conn.Host, conn.Latency.Round(time.Millisecond), err)
+		fmt.Println("  agen update and template fetches will fail until the limit resets")
+
+	case conn != nil:
+		red.Println("❌ FAILED")
+		fmt.Printf("  %s reachable in %s, but: %v\n", conn.Host, conn.Latency.Round(time.Millisecond), err)
+
+	default:
+		red.Println("❌ UNREACHABLE")
+		fmt.Printf("  Error: %v\n", err)
+		fmt.Println("  agen update and template fetches will fail; embedded templates still work offline")
+		fmt.Println("  Check your connection, proxy (HTTPS_PROXY) or firewall")
+	}
+
+	if fix {
+		fmt.Println("  (network problems can't be fixed automatically)")
+	}
+	return 1
+}
+
+// checkStateFiles validates every JSON file AGEN keeps in the config dir.
+//
+// How it works:
+//  1. Try to parse remotes, aliases.json and each profile (JSON or YAML)
+//  2. Ask the plugin package whether registry.json parses
+//  3. With --fix, back up each broken file to *.bak, then reset it
+//     (remotes/aliases become empty, broken profiles are moved aside,
+//     and the plugin registry is rebuilt from the plugins directory)
+//
+// Why? A single malformed file takes its whole command down with a
+// JSON error, and users rarely know where these files live.
+func checkStateFiles(fix bool) (issues, fixed int) {
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+
+	// resetFile backs up a broken file and writes fresh content in its
+	// place. Returns where the backup went, "" if the reset failed.
+	resetFile := func(path string, fresh []byte) string {
+		backup, err := backupStateFile(path)
+		if err != nil {
+			fmt.Printf("  Backup failed: %v\n", err)
+			return ""
+		}
+		if fresh != nil {
+			if err := os.WriteFile(path, fresh, 0644); err != nil {
+				fmt.Printf("  Reset failed: %v\n", err)
+				return ""
+			}
+		}
+		return backup
+	}
+
+	// Remotes
+	fmt.Print("Checking remotes... ")
+	if _, err := loadRemotes(); err != nil {
+		red.Println("❌ CORRUPT")
+		fmt.Printf("  Error: %v\n", err)
+		issues++
+		if path, perr := getRemotesPath(); fix && perr == nil {
+			if backup := resetFile(path, []byte("[]\n")); backup != "" {
+				green.Printf("  ✓ Reset %s; the old file is in %s\n", filepath.Base(path), backup)
+				fixed++
+			}
+		}
+	} else {
+		green.Println("✓ OK")
+	}
+
+	// Aliases
+	fmt.Print("Checking aliases... ")
+	if _, err := loadAliases(); err != nil {
+		red.Println("❌ CORRUPT")
+		fmt.Printf("  Error: %v\n", err)
+		issues++
+		if path, perr := getAliasPath(); fix && perr == nil {
+			if backup := resetFile(path, []byte("{}\n")); backup != "" {
+				green.Printf("  ✓ Reset aliases.json; the old file is in %s\n", backup)
+				fixed++
+			}
+		}
+	} else {
+		green.Println("✓ OK")
+	}
+
+	// Profiles - each file is independent, so only the broken ones move
+	fmt.Print("Checking profiles... ")
+	var badProfiles []string
+	if profilesDir, err := getProfilesDir(); err == nil {
+		entries, _ := os.ReadDir(profilesDir)
+		for _, e := range entries {
+			if e.IsDir() || (filepath.Ext(e.Name()) != ".json" && !config.IsYAML(e.Name())) {
+				continue
+			}
+			path := filepath.Join(profilesDir, e.Name())
+			data, err := os.ReadFile(path)
+			var profile Profile
+			if err == nil {
+				err = config.Unmarshal(path, data, &profile)
+			}
+			if err != nil {
+				badProfiles = append(badProfiles, path)
+			}
+		}
+	}
+	if len(badProfiles) == 0 {
+		green.Println("✓ OK")
+	} else {
+		red.Printf("❌ %d CORRUPT\n", len(badProfiles))
+		for _, path := range badProfiles {
+			fmt.Printf("  %s\n", path)
+			issues++
+			if !fix {
+				continue
+			}
+			if backup := resetFile(path, nil); backup != "" {
+				green.Printf("  ✓ Moved aside: %s\n", backup)
+				fixed++
+			}
+		}
+	}
+
+	// Plugin registry
+	fmt.Print("Checking plugin registry... ")
+	if path, err := plugin.CheckRegistry(); err != nil {
+		red.Println("❌ CORRUPT")
+		fmt.Printf("  Error: %v\n", err)
+		issues++
+		if fix {
+			if backup := resetFile(path, nil); backup != "" {
+				if manager, err := plugin.NewManager(); err == nil {
+					if count, err := manager.RebuildRegistry(); err == nil {
+						green.Printf("  ✓ Rebuilt registry (%d plugin(s)); the old file is in %s\n", count, backup)
+						fixed++
+					}
+				}
+			}
+		}
+	} else {
+		green.Println("✓ OK")
+	}
+
+	return issues, fixed
+}
+
+// backupStateFile moves path to path.bak so a reset never loses data.
+// Earlier backups are kept: if path.bak exists the file goes to
+// path.bak.1, then path.bak.2 and so on. Returns the backup's path.
+func backupStateFile(path string) (string, error) {
+	backup := path + ".bak"
+	for n := 1; ; n++ {
+		if _, err := os.Lstat(backup); os.IsNotExist(err) {
+			break
+		} else if err != nil {
+			return "", err
+		}
+		backup = fmt.Sprintf("%s.bak.%d", path, n)
+	}
+
+	if err := os.Rename(path, backup); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// cleanTarget is one path agen clean would remove
+type cleanTarget struct {
+	Path   string
+	Size   int64
+	Temp   bool   // an agen-* temp entry rather than the template cache
+	Orphan bool   // a plugin directory the registry doesn't know
+	Plugin string // a registered plugin, removed with Uninstall
+}
+
+// safeCleanPath refuses to remove anything that isn't strictly inside root.
+//
+// The cache and temp roots come from the environment (XDG_CACHE_HOME,
+// TMPDIR), so a bad value could point them somewhere we must never
+// RemoveAll. The filesystem root and the home directory are always refused,
+// as is root itself.
+func safeCleanPath(path, root string) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+
+	if path == filepath.VolumeName(path)+string(filepath.Separator) {
+		return fmt.Errorf("refusing to remove filesystem root %s", path)
+	}
+	if home, err := os.UserHomeDir(); err == nil && path == filepath.Clean(home) {
+		return fmt.Errorf("refusing to remove home directory %s", path)
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("refusing to remove %s: not inside %s", path, root)
+	}
+	return nil
+}
+
+// confirm asks a yes/no question on stdin. Anything but y/yes, including
+// EOF when stdin isn't a terminal, counts as no.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
 // runClean removes cached and temporary files
+//
+// How it works:
+//  1. Collect the template cache and agen-* temp entries, with sizes,
+//     and with --plugins the plugin directories to prune
+//  2. Check each one is inside its expected root (see safeCleanPath)
+//  3. List them; stop there with --dry-run
+//  4. Ask for confirmation unless --yes, then remove
 func runClean(cmd *cobra.Command, args []string) error {
 	cacheOnly, _ := cmd.Flags().GetBool("cache")
 	tempOnly, _ := cmd.Flags().GetBool("temp")
+	pluginsOnly, _ := cmd.Flags().GetBool("plugins")
 	cleanAll, _ := cmd.Flags().GetBool("all")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	// registered plugins are only removed when asked for in so many words
+	pruneRegistered := pluginsOnly && cleanAll
 
-	if !cacheOnly && !tempOnly {
+	if !cacheOnly && !tempOnly && !pluginsOnly {
 		cleanAll = true
 	}
 
 	cyan := color.New(color.FgCyan, color.Bold)
 	cyan.Println("\n🧹 AGEN Clean")
 
-	totalCleaned := int64(0)
+	var targets []cleanTarget
+	tempCount := 0
 
 	if cleanAll || cacheOnly {
 		cacheDir, err := os.UserCacheDir()
 		if err == nil {
 			agenCache := filepath.Join(cacheDir, "agen")
-			if size, err := getDirSize(agenCache); err == nil && size > 0 {
-				if err := os.RemoveAll(agenCache); err == nil {
-					printSuccess("Cleaned cache: %s (%s)", agenCache, formatBytes(size))
-					totalCleaned += size
+			var size int64
+			if dryRun {
+				// getCacheSize records what it measured in the cache,
+				// and a dry run mustn't write anything
+				size, _ = getDirSize(agenCache)
+			} else {
+				size = getCacheSize(agenCache)
+			}
+			if size > 0 {
+				if err := safeCleanPath(agenCache, cacheDir); err != nil {
+					return err
 				}
+				targets = append(targets, cleanTarget{Path: agenCache, Size: size})
 			} else {
 				printInfo("Cache already clean")
 			}
@@ -246,37 +703,171 @@ func runClean(cmd *cobra.Command, args []string) error {
 		pattern := filepath.Join(tempDir, "agen-*")
 		matches, _ := filepath.Glob(pattern)
 		for _, match := range matches {
-			if size, err := getDirSize(match); err == nil {
-				os.RemoveAll(match)
-				totalCleaned += size
+			size, err := getDirSize(match)
+			if err != nil {
+				continue
 			}
+			if err := safeCleanPath(match, tempDir); err != nil {
+				return err
+			}
+			targets = append(targets, cleanTarget{Path: match, Size: size, Temp: true})
+			tempCount++
 		}
-		if len(matches) > 0 {
-			printSuccess("Cleaned %d temp file(s)", len(matches))
-		} else {
+		if tempCount == 0 {
 			printInfo("No temp files to clean")
 		}
 	}
 
+	var mgr *plugin.Manager
+	if pluginsOnly {
+		var pluginTargets []cleanTarget
+		var err error
+		mgr, pluginTargets, err = pluginCleanTargets(pruneRegistered)
+		if err != nil {
+			printError("%v", err)
+			return err
+		}
+		if len(pluginTargets) == 0 {
+			printInfo("No orphaned plugin directories")
+		}
+		targets = append(targets, pluginTargets...)
+	}
+
+	if len(targets) == 0 {
+		fmt.Printf("\nTotal cleaned: %s\n", formatBytes(0))
+		return nil
+	}
+
+	total := int64(0)
+	fmt.Println()
+	for _, t := range targets {
+		fmt.Printf("  %s (%s)\n", t.Path, formatBytes(t.Size))
+		total += t.Size
+	}
+
+	if dryRun {
+		fmt.Printf("\nWould remove %d path(s), %s total. Nothing was deleted.\n", len(targets), formatBytes(total))
+		return nil
+	}
+
+	if !yes && !confirm(fmt.Sprintf("\nRemove %d path(s), %s?", len(targets), formatBytes(total))) {
+		printInfo("Aborted, nothing was deleted")
+		return nil
+	}
+
+	totalCleaned := int64(0)
+	removedTemp := 0
+	for _, t := range targets {
+		var err error
+		if t.Plugin != "" {
+			// Uninstall drops the registry entry; the files go below in
+			// case they aren't in the folder named after the plugin
+			if err = mgr.Uninstall(t.Plugin); err == nil {
+				err = os.RemoveAll(t.Path)
+			}
+		} else {
+			err = os.RemoveAll(t.Path)
+		}
+		if err != nil {
+			printWarning("Could not remove %s: %v", t.Path, err)
+			continue
+		}
+		totalCleaned += t.Size
+		switch {
+		case t.Temp:
+			removedTemp++
+		case t.Orphan:
+			printSuccess("Removed orphaned plugin directory: %s (%s)", t.Path, formatBytes(t.Size))
+		case t.Plugin != "":
+			printSuccess("Uninstalled plugin %s (%s)", t.Plugin, formatBytes(t.Size))
+		default:
+			printSuccess("Cleaned cache: %s (%s)", t.Path, formatBytes(t.Size))
+		}
+	}
+	if removedTemp > 0 {
+		printSuccess("Cleaned %d temp file(s)", removedTemp)
+	}
+
 	fmt.Printf("\nTotal cleaned: %s\n", formatBytes(totalCleaned))
 	return nil
 }
 
+// pluginCleanTargets lists the plugin directories agen clean --plugins
+// would remove: orphans (on disk, not in registry.json), and with
+// registered set every registered plugin stored in the plugins folder.
+// Plugins installed from a local path live in the user's own directory
+// and are never listed.
+//
+// A corrupt registry would make every plugin look orphaned, so it's
+// refused; doctor --fix rebuilds it first.
+func pluginCleanTargets(registered bool) (*plugin.Manager, []cleanTarget, error) {
+	registryPath, err := plugin.CheckRegistry()
+	if err != nil {
+		return nil, nil, fmt.Errorf("plugin registry is corrupt, not pruning plugins (run 'agen doctor --fix' first): %w", err)
+	}
+	// NewManager creates the plugins folder, which --dry-run mustn't do
+	// and there's no point in doing here
+	if _, err := os.Stat(filepath.Dir(registryPath)); os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+
+	mgr, err := plugin.NewManager()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open plugins: %w", err)
+	}
+
+	var targets []cleanTarget
+	orphans, err := mgr.Orphans()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list plugins: %w", err)
+	}
+	for _, path := range orphans {
+		if err := safeCleanPath(path, mgr.Dir()); err != nil {
+			return nil, nil, err
+		}
+		size, _ := getDirSize(path)
+		targets = append(targets, cleanTarget{Path: path, Size: size, Orphan: true})
+	}
+
+	if registered {
+		plugins := mgr.List()
+		sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+		for _, p := range plugins {
+			path := mgr.Path(p)
+			if safeCleanPath(path, mgr.Dir()) != nil {
+				continue // a local plugin, not ours to delete
+			}
+			size, _ := getDirSize(path)
+			targets = append(targets, cleanTarget{Path: path, Size: size, Plugin: p.Name})
+		}
+	}
+
+	return mgr, targets, nil
+}
+
 // runStats shows usage statistics
 func runStats(cmd *cobra.Command, args []string) error {
-	jsonOutput, _ := cmd.Flags().GetBool("json")
+	jsonOutput := wantJSON(cmd)
+
+	if cmd.Flags().Changed("project") {
+		projectDir, _ := cmd.Flags().GetString("project")
+		if len(args) > 0 {
+			projectDir = args[0]
+		}
+		return runProjectStats(projectDir, jsonOutput)
+	}
 
 	cyan := color.New(color.FgCyan, color.Bold)
 
 	stats := struct {
-		Version       string    `json:"version"`
-		Platform      string    `json:"platform"`
-		AgentCount    int       `json:"agent_count"`
-		SkillCount    int       `json:"skill_count"`
-		WorkflowCount int       `json:"workflow_count"`
-		CacheSize     int64     `json:"cache_size_bytes"`
-		ConfigExists  bool      `json:"config_exists"`
-		LastUsed      time.Time `json:"last_used"`
+		Version       string `json:"version"`
+		Platform      string `json:"platform"`
+		AgentCount    int    `json:"agent_count"`
+		SkillCount    int    `json:"skill_count"`
+		WorkflowCount int    `json:"workflow_count"`
+		CacheSize     int64  `json:"cache_size_bytes"`
+		ConfigExists  bool   `json:"config_exists"`
+		usageStats
 	}{
 		Version:  Version,
 		Platform: fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
@@ -292,7 +883,7 @@ func runStats(cmd *cobra.Command, args []string) error {
 	// check cache size
 	if cacheDir, err := os.UserCacheDir(); err == nil {
 		agenCache := filepath.Join(cacheDir, "agen")
-		stats.CacheSize, _ = getDirSize(agenCache)
+		stats.CacheSize = getCacheSize(agenCache)
 	}
 
 	// check config
@@ -301,12 +892,10 @@ func runStats(cmd *cobra.Command, args []string) error {
 		stats.ConfigExists = err == nil
 	}
 
-	stats.LastUsed = time.Now()
+	stats.usageStats = loadUsageStats()
 
 	if jsonOutput {
-		data, _ := json.MarshalIndent(stats, "", "  ")
-		fmt.Println(string(data))
-		return nil
+		return printJSON(stats)
 	}
 
 	cyan.Println("\n📊 AGEN Statistics")
@@ -321,78 +910,383 @@ func runStats(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Cache Size: %s\n", formatBytes(stats.CacheSize))
 	fmt.Printf("Config:     %v\n", stats.ConfigExists)
 	fmt.Println()
+	printUsageStats(stats.usageStats)
 
 	return nil
 }
 
-// runChangelog displays version changelog
+// usageCount is a name with how often it showed up
+type usageCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// usageStats is what 'agen stats' reads out of the command history
+type usageStats struct {
+	HistoryEnabled bool         `json:"history_enabled"`
+	Commands       int          `json:"commands_run"`
+	Projects       int          `json:"projects"`
+	TopCommands    []usageCount `json:"top_commands"`
+	TopAgents      []usageCount `json:"top_agents"`
+	FirstUsed      *time.Time   `json:"first_used,omitempty"`
+	LastUsed       *time.Time   `json:"last_used,omitempty"`
+}
+
+// topUsageCount is how many commands and agents stats lists
+const topUsageCount = 5
+
+// loadUsageStats summarizes the command history.
+//
+// How it works:
+//  1. Count commands by name, and take the first and last timestamps
+//  2. Every distinct directory a command ran in is a candidate project;
+//     the ones that still have an AGEN installation are the known projects
+//  3. Count each installed agent once per known project, so the top
+//     agents are the ones installed most widely, not the most re-run
+//
+// The current command isn't in the history yet, so LastUsed is the run
+// before it.
+func loadUsageStats() usageStats {
+	stats := usageStats{HistoryEnabled: usageHistoryEnabled()}
+
+	entries, err := config.LoadHistory()
+	if err != nil || len(entries) == 0 {
+		return stats
+	}
+
+	commands := make(map[string]int)
+	dirs := make(map[string]bool)
+	for _, entry := range entries {
+		commands[entry.Command]++
+		if entry.Dir != "" {
+			dirs[entry.Dir] = true
+		}
+	}
+	stats.Commands = len(entries)
+	stats.FirstUsed = &entries[0].Time
+	stats.LastUsed = &entries[len(entries)-1].Time
+	stats.TopCommands = topUsage(commands)
+
+	agents := make(map[string]int)
+	for dir := range dirs {
+		adapter := ide.Detect(dir)
+		if adapter == nil {
+			continue
+		}
+		installed, err := installedTemplates(dir, adapter)
+		if err != nil {
+			continue
+		}
+		stats.Projects++
+		for name := range installed.Agents {
+			agents[name]++
+		}
+	}
+	stats.TopAgents = topUsage(agents)
+
+	return stats
+}
+
+// topUsage returns the topUsageCount most frequent names, ties broken
+// by name
+func topUsage(counts map[string]int) []usageCount {
+	top := make([]usageCount, 0, len(counts))
+	for name, count := range counts {
+		top = append(top, usageCount{Name: name, Count: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Name < top[j].Name
+	})
+	if len(top) > topUsageCount {
+		top = top[:topUsageCount]
+	}
+	return top
+}
+
+// printUsageStats prints the history section of 'agen stats'
+func printUsageStats(stats usageStats) {
+	if stats.Commands == 0 {
+		if stats.HistoryEnabled {
+			printInfo("No usage recorded yet")
+		} else {
+			printInfo("Usage history is off (record_usage or DO_NOT_TRACK)")
+		}
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("Commands:   %d run\n", stats.Commands)
+	fmt.Printf("Projects:   %d\n", stats.Projects)
+	fmt.Printf("First used: %s\n", stats.FirstUsed.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("Last used:  %s\n", stats.LastUsed.Local().Format("2006-01-02 15:04"))
+	if !stats.HistoryEnabled {
+		printInfo("Usage history is off; these numbers are no longer updated")
+	}
+	fmt.Println()
+
+	fmt.Println("Most used commands:")
+	for _, c := range stats.TopCommands {
+		fmt.Printf("  %-20s %d\n", c.Name, c.Count)
+	}
+	fmt.Println()
+
+	if len(stats.TopAgents) > 0 {
+		fmt.Println("Most installed agents:")
+		for _, a := range stats.TopAgents {
+			fmt.Printf("  %-20s %d\n", a.Name, a.Count)
+		}
+		fmt.Println()
+	}
+}
+
+// projectStats is the per-project summary shown by 'stats --project'
+type projectStats struct {
+	Path          string     `json:"path"`
+	IDE           string     `json:"ide"`
+	ProjectType   string     `json:"project_type"`
+	Version       string     `json:"version"`
+	UpToDate      bool       `json:"up_to_date"`
+	AgentCount    int        `json:"agent_count"`
+	SkillCount    int        `json:"skill_count"`
+	WorkflowCount int        `json:"workflow_count"`
+	ModifiedFiles int        `json:"modified_files"`
+	HealthScore   int        `json:"health_score"`
+	LastUpdated   *time.Time `json:"last_updated,omitempty"`
+}
+
+// runProjectStats prints installed-template metrics for a single project.
+// It's the numbers from 'agen health' without the narrative.
+func runProjectStats(dir string, jsonOutput bool) error {
+	absPath, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", absPath)
+	}
+
+	adapter := ide.Detect(absPath)
+	if adapter == nil {
+		return fmt.Errorf("no AGEN installation found in %s", absPath)
+	}
+
+	installed, err := ide.GetInstalledInfo(absPath, adapter)
+	if err != nil {
+		return fmt.Errorf("failed to read installed templates: %w", err)
+	}
+
+	stats := projectStats{
+		Path:          absPath,
+		IDE:           adapter.Name(),
+		ProjectType:   analyzeProjectType(absPath),
+		Version:       installed.Version,
+		UpToDate:      !latestOutdated(installed),
+		AgentCount:    installed.AgentCount,
+		SkillCount:    installed.SkillCount,
+		WorkflowCount: installed.WorkflowCount,
+		ModifiedFiles: installed.ModifiedFiles,
+	}
+
+	recommendations := getRecommendedAgents(stats.ProjectType)
+	if cfg, err := config.LoadForProject(absPath); err == nil {
+		recommendations = mergeConfiguredAgents(recommendations, cfg.DefaultAgents)
+	}
+	stats.HealthScore = calculateHealthScore(installed, recommendations)
+
+	// the rules file is rewritten on every init/update; folder-based
+	// adapters may not have one, so fall back to the top-level folder
+	rulesPath := filepath.FromSlash(adapter.GetRulesPath())
+	for _, p := range []string{rulesPath, strings.SplitN(rulesPath, string(filepath.Separator), 2)[0]} {
+		if info, err := os.Stat(filepath.Join(absPath, p)); err == nil {
+			modTime := info.ModTime()
+			stats.LastUpdated = &modTime
+			break
+		}
+	}
+
+	if jsonOutput {
+		return printJSON(stats)
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Println("\n📊 Project Statistics")
+	fmt.Println()
+	fmt.Printf("Path:       %s\n", stats.Path)
+	fmt.Printf("IDE:        %s\n", stats.IDE)
+	fmt.Printf("Type:       %s\n", stats.ProjectType)
+	fmt.Println()
+	fmt.Printf("Agents:     %d\n", stats.AgentCount)
+	fmt.Printf("Skills:     %d\n", stats.SkillCount)
+	fmt.Printf("Workflows:  %d\n", stats.WorkflowCount)
+	fmt.Println()
+	if stats.UpToDate {
+		fmt.Printf("Version:    %s (up to date)\n", stats.Version)
+	} else {
+		fmt.Printf("Version:    %s (latest %s)\n", stats.Version, templates.GetLatestVersion())
+	}
+	fmt.Printf("Modified:   %d file(s)\n", stats.ModifiedFiles)
+	fmt.Printf("Health:     %d/100\n", stats.HealthScore)
+	if stats.LastUpdated != nil {
+		fmt.Printf("Updated:    %s\n", stats.LastUpdated.Format("2006-01-02 15:04"))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// runChangelog prints one release's section of the embedded CHANGELOG.md:
+// the requested version, or the newest when none is given
 func runChangelog(cmd *cobra.Command, args []string) error {
-	version := Version
+	releases := changelog.Parse(agen.Changelog)
+	if len(releases) == 0 {
+		printError("The embedded changelog has no releases")
+		return fmt.Errorf("no releases in changelog")
+	}
+
+	release := releases[0]
 	if len(args) > 0 {
-		version = args[0]
+		var ok bool
+		if release, ok = changelog.Find(releases, args[0]); !ok {
+			printError("No changelog entry for %s", args[0])
+			fmt.Printf("Available versions: %s\n", strings.Join(changelog.Versions(releases), ", "))
+			return fmt.Errorf("unknown version: %s", args[0])
+		}
 	}
 
 	cyan := color.New(color.FgCyan, color.Bold)
-	cyan.Printf("\n📝 Changelog for %s\n\n", version)
-
-	// Embedded changelog for current version
-	changelog := `## v2.0.0 (2026-01-29)
-
-### 🚀 Major Changes
-- Production-grade refactor: removed all demo/toy behavior
-- Expanded to 12 IDE support (added JetBrains, Neovim, Emacs, Aider, Claude Code, Copilot Workspace, Cline, Continue)
-- 30 agents, 46 skills, 16 workflows
-- Enhanced verification system with security scanning
-- Improved health dashboard with agent recommendations
-
-### ✨ New Features
-- Team collaboration with agen.team.json
-- Plugin system for custom templates
-- Remote repository management
-- Alias command support
-- Compose custom agents
-- Interactive agent creation wizard
-
-### 🔧 Technical
-- Strict error handling (fail loudly, no silent fallbacks)
-- Explicit configuration required (no magic defaults)
-- Enhanced test coverage
-
----
-
-## v1.0.0 (2026-01-28)
-
-### ✨ Features
-- Initial release of AGEN CLI
-- Support for 4 IDEs: Antigravity, Cursor, Windsurf, Zed
-- 20 embedded agents from antigravity-kit
-- 36 skills covering frontend, backend, security, and more
-- 11 workflow slash commands
-- Interactive TUI wizard for setup
-- Project health dashboard
-- Fuzzy search across agents/skills
-- Self-update mechanism
-- Go-based verification scripts
-`
-
-	fmt.Println(changelog)
+	if release.Date != "" {
+		cyan.Printf("\n📝 Changelog for v%s (%s)\n\n", release.Version, release.Date)
+	} else {
+		cyan.Printf("\n📝 Changelog for v%s\n\n", release.Version)
+	}
+
+	fmt.Println(release.Body)
+	fmt.Println()
 	return nil
 }
 
 // Helper functions
 
+// formatSources renders Templates.Sources counts as "cache: 3, embedded: 80"
+func formatSources(counts map[string]int) string {
+	sources := make([]string, 0, len(counts))
+	for source := range counts {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	parts := make([]string, len(sources))
+	for i, source := range sources {
+		parts[i] = fmt.Sprintf("%s: %d", source, counts[source])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// countChanged counts the templates in other whose content differs from
+// (or is missing in) base
+func countChanged(base, other *templates.Templates) int {
+	changed := 0
+	for name, agent := range other.Agents {
+		if base.Agents[name].Content != agent.Content {
+			changed++
+		}
+	}
+	for name, skill := range other.Skills {
+		if base.Skills[name].Content != skill.Content {
+			changed++
+		}
+	}
+	for name, workflow := range other.Workflows {
+		if base.Workflows[name].Content != workflow.Content {
+			changed++
+		}
+	}
+	return changed
+}
+
+// getDirSize returns the total size of the files under path.
+//
+// Subdirectories are walked in parallel, up to one goroutine per CPU; once
+// those are busy the current goroutine walks them itself, so deep trees
+// can't block. Unreadable entries are skipped, and a missing path is
+// simply empty. Symlinks are counted as links, not followed.
 func getDirSize(path string) (int64, error) {
-	var size int64
-	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, nil // ignore errors
+	}
+	if !info.IsDir() {
+		return info.Size(), nil
+	}
+
+	var total atomic.Int64
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.NumCPU())
+
+	var walk func(dir string)
+	walk = func(dir string) {
+		entries, err := os.ReadDir(dir)
 		if err != nil {
-			return nil // ignore errors
+			return
 		}
-		if !info.IsDir() {
-			size += info.Size()
+
+		var size int64
+		for _, entry := range entries {
+			entryPath := filepath.Join(dir, entry.Name())
+			if entry.IsDir() {
+				select {
+				case sem <- struct{}{}:
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						defer func() { <-sem }()
+						walk(entryPath)
+					}()
+				default:
+					walk(entryPath)
+				}
+				continue
+			}
+			if info, err := entry.Info(); err == nil {
+				size += info.Size()
+			}
 		}
-		return nil
-	})
-	return size, err
+		total.Add(size)
+	}
+
+	walk(path)
+	wg.Wait()
+	return total.Load(), nil
+}
+
+// cacheSizeRecord is the content of templates.CacheSizeFile
+type cacheSizeRecord struct {
+	Bytes int64 `json:"bytes"`
+}
+
+// getCacheSize returns the size of the AGEN cache, reusing the size
+// recorded by an earlier run. Cache writes delete the record, so it is
+// only recomputed (and saved again) after the cache actually changed.
+func getCacheSize(cacheDir string) int64 {
+	sizeFile := filepath.Join(cacheDir, templates.CacheSizeFile)
+	if data, err := os.ReadFile(sizeFile); err == nil {
+		var record cacheSizeRecord
+		if json.Unmarshal(data, &record) == nil {
+			return record.Bytes
+		}
+	}
+
+	size, _ := getDirSize(cacheDir)
+	if size > 0 {
+		if data, err := json.Marshal(cacheSizeRecord{Bytes: size}); err == nil {
+			os.WriteFile(sizeFile, data, 0644)
+		}
+	}
+	return size
 }
 
 func formatBytes(bytes int64) string {
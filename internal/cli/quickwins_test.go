@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for doctor's state file repairs
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupStateFileKeepsEarlierBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	want := []string{path + ".bak", path + ".bak.1", path + ".bak.2"}
+	for i, wantBackup := range want {
+		content := []byte{'0' + byte(i)}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		backup, err := backupStateFile(path)
+		if err != nil {
+			t.Fatalf("backupStateFile() failed: %v", err)
+		}
+		if backup != wantBackup {
+			t.Errorf("backup %d went to %s, want %s", i, backup, wantBackup)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should have been moved", path)
+		}
+	}
+
+	// every corruption is still there
+	for i, backup := range want {
+		if data, _ := os.ReadFile(backup); string(data) != string(rune('0'+i)) {
+			t.Errorf("%s = %q, want backup %d", backup, data, i)
+		}
+	}
+}
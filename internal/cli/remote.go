@@ -2,16 +2,21 @@
 // Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
 //
 // AGEN - AI Agent Template Manager
-// Remote repository management and hub commands
+// Remote repository management
 
 package cli
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 
+	"github.com/eshanized/agen/internal/config"
+	"github.com/eshanized/agen/internal/hub"
+	"github.com/eshanized/agen/internal/templates"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -30,12 +35,17 @@ var remoteCmd = &cobra.Command{
 	Short: "Manage remote repositories",
 	Long: `Manage remote template repositories.
 
-Add custom sources for agents, skills, and workflows.
+Add custom sources for agents, skills, and workflows. A remote is a
+GitHub repository or a link to a .zip, with agents/, skills/ and
+workflows/ at its top, under templates/, or where this repository keeps
+them. Install from remotes with 'agen update --remote <name>'.
 
 Examples:
   agen remote add company https://github.com/company/agents
   agen remote list
-  agen remote remove company`,
+  agen remote remove company
+  agen remote test company
+  agen update --remote company`,
 }
 
 var remoteAddCmd = &cobra.Command{
@@ -58,7 +68,18 @@ var remoteRemoveCmd = &cobra.Command{
 	RunE:  runRemoteRemove,
 }
 
-// Hub commands removed due to being mock/stubs
+var remoteTestCmd = &cobra.Command{
+	Use:   "test <name>",
+	Short: "Check that a remote is reachable and holds templates",
+	Long: `Check that a remote is reachable and holds templates.
+
+Downloads the remote's archive, as 'agen update --remote' would, and
+reports how many agents, skills and workflows it has. Failed downloads
+are explained: a 404 usually means a wrong URL or branch, or a private
+repository. An http remote that isn't a .zip is checked as a hub instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRemoteTest,
+}
 
 func init() {
 	remoteAddCmd.Flags().String("branch", "main", "branch to use")
@@ -67,16 +88,19 @@ func init() {
 	remoteCmd.AddCommand(remoteAddCmd)
 	remoteCmd.AddCommand(remoteListCmd)
 	remoteCmd.AddCommand(remoteRemoveCmd)
+	remoteCmd.AddCommand(remoteTestCmd)
 
 	rootCmd.AddCommand(remoteCmd)
 }
 
+// getRemotesPath returns remotes.json, or remotes.yaml/.yml if that's
+// what the user keeps instead
 func getRemotesPath() (string, error) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(configDir, "agen", "remotes.json"), nil
+	return config.FindFile(filepath.Join(configDir, "agen", "remotes")), nil
 }
 
 func loadRemotes() ([]RemoteRepo, error) {
@@ -94,7 +118,7 @@ func loadRemotes() ([]RemoteRepo, error) {
 	}
 
 	var remotes []RemoteRepo
-	if err := json.Unmarshal(data, &remotes); err != nil {
+	if err := config.Unmarshal(path, data, &remotes); err != nil {
 		return nil, err
 	}
 
@@ -111,12 +135,7 @@ func saveRemotes(remotes []RemoteRepo) error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(remotes, "", "  ")
-	if err != nil {
-		return err
-	}
-
-	return os.WriteFile(path, data, 0644)
+	return config.WriteFile(path, remotes)
 }
 
 func runRemoteAdd(cmd *cobra.Command, args []string) error {
@@ -209,3 +228,154 @@ func runRemoteRemove(cmd *cobra.Command, args []string) error {
 	printSuccess("Removed remote: %s", name)
 	return nil
 }
+
+// runRemoteTest fetches a remote the way update would and reports what it
+// found, or why it couldn't
+func runRemoteTest(cmd *cobra.Command, args []string) error {
+	remotes, err := loadRemotes()
+	if err != nil {
+		return err
+	}
+	r, ok := findRemote(remotes, args[0])
+	if !ok {
+		err := fmt.Errorf("remote not found: %s", args[0])
+		printError("%v", err)
+		return err
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("\n🌐 Testing remote %s\n\n", r.Name)
+	fmt.Printf("URL: %s\n", r.URL)
+
+	archiveURL, err := templates.RemoteArchiveURL(r.URL, r.Branch)
+	if err != nil {
+		if r.Type == "http" {
+			return testHubRemote(r)
+		}
+		printError("%v", err)
+		return err
+	}
+	if templates.IsGitHubRemote(r.URL) {
+		fmt.Printf("Branch: %s\n", remoteBranch(r))
+		fmt.Printf("Archive: %s\n", archiveURL)
+	}
+	fmt.Println()
+
+	tmpl, err := templates.FetchFromRemote(r.Name, r.URL, r.Branch)
+	if err != nil {
+		err = diagnoseRemoteError(r, err)
+		printError("%v", err)
+		return err
+	}
+
+	for _, kind := range []struct {
+		dir   string
+		count int
+	}{
+		{"agents/", len(tmpl.Agents)},
+		{"skills/", len(tmpl.Skills)},
+		{"workflows/", len(tmpl.Workflows)},
+	} {
+		if kind.count == 0 {
+			printWarning("%-11s missing or empty", kind.dir)
+		} else {
+			printSuccess("%-11s %d file(s)", kind.dir, kind.count)
+		}
+	}
+
+	fmt.Printf("\nInstall them with: agen update --remote %s\n", r.Name)
+	return nil
+}
+
+// testHubRemote checks an http remote that isn't an archive by asking it
+// for its package index, since hub commands are what use it
+func testHubRemote(r RemoteRepo) error {
+	fmt.Println("Type: http (used as a hub)")
+	fmt.Println()
+
+	ctx, cancel := context.WithTimeout(context.Background(), hubTimeout)
+	defer cancel()
+	pkgs, err := hub.New(r.URL).Search(ctx, "")
+	if err != nil {
+		err = fmt.Errorf("%s does not answer as a hub: %w", r.URL, err)
+		printError("%v", err)
+		return err
+	}
+
+	printSuccess("Hub answered with %d package(s)", len(pkgs))
+	printInfo("Only GitHub repositories and .zip URLs can serve templates to 'agen update --remote'")
+	return nil
+}
+
+// diagnoseRemoteError turns a failed remote download into advice. GitHub
+// answers 404, not 401, when an anonymous client asks for a private
+// repository, so a 404 names both causes.
+func diagnoseRemoteError(r RemoteRepo, err error) error {
+	var statusErr *templates.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+
+	switch statusErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("access denied (HTTP %d) for %s: the archive needs credentials, and remotes are downloaded anonymously",
+			statusErr.StatusCode, statusErr.URL)
+	case http.StatusNotFound:
+		if templates.IsGitHubRemote(r.URL) {
+			return fmt.Errorf("not found (HTTP 404) for %s: check the repository URL and that branch %q exists; private repositories also answer 404",
+				statusErr.URL, remoteBranch(r))
+		}
+		return fmt.Errorf("not found (HTTP 404) for %s: check the URL", statusErr.URL)
+	}
+	return err
+}
+
+// remoteBranch returns the branch a remote is fetched from
+func remoteBranch(r RemoteRepo) string {
+	if r.Branch == "" {
+		return "main"
+	}
+	return r.Branch
+}
+
+// findRemote returns the remote called name
+func findRemote(remotes []RemoteRepo, name string) (RemoteRepo, bool) {
+	for _, r := range remotes {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return RemoteRepo{}, false
+}
+
+// fetchRemoteTemplates fetches the named remotes and merges them in the
+// order given, so the first remote to define a template wins. Each
+// template a later remote also defines is returned as a Collision; any
+// remote that can't be fetched fails the whole set, since installing
+// only part of what the user asked for would be a silent surprise.
+func fetchRemoteTemplates(names []string) (*templates.Templates, []templates.Collision, error) {
+	remotes, err := loadRemotes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read remotes: %w", err)
+	}
+
+	var merged *templates.Templates
+	var collisions []templates.Collision
+	for _, name := range names {
+		r, ok := findRemote(remotes, name)
+		if !ok {
+			return nil, nil, fmt.Errorf("remote not found: %s (see 'agen remote list')", name)
+		}
+
+		tmpl, err := templates.FetchFromRemote(r.Name, r.URL, r.Branch)
+		if err != nil {
+			return nil, nil, err
+		}
+		if merged == nil {
+			merged = tmpl
+			continue
+		}
+		collisions = append(collisions, merged.Merge(tmpl)...)
+	}
+	return merged, collisions, nil
+}
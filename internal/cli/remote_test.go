@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for merging templates from several remotes
+
+package cli
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eshanized/agen/internal/templates"
+)
+
+// templateZip builds an archive with one directory holding the given
+// agents, each with a description naming the archive
+func templateZip(t *testing.T, from string, agents ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range agents {
+		w, err := zw.Create("repo/agents/" + name + ".md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte("---\ndescription: " + from + "\n---\n\n# " + name + "\n"))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetchRemoteTemplates(t *testing.T) {
+	archives := map[string][]byte{
+		"/company.zip": templateZip(t, "company", "reviewer", "deployer"),
+		"/shared.zip":  templateZip(t, "shared", "reviewer", "tester"),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if data, ok := archives[r.URL.Path]; ok {
+			w.Write(data)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	if err := saveRemotes([]RemoteRepo{
+		{Name: "shared", URL: srv.URL + "/shared.zip", Type: "http"},
+		{Name: "company", URL: srv.URL + "/company.zip", Type: "http"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, collisions, err := fetchRemoteTemplates([]string{"company", "shared"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(tmpl.AgentNames(), ","); got != "deployer,reviewer,tester" {
+		t.Errorf("agents = %s", got)
+	}
+	if got := tmpl.Agents["reviewer"]; got.Description != "company" || got.Source != templates.RemoteSource("company") {
+		t.Errorf("reviewer came from %s (%q), want the first remote given", got.Source, got.Description)
+	}
+	if len(collisions) != 1 || collisions[0].Name != "reviewer" || collisions[0].Ignored != templates.RemoteSource("shared") {
+		t.Errorf("collisions = %v", collisions)
+	}
+
+	if _, _, err := fetchRemoteTemplates([]string{"company", "nope"}); err == nil {
+		t.Error("fetchRemoteTemplates() with an unknown remote should fail")
+	}
+}
+
+func TestDiagnoseRemoteError(t *testing.T) {
+	github := RemoteRepo{Name: "company", URL: "https://github.com/company/agents", Branch: "stable"}
+	zipLink := RemoteRepo{Name: "mirror", URL: "https://example.com/templates.zip"}
+
+	tests := []struct {
+		remote RemoteRepo
+		err    error
+		want   string
+	}{
+		{github, &templates.StatusError{URL: "u", StatusCode: 404}, `branch "stable" exists; private repositories`},
+		{zipLink, &templates.StatusError{URL: "u", StatusCode: 404}, "check the URL"},
+		{zipLink, fmt.Errorf("remote mirror: %w", &templates.StatusError{URL: "u", StatusCode: 403}), "access denied (HTTP 403)"},
+		{github, fmt.Errorf("connection refused"), "connection refused"},
+	}
+	for _, tt := range tests {
+		if got := diagnoseRemoteError(tt.remote, tt.err).Error(); !strings.Contains(got, tt.want) {
+			t.Errorf("diagnoseRemoteError(%s, %v) = %q, want it to mention %q", tt.remote.Name, tt.err, got, tt.want)
+		}
+	}
+}
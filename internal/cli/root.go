@@ -9,7 +9,11 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/eshanized/agen/internal/config"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +25,9 @@ var (
 	BuildDate = "unknown"
 )
 
+// configFile holds the --config global flag value
+var configFile string
+
 // rootCmd is the base command when called without any subcommands.
 // Think of it as the "agen" command itself before any subcommand is added.
 var rootCmd = &cobra.Command{
@@ -43,6 +50,12 @@ For more info: https://github.com/eshanized/agen`,
 	SilenceUsage: true,
 	// We handle errors ourselves
 	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyOutputAlias(cmd); err != nil {
+			return err
+		}
+		return validateOutputFormat()
+	},
 }
 
 // Execute runs the root command. This is the main entry point called from main.go
@@ -64,6 +77,20 @@ func init() {
 	// Global flags that work on all commands
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "enable verbose output")
 	rootCmd.PersistentFlags().Bool("no-color", false, "disable colored output")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", outputText, "output format: text or json")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "use an alternate config file (env: "+config.ConfigEnvVar+")")
+	rootCmd.PersistentFlags().Bool("no-telemetry", false, "don't record this command in the local usage history")
+
+	// Runs only after a command succeeds; set here since recordUsage
+	// refers back to rootCmd
+	rootCmd.PersistentPostRun = recordUsage
+
+	// Point the config loader at --config before any command runs
+	cobra.OnInitialize(func() {
+		if configFile != "" {
+			config.SetConfigPath(configFile)
+		}
+	})
 
 	// Add version flag manually for better control
 	rootCmd.Version = Version
@@ -94,6 +121,52 @@ Built:      %s
 `, cyan("AGEN - AI Agent Template Manager"), Version, Commit, BuildDate)
 }
 
+// recordUsage appends the command that just ran to the local history
+// read by 'agen stats'. Only the command name, time and project
+// directory are kept, never arguments or flag values.
+//
+// Nothing is recorded with --no-telemetry, with DO_NOT_TRACK set, or
+// when record_usage is false in the config. Failures are ignored: the
+// history must never break a command.
+func recordUsage(cmd *cobra.Command, args []string) {
+	if cmd == rootCmd || cmd.Hidden || cmd.Name() == "help" || cmd.Name() == "completion" {
+		return
+	}
+	if off, _ := cmd.Flags().GetBool("no-telemetry"); off {
+		return
+	}
+	if !usageHistoryEnabled() {
+		return
+	}
+
+	// commands that take a project path get it as their first argument
+	dir := "."
+	if len(args) > 0 {
+		if info, err := os.Stat(args[0]); err == nil && info.IsDir() {
+			dir = args[0]
+		}
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	_ = config.RecordCommand(config.HistoryEntry{
+		Time:    time.Now(),
+		Command: strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" "),
+		Dir:     dir,
+	})
+}
+
+// usageHistoryEnabled reports whether the user allows the usage history:
+// record_usage isn't turned off and DO_NOT_TRACK isn't set
+func usageHistoryEnabled() bool {
+	if v := os.Getenv("DO_NOT_TRACK"); v != "" && v != "0" {
+		return false
+	}
+	cfg, err := config.Load()
+	return err == nil && cfg.RecordUsage
+}
+
 // checkVerbose is a helper to check if verbose mode is enabled.
 // Used throughout commands to show extra debug info
 func checkVerbose(cmd *cobra.Command) bool {
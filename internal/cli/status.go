@@ -46,8 +46,6 @@ Examples:
 //
 // NOTE: this doesn't make network requests by default. use --check-updates for that
 func runStatus(cmd *cobra.Command, args []string) error {
-	verbose := checkVerbose(cmd)
-
 	// determine target directory
 	targetDir := "."
 	if len(args) > 0 {
@@ -59,6 +57,10 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to resolve path: %w", err)
 	}
 
+	if wantJSON(cmd) {
+		return printJSON(buildStatusOutput(absPath))
+	}
+
 	cyan := color.New(color.FgCyan, color.Bold)
 	green := color.New(color.FgGreen)
 	yellow := color.New(color.FgYellow)
@@ -80,9 +82,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	// Step 2: Check installed templates
 	installed, err := ide.GetInstalledInfo(absPath, ideAdapter)
 	if err != nil {
-		if verbose {
-			printWarning("Could not read installed info: %v", err)
-		}
+		printWarning("Could not read installed info: %v", err)
 	}
 
 	if installed != nil {
@@ -109,3 +109,48 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// statusOutput is the status --output json document
+type statusOutput struct {
+	Directory     string   `json:"directory"`
+	IDE           string   `json:"ide,omitempty"`
+	Config        string   `json:"config,omitempty"`
+	Installed     bool     `json:"installed"`
+	Version       string   `json:"version,omitempty"`
+	AgentCount    int      `json:"agent_count"`
+	SkillCount    int      `json:"skill_count"`
+	WorkflowCount int      `json:"workflow_count"`
+	Agents        []string `json:"agents"`
+	Skills        []string `json:"skills"`
+	ModifiedFiles int      `json:"modified_files"`
+}
+
+// buildStatusOutput gathers the same information runStatus prints
+func buildStatusOutput(absPath string) statusOutput {
+	out := statusOutput{Directory: absPath, Agents: []string{}, Skills: []string{}}
+
+	ideAdapter := ide.Detect(absPath)
+	if ideAdapter == nil {
+		return out
+	}
+	out.IDE = ideAdapter.Name()
+	out.Config = ideAdapter.GetRulesPath()
+
+	installed, err := ide.GetInstalledInfo(absPath, ideAdapter)
+	if err != nil || installed == nil {
+		return out
+	}
+	out.Installed = true
+	out.Version = installed.Version
+	if installed.Agents != nil {
+		out.Agents = installed.Agents
+	}
+	if installed.Skills != nil {
+		out.Skills = installed.Skills
+	}
+	out.AgentCount = installed.AgentCount
+	out.SkillCount = installed.SkillCount
+	out.WorkflowCount = installed.WorkflowCount
+	out.ModifiedFiles = installed.ModifiedFiles
+	return out
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Machine-readable install/update reports
+
+package cli
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eshanized/agen/internal/config"
+	"github.com/eshanized/agen/internal/templates"
+)
+
+// fileChange is one file an init or update run touched
+type fileChange struct {
+	Path   string `json:"path"`   // relative to the project, slash-separated
+	Status string `json:"status"` // added, modified or removed
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// snapshotProject hashes the files an IDE adapter may write to.
+//
+// Every adapter writes either top-level files (.cursorrules, CLAUDE.md)
+// or into dot-directories (.agent/, .zed/, .github/), so that's all we
+// look at. Source trees, node_modules and .git are never walked, which
+// keeps this cheap on big projects.
+func snapshotProject(dir string) map[string]string {
+	snapshot := make(map[string]string)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return snapshot
+	}
+
+	add := func(path string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		rel, _ := filepath.Rel(dir, path)
+		snapshot[filepath.ToSlash(rel)] = templates.Hash(data)
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		switch {
+		case entry.Type().IsRegular():
+			add(path)
+		case entry.IsDir() && strings.HasPrefix(entry.Name(), ".") && entry.Name() != ".git":
+			filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+				if err == nil && d.Type().IsRegular() {
+					add(p)
+				}
+				return nil
+			})
+		}
+	}
+
+	return snapshot
+}
+
+// diffSnapshots lists the files that differ between two snapshots,
+// sorted by path
+func diffSnapshots(before, after map[string]string) []fileChange {
+	changes := []fileChange{}
+
+	for path, hash := range after {
+		old, existed := before[path]
+		switch {
+		case !existed:
+			changes = append(changes, fileChange{Path: path, Status: "added", SHA256: hash})
+		case old != hash:
+			changes = append(changes, fileChange{Path: path, Status: "modified", SHA256: hash})
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changes = append(changes, fileChange{Path: path, Status: "removed"})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// writeSummaryFile writes a report for --output-summary.
+// JSON unless the file name ends in .yaml/.yml.
+func writeSummaryFile(path string, summary interface{}) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return config.WriteFile(path, summary)
+}
@@ -9,8 +9,13 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/eshanized/agen/internal/ide"
 	"github.com/eshanized/agen/internal/team"
+	"github.com/eshanized/agen/internal/templates"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -84,7 +89,46 @@ var teamInfoCmd = &cobra.Command{
 	RunE:  runTeamInfo,
 }
 
+var teamMemberCmd = &cobra.Command{
+	Use:   "member",
+	Short: "Manage team members",
+	Long: `Manage the members recorded in the team config.
+
+Roles: owner, maintainer, contributor. The first member added becomes an
+owner unless --role says otherwise, and the last owner can't be removed.
+
+Examples:
+  agen team member add "Ada Lovelace" ada@example.com --role maintainer
+  agen team member list
+  agen team member remove ada@example.com`,
+}
+
+var teamMemberAddCmd = &cobra.Command{
+	Use:   "add <name> <email>",
+	Short: "Add a team member",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runTeamMemberAdd,
+}
+
+var teamMemberRemoveCmd = &cobra.Command{
+	Use:   "remove <email>",
+	Short: "Remove a team member",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTeamMemberRemove,
+}
+
+var teamMemberListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List team members",
+	Args:  cobra.NoArgs,
+	RunE:  runTeamMemberList,
+}
+
 func init() {
+	teamSyncCmd.Flags().Bool("dry-run", false, "show what would be installed without making changes")
+	teamSyncCmd.Flags().Bool("ignore-locks", false, "install locked templates from the current source instead of their locked version")
+	teamValidateCmd.Flags().Bool("ignore-locks", false, "compare locked templates with the current source instead of their locked version")
+
 	teamCmd.AddCommand(teamInitCmd)
 	teamCmd.AddCommand(teamSyncCmd)
 	teamCmd.AddCommand(teamValidateCmd)
@@ -93,6 +137,13 @@ func init() {
 	teamCmd.AddCommand(teamLockCmd)
 	teamCmd.AddCommand(teamInfoCmd)
 
+	teamMemberAddCmd.Flags().String("role", "", "owner, maintainer or contributor (default: owner for the first member, else contributor)")
+	teamMemberListCmd.Flags().Bool("json", false, "output as JSON")
+	teamMemberCmd.AddCommand(teamMemberAddCmd)
+	teamMemberCmd.AddCommand(teamMemberRemoveCmd)
+	teamMemberCmd.AddCommand(teamMemberListCmd)
+	teamCmd.AddCommand(teamMemberCmd)
+
 	rootCmd.AddCommand(teamCmd)
 }
 
@@ -106,11 +157,12 @@ func runTeamInit(cmd *cobra.Command, args []string) error {
 	}
 
 	printSuccess("Initialized team config: %s", name)
-	fmt.Printf("Config file: %s/.agen-team.json\n", cwd)
+	configPath := team.ConfigPath(cwd)
+	fmt.Printf("Config file: %s\n", configPath)
 	fmt.Println("\nNext steps:")
 	fmt.Println("  1. Add required agents: agen team add agent <name>")
 	fmt.Println("  2. Add required skills: agen team add skill <name>")
-	fmt.Println("  3. Commit .agen-team.json to version control")
+	fmt.Printf("  3. Commit %s to version control\n", filepath.Base(configPath))
 
 	_ = config
 	return nil
@@ -118,17 +170,50 @@ func runTeamInit(cmd *cobra.Command, args []string) error {
 
 func runTeamSync(cmd *cobra.Command, args []string) error {
 	cwd, _ := os.Getwd()
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	ignoreLocks, _ := cmd.Flags().GetBool("ignore-locks")
 
 	config, err := team.LoadTeamConfig(cwd)
 	if err != nil {
 		return err
 	}
 
+	adapter := ide.Detect(cwd)
+	if adapter == nil {
+		err := fmt.Errorf("no AGEN installation found. Run 'agen init' first")
+		printError("%v", err)
+		return err
+	}
+	installed, err := installedTemplates(cwd, adapter)
+	if err != nil {
+		printError("%v", err)
+		return err
+	}
+	source, sourceName, sourceAt, err := teamTemplateSource(config)
+	if err != nil {
+		printError("%v", err)
+		return err
+	}
+
 	cyan := color.New(color.FgCyan, color.Bold)
 	cyan.Printf("\n🔄 Syncing with team: %s\n\n", config.Name)
+	fmt.Printf("IDE: %s\n", adapter.Name())
+	fmt.Printf("Source: %s templates (v%s)\n\n", sourceName, source.Version)
+	if dryRun {
+		printWarning("DRY RUN: No changes will be made")
+	}
 
-	result, err := config.Sync(cwd)
+	result, err := config.Sync(cwd, team.SyncOptions{
+		Adapter:     adapter,
+		Installed:   installed,
+		Source:      source,
+		SourceName:  sourceName,
+		SourceAt:    sourceAt,
+		IgnoreLocks: ignoreLocks,
+		DryRun:      dryRun,
+	})
 	if err != nil {
+		printError("%v", err)
 		return err
 	}
 
@@ -146,6 +231,13 @@ func runTeamSync(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if len(result.Missing) > 0 {
+		fmt.Println("Not available:")
+		for _, item := range result.Missing {
+			printWarning("  ? %s", item)
+		}
+	}
+
 	if len(result.Errors) > 0 {
 		fmt.Println("Errors:")
 		for _, err := range result.Errors {
@@ -153,25 +245,98 @@ func runTeamSync(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if len(result.Added) == 0 && len(result.Updated) == 0 {
+	if len(result.Added)+len(result.Updated)+len(result.Missing)+len(result.Errors) == 0 {
 		printSuccess("Already in sync!")
 	}
 
+	if n := len(result.Missing) + len(result.Errors); n > 0 {
+		err := fmt.Errorf("%d required template(s) could not be installed", n)
+		printError("%v", err)
+		return err
+	}
 	return nil
 }
 
+// teamTemplateSource returns the templates team sync installs from, and
+// a way to fetch them at a locked version: the tag v<version> of the
+// source's repository.
+//
+// settings.template_source names a remote (see 'agen remote'), or is a
+// URL a remote could have; without it, the cached templates from the last
+// update are used, or the embedded ones, and locked versions come from
+// the official repository.
+func teamTemplateSource(config *team.TeamConfig) (*templates.Templates, string, func(string) (*templates.Templates, error), error) {
+	source := config.Settings.TemplateSource
+	if source == "" {
+		tmpl, name, err := loadOfflineTemplates()
+		return tmpl, name, func(version string) (*templates.Templates, error) {
+			return templates.FetchFromGitHub(versionTag(version))
+		}, err
+	}
+
+	name, url, branch := config.Name, source, ""
+	remotes, err := loadRemotes()
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to read remotes: %w", err)
+	}
+	if r, ok := findRemote(remotes, source); ok {
+		name, url, branch = r.Name, r.URL, r.Branch
+		source = "remote " + r.Name
+	}
+
+	tmpl, err := templates.FetchFromRemote(name, url, branch)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to fetch template_source: %w", err)
+	}
+	sourceAt := func(version string) (*templates.Templates, error) {
+		if !templates.IsGitHubRemote(url) {
+			return nil, fmt.Errorf("%s has no versions to fetch", url)
+		}
+		return templates.FetchFromRemote(name, url, versionTag(version))
+	}
+	return tmpl, source, sourceAt, nil
+}
+
+// versionTag is the git tag a locked version is fetched from
+func versionTag(version string) string {
+	return "v" + strings.TrimPrefix(version, "v")
+}
+
 func runTeamValidate(cmd *cobra.Command, args []string) error {
 	cwd, _ := os.Getwd()
+	ignoreLocks, _ := cmd.Flags().GetBool("ignore-locks")
 
 	config, err := team.LoadTeamConfig(cwd)
 	if err != nil {
 		return err
 	}
 
+	adapter := ide.Detect(cwd)
+	if adapter == nil {
+		err := fmt.Errorf("no AGEN installation found. Run 'agen init' first")
+		printError("%v", err)
+		return err
+	}
+	source, sourceName, sourceAt, err := teamTemplateSource(config)
+	if err != nil {
+		printError("%v", err)
+		return err
+	}
+
 	cyan := color.New(color.FgCyan, color.Bold)
 	cyan.Printf("\n✓ Validating against: %s\n\n", config.Name)
 
-	result := config.Validate(cwd)
+	result, err := config.Validate(cwd, team.ValidateOptions{
+		Adapter:     adapter,
+		Source:      source,
+		SourceName:  sourceName,
+		SourceAt:    sourceAt,
+		IgnoreLocks: ignoreLocks,
+	})
+	if err != nil {
+		printError("%v", err)
+		return err
+	}
 
 	if result.Valid {
 		color.New(color.FgGreen, color.Bold).Println("✨ Validation passed!")
@@ -186,6 +351,17 @@ func runTeamValidate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if len(result.Modified) > 0 {
+		fmt.Printf("\nModified (differs from %s templates):\n", sourceName)
+		for _, item := range result.Modified {
+			if config.Settings.EnforceContent {
+				printError("  ✗ %s present but modified", item)
+			} else {
+				printWarning("  ~ %s present but modified", item)
+			}
+		}
+	}
+
 	if len(result.Warnings) > 0 {
 		fmt.Println("\nWarnings:")
 		for _, warn := range result.Warnings {
@@ -193,6 +369,9 @@ func runTeamValidate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if !result.Valid {
+		return fmt.Errorf("project does not meet the requirements of team %s", config.Name)
+	}
 	return nil
 }
 
@@ -295,5 +474,98 @@ func runTeamInfo(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if len(config.Members) > 0 {
+		fmt.Println("\nMembers:")
+		printTeamMembers(config.Members)
+	}
+
 	return nil
 }
+
+func runTeamMemberAdd(cmd *cobra.Command, args []string) error {
+	role, _ := cmd.Flags().GetString("role")
+
+	cwd, _ := os.Getwd()
+	config, err := team.LoadTeamConfig(cwd)
+	if err != nil {
+		return err
+	}
+
+	member, err := config.AddMember(args[0], args[1], role)
+	if err != nil {
+		printError("%v", err)
+		return err
+	}
+	if err := config.Save(cwd); err != nil {
+		return err
+	}
+
+	printSuccess("Added %s <%s> as %s", member.Name, member.Email, member.Role)
+	return nil
+}
+
+func runTeamMemberRemove(cmd *cobra.Command, args []string) error {
+	cwd, _ := os.Getwd()
+	config, err := team.LoadTeamConfig(cwd)
+	if err != nil {
+		return err
+	}
+
+	member, err := config.RemoveMember(args[0])
+	if err != nil {
+		printError("%v", err)
+		return err
+	}
+	if err := config.Save(cwd); err != nil {
+		return err
+	}
+
+	printSuccess("Removed %s <%s>", member.Name, member.Email)
+	return nil
+}
+
+func runTeamMemberList(cmd *cobra.Command, args []string) error {
+	cwd, _ := os.Getwd()
+	config, err := team.LoadTeamConfig(cwd)
+	if err != nil {
+		return err
+	}
+
+	if wantJSON(cmd) {
+		members := config.Members
+		if members == nil {
+			members = []team.TeamMember{}
+		}
+		return printJSON(members)
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("\n👥 Members of %s\n\n", config.Name)
+	if len(config.Members) == 0 {
+		fmt.Println("No members recorded.")
+		fmt.Println("\nAdd one with:")
+		fmt.Println("  agen team member add <name> <email>")
+		return nil
+	}
+	printTeamMembers(config.Members)
+	return nil
+}
+
+// printTeamMembers prints one line per member, owners first. Roles
+// edited in by hand that agen doesn't know come last.
+func printTeamMembers(members []team.TeamMember) {
+	sorted := append([]team.TeamMember{}, members...)
+	rank := func(role string) int {
+		for i, r := range team.Roles {
+			if r == role {
+				return i
+			}
+		}
+		return len(team.Roles)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return rank(sorted[i].Role) < rank(sorted[j].Role) })
+
+	for _, m := range sorted {
+		fmt.Printf("  %-12s %s <%s>, joined %s\n", m.Role, m.Name, m.Email, m.JoinedAt.Format("2006-01-02"))
+	}
+}
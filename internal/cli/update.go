@@ -10,8 +10,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/eshanized/agen/internal/config"
 	"github.com/eshanized/agen/internal/ide"
+	"github.com/eshanized/agen/internal/team"
 	"github.com/eshanized/agen/internal/templates"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -29,11 +34,25 @@ Features:
 - Detects user modifications and offers merge options
 - Creates backups before overwriting
 - Supports specific branch selection
+- Optional incremental fetch of only the files changed upstream
+- Reuses templates fetched within the last cache_ttl_days (see --max-cache-age)
+- Layers templates from remotes (see 'agen remote') over the official ones
+- Leaves templates locked in the team config alone (see --ignore-locks)
+
+With --remote, each named remote is fetched from its own URL and branch.
+Remotes take precedence in the order given, and all of them over the
+official templates: a template defined by two remotes comes from the first
+and the clash is reported, while a remote's template replaces an official
+one of the same name.
 
 Examples:
   agen update                # Update current directory
   agen update --branch dev   # Update from dev branch
-  agen update --force        # Overwrite without prompting`,
+  agen update --remote company --remote shared   # Add remote templates, company first
+  agen update --force        # Overwrite without prompting
+  agen update --incremental  # Fetch only changed files (saves bandwidth)
+  agen update --max-cache-age 0   # Always check GitHub, ignore a fresh cache
+  agen update --output-summary report.json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runUpdate,
 }
@@ -43,6 +62,11 @@ func init() {
 	updateCmd.Flags().BoolP("force", "f", false, "overwrite modified files without prompting")
 	updateCmd.Flags().Bool("dry-run", false, "show what would be updated without making changes")
 	updateCmd.Flags().Bool("no-backup", false, "don't create backups of modified files")
+	updateCmd.Flags().Bool("incremental", false, "download only the files changed since the last cached fetch")
+	updateCmd.Flags().String("output-summary", "", "write a machine-readable update report to this file")
+	updateCmd.Flags().Bool("ignore-locks", false, "update templates locked in the team config too")
+	updateCmd.Flags().StringArray("remote", nil, "also install templates from this remote (repeatable; earlier ones win)")
+	updateCmd.Flags().String("max-cache-age", "", "use cached templates fetched within this long, e.g. 12h or 3d (default: config cache_ttl_days; 0 always fetches)")
 }
 
 // runUpdate is the main logic for the update command.
@@ -83,11 +107,26 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	branch, _ := cmd.Flags().GetString("branch")
 	force, _ := cmd.Flags().GetBool("force")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	incremental, _ := cmd.Flags().GetBool("incremental")
+	summaryFile, _ := cmd.Flags().GetString("output-summary")
+	maxCacheAgeFlag, _ := cmd.Flags().GetString("max-cache-age")
+	remoteNames, _ := cmd.Flags().GetStringArray("remote")
+	ignoreLocks, _ := cmd.Flags().GetBool("ignore-locks")
+	var warnings []string
+
+	maxCacheAge, err := resolveMaxCacheAge(maxCacheAgeFlag)
+	if err != nil {
+		return err
+	}
 
 	cyan := color.New(color.FgCyan, color.Bold)
 	cyan.Println("\n🔄 AGEN Update")
 	fmt.Printf("Directory: %s\n", absPath)
-	fmt.Printf("Branch: %s\n\n", branch)
+	fmt.Printf("Branch: %s\n", branch)
+	if len(remoteNames) > 0 {
+		fmt.Printf("Remotes: %s\n", strings.Join(remoteNames, ", "))
+	}
+	fmt.Println()
 
 	if dryRun {
 		printWarning("DRY RUN: No changes will be made")
@@ -103,14 +142,52 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	// Step 2: Fetch latest templates
 	printInfo("Fetching latest templates from GitHub...")
-	latest, err := templates.FetchFromGitHub(branch)
+	latest, err := fetchLatestTemplates(branch, incremental, verbose, maxCacheAge)
 	if err != nil {
-		// Fall back to embedded if network fails
-		printWarning("Network fetch failed, using embedded templates: %v", err)
-		latest, err = templates.LoadEmbedded()
+		// Fall back to the last pulled templates, then embedded, if network fails
+		fetchErr := err
+		var source string
+		latest, source, err = loadOfflineTemplates()
 		if err != nil {
 			return fmt.Errorf("failed to load templates: %w", err)
 		}
+		warnings = append(warnings, fmt.Sprintf("network fetch failed, used %s templates: %v", source, fetchErr))
+		printWarning("Network fetch failed, using %s templates: %v", source, fetchErr)
+	} else if len(latest.Agents)+len(latest.Skills)+len(latest.Workflows) == 0 {
+		// the fetch worked, the branch just has nothing in it
+		warnings = append(warnings, fmt.Sprintf("branch %s has no templates upstream", branch))
+		printWarning("Branch %s has no templates upstream; nothing to update", branch)
+	}
+
+	if len(remoteNames) > 0 {
+		printInfo("Fetching templates from %s...", strings.Join(remoteNames, ", "))
+		remote, collisions, err := fetchRemoteTemplates(remoteNames)
+		if err != nil {
+			printError("%v", err)
+			return err
+		}
+		for _, c := range collisions {
+			warnings = append(warnings, c.String())
+			printWarning("%s", c)
+		}
+		for _, c := range remote.Merge(latest) {
+			if verbose {
+				printInfo("%s %q from %s replaces the official one", c.Kind, c.Name, c.Kept)
+			}
+		}
+		latest = remote
+	}
+
+	var locked []string
+	if !ignoreLocks {
+		locked, err = holdTeamLocks(absPath, ideAdapter, latest)
+		if err != nil {
+			printError("%v", err)
+			return err
+		}
+		for _, note := range locked {
+			printInfo("%s", note)
+		}
 	}
 
 	if verbose {
@@ -126,11 +203,40 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		Verbose:   verbose,
 	}
 
+	var before map[string]string
+	if summaryFile != "" {
+		before = snapshotProject(absPath)
+	}
+
 	changes, err := ideAdapter.Update(latest, opts)
 	if err != nil {
 		return fmt.Errorf("update failed: %w", err)
 	}
 
+	if summaryFile != "" {
+		if len(changes.Skipped) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%d user-modified file(s) skipped", len(changes.Skipped)))
+		}
+		summary := updateSummary{
+			IDE:              ideAdapter.Name(),
+			Location:         absPath,
+			DryRun:           dryRun,
+			Branch:           branch,
+			Remotes:          remoteNames,
+			Locked:           locked,
+			AgenVersion:      Version,
+			TemplatesVersion: latest.Version,
+			Added:            append([]string{}, changes.Added...),
+			Updated:          append([]string{}, changes.Updated...),
+			Skipped:          append([]string{}, changes.Skipped...),
+			Files:            diffSnapshots(before, snapshotProject(absPath)),
+			Warnings:         warnings,
+		}
+		if err := writeSummaryFile(summaryFile, summary); err != nil {
+			return fmt.Errorf("failed to write summary: %w", err)
+		}
+	}
+
 	// Step 4: Print summary
 	if len(changes.Updated) == 0 && len(changes.Added) == 0 {
 		printSuccess("Already up to date!")
@@ -164,3 +270,152 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// updateSummary is the report written by --output-summary.
+// Added/Updated/Skipped are as the IDE adapter reports them; Files lists
+// what actually changed on disk, with hashes.
+type updateSummary struct {
+	IDE              string       `json:"ide"`
+	Location         string       `json:"location"`
+	DryRun           bool         `json:"dry_run"`
+	Branch           string       `json:"branch"`
+	Remotes          []string     `json:"remotes,omitempty"`
+	Locked           []string     `json:"locked,omitempty"`
+	AgenVersion      string       `json:"agen_version"`
+	TemplatesVersion string       `json:"templates_version"`
+	Added            []string     `json:"added"`
+	Updated          []string     `json:"updated"`
+	Skipped          []string     `json:"skipped"`
+	Files            []fileChange `json:"files"`
+	Warnings         []string     `json:"warnings,omitempty"`
+}
+
+// fetchLatestTemplates downloads templates for branch. With incremental set
+// it patches the local template cache using only the files that changed
+// upstream, and falls back to a full fetch when that isn't possible.
+// Otherwise the zip is fetched conditionally, so an unchanged branch is
+// served from the cache. Either way, a cache fetched less than maxAge ago
+// is used without touching the network.
+func fetchLatestTemplates(branch string, incremental, verbose bool, maxAge time.Duration) (*templates.Templates, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	cacheDir, err := cfg.GetCacheDir()
+
+	if err == nil && maxAge > 0 {
+		if tmpl, cacheErr := templates.LoadFreshCache(cacheDir, branch, maxAge); cacheErr == nil {
+			age, _ := templates.CacheAge(cacheDir)
+			printInfo("Using templates fetched %s ago (newer than %s); pass --max-cache-age 0 to refetch",
+				age.Round(time.Minute), maxAge)
+			return tmpl, nil
+		} else if verbose {
+			printInfo("Not using the template cache: %v", cacheErr)
+		}
+	}
+
+	if !incremental {
+		if err != nil {
+			return templates.FetchFromGitHub(branch)
+		}
+		tmpl, fromCache, err := templates.FetchCached(branch, cacheDir)
+		if err != nil {
+			return nil, err
+		}
+		if fromCache {
+			printInfo("Templates not modified upstream, using cache")
+		}
+		return tmpl, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("no cache directory for incremental update: %w", err)
+	}
+
+	tmpl, result, err := templates.FetchIncremental(branch, cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case result.Full:
+		printInfo("Downloaded full template set (%s)", result.Reason)
+	case result.ChangedFiles == 0:
+		printInfo("Template cache already at latest commit")
+	default:
+		printInfo("Fetched %d changed file(s) incrementally", result.ChangedFiles)
+	}
+	if verbose {
+		printInfo("Templates at commit %s", result.Commit)
+	}
+
+	return tmpl, nil
+}
+
+// holdTeamLocks keeps the templates locked in the project's team config
+// at their installed versions in latest (see team.TeamConfig.HoldLocked).
+// No team config means nothing is locked; one that can't be read is an
+// error, rather than silently updating what it locks.
+func holdTeamLocks(projectDir string, adapter ide.Adapter, latest *templates.Templates) ([]string, error) {
+	if _, err := os.Stat(team.ConfigPath(projectDir)); err != nil {
+		return nil, nil
+	}
+	cfg, err := team.LoadTeamConfig(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("%w (pass --ignore-locks to update anyway)", err)
+	}
+	if len(cfg.LockedVersions) == 0 {
+		return nil, nil
+	}
+
+	installed, err := installedTemplates(projectDir, adapter)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.HoldLocked(latest, installed), nil
+}
+
+// resolveMaxCacheAge turns --max-cache-age into a duration. Empty means
+// the config's cache_ttl_days; besides Go durations ("12h") it accepts
+// whole days ("3d").
+func resolveMaxCacheAge(flag string) (time.Duration, error) {
+	if flag == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = config.DefaultConfig()
+		}
+		return time.Duration(cfg.CacheTTLDays) * 24 * time.Hour, nil
+	}
+
+	if days, ok := strings.CutSuffix(flag, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid --max-cache-age %q: want e.g. 12h or 3d", flag)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(flag)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid --max-cache-age %q: want e.g. 12h or 3d", flag)
+	}
+	return d, nil
+}
+
+// loadOfflineTemplates returns the cached templates from the last
+// successful fetch, or the embedded ones if there's no cache, along with
+// which of the two it used
+func loadOfflineTemplates() (*templates.Templates, string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	if cacheDir, err := cfg.GetCacheDir(); err == nil {
+		if cached, err := templates.LoadFromCache(cacheDir); err == nil {
+			return cached, "cached", nil
+		}
+	}
+
+	tmpl, err := templates.LoadEmbedded()
+	return tmpl, "embedded", err
+}
@@ -7,9 +7,11 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 
+	"github.com/eshanized/agen/internal/config"
 	"github.com/eshanized/agen/internal/updater"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -25,15 +27,27 @@ var upgradeCmd = &cobra.Command{
 The binary will be replaced in-place. On Windows, a helper script
 may be created to complete the upgrade after the command exits.
 
+By default only full releases are offered. --prerelease switches to the
+prerelease channel, which also offers beta and release candidate builds,
+and remembers the choice as update_channel in config.json;
+--prerelease=false switches back to stable.
+
+The replaced binary is kept as a backup until the next upgrade, and
+--rollback puts it back if the new version misbehaves.
+
 Examples:
   agen upgrade        # Upgrade to latest version
-  agen upgrade --check  # Just check if update is available`,
+  agen upgrade --check  # Just check if update is available
+  agen upgrade --prerelease  # Follow prereleases from now on
+  agen upgrade --rollback    # Restore the version before the last upgrade`,
 	RunE: runUpgrade,
 }
 
 func init() {
 	upgradeCmd.Flags().Bool("check", false, "only check for updates, don't install")
 	upgradeCmd.Flags().Bool("force", false, "upgrade even if already on latest version")
+	upgradeCmd.Flags().Bool("rollback", false, "restore the binary replaced by the last upgrade")
+	upgradeCmd.Flags().Bool("prerelease", false, "follow the prerelease channel (saved to config; =false for stable)")
 }
 
 // runUpgrade is the main logic for the upgrade command.
@@ -43,7 +57,7 @@ func init() {
 //  2. Compare with current version (Version variable from root.go)
 //  3. If newer version available:
 //     a. Download the binary for current OS/arch
-//     b. Verify checksum (if provided)
+//     b. Verify its sha256 against the release's checksums.txt
 //     c. Replace current binary with new one
 //  4. On Windows, we can't replace a running binary, so we create
 //     a batch script that runs after this process exits
@@ -54,15 +68,27 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	checkOnly, _ := cmd.Flags().GetBool("check")
 	force, _ := cmd.Flags().GetBool("force")
 
+	if rollback, _ := cmd.Flags().GetBool("rollback"); rollback {
+		return runRollback()
+	}
+
 	cyan := color.New(color.FgCyan, color.Bold)
 	cyan.Println("\n🚀 AGEN Upgrade")
 	fmt.Printf("Current version: %s\n", Version)
-	fmt.Printf("Platform: %s/%s\n\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Printf("Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+
+	channel := updateChannel()
+	if cmd.Flags().Changed("prerelease") {
+		prerelease, _ := cmd.Flags().GetBool("prerelease")
+		channel = saveUpdateChannel(prerelease)
+	}
+	fmt.Printf("Channel: %s\n\n", channel)
 
 	// Step 1: Check for updates
 	printInfo("Checking for updates...")
-	release, err := updater.CheckForUpdate(Version)
+	release, err := updater.CheckForUpdate(Version, channel)
 	if err != nil {
+		printError("%v", err)
 		return fmt.Errorf("failed to check for updates: %w", err)
 	}
 
@@ -95,7 +121,8 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 	}
 
 	printInfo("Downloading %s for %s/%s...", release.Version, runtime.GOOS, runtime.GOARCH)
-	if err := updater.DownloadAndReplace(release); err != nil {
+	if err := updater.DownloadAndReplace(release, Version); err != nil {
+		printError("Upgrade failed: %v", err)
 		return fmt.Errorf("upgrade failed: %w", err)
 	}
 
@@ -109,3 +136,58 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runRollback restores the binary the last upgrade replaced
+func runRollback() error {
+	state, err := updater.Rollback(Version)
+	if errors.Is(err, updater.ErrNoRollback) {
+		printError("%v", err)
+		return err
+	}
+	if err != nil {
+		printError("Rollback failed: %v", err)
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+
+	printSuccess("Rolled back from %s to %s", state.Version, state.PreviousVersion)
+	if runtime.GOOS == "windows" {
+		printInfo("Note: %s.rollback can be deleted once this window is closed", state.Binary)
+	}
+	return nil
+}
+
+// updateChannel returns the configured update channel, stable if the
+// config can't be read
+func updateChannel() string {
+	cfg, err := config.Load()
+	if err != nil || cfg.UpdateChannel == "" {
+		return updater.ChannelStable
+	}
+	return cfg.UpdateChannel
+}
+
+// saveUpdateChannel records the prerelease or stable channel as the
+// preferred one and returns it. Failing to save only costs the preference,
+// so it's a warning.
+func saveUpdateChannel(prerelease bool) string {
+	channel := updater.ChannelStable
+	if prerelease {
+		channel = updater.ChannelPrerelease
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		printWarning("Could not save update channel: %v", err)
+		return channel
+	}
+	if cfg.UpdateChannel == channel {
+		return channel
+	}
+	cfg.UpdateChannel = channel
+	if err := cfg.Save(); err != nil {
+		printWarning("Could not save update channel: %v", err)
+		return channel
+	}
+	printInfo("Update channel set to %s", channel)
+	return channel
+}
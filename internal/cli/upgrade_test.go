@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for the upgrade command
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eshanized/agen/internal/updater"
+)
+
+// the Windows update script restores the old binary unless the new one
+// accepts this argument, so it has to be one agen exits 0 on
+func TestUpdateScriptVersionCheck(t *testing.T) {
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{updater.VersionCheckArg})
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		rootCmd.Flags().Set("version", "false")
+	})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("agen %s failed: %v", updater.VersionCheckArg, err)
+	}
+	if !strings.Contains(out.String(), "Version:") {
+		t.Errorf("agen %s printed %q, want the version", updater.VersionCheckArg, out.String())
+	}
+}
@@ -10,7 +10,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/eshanized/agen/internal/config"
 	"github.com/eshanized/agen/internal/verify"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -26,14 +29,42 @@ var verifyCmd = &cobra.Command{
 Available checks:
   --security   Security scan (secrets, vulnerabilities)
   --lint       Lint and type checking
+  --perf       Performance budgets (asset sizes, file length, lazy loading)
   --ux         UX audit (accessibility, usability)
   --seo        SEO check (meta tags, structure)
-  --all        Run all checks (default)
+  --all        Run all checks, performance included
+
+With no flags, the checks listed in verify_checks (project .agenrc or
+global config) are run, or every check but performance if none are
+configured. The performance check is opt-in since its budgets suit
+frontend projects.
+
+Individual rules can be silenced with --disable-rule (or the
+verify_disabled_rules config list), or narrowed with --only-rule.
+Rule ids look like lint/no-console; "seo/*" matches a whole category.
+The verify_rule_severity config map promotes or demotes rules, e.g.
+lint/no-console: critical makes console.log fail the run.
+
+Files ignored by the project's .gitignore files are not scanned;
+pass --no-gitignore to scan them anyway.
+
+-o json prints the results as JSON for dashboards and scripts, and
+-o markdown prints a report with one collapsible section per check,
+ready to paste into a pull request comment. Both still exit 1 on
+critical findings.
+
+--fix applies the safe fixes: it removes lines that are just a
+console.log() call, adds .env to .gitignore, and gives images without
+alt text an empty alt="". Each changed file is first copied to
+file.bak.
 
 Examples:
   agen verify                # Run all checks
   agen verify --security     # Only security scan
-  agen verify --lint --ux    # Run multiple specific checks`,
+  agen verify --lint --ux    # Run multiple specific checks
+  agen verify --perf         # Only the performance check
+  agen verify --disable-rule lint/no-console,seo/h1
+  agen verify -o markdown > report.md`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runVerify,
 }
@@ -41,11 +72,15 @@ Examples:
 func init() {
 	verifyCmd.Flags().Bool("security", false, "run security scan")
 	verifyCmd.Flags().Bool("lint", false, "run lint check")
+	verifyCmd.Flags().Bool("perf", false, "run performance check (large images, bundles and files, lazy loading)")
 	verifyCmd.Flags().Bool("ux", false, "run UX audit")
 	verifyCmd.Flags().Bool("seo", false, "run SEO check")
 	verifyCmd.Flags().Bool("all", false, "run all checks")
-	verifyCmd.Flags().Bool("fix", false, "attempt to auto-fix issues where possible")
-	verifyCmd.Flags().StringP("output", "o", "text", "output format (text, json, markdown)")
+	verifyCmd.Flags().Bool("fix", false, "apply safe fixes (console.log lines, .env in .gitignore, empty alt), backing files up to .bak")
+	verifyCmd.Flags().StringP("output", "o", verifyOutputText, "output format (text, json, markdown)")
+	verifyCmd.Flags().StringSlice("disable-rule", nil, "skip issues from these rules (e.g. lint/no-console,seo/*)")
+	verifyCmd.Flags().StringSlice("only-rule", nil, "report only issues from these rules")
+	verifyCmd.Flags().Bool("no-gitignore", false, "also scan files ignored by .gitignore")
 }
 
 // runVerify is the main logic for the verify command.
@@ -82,57 +117,176 @@ func runVerify(cmd *cobra.Command, args []string) error {
 	// figure out which checks to run
 	runSecurity, _ := cmd.Flags().GetBool("security")
 	runLint, _ := cmd.Flags().GetBool("lint")
+	runPerf, _ := cmd.Flags().GetBool("perf")
 	runUX, _ := cmd.Flags().GetBool("ux")
 	runSEO, _ := cmd.Flags().GetBool("seo")
 	runAll, _ := cmd.Flags().GetBool("all")
 
-	// if no specific checks requested, run all
-	if !runSecurity && !runLint && !runUX && !runSEO {
+	cfg, err := config.LoadForProject(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// if no specific checks requested, use the configured ones (or all)
+	if !runSecurity && !runLint && !runPerf && !runUX && !runSEO && !runAll {
+		for _, check := range cfg.VerifyChecks {
+			switch strings.ToLower(check) {
+			case "security":
+				runSecurity = true
+			case "lint":
+				runLint = true
+			case "perf", "performance":
+				runPerf = true
+			case "ux":
+				runUX = true
+			case "seo":
+				runSEO = true
+			case "all":
+				runAll = true
+			default:
+				return fmt.Errorf("unknown check in config verify_checks: %s", check)
+			}
+		}
+	}
+
+	// an explicit --all (or "all" in config) includes the opt-in
+	// performance check; running everything by default doesn't
+	if runAll {
+		runPerf = true
+	} else if !runSecurity && !runLint && !runPerf && !runUX && !runSEO {
 		runAll = true
 	}
 
-	cyan := color.New(color.FgCyan, color.Bold)
-	cyan.Println("\n🔍 AGEN Verification")
-	fmt.Printf("Directory: %s\n\n", absPath)
+	format, _ := cmd.Flags().GetString("output")
+	switch format {
+	case verifyOutputText, verifyOutputJSON, verifyOutputMarkdown:
+	default:
+		return fmt.Errorf("invalid --output %q (valid: %s, %s, %s)",
+			format, verifyOutputText, verifyOutputJSON, verifyOutputMarkdown)
+	}
+
+	// json and markdown keep stdout for the report itself, so the
+	// progress lines only show in text mode
+	text := format == verifyOutputText
+	progress := func(msg string) {
+		if text {
+			printInfo("%s", msg)
+		}
+	}
+	report := func(result verify.Result) {
+		if text {
+			printCheckResult(result)
+		}
+	}
+
+	if text {
+		cyan := color.New(color.FgCyan, color.Bold)
+		cyan.Println("\n🔍 AGEN Verification")
+		fmt.Printf("Directory: %s\n\n", absPath)
+	}
 
 	// create the runner
+	// rules disabled in config and on the command line both apply
+	disabledRules, _ := cmd.Flags().GetStringSlice("disable-rule")
+	disabledRules = append(disabledRules, cfg.VerifyDisabledRules...)
+	onlyRules, _ := cmd.Flags().GetStringSlice("only-rule")
+	noGitignore, _ := cmd.Flags().GetBool("no-gitignore")
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	for rule, severity := range cfg.VerifyRuleSeverity {
+		if !verify.ValidSeverity(severity) {
+			return fmt.Errorf("invalid severity %q for %s in config verify_rule_severity (use %s)",
+				severity, rule, strings.Join(verify.Severities, ", "))
+		}
+	}
+
 	runner := verify.NewRunner(absPath, verify.RunnerOptions{
-		Verbose: verbose,
+		Verbose:           verbose,
+		DisabledRules:     disabledRules,
+		OnlyRules:         onlyRules,
+		SeverityOverrides: cfg.VerifyRuleSeverity,
+		NoGitignore:       noGitignore,
+		Performance:       runPerf,
 	})
 
 	var results []verify.Result
 
+	// every check at once runs concurrently over one walk of the project;
+	// the results still come back in priority order
+	if runAll {
+		if runPerf {
+			progress("Running security (P0), lint (P1), performance (P3), UX (P4) and SEO (P5) checks...")
+		} else {
+			progress("Running security (P0), lint (P1), UX (P4) and SEO (P5) checks...")
+		}
+		results = runner.RunAll()
+		for _, result := range results {
+			report(result)
+		}
+		runSecurity, runLint, runPerf, runUX, runSEO = false, false, false, false, false
+	}
+
 	// Run checks in priority order (P0 → P5)
-	if runAll || runSecurity {
-		printInfo("Running security scan (P0)...")
+	if runSecurity {
+		progress("Running security scan (P0)...")
 		result := runner.RunSecurity()
 		results = append(results, result)
-		printCheckResult(result)
+		report(result)
 	}
 
-	if runAll || runLint {
-		printInfo("Running lint check (P1)...")
+	if runLint {
+		progress("Running lint check (P1)...")
 		result := runner.RunLint()
 		results = append(results, result)
-		printCheckResult(result)
+		report(result)
+	}
+
+	if runPerf {
+		progress("Running performance check (P3)...")
+		result := runner.RunPerformance()
+		results = append(results, result)
+		report(result)
 	}
 
-	if runAll || runUX {
-		printInfo("Running UX audit (P4)...")
+	if runUX {
+		progress("Running UX audit (P4)...")
 		result := runner.RunUX()
 		results = append(results, result)
-		printCheckResult(result)
+		report(result)
 	}
 
-	if runAll || runSEO {
-		printInfo("Running SEO check (P5)...")
+	if runSEO {
+		progress("Running SEO check (P5)...")
 		result := runner.RunSEO()
 		results = append(results, result)
-		printCheckResult(result)
+		report(result)
 	}
 
 	// Print summary
-	printVerifySummary(results)
+	if text {
+		printVerifySummary(results)
+	}
+
+	var applied []verify.AppliedFix
+	if fix {
+		var fixErr error
+		applied, fixErr = applyVerifyFixes(runner, results)
+		if text {
+			printAppliedFixes(applied)
+		}
+		if fixErr != nil {
+			return fmt.Errorf("failed to apply fixes: %w", fixErr)
+		}
+	}
+
+	switch format {
+	case verifyOutputJSON:
+		if err := printJSON(newVerifyReport(absPath, results, applied)); err != nil {
+			return err
+		}
+	case verifyOutputMarkdown:
+		fmt.Print(renderVerifyMarkdown(newVerifyReport(absPath, results, applied)))
+	}
 
 	// exit with error if any critical issues
 	for _, r := range results {
@@ -144,6 +298,38 @@ func runVerify(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// applyVerifyFixes applies the safe fixes the checks offered. Issues
+// without a fix are left for the user.
+func applyVerifyFixes(runner *verify.Runner, results []verify.Result) ([]verify.AppliedFix, error) {
+	var fixes []verify.Fix
+	for _, r := range results {
+		fixes = append(fixes, r.Fixes...)
+	}
+	if len(fixes) == 0 {
+		return nil, nil
+	}
+	return runner.ApplyFixes(fixes)
+}
+
+// printAppliedFixes lists what verify --fix changed
+func printAppliedFixes(applied []verify.AppliedFix) {
+	if len(applied) == 0 {
+		printInfo("Nothing to fix automatically")
+		return
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Printf("🔧 Applied %d fix(es)\n", len(applied))
+	for _, a := range applied {
+		if a.Backup != "" {
+			fmt.Printf("  ✓ %s: %s (backup: %s)\n", a.File, a.Message, a.Backup)
+		} else {
+			fmt.Printf("  ✓ %s: %s\n", a.File, a.Message)
+		}
+	}
+	fmt.Println()
+}
+
 // printCheckResult shows the result of a single check
 func printCheckResult(result verify.Result) {
 	if result.Passed {
@@ -194,3 +380,140 @@ func printVerifySummary(results []verify.Result) {
 	}
 	fmt.Println()
 }
+
+// Values for verify's own --output flag
+const (
+	verifyOutputText     = "text"
+	verifyOutputJSON     = "json"
+	verifyOutputMarkdown = "markdown"
+)
+
+// verifyReport is the verify -o json document, and what the markdown
+// report is rendered from
+type verifyReport struct {
+	Directory string              `json:"directory"`
+	Passed    bool                `json:"passed"` // no critical issues in any check
+	Critical  int                 `json:"critical"`
+	Warnings  int                 `json:"warnings"`
+	Results   []verify.Result     `json:"results"`
+	Fixed     []verify.AppliedFix `json:"fixed,omitempty"`
+}
+
+func newVerifyReport(dir string, results []verify.Result, applied []verify.AppliedFix) verifyReport {
+	report := verifyReport{
+		Directory: dir,
+		Passed:    true,
+		Results:   results,
+		Fixed:     applied,
+	}
+	for _, r := range results {
+		report.Critical += r.CriticalCount
+		report.Warnings += r.WarningCount
+		if r.HasCritical {
+			report.Passed = false
+		}
+	}
+	if report.Results == nil {
+		report.Results = []verify.Result{}
+	}
+	return report
+}
+
+// renderVerifyMarkdown renders report for a pull request comment: a
+// status line and summary table, then each check's issues in a
+// collapsible section, most severe first
+func renderVerifyMarkdown(report verifyReport) string {
+	var sb strings.Builder
+
+	sb.WriteString("## AGEN Verification\n\n")
+	switch {
+	case !report.Passed:
+		sb.WriteString(fmt.Sprintf("❌ **Failed**: %d critical, %d warning(s)\n\n", report.Critical, report.Warnings))
+	case report.Warnings > 0:
+		sb.WriteString(fmt.Sprintf("⚠️ **Passed with %d warning(s)**\n\n", report.Warnings))
+	default:
+		sb.WriteString("✅ **All checks passed**\n\n")
+	}
+
+	sb.WriteString("| Check | Status | Critical | Warnings | Issues |\n")
+	sb.WriteString("|-------|--------|----------|----------|--------|\n")
+	for _, r := range report.Results {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d | %d | %d |\n",
+			r.Name, markdownStatus(r), r.CriticalCount, r.WarningCount, len(r.Issues)))
+	}
+
+	for _, r := range report.Results {
+		if len(r.Issues) == 0 {
+			continue
+		}
+
+		issues := append([]verify.Issue{}, r.Issues...)
+		sort.SliceStable(issues, func(i, j int) bool {
+			return severityRank(issues[i].Severity) < severityRank(issues[j].Severity)
+		})
+
+		emoji, _, _ := strings.Cut(markdownStatus(r), " ")
+		sb.WriteString(fmt.Sprintf("\n<details>\n<summary>%s %s: %d issue(s)</summary>\n\n", emoji, r.Name, len(issues)))
+		sb.WriteString("| Severity | File | Line | Rule | Message |\n")
+		sb.WriteString("|----------|------|------|------|---------|\n")
+		for _, issue := range issues {
+			file, line := "", ""
+			if issue.File != "" {
+				file = "`" + issue.File + "`"
+			}
+			if issue.Line > 0 {
+				line = fmt.Sprint(issue.Line)
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | `%s` | %s |\n",
+				issue.Severity, file, line, issue.Rule, markdownCell(issueMessage(issue))))
+		}
+		sb.WriteString("\n</details>\n")
+	}
+
+	if len(report.Fixed) > 0 {
+		sb.WriteString(fmt.Sprintf("\n### 🔧 Fixed automatically (%d)\n\n", len(report.Fixed)))
+		for _, f := range report.Fixed {
+			sb.WriteString(fmt.Sprintf("- `%s`: %s\n", f.File, markdownCell(f.Message)))
+		}
+	}
+
+	return sb.String()
+}
+
+// issueMessage is an issue's message, with the file's size for size
+// findings
+func issueMessage(issue verify.Issue) string {
+	if issue.Size > 0 {
+		return fmt.Sprintf("%s (%s)", issue.Message, formatBytes(issue.Size))
+	}
+	return issue.Message
+}
+
+// markdownStatus is the status emoji and word for one check
+func markdownStatus(r verify.Result) string {
+	switch {
+	case r.HasCritical:
+		return "❌ Failed"
+	case r.WarningCount > 0:
+		return "⚠️ Warnings"
+	default:
+		return "✅ Passed"
+	}
+}
+
+// severityRank orders severities as verify.Severities does, most severe
+// first; unknown ones sort last
+func severityRank(severity string) int {
+	for i, s := range verify.Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(verify.Severities)
+}
+
+// markdownCell makes text safe inside a markdown table cell. Angle
+// brackets are escaped too, or "Missing <title> tag" renders as HTML.
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "<", "&lt;", ">", "&gt;").Replace(text)
+}
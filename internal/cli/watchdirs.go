@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Tracking the directory watches behind agen watch
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// dirWatches keeps one fsnotify watch per directory under a root.
+//
+// fsnotify isn't recursive, so every directory needs its own watch. New
+// directories (a freshly created skill) are added as they appear, and
+// removed ones are dropped along with everything that was under them so a
+// long session doesn't pile up stale watches.
+type dirWatches struct {
+	watcher *fsnotify.Watcher
+	dirs    map[string]bool
+}
+
+func newDirWatches(watcher *fsnotify.Watcher) *dirWatches {
+	return &dirWatches{watcher: watcher, dirs: make(map[string]bool)}
+}
+
+// AddTree watches root and every directory below it
+func (w *dirWatches) AddTree(root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// the first call is root itself; anything below that
+			// vanished mid-walk is just skipped
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if !info.IsDir() || w.dirs[path] {
+			return nil
+		}
+		if err := w.watcher.Add(path); err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		w.dirs[path] = true
+		return nil
+	})
+}
+
+// Handle keeps the watch set in step with one event: a created directory
+// is added with its subdirectories, a removed or renamed-away one is
+// dropped with everything under it
+func (w *dirWatches) Handle(event fsnotify.Event) {
+	if event.Op.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			w.AddTree(event.Name)
+		}
+	}
+	if event.Op.Has(fsnotify.Remove) || event.Op.Has(fsnotify.Rename) {
+		w.RemoveTree(event.Name)
+	}
+}
+
+// RemoveTree drops the watches on path and any directory below it
+func (w *dirWatches) RemoveTree(path string) {
+	prefix := path + string(filepath.Separator)
+	for dir := range w.dirs {
+		if dir == path || strings.HasPrefix(dir, prefix) {
+			// the kernel may already have dropped it; that's fine
+			w.watcher.Remove(dir)
+			delete(w.dirs, dir)
+		}
+	}
+}
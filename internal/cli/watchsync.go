@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Re-parsing and restoring templates for agen watch --sync
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eshanized/agen/internal/templates"
+)
+
+// watchedTemplate identifies the template a file under .agent/ belongs to
+type watchedTemplate struct {
+	Kind string // "agents", "skills" or "workflows"
+	Name string
+}
+
+func (w watchedTemplate) String() string {
+	return w.Kind + "/" + w.Name
+}
+
+// relPath is where the template lives relative to .agent/
+func (w watchedTemplate) relPath() string {
+	if w.Kind == "skills" {
+		return filepath.Join("skills", w.Name, "SKILL.md")
+	}
+	return filepath.Join(w.Kind, w.Name+".md")
+}
+
+// classifyTemplatePath maps a path to the template it holds:
+// agents/<name>.md, workflows/<name>.md or skills/<name>/SKILL.md.
+// Anything else (helper files, directories, editor backups) isn't one.
+func classifyTemplatePath(agentDir, path string) (watchedTemplate, bool) {
+	rel, err := filepath.Rel(agentDir, path)
+	if err != nil {
+		return watchedTemplate{}, false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+
+	switch {
+	case len(parts) == 2 && (parts[0] == "agents" || parts[0] == "workflows") &&
+		strings.HasSuffix(parts[1], ".md"):
+		name := strings.TrimSuffix(parts[1], ".md")
+		if name == "" {
+			return watchedTemplate{}, false
+		}
+		return watchedTemplate{Kind: parts[0], Name: name}, true
+	case len(parts) == 3 && parts[0] == "skills" && parts[2] == "SKILL.md" && parts[1] != "":
+		return watchedTemplate{Kind: "skills", Name: parts[1]}, true
+	}
+	return watchedTemplate{}, false
+}
+
+// templateSyncer keeps a watched .agent/ directory honest: edited templates
+// are re-parsed so mistakes show up as you save, and deleted ones can be
+// put back from the embedded set.
+type templateSyncer struct {
+	agentDir string
+	embedded *templates.Templates
+}
+
+func newTemplateSyncer(agentDir string) *templateSyncer {
+	return &templateSyncer{agentDir: agentDir}
+}
+
+// Sync handles one settled change to path.
+//
+// How it works:
+//  1. Ignore paths that aren't an agent, skill or workflow file
+//  2. If the file is still there, re-parse it and report the result
+//  3. If it's gone, offer to restore it from the embedded templates
+//
+// The file's presence decides, not the event op: a save via rename can
+// arrive as Rename|Create for a file that exists.
+func (s *templateSyncer) Sync(path string) {
+	tmpl, ok := classifyTemplatePath(s.agentDir, path)
+	if !ok {
+		return
+	}
+
+	content, err := os.ReadFile(path)
+	if err == nil {
+		if err := parseWatchedTemplate(tmpl, string(content)); err != nil {
+			printError("%s: %v", tmpl, err)
+		} else {
+			printSuccess("%s parsed OK", tmpl)
+		}
+		return
+	}
+	if !os.IsNotExist(err) {
+		printError("%s: %v", tmpl, err)
+		return
+	}
+
+	s.offerRestore(tmpl)
+}
+
+// parseWatchedTemplate runs content through the parser for its kind
+func parseWatchedTemplate(tmpl watchedTemplate, content string) error {
+	var err error
+	switch tmpl.Kind {
+	case "agents":
+		_, err = templates.ParseAgent(tmpl.Name, content)
+	case "skills":
+		_, err = templates.ParseSkill(tmpl.Name, content)
+	case "workflows":
+		_, err = templates.ParseWorkflow(tmpl.Name, content)
+	}
+	return err
+}
+
+// offerRestore asks to write a deleted template back from the embedded set.
+// Templates that agen doesn't ship (your own) are just reported as deleted.
+func (s *templateSyncer) offerRestore(tmpl watchedTemplate) {
+	if s.embedded == nil {
+		embedded, err := templates.LoadEmbedded()
+		if err != nil {
+			printWarning("%s deleted; cannot load embedded templates: %v", tmpl, err)
+			return
+		}
+		s.embedded = embedded
+	}
+
+	content, ok := embeddedContent(s.embedded, tmpl)
+	if !ok {
+		printWarning("%s deleted (not an embedded template, nothing to restore)", tmpl)
+		return
+	}
+
+	if !confirm(fmt.Sprintf("%s was deleted. Restore it from the embedded templates?", tmpl)) {
+		printInfo("Left %s deleted", tmpl)
+		return
+	}
+
+	target := filepath.Join(s.agentDir, tmpl.relPath())
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		printError("Failed to restore %s: %v", tmpl, err)
+		return
+	}
+	if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+		printError("Failed to restore %s: %v", tmpl, err)
+		return
+	}
+	printSuccess("Restored %s", tmpl)
+}
+
+// embeddedContent looks up the shipped content for a template
+func embeddedContent(tmpl *templates.Templates, w watchedTemplate) (string, bool) {
+	switch w.Kind {
+	case "agents":
+		if agent, ok := tmpl.Agents[w.Name]; ok {
+			return agent.Content, true
+		}
+	case "skills":
+		if skill, ok := tmpl.Skills[w.Name]; ok {
+			return skill.Content, true
+		}
+	case "workflows":
+		if workflow, ok := tmpl.Workflows[w.Name]; ok {
+			return workflow.Content, true
+		}
+	}
+	return "", false
+}
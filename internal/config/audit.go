@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Project-specific patterns for agen audit
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// AuditConfigFiles lists the audit pattern file names, in lookup order
+var AuditConfigFiles = []string{".agen-audit.yml", ".agen-audit.yaml"}
+
+// AuditPattern is one forbidden pattern agen audit looks for.
+//
+// Pattern is a plain substring unless Regex is set, in which case it is a
+// Go regular expression. Severity is critical, warning or info; empty
+// means warning.
+type AuditPattern struct {
+	Pattern  string `json:"pattern"`
+	Regex    bool   `json:"regex,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// AuditConfig holds a project's extra audit patterns
+type AuditConfig struct {
+	Patterns []AuditPattern `json:"patterns"`
+
+	// Path is the file this config was read from
+	Path string `json:"-"`
+}
+
+// LoadAuditConfig reads the audit pattern file in dir.
+// Returns nil (and no error) when the project has none.
+func LoadAuditConfig(dir string) (*AuditConfig, error) {
+	for _, name := range AuditConfigFiles {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+
+		audit := &AuditConfig{}
+		if err := ReadFile(path, audit); err != nil {
+			return nil, fmt.Errorf("invalid audit config %s: %w", name, err)
+		}
+		for i, p := range audit.Patterns {
+			if p.Pattern == "" {
+				return nil, fmt.Errorf("invalid audit config %s: pattern %d is empty", name, i+1)
+			}
+		}
+
+		audit.Path = path
+		return audit, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for the audit pattern file
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadAuditConfigMissing(t *testing.T) {
+	audit, err := LoadAuditConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadAuditConfig() failed: %v", err)
+	}
+	if audit != nil {
+		t.Errorf("expected nil audit config, got %+v", audit)
+	}
+}
+
+func TestLoadAuditConfig(t *testing.T) {
+	dir := t.TempDir()
+	content := `patterns:
+  - pattern: internal.corp.example
+    severity: critical
+    message: Internal hostname
+  - pattern: 'kubectl\s+delete'
+    regex: true
+`
+	if err := os.WriteFile(filepath.Join(dir, ".agen-audit.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	audit, err := LoadAuditConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadAuditConfig() failed: %v", err)
+	}
+
+	want := []AuditPattern{
+		{Pattern: "internal.corp.example", Severity: "critical", Message: "Internal hostname"},
+		{Pattern: `kubectl\s+delete`, Regex: true},
+	}
+	if !reflect.DeepEqual(audit.Patterns, want) {
+		t.Errorf("Patterns = %+v, want %+v", audit.Patterns, want)
+	}
+	if filepath.Base(audit.Path) != ".agen-audit.yml" {
+		t.Errorf("Path = %q", audit.Path)
+	}
+}
+
+func TestLoadAuditConfigInvalid(t *testing.T) {
+	tests := map[string]string{
+		"malformed":     "patterns: [",
+		"empty pattern": "patterns:\n  - severity: info\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, ".agen-audit.yaml"), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadAuditConfig(dir); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
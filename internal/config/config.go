@@ -8,6 +8,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 )
@@ -18,23 +19,40 @@ type Config struct {
 	AnalyticsEnabled bool   `json:"analytics_enabled"`
 	AnalyticsID      string `json:"analytics_id,omitempty"`
 
+	// RecordUsage keeps the local command history behind 'agen stats'
+	RecordUsage bool `json:"record_usage"`
+
 	// Update settings
 	AutoCheckUpdates bool   `json:"auto_check_updates"`
-	UpdateChannel    string `json:"update_channel"` // "stable" or "beta"
+	UpdateChannel    string `json:"update_channel"` // "stable" or "prerelease"
 
 	// Default settings
-	DefaultIDE    string `json:"default_ide,omitempty"`
-	DefaultBranch string `json:"default_branch"`
+	DefaultIDE    string   `json:"default_ide,omitempty"`
+	DefaultBranch string   `json:"default_branch"`
+	DefaultAgents []string `json:"default_agents,omitempty"`
+	DefaultSkills []string `json:"default_skills,omitempty"`
+
+	// Verify settings - checks run by 'agen verify' when no flags are given
+	VerifyChecks        []string `json:"verify_checks,omitempty"`
+	VerifyDisabledRules []string `json:"verify_disabled_rules,omitempty"`
+
+	// VerifyRuleSeverity overrides issue severities by rule id or "category/*"
+	VerifyRuleSeverity map[string]string `json:"verify_rule_severity,omitempty"`
 
 	// Cache settings
 	CacheDir     string `json:"cache_dir,omitempty"`
 	CacheTTLDays int    `json:"cache_ttl_days"`
+
+	// HubURL is the template hub 'agen hub' talks to. Without one, hub
+	// commands use the http remotes instead.
+	HubURL string `json:"hub_url,omitempty"`
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	return &Config{
 		AnalyticsEnabled: false,
+		RecordUsage:      true,
 		AutoCheckUpdates: true,
 		UpdateChannel:    "stable",
 		DefaultBranch:    "main",
@@ -53,8 +71,31 @@ func GetConfigDir() (string, error) {
 	return filepath.Join(configDir, "agen"), nil
 }
 
-// GetConfigPath returns the path to the config file
+// ConfigEnvVar names the environment variable that points AGEN at an
+// alternate config file.
+const ConfigEnvVar = "AGEN_CONFIG"
+
+// pathOverride is set by the --config global flag. Empty means "not set".
+var pathOverride string
+
+// SetConfigPath makes Load and Save use the given file instead of the
+// default location. Passing "" clears the override.
+func SetConfigPath(path string) {
+	pathOverride = path
+}
+
+// GetConfigPath returns the path to the config file.
+//
+// Resolution order: SetConfigPath (the --config flag) > AGEN_CONFIG env >
+// config.json in GetConfigDir.
 func GetConfigPath() (string, error) {
+	if pathOverride != "" {
+		return pathOverride, nil
+	}
+	if env := os.Getenv(ConfigEnvVar); env != "" {
+		return env, nil
+	}
+
 	dir, err := GetConfigDir()
 	if err != nil {
 		return "", err
@@ -62,11 +103,27 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(dir, "config.json"), nil
 }
 
+// ParseError reports a config file that exists but isn't valid JSON.
+// Callers can tell it apart from an I/O error with errors.As: a corrupt
+// file can be replaced, an unreadable one shouldn't be touched.
+type ParseError struct {
+	Path string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid config %s: %v", e.Path, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 // Load reads the config file or returns defaults if it doesn't exist.
 //
 // How it works:
-// 1. Try to find config file in standard location
-// 2. If exists, parse JSON and return
+// 1. Resolve the config file path (flag > env > standard location)
+// 2. If exists, parse JSON and return (a *ParseError if it doesn't parse)
 // 3. If not exists, return default config (don't create file yet)
 //
 // This way we don't pollute the user's system until they explicitly
@@ -87,7 +144,7 @@ func Load() (*Config, error) {
 
 	config := DefaultConfig()
 	if err := json.Unmarshal(data, config); err != nil {
-		return nil, err
+		return nil, &ParseError{Path: configPath, Err: err}
 	}
 
 	return config, nil
@@ -7,6 +7,9 @@
 package config
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -81,3 +84,73 @@ func TestLoadReturnsDefaultWhenNoFile(t *testing.T) {
 		t.Errorf("Should have default UpdateChannel, got %q", cfg.UpdateChannel)
 	}
 }
+
+func TestLoadInvalidConfig(t *testing.T) {
+	t.Cleanup(func() { SetConfigPath("") })
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"default_ide": "cursor",`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	SetConfigPath(path)
+
+	_, err := Load()
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Load() error = %v, want a *ParseError", err)
+	}
+	if parseErr.Path != path {
+		t.Errorf("ParseError.Path = %q, want %q", parseErr.Path, path)
+	}
+}
+
+func TestGetConfigPathResolutionOrder(t *testing.T) {
+	t.Cleanup(func() { SetConfigPath("") })
+
+	envPath := filepath.Join(t.TempDir(), "env.json")
+	t.Setenv(ConfigEnvVar, envPath)
+
+	path, err := GetConfigPath()
+	if err != nil {
+		t.Fatalf("GetConfigPath() failed: %v", err)
+	}
+	if path != envPath {
+		t.Errorf("with %s set, path = %q, want %q", ConfigEnvVar, path, envPath)
+	}
+
+	flagPath := filepath.Join(t.TempDir(), "flag.json")
+	SetConfigPath(flagPath)
+
+	path, err = GetConfigPath()
+	if err != nil {
+		t.Fatalf("GetConfigPath() failed: %v", err)
+	}
+	if path != flagPath {
+		t.Errorf("with override set, path = %q, want %q", path, flagPath)
+	}
+}
+
+func TestLoadAndSaveUseOverride(t *testing.T) {
+	t.Cleanup(func() { SetConfigPath("") })
+
+	path := filepath.Join(t.TempDir(), "nested", "alt.json")
+	SetConfigPath(path)
+
+	cfg := DefaultConfig()
+	cfg.DefaultIDE = "cursor"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("config not written to override path: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if loaded.DefaultIDE != "cursor" {
+		t.Errorf("DefaultIDE = %q, want 'cursor'", loaded.DefaultIDE)
+	}
+}
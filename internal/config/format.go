@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// A cross-platform CLI tool for managing AI agent templates
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileExtensions lists the config file extensions AGEN reads, in lookup
+// order. JSON comes first and is what new files are written as.
+var FileExtensions = []string{".json", ".yaml", ".yml"}
+
+// IsYAML reports whether path has a YAML extension
+func IsYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// FindFile returns the existing config file for base (a path without
+// extension), trying each of FileExtensions. If none exists it returns
+// base + ".json", the path a new file should be written to.
+func FindFile(base string) string {
+	for _, ext := range FileExtensions {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext
+		}
+	}
+	return base + ".json"
+}
+
+// Unmarshal decodes data into v, picking JSON or YAML from the path.
+//
+// YAML goes through JSON on the way in so the structs' existing json tags
+// (snake_case names, time formats) apply to both formats - no duplicate
+// yaml tags needed. A file without a YAML extension that isn't valid JSON
+// and doesn't look like JSON is given a second chance as YAML.
+func Unmarshal(path string, data []byte, v interface{}) error {
+	if !IsYAML(path) {
+		err := json.Unmarshal(data, v)
+		if err == nil || looksLikeJSON(data) {
+			return err
+		}
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	asJSON, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(asJSON, v)
+}
+
+// Marshal encodes v as indented JSON, or as YAML if path has a YAML
+// extension. Field order and names follow the json tags either way.
+func Marshal(path string, v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil || !IsYAML(path) {
+		return data, err
+	}
+
+	// JSON is valid YAML, so parsing it into a node keeps the field order;
+	// clearing the styles turns the flow {...} output into block YAML.
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	clearStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadFile reads and decodes the config file at path into v
+func ReadFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return Unmarshal(path, data, v)
+}
+
+// WriteFile encodes v in the format implied by path and writes it
+func WriteFile(path string, v interface{}) error {
+	data, err := Marshal(path, v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// looksLikeJSON reports whether data starts like a JSON document
+func looksLikeJSON(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+// clearStyle resets node styles so the encoder picks block style
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for JSON/YAML config file handling
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type formatSample struct {
+	Name     string   `json:"name"`
+	Enabled  string   `json:"enabled"`
+	TeamName string   `json:"team_name"`
+	Agents   []string `json:"agents"`
+}
+
+func TestFindFile(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "remotes")
+
+	if got := FindFile(base); got != base+".json" {
+		t.Errorf("FindFile() with no file = %q, want .json default", got)
+	}
+
+	if err := os.WriteFile(base+".yml", []byte("[]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindFile(base); got != base+".yml" {
+		t.Errorf("FindFile() = %q, want existing .yml", got)
+	}
+}
+
+func TestUnmarshalYAMLUsesJSONTags(t *testing.T) {
+	data := []byte("name: web\nteam_name: Platform\nagents:\n  - frontend-specialist\n  - debugger\n")
+
+	var s formatSample
+	if err := Unmarshal("team.yaml", data, &s); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if s.Name != "web" || s.TeamName != "Platform" || len(s.Agents) != 2 {
+		t.Errorf("Unmarshal() = %+v", s)
+	}
+}
+
+func TestUnmarshalFallsBackToYAML(t *testing.T) {
+	var s formatSample
+	if err := Unmarshal(".agenrc", []byte("name: web\n"), &s); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if s.Name != "web" {
+		t.Errorf("Name = %q, want web", s.Name)
+	}
+
+	// broken JSON must report the JSON error, not a confusing YAML one
+	if err := Unmarshal("x.json", []byte(`{"name": `), &s); err == nil {
+		t.Error("expected error for truncated JSON")
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	in := formatSample{Name: "web", Enabled: "true", TeamName: "Platform", Agents: []string{"debugger"}}
+
+	for _, name := range []string{"sample.json", "sample.yaml", "sample.yml"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := WriteFile(path, in); err != nil {
+				t.Fatalf("WriteFile() failed: %v", err)
+			}
+
+			data, _ := os.ReadFile(path)
+			if IsYAML(name) {
+				if strings.HasPrefix(string(data), "{") {
+					t.Errorf("expected block YAML, got:\n%s", data)
+				}
+				if !strings.Contains(string(data), "team_name: Platform") {
+					t.Errorf("expected json tag names in YAML, got:\n%s", data)
+				}
+			}
+
+			var out formatSample
+			if err := ReadFile(path, &out); err != nil {
+				t.Fatalf("ReadFile() failed: %v", err)
+			}
+			// "true" must stay a string, not turn into a YAML bool
+			if out.Name != in.Name || out.Enabled != "true" || out.TeamName != in.TeamName || len(out.Agents) != 1 {
+				t.Errorf("round trip = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Local command history behind 'agen stats'
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryEntry is one recorded command run
+type HistoryEntry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`       // e.g. "init" or "profile save"
+	Dir     string    `json:"dir,omitempty"` // project directory the command ran against
+}
+
+// maxHistorySize caps the history file. Once an append pushes it past
+// this, the oldest half of the entries is dropped.
+const maxHistorySize = 1 << 20
+
+// GetHistoryPath returns the path to the command history, history.jsonl
+// in GetConfigDir. It stays there even when --config points elsewhere.
+func GetHistoryPath() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// RecordCommand appends entry to the command history, one JSON object
+// per line. Nothing ever leaves the machine; the file only feeds
+// 'agen stats'.
+func RecordCommand(entry HistoryEntry) error {
+	path, err := GetHistoryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() > maxHistorySize {
+		return trimHistory(path)
+	}
+	return nil
+}
+
+// LoadHistory reads the command history, oldest first. A missing file
+// is an empty history, and lines that don't parse (say, from a write cut
+// short) are skipped.
+func LoadHistory() ([]HistoryEntry, error) {
+	path, err := GetHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) != nil || entry.Command == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// trimHistory rewrites the history with only its newer half
+func trimHistory(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	lines := bytes.SplitAfter(bytes.TrimRight(data, "\n"), []byte("\n"))
+	kept := bytes.Join(lines[len(lines)/2:], nil)
+	if !bytes.HasSuffix(kept, []byte("\n")) {
+		kept = append(kept, '\n')
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, kept, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for the command history
+
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAndLoadHistory(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	entries, err := LoadHistory()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("LoadHistory() on a fresh config dir = %v, %v; want empty", entries, err)
+	}
+
+	start := time.Now().Truncate(time.Second)
+	for _, command := range []string{"init", "profile save"} {
+		if err := RecordCommand(HistoryEntry{Time: start, Command: command, Dir: "/work/app"}); err != nil {
+			t.Fatalf("RecordCommand(%q) failed: %v", command, err)
+		}
+	}
+
+	// a half-written line must not hide the rest
+	path, _ := GetHistoryPath()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2026-`)
+	f.Close()
+
+	entries, err = LoadHistory()
+	if err != nil {
+		t.Fatalf("LoadHistory() failed: %v", err)
+	}
+	if len(entries) != 2 || entries[1].Command != "profile save" || entries[1].Dir != "/work/app" {
+		t.Errorf("LoadHistory() = %+v, want init then profile save", entries)
+	}
+	if !entries[0].Time.Equal(start) {
+		t.Errorf("Time = %v, want %v", entries[0].Time, start)
+	}
+}
+
+func TestRecordCommandTrimsHistory(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir := "/" + strings.Repeat("d", 1000)
+	for i := 0; i < 1100; i++ {
+		if err := RecordCommand(HistoryEntry{Time: time.Now(), Command: "status", Dir: dir}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path, _ := GetHistoryPath()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > maxHistorySize {
+		t.Errorf("history is %d bytes, want at most %d", info.Size(), maxHistorySize)
+	}
+
+	entries, err := LoadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || len(entries) >= 1100 {
+		t.Errorf("kept %d entries after trimming, want some but not all", len(entries))
+	}
+}
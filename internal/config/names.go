@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Validation for user-chosen names that become file names
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// MaxNameLength is the longest name ValidateName accepts
+const MaxNameLength = 64
+
+// namePattern allows letters, digits, '-' and '_', not starting with '-'
+// so a name can't be mistaken for a flag
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_-]*$`)
+
+// ValidateName checks a name that will be used as a file name under the
+// config dir, such as a profile name. Anything that could leave the
+// directory ("../x", "a/b", an absolute path) fails the pattern, since
+// '.', '/' and '\' are never allowed. kind names the thing in the error,
+// e.g. "profile".
+func ValidateName(kind, name string) error {
+	if name == "" {
+		return fmt.Errorf("%s name is empty", kind)
+	}
+	if len(name) > MaxNameLength {
+		return fmt.Errorf("%s name is longer than %d characters", kind, MaxNameLength)
+	}
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid %s name %q: use only letters, digits, '-' and '_'", kind, name)
+	}
+	return nil
+}
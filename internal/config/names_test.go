@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for name validation
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"frontend", true},
+		{"backend-api_v2", true},
+		{"_scratch", true},
+		{"", false},
+		{"..", false},
+		{"../../evil", false},
+		{"..\\..\\evil", false},
+		{"team/frontend", false},
+		{"/etc/passwd", false},
+		{"C:\\profiles\\x", false},
+		{"my.profile", false},
+		{"-rf", false},
+		{"front end", false},
+		{"naïve", false},
+		{strings.Repeat("a", MaxNameLength), true},
+		{strings.Repeat("a", MaxNameLength+1), false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateName("profile", tt.name)
+		if tt.valid && err != nil {
+			t.Errorf("ValidateName(%q) = %v, want nil", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("ValidateName(%q) = nil, want an error", tt.name)
+		}
+	}
+}
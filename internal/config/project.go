@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// A cross-platform CLI tool for managing AI agent templates
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectConfigFiles lists the per-project config file names, in the
+// order they are looked up. The first one found wins.
+var ProjectConfigFiles = []string{".agenrc", ".agen.yml", ".agen.yaml"}
+
+// ProjectConfig holds project-local defaults committed alongside the code.
+//
+// Unlike the team config (.agen-team.json) nothing here is enforced - these
+// are just defaults that win over the global config when running inside
+// the project. Empty fields leave the global value alone.
+type ProjectConfig struct {
+	DefaultIDE    string   `json:"default_ide,omitempty"`
+	DefaultBranch string   `json:"default_branch,omitempty"`
+	DefaultAgents []string `json:"default_agents,omitempty"`
+	DefaultSkills []string `json:"default_skills,omitempty"`
+	VerifyChecks  []string `json:"verify_checks,omitempty"`
+
+	VerifyDisabledRules []string          `json:"verify_disabled_rules,omitempty"`
+	VerifyRuleSeverity  map[string]string `json:"verify_rule_severity,omitempty"`
+
+	// Path is the file this config was read from
+	Path string `json:"-"`
+}
+
+// FindProjectConfig returns the path of the project config file in dir,
+// or "" if there isn't one.
+func FindProjectConfig(dir string) string {
+	for _, name := range ProjectConfigFiles {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadProjectConfig reads the project config in dir.
+// Returns nil (and no error) when the project has no config file.
+//
+// .agen.yml / .agen.yaml are YAML; .agenrc may be either JSON or YAML.
+func LoadProjectConfig(dir string) (*ProjectConfig, error) {
+	path := FindProjectConfig(dir)
+	if path == "" {
+		return nil, nil
+	}
+
+	project := &ProjectConfig{}
+	if err := ReadFile(path, project); err != nil {
+		return nil, fmt.Errorf("invalid project config %s: %w", filepath.Base(path), err)
+	}
+
+	project.Path = path
+	return project, nil
+}
+
+// Merge applies the project config over c. Project values win; fields the
+// project leaves empty keep their global value.
+func (c *Config) Merge(project *ProjectConfig) {
+	if project == nil {
+		return
+	}
+
+	if project.DefaultIDE != "" {
+		c.DefaultIDE = project.DefaultIDE
+	}
+	if project.DefaultBranch != "" {
+		c.DefaultBranch = project.DefaultBranch
+	}
+	if len(project.DefaultAgents) > 0 {
+		c.DefaultAgents = project.DefaultAgents
+	}
+	if len(project.DefaultSkills) > 0 {
+		c.DefaultSkills = project.DefaultSkills
+	}
+	if len(project.VerifyChecks) > 0 {
+		c.VerifyChecks = project.VerifyChecks
+	}
+	if len(project.VerifyDisabledRules) > 0 {
+		c.VerifyDisabledRules = project.VerifyDisabledRules
+	}
+
+	// severities merge per rule so a project can adjust one rule without
+	// repeating the team-wide list
+	if len(project.VerifyRuleSeverity) > 0 {
+		merged := make(map[string]string, len(c.VerifyRuleSeverity)+len(project.VerifyRuleSeverity))
+		for rule, severity := range c.VerifyRuleSeverity {
+			merged[rule] = severity
+		}
+		for rule, severity := range project.VerifyRuleSeverity {
+			merged[rule] = severity
+		}
+		c.VerifyRuleSeverity = merged
+	}
+}
+
+// LoadForProject loads the global config and merges the project config
+// from dir over it.
+//
+// Precedence (highest first): project file > global config > defaults.
+func LoadForProject(dir string) (*Config, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := LoadProjectConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Merge(project)
+	return cfg, nil
+}
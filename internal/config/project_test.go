@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for per-project configuration
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadProjectConfigMissing(t *testing.T) {
+	project, err := LoadProjectConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() failed: %v", err)
+	}
+	if project != nil {
+		t.Errorf("expected nil project config, got %+v", project)
+	}
+}
+
+func TestLoadProjectConfigFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"json", ".agenrc", `{"default_ide": "cursor", "default_agents": ["debugger"]}`},
+		{"yaml", ".agen.yml", "default_ide: cursor\ndefault_agents:\n  - debugger\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			project, err := LoadProjectConfig(dir)
+			if err != nil {
+				t.Fatalf("LoadProjectConfig() failed: %v", err)
+			}
+			if project == nil {
+				t.Fatal("LoadProjectConfig() returned nil")
+			}
+			if project.DefaultIDE != "cursor" {
+				t.Errorf("DefaultIDE = %q, want 'cursor'", project.DefaultIDE)
+			}
+			if len(project.DefaultAgents) != 1 || project.DefaultAgents[0] != "debugger" {
+				t.Errorf("DefaultAgents = %v, want [debugger]", project.DefaultAgents)
+			}
+		})
+	}
+}
+
+func TestLoadProjectConfigInvalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".agenrc"), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadProjectConfig(dir); err == nil {
+		t.Error("expected error for malformed .agenrc")
+	}
+}
+
+func TestMergeProjectWins(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DefaultIDE = "zed"
+	cfg.VerifyChecks = []string{"security"}
+
+	cfg.Merge(&ProjectConfig{
+		DefaultIDE:   "cursor",
+		VerifyChecks: []string{"lint", "ux"},
+	})
+
+	if cfg.DefaultIDE != "cursor" {
+		t.Errorf("DefaultIDE = %q, want 'cursor'", cfg.DefaultIDE)
+	}
+	if len(cfg.VerifyChecks) != 2 {
+		t.Errorf("VerifyChecks = %v, want [lint ux]", cfg.VerifyChecks)
+	}
+	// fields the project leaves empty keep the global value
+	if cfg.DefaultBranch != "main" {
+		t.Errorf("DefaultBranch = %q, want 'main'", cfg.DefaultBranch)
+	}
+}
+
+func TestMergeRuleSeverity(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.VerifyRuleSeverity = map[string]string{"lint/no-console": "critical", "seo/*": "info"}
+
+	cfg.Merge(&ProjectConfig{
+		VerifyRuleSeverity: map[string]string{"seo/*": "warning"},
+	})
+
+	want := map[string]string{"lint/no-console": "critical", "seo/*": "warning"}
+	if !reflect.DeepEqual(cfg.VerifyRuleSeverity, want) {
+		t.Errorf("VerifyRuleSeverity = %v, want %v", cfg.VerifyRuleSeverity, want)
+	}
+}
+
+func TestLoadForProject(t *testing.T) {
+	t.Cleanup(func() { SetConfigPath("") })
+
+	global := DefaultConfig()
+	global.DefaultIDE = "zed"
+	global.DefaultBranch = "develop"
+	SetConfigPath(filepath.Join(t.TempDir(), "config.json"))
+	if err := global.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".agenrc"), []byte(`{"default_ide": "cursor"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadForProject(dir)
+	if err != nil {
+		t.Fatalf("LoadForProject() failed: %v", err)
+	}
+	if cfg.DefaultIDE != "cursor" {
+		t.Errorf("DefaultIDE = %q, want project value 'cursor'", cfg.DefaultIDE)
+	}
+	if cfg.DefaultBranch != "develop" {
+		t.Errorf("DefaultBranch = %q, want global value 'develop'", cfg.DefaultBranch)
+	}
+}
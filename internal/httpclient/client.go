@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Shared HTTP client for all outbound network calls
+
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// UserAgent is sent with every request. GitHub rejects API calls
+// without one, so we always set it.
+const UserAgent = "agen-cli"
+
+// DefaultTimeout caps how long a single request may take when the
+// caller's context has no deadline of its own.
+const DefaultTimeout = 2 * time.Minute
+
+// Client is the shared HTTP client used by templates, updater and plugins.
+//
+// Why a package variable? Tests swap it (or point it at an httptest.Server)
+// so the fetch/update paths can be exercised without touching the network.
+var Client = &http.Client{Timeout: DefaultTimeout}
+
+// NewRequest builds a GET request bound to ctx with our standard headers.
+func NewRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	return req, nil
+}
+
+// Do sends the request using the shared client.
+func Do(req *http.Request) (*http.Response, error) {
+	return Client.Do(req)
+}
+
+// Get is a convenience wrapper for a plain GET with standard headers.
+func Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := NewRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return Do(req)
+}
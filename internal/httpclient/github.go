@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// GitHub authentication and rate-limit reporting
+
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// TokenEnvVars are checked in order for a GitHub token. AGEN_GITHUB_TOKEN
+// comes first so it can override a GITHUB_TOKEN that CI sets for
+// something else.
+var TokenEnvVars = []string{"AGEN_GITHUB_TOKEN", "GITHUB_TOKEN"}
+
+// GitHubToken returns the first token set in TokenEnvVars, or "".
+func GitHubToken() string {
+	for _, name := range TokenEnvVars {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// NewGitHubRequest builds a GET request for a GitHub URL, authenticated
+// when a token is set.
+//
+// Why only GitHub requests? Unauthenticated API calls are limited to 60
+// an hour per IP, which shared CI runners exhaust quickly; a token raises
+// that to 5000. The token must never go to other hosts (plugin URLs, say),
+// so plain NewRequest stays anonymous.
+func NewGitHubRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := NewRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if token := GitHubToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+// GetGitHub is Get for GitHub URLs, see NewGitHubRequest.
+func GetGitHub(ctx context.Context, url string) (*http.Response, error) {
+	req, err := NewGitHubRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return Do(req)
+}
+
+// RateLimitError reports that GitHub refused a request because the
+// caller's rate limit is used up.
+type RateLimitError struct {
+	Limit         int       // requests allowed per window, 0 if unknown
+	Reset         time.Time // when the window resets, zero if unknown
+	Authenticated bool      // whether a token was sent
+}
+
+func (e *RateLimitError) Error() string {
+	msg := "GitHub API rate limit exceeded"
+	if e.Limit > 0 {
+		msg += fmt.Sprintf(" (%d requests/hour)", e.Limit)
+	}
+	if !e.Reset.IsZero() {
+		msg += fmt.Sprintf(", resets at %s", e.Reset.Local().Format("15:04"))
+	}
+	if !e.Authenticated {
+		msg += "; set GITHUB_TOKEN to raise the limit"
+	}
+	return msg
+}
+
+// CheckRateLimit returns a *RateLimitError if resp is GitHub saying the
+// rate limit is exhausted, and nil for any other response.
+//
+// GitHub answers 403 (or 429) with X-RateLimit-Remaining: 0 in that case;
+// a 403 without that header is a real permission error and is left to
+// the caller's normal status handling.
+func CheckRateLimit(resp *http.Response) error {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return nil
+	}
+
+	e := &RateLimitError{
+		Authenticated: resp.Request != nil && resp.Request.Header.Get("Authorization") != "",
+	}
+	if limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); err == nil {
+		e.Limit = limit
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		e.Reset = time.Unix(reset, 0)
+	}
+	return e
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Client for template hub registries
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/eshanized/agen/internal/httpclient"
+)
+
+// maxResponseSize caps how much of a hub response is read
+const maxResponseSize = 4 << 20
+
+// ErrNotFound is returned by Lookup when the hub has no such package
+var ErrNotFound = errors.New("package not found")
+
+// Package is one plugin listed on a hub
+type Package struct {
+	Name        string `json:"name"`
+	Author      string `json:"author"`
+	Version     string `json:"version,omitempty"`
+	Downloads   int    `json:"downloads"`
+	Description string `json:"description"`
+
+	// Source is where the plugin is downloaded from, in any form
+	// 'agen plugin install' takes (github.com/user/repo, a .zip URL).
+	// Filled in by Lookup; search results may leave it out.
+	Source string `json:"source,omitempty"`
+	SHA256 string `json:"sha256,omitempty"` // of the archive at Source, if the hub knows it
+}
+
+// InstallSource returns Source pinned to SHA256 when the hub gave one,
+// so the plugin manager refuses an archive that doesn't match
+func (p Package) InstallSource() string {
+	if p.SHA256 == "" {
+		return p.Source
+	}
+	return p.Source + "@sha256:" + p.SHA256
+}
+
+// ID returns the package's author/name, the form hub commands take
+func (p Package) ID() string {
+	if p.Author == "" {
+		return p.Name
+	}
+	return p.Author + "/" + p.Name
+}
+
+// searchResponse is the body of GET /api/v1/search
+type searchResponse struct {
+	Packages []Package `json:"packages"`
+}
+
+// Client talks to one hub.
+//
+// The API is plain JSON over HTTP, under /api/v1 of the base URL:
+//
+//	GET /api/v1/search?q=<query>          →  {"packages": [Package, ...]}
+//	GET /api/v1/packages/<author>/<name>  →  Package, or 404
+//	POST /api/v1/packages                 ←  plugin .zip, bearer token
+//	                                      →  Package
+type Client struct {
+	BaseURL string
+}
+
+// New returns a client for the hub at baseURL
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Search asks the hub for packages matching query. No matches is an
+// empty slice and no error; an unreachable hub or a bad response is an
+// error.
+func (c *Client) Search(ctx context.Context, query string) ([]Package, error) {
+	var resp searchResponse
+	if err := c.getJSON(ctx, "/api/v1/search?q="+url.QueryEscape(query), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Packages == nil {
+		resp.Packages = []Package{}
+	}
+	return resp.Packages, nil
+}
+
+// Lookup returns the package author/name, with the source to install it
+// from. Returns ErrNotFound when the hub doesn't have it.
+func (c *Client) Lookup(ctx context.Context, author, name string) (*Package, error) {
+	var p Package
+	err := c.getJSON(ctx, "/api/v1/packages/"+url.PathEscape(author)+"/"+url.PathEscape(name), &p)
+	if err != nil {
+		return nil, err
+	}
+	if p.Source == "" {
+		return nil, fmt.Errorf("%s/%s on %s has no download source", author, name, c.BaseURL)
+	}
+	return &p, nil
+}
+
+// PublishPath is where Publish uploads archives, relative to the base URL
+const PublishPath = "/api/v1/packages"
+
+// Publish uploads a plugin archive, authenticated with token. sum is the
+// archive's sha256 in hex; the hub checks it against the body it received.
+// Returns the package as the hub now lists it.
+func (c *Client) Publish(ctx context.Context, token string, archive []byte, sum string) (*Package, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+PublishPath, bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", httpclient.UserAgent)
+	req.Header.Set("Content-Type", "application/zip")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Agen-Sha256", sum)
+
+	resp, err := httpclient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("%s rejected the token (status %d)", c.BaseURL, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated:
+		if msg := strings.TrimSpace(string(data)); msg != "" && len(msg) < 200 {
+			return nil, fmt.Errorf("%s returned status %d: %s", c.BaseURL, resp.StatusCode, msg)
+		}
+		return nil, fmt.Errorf("%s returned status %d", c.BaseURL, resp.StatusCode)
+	}
+
+	var p Package
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid response from %s: %w", c.BaseURL, err)
+	}
+	return &p, nil
+}
+
+// getJSON fetches path from the hub and decodes the JSON body into v
+func (c *Client) getJSON(ctx context.Context, path string, v interface{}) error {
+	resp, err := httpclient.Get(ctx, c.BaseURL+path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", c.BaseURL, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", c.BaseURL, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for the hub client
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/search" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("q") {
+		case "react native":
+			w.Write([]byte(`{"packages": [{"name": "rn-agents", "author": "acme", "downloads": 42, "description": "React Native agents"}]}`))
+		case "broken":
+			w.Write([]byte(`<html>`))
+		default:
+			w.Write([]byte(`{"packages": []}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL + "/")
+	ctx := context.Background()
+
+	got, err := c.Search(ctx, "react native")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID() != "acme/rn-agents" || got[0].Downloads != 42 {
+		t.Errorf("Search() = %+v", got)
+	}
+
+	if got, err := c.Search(ctx, "nothing"); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("Search() with no matches = %v, %v; want an empty list", got, err)
+	}
+	if _, err := c.Search(ctx, "broken"); err == nil {
+		t.Error("Search() accepted a response that isn't JSON")
+	}
+	if _, err := New(srv.URL+"/elsewhere").Search(ctx, "x"); err == nil {
+		t.Error("Search() against a 404 should fail")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/packages/acme/rn-agents":
+			w.Write([]byte(`{"name": "rn-agents", "author": "acme", "version": "1.2.0", "source": "https://hub.test/rn-agents.zip", "sha256": "abc"}`))
+		case "/api/v1/packages/acme/no-source":
+			w.Write([]byte(`{"name": "no-source", "author": "acme"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	ctx := context.Background()
+
+	p, err := c.Lookup(ctx, "acme", "rn-agents")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.InstallSource(); got != "https://hub.test/rn-agents.zip@sha256:abc" {
+		t.Errorf("InstallSource() = %q", got)
+	}
+
+	if _, err := c.Lookup(ctx, "acme", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() of a missing package = %v, want ErrNotFound", err)
+	}
+	if _, err := c.Lookup(ctx, "acme", "no-source"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() without a source = %v, want an error", err)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	var gotBody, gotSum string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != PublishPath {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		gotBody, gotSum = string(body), r.Header.Get("X-Agen-Sha256")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"name": "rn-agents", "author": "acme", "version": "1.2.0"}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	ctx := context.Background()
+
+	p, err := c.Publish(ctx, "secret", []byte("zip bytes"), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ID() != "acme/rn-agents" || p.Version != "1.2.0" {
+		t.Errorf("Publish() = %+v", p)
+	}
+	if gotBody != "zip bytes" || gotSum != "abc" {
+		t.Errorf("hub received body %q, sha256 %q", gotBody, gotSum)
+	}
+
+	if _, err := c.Publish(ctx, "wrong", []byte("zip bytes"), "abc"); err == nil {
+		t.Error("Publish() with a rejected token should fail")
+	}
+}
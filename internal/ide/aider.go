@@ -175,6 +175,15 @@ func (a *AiderAdapter) Update(tmpl *templates.Templates, opts UpdateOptions) (*U
 	return changes, nil
 }
 
+// GetInstalledContent parses .aider-context.md; .aider.conf.yml only points at it
+func (a *AiderAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	return readRulesFile(filepath.Join(projectPath, ".aider-context.md"), rulesSections{
+		Agents:    "Available Modes",
+		Skills:    "Skills",
+		Workflows: "Commands",
+	})
+}
+
 // GetRulesPath returns the path to the context file
 func (a *AiderAdapter) GetRulesPath() string {
 	return ".aider-context.md"
@@ -48,12 +48,17 @@ func (a *AntigravityAdapter) Detect(projectPath string) bool {
 // 1. Create .agent/ directory if it doesn't exist
 // 2. Copy agents/, skills/, workflows/, rules/ folders
 // 3. Handle conflicts if --force not set
+// 4. Write .agent/manifest.json with each template's sha256
 //
 // The Antigravity format is the most complete - it includes everything.
 // Other adapters convert FROM this format to their specific format.
 func (a *AntigravityAdapter) Install(tmpl *templates.Templates, opts InstallOptions) error {
 	agentDir := filepath.Join(opts.TargetDir, ".agent")
 
+	if err := checkDir(agentDir); err != nil {
+		return err
+	}
+
 	// check if already exists
 	if _, err := os.Stat(agentDir); err == nil && !opts.Force {
 		if opts.DryRun {
@@ -81,12 +86,15 @@ func (a *AntigravityAdapter) Install(tmpl *templates.Templates, opts InstallOpti
 		}
 	}
 
-	// Install templates
-	if !opts.DryRun {
-		return tmpl.InstallTo(agentDir)
+	if opts.DryRun {
+		return nil
 	}
 
-	return nil
+	// Install templates, then record their checksums for agen audit
+	if err := tmpl.InstallTo(agentDir); err != nil {
+		return err
+	}
+	return BuildManifest(tmpl).Save(agentDir)
 }
 
 // Update updates installed templates with conflict detection.
@@ -99,6 +107,10 @@ func (a *AntigravityAdapter) Update(tmpl *templates.Templates, opts UpdateOption
 
 	agentDir := filepath.Join(opts.TargetDir, ".agent")
 
+	if err := checkDir(agentDir); err != nil {
+		return nil, err
+	}
+
 	// Compare agents
 	for name, agent := range tmpl.Agents {
 		agentPath := filepath.Join(agentDir, "agents", name+".md")
@@ -150,9 +162,25 @@ func (a *AntigravityAdapter) Update(tmpl *templates.Templates, opts UpdateOption
 		}
 	}
 
+	if !opts.DryRun {
+		if err := refreshManifest(agentDir, tmpl); err != nil {
+			return nil, err
+		}
+	}
+
 	return changes, nil
 }
 
+// GetInstalledContent reads the .agent/ folder. Every template is its own
+// file here, so the content is the full template.
+func (a *AntigravityAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	agentDir := filepath.Join(projectPath, ".agent")
+	if err := checkDir(agentDir); err != nil {
+		return nil, err
+	}
+	return readAgentDir(agentDir), nil
+}
+
 // GetRulesPath returns the path to the rules file
 func (a *AntigravityAdapter) GetRulesPath() string {
 	return ".agent/rules/GEMINI.md"
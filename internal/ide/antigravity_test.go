@@ -9,6 +9,7 @@ package ide
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -128,6 +129,21 @@ func TestAntigravityAdapter_Install(t *testing.T) {
 		}
 	})
 
+	t.Run("install errors when .agent is a file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		os.WriteFile(filepath.Join(tmpDir, ".agent"), []byte("oops"), 0644)
+
+		opts := InstallOptions{
+			TargetDir: tmpDir,
+			Force:     true,
+		}
+
+		err := adapter.Install(tmpl, opts)
+		if err == nil || !strings.Contains(err.Error(), "expected .agent to be a directory") {
+			t.Errorf("Install() error = %v, want directory type error", err)
+		}
+	})
+
 	t.Run("dry run does not create files", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		opts := InstallOptions{
@@ -152,6 +152,15 @@ func (c *ClaudeCodeAdapter) Update(tmpl *templates.Templates, opts UpdateOptions
 	return changes, nil
 }
 
+// GetInstalledContent parses the generated sections of CLAUDE.md
+func (c *ClaudeCodeAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	return readRulesFile(filepath.Join(projectPath, "CLAUDE.md"), rulesSections{
+		Agents:    "Available Agents",
+		Skills:    "Skills Reference",
+		Workflows: "Workflow Commands",
+	})
+}
+
 // GetRulesPath returns the path to the rules file
 func (c *ClaudeCodeAdapter) GetRulesPath() string {
 	return "CLAUDE.md"
@@ -151,6 +151,15 @@ func (c *ClineAdapter) Update(tmpl *templates.Templates, opts UpdateOptions) (*U
 	return changes, nil
 }
 
+// GetInstalledContent parses the modes, skills and commands out of .clinerules
+func (c *ClineAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	return readRulesFile(filepath.Join(projectPath, ".clinerules"), rulesSections{
+		Agents:    "Available Modes",
+		Skills:    "Skills Reference",
+		Workflows: "Commands",
+	})
+}
+
 // GetRulesPath returns the path to the rules file
 func (c *ClineAdapter) GetRulesPath() string {
 	return ".clinerules"
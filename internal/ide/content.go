@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Reading installed templates back out of each IDE format
+
+package ide
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eshanized/agen/internal/templates"
+)
+
+// InstalledContent is what an adapter has on disk for each template, keyed
+// by template name. The content is whatever the adapter wrote for that
+// template: the whole file for folder-based formats, or the template's
+// entry in the rules file for single-file ones (usually just the
+// description).
+type InstalledContent struct {
+	Agents    map[string]string
+	Skills    map[string]string
+	Workflows map[string]string
+}
+
+func newInstalledContent() *InstalledContent {
+	return &InstalledContent{
+		Agents:    make(map[string]string),
+		Skills:    make(map[string]string),
+		Workflows: make(map[string]string),
+	}
+}
+
+// RenderedContent installs tmpl with adapter into a scratch directory and
+// reads it back with GetInstalledContent.
+//
+// Comparing this with a project's GetInstalledContent shows what an update
+// would change, in the adapter's own format. That matters for single-file
+// adapters, which only keep a summary of each template, so comparing
+// against the raw template would flag everything as modified.
+func RenderedContent(adapter Adapter, tmpl *templates.Templates) (*InstalledContent, error) {
+	dir, err := os.MkdirTemp("", "agen-render-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := adapter.Install(tmpl, InstallOptions{TargetDir: dir, Force: true}); err != nil {
+		return nil, err
+	}
+	return adapter.GetInstalledContent(dir)
+}
+
+// readAgentDir reads the .agent/ layout used by Antigravity: agents and
+// workflows as name.md, skills as name/SKILL.md
+func readAgentDir(agentDir string) *InstalledContent {
+	content := newInstalledContent()
+	readMarkdownDir(filepath.Join(agentDir, "agents"), content.Agents)
+	readMarkdownDir(filepath.Join(agentDir, "workflows"), content.Workflows)
+
+	skillsDir := filepath.Join(agentDir, "skills")
+	if entries, err := os.ReadDir(skillsDir); err == nil {
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			if data, err := os.ReadFile(filepath.Join(skillsDir, entry.Name(), "SKILL.md")); err == nil {
+				content.Skills[entry.Name()] = string(data)
+			}
+		}
+	}
+
+	return content
+}
+
+// readMarkdownDir adds every name.md file in dir to into, keyed by name.
+// A missing directory adds nothing.
+func readMarkdownDir(dir string, into map[string]string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, entry.Name())); err == nil {
+			into[strings.TrimSuffix(entry.Name(), ".md")] = string(data)
+		}
+	}
+}
+
+// rulesSections names the "## " headings a single-file adapter writes its
+// agents, skills and workflows under. An empty heading means the format
+// doesn't include that kind.
+type rulesSections struct {
+	Agents    string
+	Skills    string
+	Workflows string
+}
+
+// readRulesFile parses a generated rules file. A missing file reads as
+// nothing installed.
+func readRulesFile(path string, sections rulesSections) (*InstalledContent, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return newInstalledContent(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseRulesContent(string(data), sections), nil
+}
+
+// parseRulesContent splits a generated rules file back into templates.
+//
+// How it works:
+//  1. Walk the file by "## " sections, keeping only the three we know
+//  2. In the agents section, each "### name" heading starts an agent and
+//     everything up to the next heading is its content
+//  3. In the skills and workflows sections, each list item or table row
+//     is one template: "- **name**: text", "- `/name`: text",
+//     "- **/name** - text" or "| name | text |"
+//
+// Leading "@" and "/" are dropped from names, so they match template names.
+func parseRulesContent(content string, sections rulesSections) *InstalledContent {
+	installed := newInstalledContent()
+
+	const (
+		otherSection = iota
+		agentSection
+		listSection
+	)
+	kind := otherSection
+	var list map[string]string
+
+	var agent string
+	var agentLines []string
+	flushAgent := func() {
+		if agent != "" {
+			installed.Agents[agent] = strings.TrimSpace(strings.Join(agentLines, "\n"))
+		}
+		agent, agentLines = "", nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			flushAgent()
+			kind, list = otherSection, nil
+			switch heading := strings.TrimSpace(strings.TrimPrefix(line, "## ")); {
+			case sections.Agents != "" && heading == sections.Agents:
+				kind = agentSection
+			case sections.Skills != "" && heading == sections.Skills:
+				kind, list = listSection, installed.Skills
+			case sections.Workflows != "" && heading == sections.Workflows:
+				kind, list = listSection, installed.Workflows
+			}
+			continue
+		}
+
+		switch kind {
+		case agentSection:
+			if strings.HasPrefix(line, "### ") {
+				flushAgent()
+				agent = cleanTemplateName(strings.TrimPrefix(line, "### "))
+			} else if agent != "" {
+				agentLines = append(agentLines, line)
+			}
+
+		case listSection:
+			next := ""
+			if i+1 < len(lines) {
+				next = lines[i+1]
+			}
+			if name, text, ok := parseListEntry(line, next); ok {
+				list[name] = text
+			}
+		}
+	}
+	flushAgent()
+
+	return installed
+}
+
+// parseListEntry reads one skill or workflow line of a rules file. next is
+// the following line, used to skip a table's header row.
+func parseListEntry(line, next string) (name, text string, ok bool) {
+	line = strings.TrimSpace(line)
+
+	if strings.HasPrefix(line, "|") {
+		if isTableSeparator(line) || isTableSeparator(strings.TrimSpace(next)) {
+			return "", "", false
+		}
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		if len(cells) < 2 {
+			return "", "", false
+		}
+		name = cleanTemplateName(cells[0])
+		return name, strings.TrimSpace(strings.Join(cells[1:], "|")), name != ""
+	}
+
+	if !strings.HasPrefix(line, "- ") {
+		return "", "", false
+	}
+	line = strings.TrimPrefix(line, "- ")
+
+	for _, mark := range []string{"**", "`"} {
+		if !strings.HasPrefix(line, mark) {
+			continue
+		}
+		end := strings.Index(line[len(mark):], mark)
+		if end < 0 {
+			return "", "", false
+		}
+		name = cleanTemplateName(line[len(mark) : len(mark)+end])
+		rest := strings.TrimSpace(line[len(mark)+end+len(mark):])
+		for _, sep := range []string{":", "-"} {
+			if strings.HasPrefix(rest, sep) {
+				rest = strings.TrimSpace(strings.TrimPrefix(rest, sep))
+				break
+			}
+		}
+		return name, rest, name != ""
+	}
+
+	return "", "", false
+}
+
+// isTableSeparator reports whether line is a markdown table rule like |---|---|
+func isTableSeparator(line string) bool {
+	if !strings.HasPrefix(line, "|") {
+		return false
+	}
+	return strings.Trim(line, "|-: ") == ""
+}
+
+// cleanTemplateName strips the decoration rules files put around names
+func cleanTemplateName(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimPrefix(name, "@")
+	name = strings.TrimPrefix(name, "/")
+	return name
+}
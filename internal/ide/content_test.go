@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for reading installed content back from each IDE format
+
+package ide
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TestGetInstalledContentRoundTrip installs the mock templates with every
+// adapter and checks the same names come back out
+func TestGetInstalledContentRoundTrip(t *testing.T) {
+	tmpl := createMockTemplates()
+	agents := []string{"another-agent", "test-agent"}
+	skills := []string{"api-patterns", "test-skill"}
+	workflows := []string{"deploy", "test-workflow"}
+
+	for name, adapter := range adapters {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := adapter.Install(tmpl, InstallOptions{TargetDir: dir, Force: true}); err != nil {
+				t.Fatalf("Install() failed: %v", err)
+			}
+
+			content, err := adapter.GetInstalledContent(dir)
+			if err != nil {
+				t.Fatalf("GetInstalledContent() failed: %v", err)
+			}
+
+			if got := sortedKeys(content.Agents); !reflect.DeepEqual(got, agents) {
+				t.Errorf("Agents = %v, want %v", got, agents)
+			}
+			if got := sortedKeys(content.Skills); !reflect.DeepEqual(got, skills) {
+				t.Errorf("Skills = %v, want %v", got, skills)
+			}
+			// Zed only writes agent prompts and the skill list
+			if name == "zed" {
+				return
+			}
+			if got := sortedKeys(content.Workflows); !reflect.DeepEqual(got, workflows) {
+				t.Errorf("Workflows = %v, want %v", got, workflows)
+			}
+		})
+	}
+}
+
+func TestGetInstalledContentMissing(t *testing.T) {
+	for name, adapter := range adapters {
+		content, err := adapter.GetInstalledContent(t.TempDir())
+		if err != nil {
+			t.Errorf("%s: GetInstalledContent() on empty project failed: %v", name, err)
+			continue
+		}
+		if len(content.Agents)+len(content.Skills)+len(content.Workflows) != 0 {
+			t.Errorf("%s: GetInstalledContent() on empty project = %+v", name, content)
+		}
+	}
+}
+
+func TestRenderedContentDetectsChanges(t *testing.T) {
+	adapter := &CursorAdapter{}
+	tmpl := createMockTemplates()
+
+	dir := t.TempDir()
+	if err := adapter.Install(tmpl, InstallOptions{TargetDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	installed, err := adapter.GetInstalledContent(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agent := tmpl.Agents["test-agent"]
+	agent.Description = "Changed upstream"
+	tmpl.Agents["test-agent"] = agent
+
+	latest, err := RenderedContent(adapter, tmpl)
+	if err != nil {
+		t.Fatalf("RenderedContent() failed: %v", err)
+	}
+
+	if latest.Agents["test-agent"] == installed.Agents["test-agent"] {
+		t.Error("changed agent description not reflected in rendered content")
+	}
+	if latest.Agents["another-agent"] != installed.Agents["another-agent"] {
+		t.Errorf("unchanged agent differs: %q vs %q", latest.Agents["another-agent"], installed.Agents["another-agent"])
+	}
+}
+
+func TestParseRulesContent(t *testing.T) {
+	content := "# Rules\n\n" +
+		"## Global Rules\n\n### Code Quality\n- **not-a-skill**: ignored\n\n" +
+		"## Specialist Agents\n\n### @backend\nBuilds APIs\n\n### frontend\nBuilds UIs\nwith care\n\n" +
+		"## Domain Skills\n\n| Skill | Purpose |\n|-------|--------|\n| api | REST design |\n\n" +
+		"## Commands\n\n- **/deploy** - Ship it\n- `/plan`: Plan it\n"
+
+	got := parseRulesContent(content, rulesSections{
+		Agents:    "Specialist Agents",
+		Skills:    "Domain Skills",
+		Workflows: "Commands",
+	})
+
+	want := &InstalledContent{
+		Agents:    map[string]string{"backend": "Builds APIs", "frontend": "Builds UIs\nwith care"},
+		Skills:    map[string]string{"api": "REST design"},
+		Workflows: map[string]string{"deploy": "Ship it", "plan": "Plan it"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRulesContent() = %+v, want %+v", got, want)
+	}
+}
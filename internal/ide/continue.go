@@ -49,6 +49,10 @@ func (c *ContinueAdapter) Install(tmpl *templates.Templates, opts InstallOptions
 	continueDir := filepath.Join(opts.TargetDir, ".continue")
 	rulesFile := filepath.Join(opts.TargetDir, ".continuerules")
 
+	if err := checkDir(continueDir); err != nil {
+		return err
+	}
+
 	// Check if exists and not forcing
 	if _, err := os.Stat(continueDir); err == nil && !opts.Force {
 		if opts.DryRun {
@@ -184,6 +188,15 @@ func (c *ContinueAdapter) Update(tmpl *templates.Templates, opts UpdateOptions)
 	return changes, nil
 }
 
+// GetInstalledContent parses .continuerules. The .continue/ folder only holds config
+func (c *ContinueAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	return readRulesFile(filepath.Join(projectPath, ".continuerules"), rulesSections{
+		Agents:    "Available Agents",
+		Skills:    "Skills",
+		Workflows: "Slash Commands",
+	})
+}
+
 // GetRulesPath returns the path to the rules file
 func (c *ContinueAdapter) GetRulesPath() string {
 	return ".continuerules"
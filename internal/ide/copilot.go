@@ -40,6 +40,10 @@ func (c *CopilotWorkspaceAdapter) Install(tmpl *templates.Templates, opts Instal
 	githubDir := filepath.Join(opts.TargetDir, ".github")
 	instructionsFile := filepath.Join(githubDir, "copilot-instructions.md")
 
+	if err := checkDir(githubDir); err != nil {
+		return err
+	}
+
 	if _, err := os.Stat(instructionsFile); err == nil && !opts.Force {
 		if opts.DryRun {
 			return nil
@@ -155,6 +159,15 @@ func (c *CopilotWorkspaceAdapter) Update(tmpl *templates.Templates, opts UpdateO
 	return changes, nil
 }
 
+// GetInstalledContent parses .github/copilot-instructions.md
+func (c *CopilotWorkspaceAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	return readRulesFile(filepath.Join(projectPath, ".github", "copilot-instructions.md"), rulesSections{
+		Agents:    "Available Personas",
+		Skills:    "Technical Capabilities",
+		Workflows: "Workflow Commands",
+	})
+}
+
 // GetRulesPath returns the path to the instructions file
 func (c *CopilotWorkspaceAdapter) GetRulesPath() string {
 	return ".github/copilot-instructions.md"
@@ -168,6 +168,15 @@ func (c *CursorAdapter) Update(tmpl *templates.Templates, opts UpdateOptions) (*
 	return changes, nil
 }
 
+// GetInstalledContent parses the agents, skills and workflow commands out of .cursorrules
+func (c *CursorAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	return readRulesFile(filepath.Join(projectPath, ".cursorrules"), rulesSections{
+		Agents:    "Available Agents",
+		Skills:    "Skills",
+		Workflows: "Workflow Commands",
+	})
+}
+
 // GetRulesPath returns the path to the rules file
 func (c *CursorAdapter) GetRulesPath() string {
 	return ".cursorrules"
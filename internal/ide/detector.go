@@ -7,9 +7,11 @@
 package ide
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode"
 
 	"github.com/eshanized/agen/internal/templates"
 )
@@ -40,6 +42,10 @@ type Adapter interface {
 
 	// GetRulesPath returns the path to the main rules/config file
 	GetRulesPath() string
+
+	// GetInstalledContent reads back what's installed in projectPath for
+	// each template, in this IDE's format (see InstalledContent)
+	GetInstalledContent(projectPath string) (*InstalledContent, error)
 }
 
 // InstallOptions configures template installation
@@ -72,11 +78,43 @@ type InstalledInfo struct {
 	AgentCount    int
 	SkillCount    int
 	WorkflowCount int
-	ModifiedFiles int
+	ModifiedFiles int      // changed since install, per .agent/manifest.json
 	Agents        []string // list of installed agent names
 	Skills        []string // list of installed skill names
 }
 
+// checkDir makes sure path is usable as a directory before we read from or
+// write into it. A missing path is fine (we'll create it). A regular file,
+// or a symlink pointing nowhere, is reported up front - otherwise
+// MkdirAll/ReadDir fail halfway through an install with a confusing error.
+func checkDir(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		// follow the link and check what it points at
+		target, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("expected %s to be a directory but found a broken symlink", filepath.Base(path))
+		}
+		if err != nil {
+			return err
+		}
+		info = target
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("expected %s to be a directory but found a file (%s)", filepath.Base(path), path)
+	}
+
+	return nil
+}
+
 // adapters holds all registered IDE adapters
 var adapters = make(map[string]Adapter)
 
@@ -85,10 +123,102 @@ func RegisterAdapter(name string, adapter Adapter) {
 	adapters[name] = adapter
 }
 
+// adapterAliases maps other common names for an IDE to its registry key.
+// Both sides are in normalized form (see normalizeAdapterName).
+var adapterAliases = map[string]string{
+	"claude":        "claudecode",
+	"copilot":       "copilotworkspace",
+	"githubcopilot": "copilotworkspace",
+	"nvim":          "neovim",
+	"intellij":      "jetbrains",
+	"idea":          "jetbrains",
+	"goland":        "jetbrains",
+	"pycharm":       "jetbrains",
+	"webstorm":      "jetbrains",
+}
+
+// minPrefixLen is the shortest input matched as a prefix of an IDE name
+const minPrefixLen = 3
+
+// normalizeAdapterName lowercases name and drops separators, so
+// "Claude-Code", "claude_code" and "claudecode" all compare equal
+func normalizeAdapterName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '.', ' ':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, strings.TrimSpace(name))
+}
+
 // GetAdapter returns the adapter for a specific IDE name.
 // returns nil if not found.
+//
+// How it works:
+// 1. An exact registry key always wins
+// 2. Otherwise case and separators are ignored, and aliases are resolved
+// 3. As a last resort, a prefix of at least 3 letters matches if it fits
+// exactly one IDE ("wind" → windsurf, but "c" matches too many)
 func GetAdapter(name string) Adapter {
-	return adapters[name]
+	key, ok := ResolveAdapterName(name)
+	if !ok {
+		return nil
+	}
+	return adapters[key]
+}
+
+// ResolveAdapterName returns the registry key GetAdapter would look name
+// up by ("Cursor" → cursor, "claude-code" → claudecode), and false if
+// name matches no IDE
+func ResolveAdapterName(name string) (string, bool) {
+	if _, ok := adapters[name]; ok {
+		return name, true
+	}
+
+	key := normalizeAdapterName(name)
+	if key == "" {
+		return "", false
+	}
+	if alias, ok := adapterAliases[key]; ok {
+		key = alias
+	}
+
+	var match string
+	matches := 0
+	for registered := range adapters {
+		normalized := normalizeAdapterName(registered)
+		if normalized == key {
+			return registered, true
+		}
+		if len(key) >= minPrefixLen && strings.HasPrefix(normalized, key) {
+			match = registered
+			matches++
+		}
+	}
+
+	if matches == 1 {
+		return match, true
+	}
+	return "", false
+}
+
+// detectionOrder is the priority order Detect tries adapters in:
+// explicit IDE config files first, the shared .agent/ folder last.
+// Every adapter registered in init() belongs here.
+var detectionOrder = []string{
+	"cursor",           // .cursorrules
+	"windsurf",         // .windsurfrules
+	"cline",            // .clinerules
+	"continue",         // .continue/ or .continuerules
+	"claudecode",       // CLAUDE.md
+	"copilotworkspace", // .github/copilot-instructions.md
+	"aider",            // .aider.conf.yml
+	"jetbrains",        // .idea/
+	"zed",              // .zed/
+	"neovim",           // .nvim/ or .nvim.lua
+	"emacs",            // .dir-locals.el
+	"antigravity",      // .agent/ (check last since other IDEs might also have agents)
 }
 
 // Detect attempts to auto-detect which IDE is being used in the project.
@@ -105,23 +235,6 @@ func GetAdapter(name string) Adapter {
 //
 // Returns: detected IDE adapter or nil if no IDE detected
 func Detect(projectPath string) Adapter {
-	// Priority order for detection
-	// check for explicit IDE config files first
-	detectionOrder := []string{
-		"cursor",           // .cursorrules
-		"windsurf",         // .windsurfrules
-		"cline",            // .clinerules
-		"continue",         // .continue/ or .continuerules
-		"claudecode",       // CLAUDE.md
-		"copilotworkspace", // .github/copilot-instructions.md
-		"aider",            // .aider.conf.yml
-		"jetbrains",        // .idea/
-		"zed",              // .zed/
-		"neovim",           // .nvim/ or .nvim.lua
-		"emacs",            // .dir-locals.el
-		"antigravity",      // .agent/ (check last since other IDEs might also have agents)
-	}
-
 	for _, name := range detectionOrder {
 		if adapter, ok := adapters[name]; ok {
 			if adapter.Detect(projectPath) {
@@ -146,6 +259,10 @@ func GetInstalledInfo(projectPath string, adapter Adapter) (*InstalledInfo, erro
 	// Since all adapters currently populate .agent/ internally or are based on it,
 	// checking .agent/ is a reasonable default, but let's be more specific for single-file IDEs.
 
+	if err := checkDir(filepath.Join(projectPath, ".agent")); err != nil {
+		return nil, err
+	}
+
 	agentDir := filepath.Join(projectPath, ".agent", "agents")
 	skillDir := filepath.Join(projectPath, ".agent", "skills")
 	workflowDir := filepath.Join(projectPath, ".agent", "workflows")
@@ -200,6 +317,17 @@ func GetInstalledInfo(projectPath string, adapter Adapter) (*InstalledInfo, erro
 		}
 	}
 
+	// count files changed since install. Installs made before manifests
+	// existed have nothing to compare against and report none.
+	dotAgent := filepath.Join(projectPath, ".agent")
+	if m, err := LoadManifest(dotAgent); err == nil && m != nil {
+		for _, mismatch := range m.Verify(dotAgent) {
+			if !mismatch.Missing {
+				info.ModifiedFiles++
+			}
+		}
+	}
+
 	return info, nil
 }
 
@@ -154,6 +154,94 @@ func TestGetAdapter(t *testing.T) {
 	}
 }
 
+func TestDetectionOrderCoversAdapters(t *testing.T) {
+	inOrder := make(map[string]bool)
+	for _, name := range detectionOrder {
+		if inOrder[name] {
+			t.Errorf("%q is listed twice in detectionOrder", name)
+		}
+		inOrder[name] = true
+		if _, ok := adapters[name]; !ok {
+			t.Errorf("%q is in detectionOrder but not registered", name)
+		}
+	}
+
+	for name := range adapters {
+		if !inOrder[name] {
+			t.Errorf("%q is registered but Detect never tries it", name)
+		}
+	}
+}
+
+func TestGetAdapterFuzzy(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string // "" means no match
+	}{
+		// case and separators
+		{"ClaudeCode", "ClaudeCode"},
+		{"claude-code", "ClaudeCode"},
+		{"Claude_Code", "ClaudeCode"},
+		{" Cursor ", "Cursor"},
+		// aliases
+		{"claude", "ClaudeCode"},
+		{"copilot", "CopilotWorkspace"},
+		{"GitHub-Copilot", "CopilotWorkspace"},
+		{"nvim", "Neovim"},
+		{"IntelliJ", "JetBrains"},
+		{"goland", "JetBrains"},
+		// unique prefixes
+		{"wind", "Windsurf"},
+		{"jet", "JetBrains"},
+		{"anti", "Antigravity"},
+		// too short or ambiguous
+		{"ze", ""},
+		{"c", ""},
+		{"", ""},
+		{"vscode", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := GetAdapter(tt.name)
+			if tt.expected == "" {
+				if adapter != nil {
+					t.Errorf("GetAdapter(%q) = %s, want nil", tt.name, adapter.Name())
+				}
+				return
+			}
+			if adapter == nil {
+				t.Fatalf("GetAdapter(%q) returned nil", tt.name)
+			}
+			if adapter.Name() != tt.expected {
+				t.Errorf("GetAdapter(%q).Name() = %q, want %q", tt.name, adapter.Name(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveAdapterName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string // "" means no match
+	}{
+		{"cursor", "cursor"},
+		{"Cursor", "cursor"},
+		{"claude-code", "claudecode"},
+		{"nvim", "neovim"},
+		{"wind", "windsurf"},
+		{"c", ""},
+		{"notepad", ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := ResolveAdapterName(tt.name)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("ResolveAdapterName(%q) = %q, %v; want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
 func TestAdapterNames(t *testing.T) {
 	adapters := []struct {
 		adapter Adapter
@@ -335,6 +423,7 @@ func TestRegisterAdapter(t *testing.T) {
 	// Test registering a custom adapter
 	customAdapter := &CursorAdapter{} // Using CursorAdapter as a stand-in
 	RegisterAdapter("custom-test", customAdapter)
+	t.Cleanup(func() { delete(adapters, "custom-test") })
 
 	retrieved := GetAdapter("custom-test")
 	if retrieved == nil {
@@ -344,3 +433,78 @@ func TestRegisterAdapter(t *testing.T) {
 		t.Error("GetAdapter() should return the registered adapter")
 	}
 }
+
+func TestCheckDir(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	dir := filepath.Join(tmpDir, "dir")
+	os.MkdirAll(dir, 0755)
+
+	file := filepath.Join(tmpDir, "file")
+	os.WriteFile(file, []byte("x"), 0644)
+
+	dirLink := filepath.Join(tmpDir, "dir-link")
+	brokenLink := filepath.Join(tmpDir, "broken-link")
+	if err := os.Symlink(dir, dirLink); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	os.Symlink(filepath.Join(tmpDir, "nowhere"), brokenLink)
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"missing", filepath.Join(tmpDir, "missing"), false},
+		{"directory", dir, false},
+		{"symlink to directory", dirLink, false},
+		{"regular file", file, true},
+		{"broken symlink", brokenLink, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDir(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkDir(%s) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetInstalledInfoAgentFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, ".agent"), []byte("x"), 0644)
+
+	if _, err := GetInstalledInfo(tmpDir, &AntigravityAdapter{}); err == nil {
+		t.Error("GetInstalledInfo() should error when .agent is a file")
+	}
+}
+
+func TestGetInstalledInfoModifiedFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := (&AntigravityAdapter{}).Install(createMockTemplates(), InstallOptions{TargetDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := GetInstalledInfo(dir, &AntigravityAdapter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ModifiedFiles != 0 {
+		t.Errorf("ModifiedFiles on a fresh install = %d, want 0", info.ModifiedFiles)
+	}
+
+	agentDir := filepath.Join(dir, ".agent")
+	if err := os.WriteFile(filepath.Join(agentDir, "agents", "test-agent.md"), []byte("customized"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// deleted files are missing, not modified
+	if err := os.Remove(filepath.Join(agentDir, "workflows", "deploy.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, _ = GetInstalledInfo(dir, &AntigravityAdapter{}); info.ModifiedFiles != 1 {
+		t.Errorf("ModifiedFiles = %d, want 1", info.ModifiedFiles)
+	}
+}
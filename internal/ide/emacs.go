@@ -46,6 +46,10 @@ func (e *EmacsAdapter) Install(tmpl *templates.Templates, opts InstallOptions) e
 	emacsDir := filepath.Join(opts.TargetDir, ".emacs-project")
 	rulesFile := filepath.Join(emacsDir, "ai-context.md")
 
+	if err := checkDir(emacsDir); err != nil {
+		return err
+	}
+
 	if _, err := os.Stat(emacsDir); err == nil && !opts.Force {
 		if opts.DryRun {
 			return nil
@@ -175,6 +179,15 @@ func (e *EmacsAdapter) Update(tmpl *templates.Templates, opts UpdateOptions) (*U
 	return changes, nil
 }
 
+// GetInstalledContent parses .emacs-project/ai-context.md
+func (e *EmacsAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	return readRulesFile(filepath.Join(projectPath, ".emacs-project", "ai-context.md"), rulesSections{
+		Agents:    "Available Agents",
+		Skills:    "Skills",
+		Workflows: "Commands",
+	})
+}
+
 // GetRulesPath returns the path to the rules file
 func (e *EmacsAdapter) GetRulesPath() string {
 	return ".emacs-project/ai-context.md"
@@ -45,6 +45,10 @@ func (j *JetBrainsAdapter) Install(tmpl *templates.Templates, opts InstallOption
 	aiConfigFile := filepath.Join(ideaDir, "ai-assistant.xml")
 	rulesFile := filepath.Join(opts.TargetDir, ".jbrules.md")
 
+	if err := checkDir(ideaDir); err != nil {
+		return err
+	}
+
 	if _, err := os.Stat(aiConfigFile); err == nil && !opts.Force {
 		if opts.DryRun {
 			return nil
@@ -171,6 +175,15 @@ func (j *JetBrainsAdapter) Update(tmpl *templates.Templates, opts UpdateOptions)
 	return changes, nil
 }
 
+// GetInstalledContent parses .jbrules.md. The .idea/ config has no template content
+func (j *JetBrainsAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	return readRulesFile(filepath.Join(projectPath, ".jbrules.md"), rulesSections{
+		Agents:    "Development Roles",
+		Skills:    "Technical Skills",
+		Workflows: "Workflow Commands",
+	})
+}
+
 // GetRulesPath returns the path to the rules file
 func (j *JetBrainsAdapter) GetRulesPath() string {
 	return ".jbrules.md"
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Checksum manifest for installed .agent/ templates
+
+package ide
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/eshanized/agen/internal/templates"
+)
+
+// ManifestFile is the manifest's name inside .agent/
+const ManifestFile = "manifest.json"
+
+// Manifest records the sha256 of every template file agen wrote into
+// .agent/, so agen audit can tell which ones changed since.
+//
+// Paths are relative to .agent/ and use forward slashes
+// (agents/debugger.md, skills/clean-code/SKILL.md).
+type Manifest struct {
+	Version   string            `json:"version"`
+	Generated time.Time         `json:"generated"`
+	Files     map[string]string `json:"files"`
+}
+
+// ManifestMismatch is a manifest entry whose file no longer matches
+type ManifestMismatch struct {
+	Path    string
+	Missing bool // deleted rather than changed
+}
+
+// BuildManifest hashes the files InstallTo writes for tmpl
+func BuildManifest(tmpl *templates.Templates) *Manifest {
+	m := &Manifest{
+		Version:   tmpl.Version,
+		Generated: time.Now().UTC(),
+		Files:     make(map[string]string),
+	}
+	for name, agent := range tmpl.Agents {
+		m.Files["agents/"+name+".md"] = templates.Hash([]byte(agent.Content))
+	}
+	for name, skill := range tmpl.Skills {
+		m.Files["skills/"+name+"/SKILL.md"] = templates.Hash([]byte(skill.Content))
+	}
+	for name, workflow := range tmpl.Workflows {
+		m.Files["workflows/"+name+".md"] = templates.Hash([]byte(workflow.Content))
+	}
+	return m
+}
+
+// refreshManifest brings .agent/manifest.json up to date after an update.
+//
+// Every template whose file now matches tmpl exactly gets its hash
+// recorded; files that were left alone because they were modified locally
+// keep the entry from the previous install, so audit still flags them.
+func refreshManifest(agentDir string, tmpl *templates.Templates) error {
+	m, err := LoadManifest(agentDir)
+	if err != nil || m == nil {
+		m = &Manifest{Files: make(map[string]string)}
+	}
+
+	latest := BuildManifest(tmpl)
+	for path, hash := range latest.Files {
+		content, err := os.ReadFile(filepath.Join(agentDir, filepath.FromSlash(path)))
+		if err == nil && templates.Hash(content) == hash {
+			m.Files[path] = hash
+		}
+	}
+
+	m.Version = latest.Version
+	m.Generated = latest.Generated
+	return m.Save(agentDir)
+}
+
+// LoadManifest reads .agent/manifest.json.
+// Returns nil (and no error) when there isn't one, e.g. an install made
+// before manifests existed.
+func LoadManifest(agentDir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(agentDir, ManifestFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]string)
+	}
+	return m, nil
+}
+
+// Save writes the manifest to .agent/manifest.json
+func (m *Manifest) Save(agentDir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(agentDir, ManifestFile), append(data, '\n'), 0644)
+}
+
+// Verify re-hashes every file in the manifest and returns the ones that
+// changed or are gone, sorted by path. Files added since install aren't
+// in the manifest and aren't reported.
+func (m *Manifest) Verify(agentDir string) []ManifestMismatch {
+	var mismatches []ManifestMismatch
+	for path, want := range m.Files {
+		content, err := os.ReadFile(filepath.Join(agentDir, filepath.FromSlash(path)))
+		if err != nil {
+			mismatches = append(mismatches, ManifestMismatch{Path: path, Missing: os.IsNotExist(err)})
+			continue
+		}
+		if templates.Hash(content) != want {
+			mismatches = append(mismatches, ManifestMismatch{Path: path})
+		}
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].Path < mismatches[j].Path })
+	return mismatches
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for the installed-template checksum manifest
+
+package ide
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInstallWritesManifest(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := createMockTemplates()
+	if err := (&AntigravityAdapter{}).Install(tmpl, InstallOptions{TargetDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	agentDir := filepath.Join(dir, ".agent")
+	m, err := LoadManifest(agentDir)
+	if err != nil || m == nil {
+		t.Fatalf("LoadManifest() = %v, %v", m, err)
+	}
+	if _, ok := m.Files["skills/test-skill/SKILL.md"]; !ok {
+		t.Errorf("manifest missing skill entry: %v", m.Files)
+	}
+	if got := m.Verify(agentDir); len(got) != 0 {
+		t.Errorf("Verify() on fresh install = %v", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(agentDir, "agents", "test-agent.md"), []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(agentDir, "workflows", "deploy.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ManifestMismatch{
+		{Path: "agents/test-agent.md"},
+		{Path: "workflows/deploy.md", Missing: true},
+	}
+	if got := m.Verify(agentDir); !reflect.DeepEqual(got, want) {
+		t.Errorf("Verify() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadManifestMissing(t *testing.T) {
+	m, err := LoadManifest(t.TempDir())
+	if err != nil || m != nil {
+		t.Errorf("LoadManifest() = %v, %v; want nil, nil", m, err)
+	}
+}
+
+func TestUpdateKeepsManifestForModifiedFiles(t *testing.T) {
+	dir := t.TempDir()
+	adapter := &AntigravityAdapter{}
+	tmpl := createMockTemplates()
+	if err := adapter.Install(tmpl, InstallOptions{TargetDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	agentDir := filepath.Join(dir, ".agent")
+	if err := os.WriteFile(filepath.Join(agentDir, "agents", "test-agent.md"), []byte("local edit"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// without --force the local edit is skipped, and must still show as modified
+	if _, err := adapter.Update(tmpl, UpdateOptions{TargetDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := LoadManifest(agentDir)
+	if err != nil || m == nil {
+		t.Fatalf("LoadManifest() = %v, %v", m, err)
+	}
+	want := []ManifestMismatch{{Path: "agents/test-agent.md"}}
+	if got := m.Verify(agentDir); !reflect.DeepEqual(got, want) {
+		t.Errorf("Verify() after update = %+v, want %+v", got, want)
+	}
+}
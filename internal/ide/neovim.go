@@ -53,6 +53,10 @@ func (n *NeovimAdapter) Install(tmpl *templates.Templates, opts InstallOptions)
 	nvimDir := filepath.Join(opts.TargetDir, ".nvim")
 	rulesFile := filepath.Join(nvimDir, "ai-rules.md")
 
+	if err := checkDir(nvimDir); err != nil {
+		return err
+	}
+
 	if _, err := os.Stat(nvimDir); err == nil && !opts.Force {
 		if opts.DryRun {
 			return nil
@@ -191,6 +195,15 @@ func (n *NeovimAdapter) Update(tmpl *templates.Templates, opts UpdateOptions) (*
 	return changes, nil
 }
 
+// GetInstalledContent parses .nvim/ai-rules.md
+func (n *NeovimAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	return readRulesFile(filepath.Join(projectPath, ".nvim", "ai-rules.md"), rulesSections{
+		Agents:    "Available Agents",
+		Skills:    "Skills",
+		Workflows: "Commands",
+	})
+}
+
 // GetRulesPath returns the path to the rules file
 func (n *NeovimAdapter) GetRulesPath() string {
 	return ".nvim/ai-rules.md"
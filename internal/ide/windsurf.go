@@ -143,6 +143,15 @@ func (w *WindsurfAdapter) Update(tmpl *templates.Templates, opts UpdateOptions)
 	return changes, nil
 }
 
+// GetInstalledContent parses .windsurfrules; skills are read from the table
+func (w *WindsurfAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	return readRulesFile(filepath.Join(projectPath, ".windsurfrules"), rulesSections{
+		Agents:    "Specialist Agents",
+		Skills:    "Domain Skills",
+		Workflows: "Commands",
+	})
+}
+
 // GetRulesPath returns the path to the rules file
 func (w *WindsurfAdapter) GetRulesPath() string {
 	return ".windsurfrules"
@@ -62,6 +62,10 @@ func (z *ZedAdapter) Install(tmpl *templates.Templates, opts InstallOptions) err
 	zedDir := filepath.Join(opts.TargetDir, ".zed")
 	promptsDir := filepath.Join(zedDir, "prompts")
 
+	if err := checkDir(zedDir); err != nil {
+		return err
+	}
+
 	if opts.DryRun {
 		return nil
 	}
@@ -142,6 +146,10 @@ func (z *ZedAdapter) Update(tmpl *templates.Templates, opts UpdateOptions) (*Upd
 
 	zedDir := filepath.Join(opts.TargetDir, ".zed")
 
+	if err := checkDir(zedDir); err != nil {
+		return nil, err
+	}
+
 	if _, err := os.Stat(zedDir); os.IsNotExist(err) {
 		if err := z.Install(tmpl, InstallOptions{
 			TargetDir: opts.TargetDir,
@@ -169,6 +177,21 @@ func (z *ZedAdapter) Update(tmpl *templates.Templates, opts UpdateOptions) (*Upd
 	return changes, nil
 }
 
+// GetInstalledContent reads one prompt file per agent from .zed/prompts and
+// the skill list from rules.md. Zed has no workflows.
+func (z *ZedAdapter) GetInstalledContent(projectPath string) (*InstalledContent, error) {
+	promptsDir := filepath.Join(projectPath, ".zed", "prompts")
+
+	content, err := readRulesFile(filepath.Join(promptsDir, "rules.md"), rulesSections{Skills: "Skills"})
+	if err != nil {
+		return nil, err
+	}
+	readMarkdownDir(promptsDir, content.Agents)
+	delete(content.Agents, "rules")
+
+	return content, nil
+}
+
 // GetRulesPath returns the main config path
 func (z *ZedAdapter) GetRulesPath() string {
 	return ".zed/settings.json"
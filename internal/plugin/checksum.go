@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Checksum verification for downloaded plugin archives
+
+package plugin
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/eshanized/agen/internal/httpclient"
+)
+
+// checksumSuffix pins a source to an archive hash:
+// https://example.com/plugin.zip@sha256:<64 hex digits>
+const checksumSuffix = "@sha256:"
+
+// splitChecksum strips an @sha256:<hex> suffix from source.
+// Returns the bare source and the lower-cased hash ("" if none was given).
+func splitChecksum(source string) (string, string, error) {
+	i := strings.LastIndex(source, checksumSuffix)
+	if i < 0 {
+		return source, "", nil
+	}
+
+	sum := strings.ToLower(source[i+len(checksumSuffix):])
+	if !validSHA256(sum) {
+		return "", "", fmt.Errorf("invalid sha256 in %q: want 64 hex digits", source)
+	}
+	return source[:i], sum, nil
+}
+
+// validSHA256 reports whether s looks like a hex-encoded sha256
+func validSHA256(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// fetchSidecarChecksum looks for <url>.sha256 next to an archive.
+//
+// The file may hold just the hash or sha256sum output ("<hash>  name").
+// Returns "" (and no error) when there's no such file, so publishing one
+// stays optional; a file that exists but doesn't parse is an error.
+func fetchSidecarChecksum(ctx context.Context, url string) (string, error) {
+	resp, err := httpclient.Get(ctx, url+".sha256")
+	if err != nil {
+		return "", nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s.sha256: %w", url, err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || !validSHA256(strings.ToLower(fields[0])) {
+		return "", fmt.Errorf("%s.sha256 does not contain a sha256 hash", url)
+	}
+	return strings.ToLower(fields[0]), nil
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Bounded, timed downloads of plugin archives
+
+package plugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/eshanized/agen/internal/httpclient"
+)
+
+// DefaultDownloadTimeout bounds a whole plugin download, headers to last byte
+const DefaultDownloadTimeout = 60 * time.Second
+
+// MaxDownloadSize is the largest plugin archive we'll write to disk.
+// Plugins are markdown; anything near this is a mistake or a hostile server.
+// A variable so tests can lower it.
+var MaxDownloadSize int64 = 100 << 20 // 100 MB
+
+// downloadArchive streams url into dest and returns its sha256.
+//
+// How it works:
+//  1. Refuse up front if Content-Length is over MaxDownloadSize
+//  2. Copy at most MaxDownloadSize+1 bytes, hashing as they're written,
+//     so a server that lies about (or omits) the length is still stopped
+//  3. Compare what arrived with Content-Length to catch truncated bodies
+//
+// On any error the partial file is removed.
+func downloadArchive(url, dest string, timeout time.Duration) (sum string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resp, err := httpclient.Get(ctx, url)
+	if err != nil {
+		return "", describeDownloadError(ctx, timeout, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	}
+	if resp.ContentLength > MaxDownloadSize {
+		return "", fmt.Errorf("archive is %d bytes, over the %d MB limit", resp.ContentLength, MaxDownloadSize>>20)
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer func() {
+		out.Close()
+		if err != nil {
+			os.Remove(dest)
+		}
+	}()
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, hash), io.LimitReader(resp.Body, MaxDownloadSize+1))
+	if err != nil {
+		return "", fmt.Errorf("download interrupted after %d bytes: %w", n, describeDownloadError(ctx, timeout, err))
+	}
+	if n > MaxDownloadSize {
+		return "", fmt.Errorf("archive exceeds the %d MB limit; download aborted", MaxDownloadSize>>20)
+	}
+	if resp.ContentLength >= 0 && n != resp.ContentLength {
+		return "", fmt.Errorf("download incomplete: got %d of %d bytes", n, resp.ContentLength)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// describeDownloadError says so when err was caused by the timeout running out
+func describeDownloadError(ctx context.Context, timeout time.Duration, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+	return err
+}
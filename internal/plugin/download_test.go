@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for plugin archive downloads
+
+package plugin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadArchive(t *testing.T) {
+	saved := MaxDownloadSize
+	MaxDownloadSize = 16
+	defer func() { MaxDownloadSize = saved }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok.zip":
+			w.Write([]byte("small"))
+		case "/big.zip":
+			// no Content-Length, so only the copy limit can stop it
+			w.Header().Set("Transfer-Encoding", "chunked")
+			w.(http.Flusher).Flush()
+			w.Write([]byte(strings.Repeat("x", 64)))
+		case "/declared.zip":
+			w.Header().Set("Content-Length", "1000")
+		case "/truncated.zip":
+			w.Header().Set("Content-Length", "10")
+			w.Write([]byte("abc"))
+		case "/slow.zip":
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		path    string
+		timeout time.Duration
+		wantErr string
+	}{
+		{"/ok.zip", time.Second, ""},
+		{"/big.zip", time.Second, "exceeds"},
+		{"/declared.zip", time.Second, "over the"},
+		{"/truncated.zip", time.Second, "interrupted after 3 bytes"},
+		{"/slow.zip", 50 * time.Millisecond, "timed out after 50ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "plugin.zip")
+			sum, err := downloadArchive(srv.URL+tt.path, dest, tt.timeout)
+
+			if tt.wantErr == "" {
+				if err != nil || len(sum) != 64 {
+					t.Fatalf("downloadArchive() = %q, %v", sum, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("downloadArchive() error = %v, want %q", err, tt.wantErr)
+			}
+			if _, err := os.Stat(dest); !os.IsNotExist(err) {
+				t.Error("partial download was left on disk")
+			}
+		})
+	}
+}
@@ -8,14 +8,19 @@ package plugin
 
 import (
 	"archive/zip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/eshanized/agen/internal/config"
+	"github.com/eshanized/agen/internal/templates"
 )
 
 // Plugin represents an installed plugin
@@ -31,6 +36,7 @@ type Plugin struct {
 	Skills      []string          `json:"skills,omitempty"`
 	Workflows   []string          `json:"workflows,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	Dir         string            `json:"dir,omitempty"` // where the plugin's files live
 }
 
 // PluginType indicates what kind of plugin this is
@@ -47,6 +53,7 @@ const (
 type Manager struct {
 	pluginDir string
 	registry  *Registry
+	timeout   time.Duration // per download, see SetTimeout
 }
 
 // Registry stores information about installed plugins
@@ -55,15 +62,37 @@ type Registry struct {
 	path    string
 }
 
+// getPluginDir returns where plugins and their registry are stored
+func getPluginDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "agen", "plugins"), nil
+}
+
+// CheckRegistry verifies the registry file parses.
+// a missing registry is fine (no plugins yet), so that returns nil.
+func CheckRegistry() (string, error) {
+	pluginDir, err := getPluginDir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(pluginDir, "registry.json")
+	if _, err := loadRegistry(pluginDir); err != nil && !os.IsNotExist(err) {
+		return path, err
+	}
+	return path, nil
+}
+
 // NewManager creates a new plugin manager
 func NewManager() (*Manager, error) {
-	// Get user config directory
-	configDir, err := os.UserConfigDir()
+	pluginDir, err := getPluginDir()
 	if err != nil {
 		return nil, err
 	}
 
-	pluginDir := filepath.Join(configDir, "agen", "plugins")
 	if err := os.MkdirAll(pluginDir, 0755); err != nil {
 		return nil, err
 	}
@@ -79,56 +108,176 @@ func NewManager() (*Manager, error) {
 	return &Manager{
 		pluginDir: pluginDir,
 		registry:  registry,
+		timeout:   DefaultDownloadTimeout,
 	}, nil
 }
 
+// SetTimeout changes how long each plugin download may take.
+// Zero or less keeps the current value.
+func (m *Manager) SetTimeout(d time.Duration) {
+	if d > 0 {
+		m.timeout = d
+	}
+}
+
 // Install installs a plugin from a source
 //
 // Supported sources:
 // - GitHub: github.com/user/repo
 // - Local: path/to/plugin
 // - URL: https://example.com/plugin.zip
+//
+// Downloaded sources can be pinned with an @sha256:<hex> suffix; the
+// archive is hashed and nothing is installed if it doesn't match. A URL
+// without one is still checked against <url>.sha256 when the server has
+// that file. A pinned GitHub source is fetched as the archive for its ref
+// instead of with git clone, so there's something to hash.
 func (m *Manager) Install(source string) (*Plugin, error) {
+	plugin, err := m.fetch(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register the plugin
+	plugin.InstalledAt = time.Now().UTC().Format(time.RFC3339)
+	m.registry.Plugins[plugin.Name] = plugin
+	if err := m.registry.save(); err != nil {
+		return nil, fmt.Errorf("failed to save registry: %w", err)
+	}
+
+	return plugin, nil
+}
+
+// fetch downloads, clones or reads a plugin from source and loads its
+// metadata, without touching the registry. The source is recorded on the
+// plugin (as an absolute path for local ones) so Upgrade can fetch it again.
+func (m *Manager) fetch(source string) (*Plugin, error) {
+	bare, checksum, err := splitChecksum(source)
+	if err != nil {
+		return nil, err
+	}
+
 	var plugin *Plugin
-	var err error
+	local := false
 
-	if strings.HasPrefix(source, "github.com/") {
-		plugin, err = m.installFromGitHub(source)
-	} else if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
-		plugin, err = m.installFromURL(source)
+	if strings.HasPrefix(bare, "github.com/") {
+		if checksum != "" {
+			plugin, err = m.installFromGitHubArchive(bare, checksum)
+		} else {
+			plugin, err = m.installFromGitHub(bare)
+		}
+	} else if strings.HasPrefix(bare, "http://") || strings.HasPrefix(bare, "https://") {
+		plugin, err = m.installFromURL(bare, checksum)
 	} else {
-		plugin, err = m.installFromLocal(source)
+		if checksum != "" {
+			return nil, fmt.Errorf("@sha256 only applies to downloaded plugins, not local paths")
+		}
+		plugin, err = m.installFromLocal(bare)
+		local = true
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	// Register the plugin
-	m.registry.Plugins[plugin.Name] = plugin
+	plugin.Source = source
+	if local {
+		plugin.Source = plugin.Dir
+	}
+	return plugin, nil
+}
+
+// UpgradeResult describes what Upgrade did to one plugin
+type UpgradeResult struct {
+	Name       string `json:"name"`
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+	Changed    bool   `json:"changed"` // false when the source had nothing new
+}
+
+// Upgrade fetches a plugin again from the source it was installed from.
+//
+// How it works:
+//  1. Note the installed version and content (git HEAD, or archive sha256)
+//  2. Re-fetch the source the same way Install does
+//  3. If neither the content nor the plugin.json version moved, leave the
+//     registry alone; otherwise record the new version and InstalledAt
+//
+// A git clone with no new commits is not an error, just an unchanged result.
+func (m *Manager) Upgrade(name string) (*UpgradeResult, error) {
+	old, err := m.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if old.Source == "" {
+		return nil, fmt.Errorf("plugin %s has no recorded source; reinstall it with agen plugin install", name)
+	}
+
+	dir := m.Path(old)
+	headBefore := gitHead(dir)
+
+	plugin, err := m.fetch(old.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade %s: %w", name, err)
+	}
+
+	result := &UpgradeResult{
+		Name:       name,
+		OldVersion: old.Version,
+		NewVersion: plugin.Version,
+	}
+	result.Changed = plugin.Version != old.Version ||
+		gitHead(m.Path(plugin)) != headBefore ||
+		plugin.Metadata["sha256"] != old.Metadata["sha256"]
+
+	if !result.Changed {
+		return result, nil
+	}
+
+	// keep the registry key stable even if plugin.json renamed the plugin
+	plugin.Name = name
+	plugin.InstalledAt = time.Now().UTC().Format(time.RFC3339)
+	m.registry.Plugins[name] = plugin
 	if err := m.registry.save(); err != nil {
 		return nil, fmt.Errorf("failed to save registry: %w", err)
 	}
 
-	return plugin, nil
+	return result, nil
 }
 
-// installFromGitHub clones a plugin from GitHub
-func (m *Manager) installFromGitHub(source string) (*Plugin, error) {
-	// Parse source: github.com/user/repo[@version]
+// gitHead returns the commit checked out in dir, or "" if it isn't a git
+// clone (archive and local installs)
+func gitHead(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// parseGitHubSource splits github.com/user/repo[@version] into the repo
+// path, the plugin name (the repo) and the ref, which defaults to main
+func parseGitHubSource(source string) (repoPath, pluginName, version string, err error) {
 	parts := strings.Split(source, "@")
-	repoPath := strings.TrimPrefix(parts[0], "github.com/")
-	version := "main"
+	repoPath = strings.TrimPrefix(parts[0], "github.com/")
+	version = "main"
 	if len(parts) > 1 {
 		version = parts[1]
 	}
 
-	// Clone directory name
 	repoParts := strings.Split(repoPath, "/")
 	if len(repoParts) < 2 {
-		return nil, fmt.Errorf("invalid GitHub source: %s", source)
+		return "", "", "", fmt.Errorf("invalid GitHub source: %s", source)
+	}
+	return repoPath, repoParts[1], version, nil
+}
+
+// installFromGitHub clones a plugin from GitHub
+func (m *Manager) installFromGitHub(source string) (*Plugin, error) {
+	repoPath, pluginName, version, err := parseGitHubSource(source)
+	if err != nil {
+		return nil, err
 	}
-	pluginName := repoParts[1]
 
 	targetDir := filepath.Join(m.pluginDir, pluginName)
 
@@ -152,73 +301,116 @@ func (m *Manager) installFromGitHub(source string) (*Plugin, error) {
 	return m.loadPluginMetadata(targetDir)
 }
 
-// installFromURL downloads and extracts a plugin from a URL
-func (m *Manager) installFromURL(source string) (*Plugin, error) {
-	// Create temp directory for download
-	tempDir, err := os.MkdirTemp("", "agen-plugin-*")
+// installFromGitHubArchive installs a pinned GitHub source from the zip
+// GitHub serves for the ref, after checking it against checksum
+func (m *Manager) installFromGitHubArchive(source, checksum string) (*Plugin, error) {
+	repoPath, pluginName, version, err := parseGitHubSource(source)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp dir: %w", err)
-	}
-	defer os.RemoveAll(tempDir)
-
-	// Download the file
-	resp, err := http.Get(source)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("download failed with status: %d", resp.StatusCode)
-	}
+	archiveURL := fmt.Sprintf("https://github.com/%s/archive/%s.zip", repoPath, version)
+	return m.installArchive(archiveURL, pluginName, checksum)
+}
 
+// installFromURL downloads and extracts a plugin from a URL
+func (m *Manager) installFromURL(source, checksum string) (*Plugin, error) {
 	// Determine filename from URL or Content-Disposition
 	filename := filepath.Base(source)
 	if filename == "" || filename == "/" {
 		filename = "plugin.zip"
 	}
+	if !strings.HasSuffix(filename, ".zip") {
+		return nil, fmt.Errorf("unsupported file format: %s", filename)
+	}
+
+	// no pinned hash: use the publisher's .sha256 file if there is one
+	if checksum == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+		sidecar, err := fetchSidecarChecksum(ctx, source)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		checksum = sidecar
+	}
 
-	// Save to temp file
-	tempFile := filepath.Join(tempDir, filename)
-	out, err := os.Create(tempFile)
+	return m.installArchive(source, strings.TrimSuffix(filename, ".zip"), checksum)
+}
+
+// installArchive downloads a plugin zip, verifies it against checksum
+// (when one is known), and extracts it into the plugin directory.
+//
+// How it works:
+//  1. Download to a temp file, hashing as it's written (bounded in time
+//     and size, see downloadArchive)
+//  2. Refuse to go on if the sha256 doesn't match
+//  3. Extract, take the first top-level directory as the plugin root
+//     (archives usually wrap everything in one)
+//  4. Copy it into a staging directory beside the installed plugin and
+//     swap the two, so the old version stays until the new one is in place
+func (m *Manager) installArchive(url, pluginName, checksum string) (*Plugin, error) {
+	// Create temp directory for download
+	tempDir, err := os.MkdirTemp("", "agen-plugin-*")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create file: %w", err)
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
 	}
+	defer os.RemoveAll(tempDir)
 
-	_, err = io.Copy(out, resp.Body)
-	out.Close()
+	tempFile := filepath.Join(tempDir, pluginName+".zip")
+	sum, err := downloadArchive(url, tempFile, m.timeout)
 	if err != nil {
-		return nil, fmt.Errorf("failed to save file: %w", err)
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
 	}
 
-	// Extract if zip
-	if strings.HasSuffix(filename, ".zip") {
-		extractDir := filepath.Join(tempDir, "extracted")
-		if err := extractZip(tempFile, extractDir); err != nil {
-			return nil, fmt.Errorf("failed to extract: %w", err)
-		}
+	if checksum != "" && sum != checksum {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected sha256 %s, got %s; not installing", url, checksum, sum)
+	}
 
-		// Find the plugin directory (first directory with plugin.json or agents/)
-		entries, _ := os.ReadDir(extractDir)
-		pluginSrc := extractDir
-		for _, e := range entries {
-			if e.IsDir() {
-				pluginSrc = filepath.Join(extractDir, e.Name())
-				break
-			}
-		}
+	extractDir := filepath.Join(tempDir, "extracted")
+	if err := extractZip(tempFile, extractDir); err != nil {
+		return nil, fmt.Errorf("failed to extract: %w", err)
+	}
 
-		// Copy to plugins directory
-		pluginName := filepath.Base(strings.TrimSuffix(filename, ".zip"))
-		targetDir := filepath.Join(m.pluginDir, pluginName)
-		if err := copyDir(pluginSrc, targetDir); err != nil {
-			return nil, fmt.Errorf("failed to install: %w", err)
+	// Find the plugin directory (first directory with plugin.json or agents/)
+	entries, _ := os.ReadDir(extractDir)
+	pluginSrc := extractDir
+	for _, e := range entries {
+		if e.IsDir() {
+			pluginSrc = filepath.Join(extractDir, e.Name())
+			break
 		}
+	}
 
-		return m.loadPluginMetadata(targetDir)
+	// Stage next to the plugins directory entry, then swap it in, so an
+	// upgrade doesn't leave files the new version dropped behind
+	if err := os.MkdirAll(m.pluginDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to install: %w", err)
 	}
+	staging, err := os.MkdirTemp(m.pluginDir, "."+pluginName+"-new-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to install: %w", err)
+	}
+	defer os.RemoveAll(staging)
 
-	return nil, fmt.Errorf("unsupported file format: %s", filename)
+	if err := copyDir(pluginSrc, staging); err != nil {
+		return nil, fmt.Errorf("failed to install: %w", err)
+	}
+	targetDir := filepath.Join(m.pluginDir, pluginName)
+	if err := replaceDir(staging, targetDir); err != nil {
+		return nil, fmt.Errorf("failed to install: %w", err)
+	}
+
+	plugin, err := m.loadPluginMetadata(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	// recorded even when unpinned so Upgrade can tell if the archive changed
+	if plugin.Metadata == nil {
+		plugin.Metadata = make(map[string]string)
+	}
+	plugin.Metadata["sha256"] = sum
+	return plugin, nil
 }
 
 // extractZip extracts a zip file to a directory
@@ -232,7 +424,10 @@ func extractZip(src, dest string) error {
 	os.MkdirAll(dest, 0755)
 
 	for _, f := range r.File {
-		fpath := filepath.Join(dest, f.Name)
+		fpath, err := zipEntryPath(dest, f.Name)
+		if err != nil {
+			return err
+		}
 
 		if f.FileInfo().IsDir() {
 			os.MkdirAll(fpath, f.Mode())
@@ -265,6 +460,42 @@ func extractZip(src, dest string) error {
 	return nil
 }
 
+// zipEntryPath resolves an archive entry name under dest, rejecting any
+// that would land outside it (e.g. "../evil.txt") - the Zip Slip attack
+func zipEntryPath(dest, name string) (string, error) {
+	root := filepath.Clean(dest)
+	fpath := filepath.Join(root, name)
+	if fpath != root && !strings.HasPrefix(fpath, root+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+	return fpath, nil
+}
+
+// replaceDir moves src to dest, replacing whatever is at dest.
+// The old dest is renamed aside first and only removed once src is in
+// place; if the move fails, it's put back.
+func replaceDir(src, dest string) error {
+	backup := ""
+	if _, err := os.Lstat(dest); err == nil {
+		backup = filepath.Join(filepath.Dir(dest), fmt.Sprintf(".%s-old-%d", filepath.Base(dest), time.Now().UnixNano()))
+		if err := os.Rename(dest, backup); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(src, dest); err != nil {
+		if backup != "" {
+			os.Rename(backup, dest)
+		}
+		return err
+	}
+
+	if backup != "" {
+		os.RemoveAll(backup)
+	}
+	return nil
+}
+
 // copyDir copies a directory recursively
 func copyDir(src, dest string) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
@@ -308,9 +539,10 @@ func (m *Manager) installFromLocal(source string) (*Plugin, error) {
 	return m.loadPluginMetadata(absPath)
 }
 
-// loadPluginMetadata reads plugin.json from a plugin directory
+// loadPluginMetadata reads plugin.json (or plugin.yaml/.yml) from a
+// plugin directory
 func (m *Manager) loadPluginMetadata(dir string) (*Plugin, error) {
-	metadataPath := filepath.Join(dir, "plugin.json")
+	metadataPath := config.FindFile(filepath.Join(dir, "plugin"))
 	data, err := os.ReadFile(metadataPath)
 	if err != nil {
 		// Try to infer from directory structure
@@ -318,9 +550,10 @@ func (m *Manager) loadPluginMetadata(dir string) (*Plugin, error) {
 	}
 
 	var plugin Plugin
-	if err := json.Unmarshal(data, &plugin); err != nil {
-		return nil, fmt.Errorf("invalid plugin.json: %w", err)
+	if err := config.Unmarshal(metadataPath, data, &plugin); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", filepath.Base(metadataPath), err)
 	}
+	plugin.Dir = dir
 
 	return &plugin, nil
 }
@@ -333,21 +566,45 @@ func (m *Manager) inferPluginMetadata(dir string) (*Plugin, error) {
 		Version: "0.0.0",
 		Type:    PluginTypeBundle,
 		Source:  dir,
+		Dir:     dir,
 	}
 
 	// Scan for agents, skills, workflows
+	contents := scanContents(dir)
+	plugin.Agents = contents.Agents
+	plugin.Skills = contents.Skills
+	plugin.Workflows = contents.Workflows
+
+	return plugin, nil
+}
+
+// Contents lists the templates actually present in a plugin directory
+type Contents struct {
+	Agents    []string
+	Skills    []string
+	Workflows []string
+}
+
+// scanContents walks the agents/, skills/ and workflows/ folders of a plugin.
+// a skill only counts if its directory contains a SKILL.md.
+func scanContents(dir string) Contents {
+	var c Contents
+
 	if entries, err := os.ReadDir(filepath.Join(dir, "agents")); err == nil {
 		for _, e := range entries {
 			if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") {
-				plugin.Agents = append(plugin.Agents, strings.TrimSuffix(e.Name(), ".md"))
+				c.Agents = append(c.Agents, strings.TrimSuffix(e.Name(), ".md"))
 			}
 		}
 	}
 
 	if entries, err := os.ReadDir(filepath.Join(dir, "skills")); err == nil {
 		for _, e := range entries {
-			if e.IsDir() {
-				plugin.Skills = append(plugin.Skills, e.Name())
+			if !e.IsDir() {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(dir, "skills", e.Name(), "SKILL.md")); err == nil {
+				c.Skills = append(c.Skills, e.Name())
 			}
 		}
 	}
@@ -355,12 +612,164 @@ func (m *Manager) inferPluginMetadata(dir string) (*Plugin, error) {
 	if entries, err := os.ReadDir(filepath.Join(dir, "workflows")); err == nil {
 		for _, e := range entries {
 			if !e.IsDir() && strings.HasSuffix(e.Name(), ".md") {
-				plugin.Workflows = append(plugin.Workflows, strings.TrimSuffix(e.Name(), ".md"))
+				c.Workflows = append(c.Workflows, strings.TrimSuffix(e.Name(), ".md"))
 			}
 		}
 	}
 
-	return plugin, nil
+	return c
+}
+
+// Path returns the directory holding the plugin's files.
+// older registries don't record Dir, so fall back to the plugins folder.
+func (m *Manager) Path(p *Plugin) string {
+	if p.Dir != "" {
+		return p.Dir
+	}
+	return filepath.Join(m.pluginDir, p.Name)
+}
+
+// Dir returns the directory plugins are installed into
+func (m *Manager) Dir() string {
+	return m.pluginDir
+}
+
+// Orphans returns directories in the plugins folder that no registry
+// entry points to, left behind by an interrupted install or a registry
+// that was reset. Sorted, full paths.
+func (m *Manager) Orphans() ([]string, error) {
+	registered := make(map[string]bool)
+	for _, p := range m.registry.Plugins {
+		registered[filepath.Clean(m.Path(p))] = true
+	}
+
+	entries, err := os.ReadDir(m.pluginDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []string
+	for _, e := range entries {
+		path := filepath.Join(m.pluginDir, e.Name())
+		if e.IsDir() && !registered[path] {
+			orphans = append(orphans, path)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans, nil
+}
+
+// Inspect scans a plugin's files and reconciles them with its manifest.
+//
+// How it works:
+// 1. Look up the plugin in the registry
+// 2. Scan its directory for agents, skills and workflows
+// 3. Report anything declared but missing, or present but undeclared
+//
+// Why? A hand-written plugin.json easily drifts from the files it ships,
+// and nothing else notices until an install silently skips a template.
+func (m *Manager) Inspect(name string) (*Plugin, Contents, []string, error) {
+	plugin, err := m.Get(name)
+	if err != nil {
+		return nil, Contents{}, nil, err
+	}
+
+	dir := m.Path(plugin)
+	if _, err := os.Stat(dir); err != nil {
+		return plugin, Contents{}, []string{fmt.Sprintf("plugin directory missing: %s", dir)}, nil
+	}
+
+	contents := scanContents(dir)
+
+	var issues []string
+	issues = append(issues, diffNames("agent", plugin.Agents, contents.Agents)...)
+	issues = append(issues, diffNames("skill", plugin.Skills, contents.Skills)...)
+	issues = append(issues, diffNames("workflow", plugin.Workflows, contents.Workflows)...)
+
+	return plugin, contents, issues, nil
+}
+
+// Verify re-scans a plugin's directory and reports where its registry
+// entry and the files on disk disagree. It's Inspect without the contents,
+// for callers that only care whether the entry has drifted.
+func (m *Manager) Verify(name string) ([]string, error) {
+	_, _, issues, err := m.Inspect(name)
+	return issues, err
+}
+
+// Fix rewrites a plugin's registry entry to list the agents, skills and
+// workflows that are actually in its directory. Everything else in the
+// entry (version, source, checksum) is kept.
+func (m *Manager) Fix(name string) error {
+	plugin, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+
+	dir := m.Path(plugin)
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("plugin directory missing: %s; reinstall or uninstall %s", dir, name)
+	}
+
+	scanned, err := m.inferPluginMetadata(dir)
+	if err != nil {
+		return err
+	}
+	plugin.Agents = scanned.Agents
+	plugin.Skills = scanned.Skills
+	plugin.Workflows = scanned.Workflows
+
+	return m.registry.save()
+}
+
+// diffNames reports entries that exist on only one side of declared/actual
+func diffNames(kind string, declared, actual []string) []string {
+	onDisk := make(map[string]bool, len(actual))
+	for _, n := range actual {
+		onDisk[n] = true
+	}
+	listed := make(map[string]bool, len(declared))
+	for _, n := range declared {
+		listed[n] = true
+	}
+
+	var issues []string
+	for _, n := range declared {
+		if !onDisk[n] {
+			issues = append(issues, fmt.Sprintf("manifest lists %s %s but its file is missing", kind, n))
+		}
+	}
+	for _, n := range actual {
+		if !listed[n] {
+			issues = append(issues, fmt.Sprintf("%s %s is on disk but not listed in the manifest", kind, n))
+		}
+	}
+	return issues
+}
+
+// LoadPlugins reads the templates of every installed plugin into one set.
+//
+// Plugins are merged in name order, so when two of them ship a template
+// with the same name the first plugin alphabetically keeps it and the
+// clash is returned as a collision. Merge the result into the base set
+// with Templates.Merge so embedded templates win the same way.
+func (m *Manager) LoadPlugins() (*templates.Templates, []templates.Collision) {
+	merged := &templates.Templates{
+		Version:   templates.CurrentVersion,
+		Agents:    make(map[string]templates.Agent),
+		Skills:    make(map[string]templates.Skill),
+		Workflows: make(map[string]templates.Workflow),
+	}
+
+	plugins := m.List()
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+
+	var collisions []templates.Collision
+	for _, p := range plugins {
+		loaded := templates.LoadFromPlugin(m.Path(p), p.Name)
+		collisions = append(collisions, merged.Merge(loaded)...)
+	}
+	return merged, collisions
 }
 
 // Uninstall removes a plugin
@@ -404,6 +813,49 @@ func (m *Manager) Get(name string) (*Plugin, error) {
 	return plugin, nil
 }
 
+// RebuildRegistry recreates the registry from the plugins directory.
+//
+// How it works:
+// 1. Keep any existing entries whose files are outside the plugins dir (local installs)
+// 2. Re-read metadata for every subdirectory of the plugins dir
+// 3. Save the result, replacing whatever registry was there
+//
+// Used by "agen doctor --fix" when registry.json is corrupt or lost.
+func (m *Manager) RebuildRegistry() (int, error) {
+	plugins := make(map[string]*Plugin)
+
+	for name, p := range m.registry.Plugins {
+		if p.Dir != "" && filepath.Dir(p.Dir) != m.pluginDir {
+			if _, err := os.Stat(p.Dir); err == nil {
+				plugins[name] = p
+			}
+		}
+	}
+
+	entries, err := os.ReadDir(m.pluginDir)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		p, err := m.loadPluginMetadata(filepath.Join(m.pluginDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		plugins[p.Name] = p
+	}
+
+	m.registry.Plugins = plugins
+	if err := m.registry.save(); err != nil {
+		return 0, err
+	}
+
+	return len(plugins), nil
+}
+
 // Create initializes a new plugin project
 func (m *Manager) Create(name, pluginType string) (string, error) {
 	targetDir := filepath.Join(".", name)
@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for plugin archive extraction and upgrades
+
+package plugin
+
+import (
+	"archive/zip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeZip creates a zip at path with the given entry names and contents
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractZipRejectsTraversal(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "evil.zip")
+	writeZip(t, archive, map[string]string{"../evil.txt": "pwned"})
+
+	dest := filepath.Join(dir, "out")
+	if err := extractZip(archive, dest); err == nil {
+		t.Fatal("extractZip() accepted an entry outside the destination")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil.txt")); !os.IsNotExist(err) {
+		t.Error("evil.txt was written outside the destination")
+	}
+}
+
+func TestExtractZip(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "plugin.zip")
+	writeZip(t, archive, map[string]string{
+		"demo/agents/demo.md":         "# Demo",
+		"demo/skills/x/../y/SKILL.md": "# Y",
+	})
+
+	dest := filepath.Join(dir, "out")
+	if err := extractZip(archive, dest); err != nil {
+		t.Fatalf("extractZip() failed: %v", err)
+	}
+	for _, path := range []string{"demo/agents/demo.md", "demo/skills/y/SKILL.md"} {
+		if _, err := os.Stat(filepath.Join(dest, path)); err != nil {
+			t.Errorf("%s not extracted: %v", path, err)
+		}
+	}
+}
+
+func TestUpgradeLocalPlugin(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	src := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(filepath.Join(src, "agents"), 0755)
+	os.WriteFile(filepath.Join(src, "agents", "demo.md"), []byte("# Demo\n"), 0644)
+	manifest := filepath.Join(src, "plugin.json")
+	os.WriteFile(manifest, []byte(`{"name": "demo", "version": "1.0.0"}`), 0644)
+
+	m, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Install(src); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	result, err := m.Upgrade("demo")
+	if err != nil || result.Changed {
+		t.Fatalf("Upgrade() with nothing new = %+v, %v", result, err)
+	}
+
+	os.WriteFile(manifest, []byte(`{"name": "demo", "version": "1.1.0"}`), 0644)
+	result, err = m.Upgrade("demo")
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if !result.Changed || result.OldVersion != "1.0.0" || result.NewVersion != "1.1.0" {
+		t.Errorf("Upgrade() = %+v, want 1.0.0 -> 1.1.0", result)
+	}
+
+	// a fresh manager reads the bumped version back from the registry
+	m, _ = NewManager()
+	if p, _ := m.Get("demo"); p == nil || p.Version != "1.1.0" || p.InstalledAt == "" {
+		t.Errorf("registry entry = %+v", p)
+	}
+}
+
+func TestUpgradeArchiveRemovesDroppedFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	archive := filepath.Join(t.TempDir(), "demo.zip")
+	writeZip(t, archive, map[string]string{
+		"demo-main/plugin.json":      `{"name": "demo", "version": "1.0.0"}`,
+		"demo-main/agents/demo.md":   "# Demo",
+		"demo-main/agents/legacy.md": "# Legacy",
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/demo.zip" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, archive)
+	}))
+	defer srv.Close()
+
+	m, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plugin, err := m.Install(srv.URL + "/demo.zip")
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	dir := m.Path(plugin)
+	if _, err := os.Stat(filepath.Join(dir, "agents", "legacy.md")); err != nil {
+		t.Fatalf("legacy.md not installed: %v", err)
+	}
+
+	writeZip(t, archive, map[string]string{
+		"demo-main/plugin.json":    `{"name": "demo", "version": "2.0.0"}`,
+		"demo-main/agents/demo.md": "# Demo v2",
+	})
+	if result, err := m.Upgrade("demo"); err != nil || !result.Changed {
+		t.Fatalf("Upgrade() = %+v, %v", result, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "agents", "legacy.md")); !os.IsNotExist(err) {
+		t.Error("legacy.md survived an upgrade to a version without it")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "agents", "demo.md")); string(data) != "# Demo v2" {
+		t.Errorf("demo.md = %q, want the upgraded content", data)
+	}
+
+	// no staging or backup directories are left behind
+	if orphans, _ := m.Orphans(); len(orphans) != 0 {
+		t.Errorf("Orphans() after upgrade = %v", orphans)
+	}
+}
+
+func TestVerifyAndFix(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	src := filepath.Join(t.TempDir(), "kit")
+	os.MkdirAll(filepath.Join(src, "agents"), 0755)
+	os.WriteFile(filepath.Join(src, "agents", "one.md"), []byte("# One\n"), 0644)
+
+	m, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Install(src); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if issues, err := m.Verify("kit"); err != nil || len(issues) != 0 {
+		t.Fatalf("Verify() after install = %v, %v", issues, err)
+	}
+
+	// drift: one agent added, the other removed by hand
+	os.Remove(filepath.Join(src, "agents", "one.md"))
+	os.WriteFile(filepath.Join(src, "agents", "two.md"), []byte("# Two\n"), 0644)
+
+	issues, _ := m.Verify("kit")
+	if len(issues) != 2 {
+		t.Fatalf("Verify() = %v, want 2 issues", issues)
+	}
+
+	if err := m.Fix("kit"); err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if issues, _ := m.Verify("kit"); len(issues) != 0 {
+		t.Errorf("Verify() after Fix = %v", issues)
+	}
+	if p, _ := m.Get("kit"); len(p.Agents) != 1 || p.Agents[0] != "two" {
+		t.Errorf("Agents after Fix = %v, want [two]", p.Agents)
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	m, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a registered plugin in the plugins folder, one left over from an
+	// interrupted install, and a local plugin that lives elsewhere
+	for _, name := range []string{"kept", "leftover"} {
+		os.MkdirAll(filepath.Join(m.Dir(), name), 0755)
+	}
+	local := filepath.Join(t.TempDir(), "local")
+	os.MkdirAll(local, 0755)
+	m.registry.Plugins["kept"] = &Plugin{Name: "kept"}
+	m.registry.Plugins["local"] = &Plugin{Name: "local", Dir: local}
+
+	orphans, err := m.Orphans()
+	if err != nil {
+		t.Fatalf("Orphans() error = %v", err)
+	}
+	if len(orphans) != 1 || orphans[0] != filepath.Join(m.Dir(), "leftover") {
+		t.Errorf("Orphans() = %v, want only leftover", orphans)
+	}
+}
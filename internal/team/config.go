@@ -7,11 +7,17 @@
 package team
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/mail"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/eshanized/agen/internal/config"
+	"github.com/eshanized/agen/internal/ide"
+	"github.com/eshanized/agen/internal/templates"
 )
 
 // TeamConfig represents shared team configuration
@@ -24,14 +30,19 @@ type TeamConfig struct {
 	RequiredSkills []string          `json:"required_skills,omitempty"`
 	LockedVersions map[string]string `json:"locked_versions,omitempty"`
 	Settings       TeamSettings      `json:"settings"`
+	Members        []TeamMember      `json:"members,omitempty"`
 	CreatedAt      time.Time         `json:"created_at"`
 	UpdatedAt      time.Time         `json:"updated_at"`
+
+	// path is the file this config was loaded from, so Save keeps its format
+	path string
 }
 
 // TeamSettings contains team-wide settings
 type TeamSettings struct {
 	EnforceAgents  bool   `json:"enforce_agents"`
 	EnforceSkills  bool   `json:"enforce_skills"`
+	EnforceContent bool   `json:"enforce_content"`
 	AllowPlugins   bool   `json:"allow_plugins"`
 	DefaultIDE     string `json:"default_ide,omitempty"`
 	TemplateSource string `json:"template_source,omitempty"`
@@ -46,13 +57,30 @@ type TeamMember struct {
 	JoinedAt time.Time `json:"joined_at"`
 }
 
-const teamConfigFile = ".agen-team.json"
+// Member roles. Owners run the team config; a team keeps at least one
+// once it has any.
+const (
+	RoleOwner       = "owner"
+	RoleMaintainer  = "maintainer"
+	RoleContributor = "contributor"
+)
+
+// Roles lists the member roles, most privileged first
+var Roles = []string{RoleOwner, RoleMaintainer, RoleContributor}
+
+// teamConfigBase is the team config file name without its extension.
+// It may be .json (the default for new files), .yaml or .yml.
+const teamConfigBase = ".agen-team"
+
+// ConfigPath returns the team config file in dir: the existing one if
+// there is one, otherwise where a new JSON file would go.
+func ConfigPath(dir string) string {
+	return config.FindFile(filepath.Join(dir, teamConfigBase))
+}
 
 // InitTeam initializes team configuration in a project
 func InitTeam(dir, name string) (*TeamConfig, error) {
-	configPath := filepath.Join(dir, teamConfigFile)
-
-	if _, err := os.Stat(configPath); err == nil {
+	if _, err := os.Stat(ConfigPath(dir)); err == nil {
 		return nil, fmt.Errorf("team config already exists")
 	}
 
@@ -80,32 +108,33 @@ func InitTeam(dir, name string) (*TeamConfig, error) {
 
 // LoadTeamConfig loads team configuration from a project
 func LoadTeamConfig(dir string) (*TeamConfig, error) {
-	configPath := filepath.Join(dir, teamConfigFile)
+	configPath := ConfigPath(dir)
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("no team config found: %w", err)
 	}
 
-	var config TeamConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	var teamConfig TeamConfig
+	if err := config.Unmarshal(configPath, data, &teamConfig); err != nil {
 		return nil, fmt.Errorf("invalid team config: %w", err)
 	}
+	teamConfig.path = configPath
 
-	return &config, nil
+	return &teamConfig, nil
 }
 
-// Save writes the team config to disk
+// Save writes the team config to disk, keeping the format (JSON or YAML)
+// of the file it was loaded from.
 func (c *TeamConfig) Save(dir string) error {
 	c.UpdatedAt = time.Now()
 
-	data, err := json.MarshalIndent(c, "", "  ")
-	if err != nil {
-		return err
+	configPath := c.path
+	if configPath == "" || filepath.Dir(configPath) != filepath.Clean(dir) {
+		configPath = ConfigPath(dir)
 	}
 
-	configPath := filepath.Join(dir, teamConfigFile)
-	return os.WriteFile(configPath, data, 0644)
+	return config.WriteFile(configPath, c)
 }
 
 // AddRequired adds a required agent or skill
@@ -157,6 +186,87 @@ func (c *TeamConfig) RemoveRequired(itemType, name string) error {
 	return fmt.Errorf("not found: %s", name)
 }
 
+// AddMember records a new member. Members are told apart by email, which
+// must be a plain address (no display name). An empty role makes the
+// first member the owner and anyone after a contributor.
+func (c *TeamConfig) AddMember(name, email, role string) (*TeamMember, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("member name is empty")
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return nil, fmt.Errorf("invalid email %q", email)
+	}
+
+	if role == "" {
+		role = RoleContributor
+		if c.owners() == 0 {
+			role = RoleOwner
+		}
+	}
+	if !validRole(role) {
+		return nil, fmt.Errorf("unknown role %q: want one of %s", role, strings.Join(Roles, ", "))
+	}
+
+	if _, m := c.findMember(email); m != nil {
+		return nil, fmt.Errorf("%s is already a member (%s)", email, m.Role)
+	}
+
+	c.Members = append(c.Members, TeamMember{
+		Name:     name,
+		Email:    email,
+		Role:     role,
+		JoinedAt: time.Now(),
+	})
+	return &c.Members[len(c.Members)-1], nil
+}
+
+// RemoveMember removes the member with email. The last owner can't be
+// removed: someone has to be left to run the team.
+func (c *TeamConfig) RemoveMember(email string) (*TeamMember, error) {
+	i, m := c.findMember(email)
+	if m == nil {
+		return nil, fmt.Errorf("not a member: %s", email)
+	}
+	if m.Role == RoleOwner && c.owners() == 1 {
+		return nil, fmt.Errorf("%s is the team's last owner: add another owner first", email)
+	}
+
+	removed := *m
+	c.Members = append(c.Members[:i], c.Members[i+1:]...)
+	return &removed, nil
+}
+
+// findMember returns the member with email (compared case-insensitively)
+// and its index, or nil
+func (c *TeamConfig) findMember(email string) (int, *TeamMember) {
+	for i := range c.Members {
+		if strings.EqualFold(c.Members[i].Email, email) {
+			return i, &c.Members[i]
+		}
+	}
+	return -1, nil
+}
+
+// owners counts the members with the owner role
+func (c *TeamConfig) owners() int {
+	n := 0
+	for _, m := range c.Members {
+		if m.Role == RoleOwner {
+			n++
+		}
+	}
+	return n
+}
+
+func validRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 // LockVersion locks a specific template version
 func (c *TeamConfig) LockVersion(name, version string) {
 	if c.LockedVersions == nil {
@@ -170,124 +280,319 @@ func (c *TeamConfig) UnlockVersion(name string) {
 	delete(c.LockedVersions, name)
 }
 
-// Sync synchronizes local installation with team config
+// SyncResult reports what Sync did
 type SyncResult struct {
 	Added   []string `json:"added"`
 	Updated []string `json:"updated"`
 	Removed []string `json:"removed"`
+	Missing []string `json:"missing"` // required, but the source doesn't have them
 	Errors  []string `json:"errors"`
 }
 
-// Sync synchronizes the project with team requirements
-func (c *TeamConfig) Sync(projectDir string) (*SyncResult, error) {
+// SyncOptions says how Sync reaches the project and where templates come
+// from
+type SyncOptions struct {
+	Adapter ide.Adapter // the project's IDE
+
+	// Installed is everything the project has now. Single-file IDEs
+	// re-render their whole file, so anything left out would be dropped.
+	Installed *templates.Templates
+
+	// Source provides the templates that are missing, and SourceName
+	// describes it in messages ("embedded", "remote acme", ...)
+	Source     *templates.Templates
+	SourceName string
+
+	// SourceAt returns the source as of a version, for templates locked
+	// to one other than Source.Version. Nil means locked templates can
+	// only be installed when Source is at their version.
+	SourceAt func(version string) (*templates.Templates, error)
+
+	IgnoreLocks bool // install from Source whatever the locks say
+	DryRun      bool
+}
+
+// Sync installs the required agents and skills the project doesn't have,
+// taking them from opts.Source and writing them through opts.Adapter.
+// Templates already installed are left alone. A template locked to a
+// version is installed from the source at that version (see SourceAt).
+// A required template the source doesn't have is reported in Missing; one
+// whose locked version can't be fetched is reported in Errors.
+func (c *TeamConfig) Sync(projectDir string, opts SyncOptions) (*SyncResult, error) {
 	result := &SyncResult{
 		Added:   []string{},
 		Updated: []string{},
 		Removed: []string{},
+		Missing: []string{},
 		Errors:  []string{},
 	}
 
-	// Create .agent directories if needed
-	agentDir := filepath.Join(projectDir, ".agent", "agents")
-	skillDir := filepath.Join(projectDir, ".agent", "skills")
-	os.MkdirAll(agentDir, 0755)
-	os.MkdirAll(skillDir, 0755)
+	set := &templates.Templates{
+		Version:   opts.Installed.Version,
+		Agents:    make(map[string]templates.Agent),
+		Skills:    make(map[string]templates.Skill),
+		Workflows: make(map[string]templates.Workflow),
+	}
+	set.Merge(opts.Installed)
 
-	// Check required agents are installed
-	for _, agent := range c.RequiredAgents {
-		agentPath := filepath.Join(agentDir, agent+".md")
-		if _, err := os.Stat(agentPath); os.IsNotExist(err) {
-			// Try to copy from embedded templates
-			if err := copyEmbeddedAgent(agent, agentPath); err != nil {
-				result.Errors = append(result.Errors, fmt.Sprintf("agent:%s - %v", agent, err))
-			} else {
-				result.Added = append(result.Added, "agent:"+agent)
-			}
+	pinned := make(map[string]*templates.Templates)
+	for _, name := range c.RequiredAgents {
+		if _, ok := set.Agents[name]; ok {
+			continue
+		}
+		source, label, err := c.sourceFor(name, opts, pinned)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("agent:%s - %v", name, err))
+			continue
+		}
+		agent, ok := source.Agents[name]
+		if !ok {
+			result.Missing = append(result.Missing, fmt.Sprintf("agent:%s - not in %s templates", name, label))
+			continue
 		}
+		set.Agents[name] = agent
+		result.Added = append(result.Added, c.syncedName("agent", name, opts))
 	}
 
-	// Check required skills are installed
-	for _, skill := range c.RequiredSkills {
-		skillPath := filepath.Join(skillDir, skill)
-		if _, err := os.Stat(skillPath); os.IsNotExist(err) {
-			// Try to copy from embedded templates
-			if err := copyEmbeddedSkill(skill, skillPath); err != nil {
-				result.Errors = append(result.Errors, fmt.Sprintf("skill:%s - %v", skill, err))
-			} else {
-				result.Added = append(result.Added, "skill:"+skill)
-			}
+	for _, name := range c.RequiredSkills {
+		if _, ok := set.Skills[name]; ok {
+			continue
+		}
+		source, label, err := c.sourceFor(name, opts, pinned)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("skill:%s - %v", name, err))
+			continue
 		}
+		skill, ok := source.Skills[name]
+		if !ok {
+			result.Missing = append(result.Missing, fmt.Sprintf("skill:%s - not in %s templates", name, label))
+			continue
+		}
+		set.Skills[name] = skill
+		result.Added = append(result.Added, c.syncedName("skill", name, opts))
+	}
+
+	if len(result.Added) == 0 || opts.DryRun {
+		return result, nil
 	}
 
+	// the rest of the set is what's installed, so forcing only adds the
+	// new templates (or re-renders a single-file format, which an unforced
+	// update would skip)
+	if _, err := opts.Adapter.Update(set, ide.UpdateOptions{TargetDir: projectDir, Force: true}); err != nil {
+		return nil, fmt.Errorf("failed to install for %s: %w", opts.Adapter.Name(), err)
+	}
 	return result, nil
 }
 
-// copyEmbeddedAgent copies an agent from embedded templates
-func copyEmbeddedAgent(name, destPath string) error {
-	// Read from embedded templates data
-	srcPath := filepath.Join("agents", name+".md")
-	content, err := readEmbeddedFile(srcPath)
-	if err != nil {
-		return fmt.Errorf("agent not found in templates: %s", name)
+// Locked returns the version name is locked to, if it is
+func (c *TeamConfig) Locked(name string) (string, bool) {
+	version, ok := c.LockedVersions[name]
+	return version, ok
+}
+
+// HoldLocked keeps every locked template in latest at its installed copy,
+// so installing latest doesn't move it, and returns a note for each one
+// held. A locked template that isn't installed is taken out of latest
+// altogether: 'agen team sync' installs it at its locked version.
+func (c *TeamConfig) HoldLocked(latest, installed *templates.Templates) []string {
+	names := make([]string, 0, len(c.LockedVersions))
+	for name := range c.LockedVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var notes []string
+	note := func(kind, name string) {
+		notes = append(notes, fmt.Sprintf("%s %s locked at %s: not updated", kind, name, c.LockedVersions[name]))
+	}
+	for _, name := range names {
+		if _, ok := latest.Agents[name]; ok {
+			if agent, ok := installed.Agents[name]; ok {
+				latest.Agents[name] = agent
+			} else {
+				delete(latest.Agents, name)
+			}
+			note("agent", name)
+		}
+		if _, ok := latest.Skills[name]; ok {
+			if skill, ok := installed.Skills[name]; ok {
+				latest.Skills[name] = skill
+			} else {
+				delete(latest.Skills, name)
+			}
+			note("skill", name)
+		}
+		if _, ok := latest.Workflows[name]; ok {
+			if workflow, ok := installed.Workflows[name]; ok {
+				latest.Workflows[name] = workflow
+			} else {
+				delete(latest.Workflows, name)
+			}
+			note("workflow", name)
+		}
 	}
-	return os.WriteFile(destPath, content, 0644)
+	return notes
 }
 
-// copyEmbeddedSkill copies a skill from embedded templates
-func copyEmbeddedSkill(name, destPath string) error {
-	os.MkdirAll(destPath, 0755)
-	srcPath := filepath.Join("skills", name, "SKILL.md")
-	content, err := readEmbeddedFile(srcPath)
+// sourceFor returns the templates to install name from, and how to
+// describe them: opts.Source, or the source at name's locked version.
+// Each locked version is fetched once, through pinned.
+func (c *TeamConfig) sourceFor(name string, opts SyncOptions, pinned map[string]*templates.Templates) (*templates.Templates, string, error) {
+	locked, ok := c.Locked(name)
+	if !ok || opts.IgnoreLocks || sameVersion(locked, opts.Source.Version) {
+		return opts.Source, opts.SourceName, nil
+	}
+
+	label := fmt.Sprintf("%s v%s", opts.SourceName, strings.TrimPrefix(locked, "v"))
+	if source, ok := pinned[locked]; ok {
+		return source, label, nil
+	}
+	if opts.SourceAt == nil {
+		return nil, "", fmt.Errorf("locked at %s, but the source has %s", locked, opts.Source.Version)
+	}
+	source, err := opts.SourceAt(locked)
 	if err != nil {
-		return fmt.Errorf("skill not found in templates: %s", name)
+		return nil, "", fmt.Errorf("locked at %s, which could not be fetched: %w", locked, err)
+	}
+	pinned[locked] = source
+	return source, label, nil
+}
+
+// syncedName is how Sync reports an added template, with its locked
+// version when that's what was installed
+func (c *TeamConfig) syncedName(kind, name string, opts SyncOptions) string {
+	if locked, ok := c.Locked(name); ok && !opts.IgnoreLocks {
+		return fmt.Sprintf("%s:%s (locked at %s)", kind, name, locked)
 	}
-	return os.WriteFile(filepath.Join(destPath, "SKILL.md"), content, 0644)
+	return kind + ":" + name
 }
 
-// readEmbeddedFile reads a file from embedded templates
-func readEmbeddedFile(path string) ([]byte, error) {
-	// Try to read from internal/templates/data
-	fullPath := filepath.Join("internal", "templates", "data", path)
-	return os.ReadFile(fullPath)
+// sameVersion compares versions with or without a leading "v"
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
 }
 
-// Validate checks if the project meets team requirements
+// ValidationResult reports how a project measures up to the team config
 type ValidationResult struct {
 	Valid    bool     `json:"valid"`
 	Missing  []string `json:"missing"`
+	Modified []string `json:"modified"` // installed, but not as the source has them
 	Warnings []string `json:"warnings"`
 }
 
-func (c *TeamConfig) Validate(projectDir string) *ValidationResult {
+// ValidateOptions says how Validate reads the project and what its
+// templates should look like. Source, SourceName and SourceAt are as for
+// SyncOptions.
+type ValidateOptions struct {
+	Adapter     ide.Adapter
+	Source      *templates.Templates
+	SourceName  string
+	SourceAt    func(version string) (*templates.Templates, error)
+	IgnoreLocks bool
+}
+
+// Validate checks that the required agents and skills are installed and
+// unmodified. Content is compared the way 'agen diff' does: the source's
+// copy (at its locked version, if it has one) is rendered through the
+// adapter and hashed against what's installed, so single-file IDEs compare
+// like with like. Missing templates fail validation under EnforceAgents or
+// EnforceSkills, modified ones under EnforceContent; otherwise they're
+// reported without failing.
+func (c *TeamConfig) Validate(projectDir string, opts ValidateOptions) (*ValidationResult, error) {
 	result := &ValidationResult{
 		Valid:    true,
 		Missing:  []string{},
+		Modified: []string{},
 		Warnings: []string{},
 	}
 
-	// Check required agents
-	agentDir := filepath.Join(projectDir, ".agent", "agents")
-	for _, agent := range c.RequiredAgents {
-		agentPath := filepath.Join(agentDir, agent+".md")
-		if _, err := os.Stat(agentPath); os.IsNotExist(err) {
-			result.Missing = append(result.Missing, "agent:"+agent)
+	installed, err := opts.Adapter.GetInstalledContent(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read installed templates: %w", err)
+	}
+
+	sync := SyncOptions{Source: opts.Source, SourceName: opts.SourceName, SourceAt: opts.SourceAt, IgnoreLocks: opts.IgnoreLocks}
+	pinned := make(map[string]*templates.Templates)
+
+	// canonical holds the source's copy of each installed requirement,
+	// rendered in one go below
+	canonical := &templates.Templates{
+		Version:   opts.Source.Version,
+		Agents:    make(map[string]templates.Agent),
+		Skills:    make(map[string]templates.Skill),
+		Workflows: make(map[string]templates.Workflow),
+	}
+
+	for _, name := range c.RequiredAgents {
+		if _, ok := installed.Agents[name]; !ok {
+			result.Missing = append(result.Missing, "agent:"+name)
 			if c.Settings.EnforceAgents {
 				result.Valid = false
 			}
+			continue
+		}
+		source, label, err := c.sourceFor(name, sync, pinned)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("agent:%s - content not checked: %v", name, err))
+			continue
+		}
+		agent, ok := source.Agents[name]
+		if !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("agent:%s - not in %s templates, content not checked", name, label))
+			continue
 		}
+		canonical.Agents[name] = agent
 	}
 
-	// Check required skills
-	skillDir := filepath.Join(projectDir, ".agent", "skills")
-	for _, skill := range c.RequiredSkills {
-		skillPath := filepath.Join(skillDir, skill)
-		if _, err := os.Stat(skillPath); os.IsNotExist(err) {
-			result.Missing = append(result.Missing, "skill:"+skill)
+	for _, name := range c.RequiredSkills {
+		if _, ok := installed.Skills[name]; !ok {
+			result.Missing = append(result.Missing, "skill:"+name)
 			if c.Settings.EnforceSkills {
 				result.Valid = false
 			}
+			continue
 		}
+		source, label, err := c.sourceFor(name, sync, pinned)
+		if err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("skill:%s - content not checked: %v", name, err))
+			continue
+		}
+		skill, ok := source.Skills[name]
+		if !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("skill:%s - not in %s templates, content not checked", name, label))
+			continue
+		}
+		canonical.Skills[name] = skill
 	}
 
-	return result
+	if len(canonical.Agents)+len(canonical.Skills) == 0 {
+		return result, nil
+	}
+	rendered, err := ide.RenderedContent(opts.Adapter, canonical)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s templates: %w", opts.SourceName, err)
+	}
+
+	drifted := func(kind, name string, installed, want map[string]string) {
+		if templates.Hash([]byte(installed[name])) == templates.Hash([]byte(want[name])) {
+			return
+		}
+		result.Modified = append(result.Modified, kind+":"+name)
+		if c.Settings.EnforceContent {
+			result.Valid = false
+		}
+	}
+	for _, name := range c.RequiredAgents {
+		if _, ok := canonical.Agents[name]; ok {
+			drifted("agent", name, installed.Agents, rendered.Agents)
+		}
+	}
+	for _, name := range c.RequiredSkills {
+		if _, ok := canonical.Skills[name]; ok {
+			drifted("skill", name, installed.Skills, rendered.Skills)
+		}
+	}
+
+	return result, nil
 }
@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for team config sync
+
+package team
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eshanized/agen/internal/ide"
+	"github.com/eshanized/agen/internal/templates"
+)
+
+// testSet returns a template set holding the named agents and skills
+func testSet(version string, agents, skills []string) *templates.Templates {
+	tmpl := &templates.Templates{
+		Version:   version,
+		Agents:    make(map[string]templates.Agent),
+		Skills:    make(map[string]templates.Skill),
+		Workflows: make(map[string]templates.Workflow),
+	}
+	for _, name := range agents {
+		tmpl.Agents[name] = templates.Agent{Name: name, Content: "# " + name + "\n"}
+	}
+	for _, name := range skills {
+		tmpl.Skills[name] = templates.Skill{Name: name, Content: "# " + name + "\n"}
+	}
+	return tmpl
+}
+
+func TestSync(t *testing.T) {
+	dir := t.TempDir()
+	adapter := ide.GetAdapter("antigravity")
+	installed := testSet("2.0.0", []string{"debugger"}, nil)
+	if err := adapter.Install(installed, ide.InstallOptions{TargetDir: dir, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &TeamConfig{
+		Name:           "acme",
+		RequiredAgents: []string{"debugger", "security-auditor", "made-up", "frontend-specialist"},
+		RequiredSkills: []string{"clean-code"},
+		LockedVersions: map[string]string{"frontend-specialist": "1.2.0"},
+	}
+	source := testSet("2.0.0", []string{"debugger", "security-auditor", "frontend-specialist"}, []string{"clean-code"})
+
+	result, err := cfg.Sync(dir, SyncOptions{
+		Adapter:    adapter,
+		Installed:  installed,
+		Source:     source,
+		SourceName: "embedded",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(result.Added, ","); got != "agent:security-auditor,skill:clean-code" {
+		t.Errorf("Added = %s", got)
+	}
+	if len(result.Missing) != 1 || !strings.HasPrefix(result.Missing[0], "agent:made-up") {
+		t.Errorf("Missing = %v", result.Missing)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "locked at 1.2.0") {
+		t.Errorf("Errors = %v", result.Errors)
+	}
+
+	for _, path := range []string{"agents/debugger.md", "agents/security-auditor.md", "skills/clean-code/SKILL.md"} {
+		if _, err := os.Stat(filepath.Join(dir, ".agent", path)); err != nil {
+			t.Errorf("%s not installed: %v", path, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".agent", "agents", "frontend-specialist.md")); err == nil {
+		t.Error("a locked agent was installed from a source at another version")
+	}
+}
+
+// installedProject returns a project with an empty Antigravity install
+func installedProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := ide.GetAdapter("antigravity").Install(testSet("2.0.0", nil, nil), ide.InstallOptions{TargetDir: dir, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestSyncInstallsLockedVersion(t *testing.T) {
+	dir := installedProject(t)
+	adapter := ide.GetAdapter("antigravity")
+	cfg := &TeamConfig{
+		RequiredAgents: []string{"frontend-specialist", "debugger"},
+		LockedVersions: map[string]string{"frontend-specialist": "v1.2.0"},
+	}
+
+	var fetched []string
+	opts := SyncOptions{
+		Adapter:    adapter,
+		Installed:  testSet("2.0.0", nil, nil),
+		Source:     testSet("2.0.0", []string{"frontend-specialist", "debugger"}, nil),
+		SourceName: "embedded",
+		SourceAt: func(version string) (*templates.Templates, error) {
+			fetched = append(fetched, version)
+			old := testSet(version, []string{"frontend-specialist"}, nil)
+			old.Agents["frontend-specialist"] = templates.Agent{Name: "frontend-specialist", Content: "# old\n"}
+			return old, nil
+		},
+	}
+
+	result, err := cfg.Sync(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(result.Added, ","); got != "agent:frontend-specialist (locked at v1.2.0),agent:debugger" {
+		t.Errorf("Added = %s", got)
+	}
+	if len(fetched) != 1 || fetched[0] != "v1.2.0" {
+		t.Errorf("SourceAt called with %v", fetched)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, ".agent", "agents", "frontend-specialist.md"))
+	if err != nil || !strings.Contains(string(content), "# old") {
+		t.Errorf("frontend-specialist = %q, %v; want the locked version", content, err)
+	}
+
+	// --ignore-locks takes it from the current source
+	dir = installedProject(t)
+	opts.IgnoreLocks = true
+	if _, err := cfg.Sync(dir, opts); err != nil {
+		t.Fatal(err)
+	}
+	content, _ = os.ReadFile(filepath.Join(dir, ".agent", "agents", "frontend-specialist.md"))
+	if strings.Contains(string(content), "# old") {
+		t.Error("IgnoreLocks still installed the locked version")
+	}
+}
+
+func TestHoldLocked(t *testing.T) {
+	cfg := &TeamConfig{LockedVersions: map[string]string{"debugger": "1.0.0", "clean-code": "1.0.0", "unused": "1.0.0"}}
+	installed := testSet("1.0.0", []string{"debugger"}, nil)
+	installed.Agents["debugger"] = templates.Agent{Name: "debugger", Content: "# installed\n"}
+	latest := testSet("2.0.0", []string{"debugger", "frontend-specialist"}, []string{"clean-code"})
+
+	notes := cfg.HoldLocked(latest, installed)
+
+	if got := latest.Agents["debugger"].Content; got != "# installed\n" {
+		t.Errorf("locked debugger = %q, want the installed copy", got)
+	}
+	if _, ok := latest.Skills["clean-code"]; ok {
+		t.Error("a locked skill that isn't installed was left in the update")
+	}
+	if _, ok := latest.Agents["frontend-specialist"]; !ok {
+		t.Error("an unlocked agent was dropped")
+	}
+	want := "skill clean-code locked at 1.0.0: not updated,agent debugger locked at 1.0.0: not updated"
+	if got := strings.Join(notes, ","); got != want {
+		t.Errorf("notes = %s", got)
+	}
+}
+
+func TestValidateContent(t *testing.T) {
+	dir := installedProject(t)
+	adapter := ide.GetAdapter("antigravity")
+	source := testSet("2.0.0", []string{"debugger", "security-auditor"}, []string{"clean-code"})
+	if err := adapter.Install(source, ide.InstallOptions{TargetDir: dir, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, ".agent", "agents", "security-auditor.md")
+	if err := os.WriteFile(path, []byte("# edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &TeamConfig{
+		RequiredAgents: []string{"debugger", "security-auditor", "frontend-specialist"},
+		RequiredSkills: []string{"clean-code"},
+	}
+	opts := ValidateOptions{Adapter: adapter, Source: source, SourceName: "embedded"}
+
+	result, err := cfg.Validate(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid {
+		t.Error("Validate() failed without any enforcement")
+	}
+	if got := strings.Join(result.Missing, ","); got != "agent:frontend-specialist" {
+		t.Errorf("Missing = %s", got)
+	}
+	if got := strings.Join(result.Modified, ","); got != "agent:security-auditor" {
+		t.Errorf("Modified = %s", got)
+	}
+
+	cfg.Settings.EnforceContent = true
+	if result, err := cfg.Validate(dir, opts); err != nil || result.Valid {
+		t.Errorf("Validate() with enforce_content = %+v, %v; want invalid", result, err)
+	}
+}
+
+func TestMembers(t *testing.T) {
+	cfg := &TeamConfig{}
+
+	first, err := cfg.AddMember("Ada Lovelace", "ada@example.com", "")
+	if err != nil || first.Role != RoleOwner || first.JoinedAt.IsZero() {
+		t.Fatalf("AddMember() first = %+v, %v; want an owner with a join time", first, err)
+	}
+	second, err := cfg.AddMember("Grace Hopper", "grace@example.com", "")
+	if err != nil || second.Role != RoleContributor {
+		t.Fatalf("AddMember() second = %+v, %v; want a contributor", second, err)
+	}
+
+	for _, bad := range []struct{ name, email, role string }{
+		{"Ada", "ADA@example.com", ""},
+		{"Bob", "not-an-email", ""},
+		{"Bob", "Bob <bob@example.com>", ""},
+		{"Bob", "bob@example.com", "lead"},
+		{"", "bob@example.com", ""},
+	} {
+		if _, err := cfg.AddMember(bad.name, bad.email, bad.role); err == nil {
+			t.Errorf("AddMember(%q, %q, %q) should fail", bad.name, bad.email, bad.role)
+		}
+	}
+
+	if _, err := cfg.RemoveMember("ada@example.com"); err == nil {
+		t.Error("RemoveMember() removed the last owner")
+	}
+	if _, err := cfg.AddMember("Alan Turing", "alan@example.com", RoleOwner); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.RemoveMember("Ada@Example.com"); err != nil {
+		t.Errorf("RemoveMember() with another owner left = %v", err)
+	}
+	if _, err := cfg.RemoveMember("nobody@example.com"); err == nil {
+		t.Error("RemoveMember() of a non-member should fail")
+	}
+	if len(cfg.Members) != 2 {
+		t.Errorf("members = %+v", cfg.Members)
+	}
+}
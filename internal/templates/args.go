@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Workflow argument schemas
+
+package templates
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WorkflowArg is one argument a workflow accepts, declared in frontmatter:
+//
+//	args:
+//	  - name: target
+//	    required: true
+//	    description: Environment to deploy to
+//	  - dry-run
+//
+// A bare string is shorthand for an optional argument with no description.
+type WorkflowArg struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool   `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// parseWorkflowArgs reads the args frontmatter value. Entries without a
+// name are dropped, and so are repeated names after the first.
+func parseWorkflowArgs(value interface{}) []WorkflowArg {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var args []WorkflowArg
+	seen := make(map[string]bool)
+	for _, item := range items {
+		var arg WorkflowArg
+		switch v := item.(type) {
+		case string:
+			arg.Name = v
+		case map[string]interface{}:
+			arg.Name, _ = v["name"].(string)
+			arg.Description, _ = v["description"].(string)
+			arg.Required, _ = v["required"].(bool)
+		}
+
+		arg.Name = strings.TrimSpace(arg.Name)
+		if arg.Name == "" || seen[arg.Name] {
+			continue
+		}
+		seen[arg.Name] = true
+		args = append(args, arg)
+	}
+	return args
+}
+
+// ValidateArgs checks provided values against the workflow's declared args.
+// Required args must be present and non-empty, and names the workflow
+// doesn't declare are rejected so typos don't go unnoticed.
+func (w Workflow) ValidateArgs(provided map[string]string) error {
+	declared := make(map[string]bool, len(w.Args))
+	var missing []string
+	for _, arg := range w.Args {
+		declared[arg.Name] = true
+		if arg.Required && strings.TrimSpace(provided[arg.Name]) == "" {
+			missing = append(missing, arg.Name)
+		}
+	}
+
+	var unknown []string
+	for name := range provided {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "missing required argument(s): "+strings.Join(missing, ", "))
+	}
+	if len(unknown) > 0 {
+		problems = append(problems, "unknown argument(s): "+strings.Join(unknown, ", "))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("workflow %s: %s", w.Name, strings.Join(problems, "; "))
+	if len(w.Args) == 0 {
+		return fmt.Errorf("%s (it takes no arguments)", msg)
+	}
+	return fmt.Errorf("%s\n\n%s", msg, w.ArgsUsage())
+}
+
+// ArgsUsage formats the declared args for help output, one per line
+func (w Workflow) ArgsUsage() string {
+	if len(w.Args) == 0 {
+		return "Arguments: none"
+	}
+
+	width := 0
+	for _, arg := range w.Args {
+		if len(arg.Name) > width {
+			width = len(arg.Name)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("Arguments:")
+	for _, arg := range w.Args {
+		line := fmt.Sprintf("\n  %-*s  %s", width, arg.Name, arg.Description)
+		if arg.Required {
+			line += " (required)"
+		}
+		b.WriteString(strings.TrimRight(line, " "))
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for workflow argument schemas
+
+package templates
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const argsWorkflow = `---
+description: Deploy the project
+args:
+  - name: target
+    required: true
+    description: Environment to deploy to
+  - name: tag
+    description: Release tag
+  - dry-run
+  - name: target
+---
+
+# Deploy
+`
+
+func TestParseWorkflowArgs(t *testing.T) {
+	w := parseWorkflowFile(argsWorkflow)
+
+	want := []WorkflowArg{
+		{Name: "target", Description: "Environment to deploy to", Required: true},
+		{Name: "tag", Description: "Release tag"},
+		{Name: "dry-run"},
+	}
+	if !reflect.DeepEqual(w.Args, want) {
+		t.Errorf("Args = %+v, want %+v", w.Args, want)
+	}
+
+	if plain := parseWorkflowFile("---\ndescription: x\n---\n"); plain.Args != nil {
+		t.Errorf("Args without schema = %+v, want nil", plain.Args)
+	}
+}
+
+func TestValidateArgs(t *testing.T) {
+	w := parseWorkflowFile(argsWorkflow)
+	w.Name = "deploy"
+
+	tests := []struct {
+		name     string
+		provided map[string]string
+		wantErr  []string
+	}{
+		{"required given", map[string]string{"target": "prod"}, nil},
+		{"all given", map[string]string{"target": "prod", "tag": "v1", "dry-run": "true"}, nil},
+		{"missing required", map[string]string{"tag": "v1"}, []string{"missing required argument(s): target", "Arguments:"}},
+		{"empty required", map[string]string{"target": " "}, []string{"missing required argument(s): target"}},
+		{"unknown", map[string]string{"target": "prod", "tagg": "v1"}, []string{"unknown argument(s): tagg"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := w.ValidateArgs(tt.provided)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("ValidateArgs() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateArgs() = nil, want error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err, want)
+				}
+			}
+		})
+	}
+
+	none := Workflow{Name: "plan"}
+	if err := none.ValidateArgs(map[string]string{"x": "1"}); err == nil || !strings.Contains(err.Error(), "takes no arguments") {
+		t.Errorf("ValidateArgs() on workflow without args = %v", err)
+	}
+}
+
+func TestArgsUsage(t *testing.T) {
+	w := parseWorkflowFile(argsWorkflow)
+
+	want := "Arguments:\n" +
+		"  target   Environment to deploy to (required)\n" +
+		"  tag      Release tag\n" +
+		"  dry-run"
+	if got := w.ArgsUsage(); got != want {
+		t.Errorf("ArgsUsage() =\n%s\nwant\n%s", got, want)
+	}
+}
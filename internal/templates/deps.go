@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// A cross-platform CLI tool for managing AI agent templates
+
+package templates
+
+import (
+	"sort"
+	"strings"
+)
+
+// CycleError reports a loop in the agent → skills → requires graph
+type CycleError struct {
+	Path   []string // e.g. skill/a → skill/b → skill/a
+	Agents []string // agents that list a skill in the loop
+}
+
+func (e *CycleError) Error() string {
+	return "circular skill dependency: " + strings.Join(e.Path, " → ")
+}
+
+// skillPath labels skill names for a cycle path
+func skillPath(names []string) []string {
+	path := make([]string, len(names))
+	for i, name := range names {
+		path[i] = "skill/" + name
+	}
+	return path
+}
+
+// ResolveSkills expands names with every skill they require, directly or
+// transitively. The requested names come first, then the additions in the
+// order they were found. Required skills that don't exist are kept so the
+// caller can report them as not found.
+//
+// Returns a *CycleError instead of looping forever if a requested skill
+// depends on itself through its requirements.
+func (t *Templates) ResolveSkills(names []string) ([]string, error) {
+	resolved := append([]string(nil), names...)
+	included := make(map[string]bool, len(names))
+	for _, name := range names {
+		included[name] = true
+	}
+
+	done := make(map[string]bool)
+	var walk func(name string, path []string) error
+	walk = func(name string, path []string) error {
+		for i, p := range path {
+			if p == name {
+				return &CycleError{Path: skillPath(append(path[i:], name))}
+			}
+		}
+		if done[name] {
+			return nil
+		}
+
+		path = append(path, name)
+		for _, req := range t.Skills[name].Requires {
+			if err := walk(req, path); err != nil {
+				return err
+			}
+			if !included[req] {
+				included[req] = true
+				resolved = append(resolved, req)
+			}
+		}
+		done[name] = true
+		return nil
+	}
+
+	for _, name := range names {
+		if err := walk(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
+
+// FindCycles walks the whole dependency graph and returns every loop it
+// finds, each with the agents that would pull it in.
+//
+// How it works:
+//  1. Depth-first search over skills, following their requires lists
+//  2. Reaching a skill that's still on the stack means a loop; the stack
+//     from that skill onwards is the cycle path
+//  3. Look up which agents list any skill in the loop
+//
+// Agents only point at skills, so loops always run through skills; the
+// agents are reported because they're what a user actually installs.
+func (t *Templates) FindCycles() []*CycleError {
+	const (
+		unvisited = iota
+		onStack
+		finished
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycles []*CycleError
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = onStack
+		stack = append(stack, name)
+
+		for _, req := range t.Skills[name].Requires {
+			switch state[req] {
+			case unvisited:
+				if _, ok := t.Skills[req]; ok {
+					visit(req)
+				}
+			case onStack:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == req {
+						loop := append(append([]string(nil), stack[i:]...), req)
+						cycles = append(cycles, &CycleError{Path: skillPath(loop), Agents: t.agentsUsing(loop)})
+						break
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = finished
+	}
+
+	for _, name := range t.SkillNames() {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+
+	return cycles
+}
+
+// agentsUsing returns the sorted agents that list any of skills
+func (t *Templates) agentsUsing(skills []string) []string {
+	inLoop := make(map[string]bool, len(skills))
+	for _, s := range skills {
+		inLoop[s] = true
+	}
+
+	var agents []string
+	for name, agent := range t.Agents {
+		for _, s := range agent.Skills {
+			if inLoop[s] {
+				agents = append(agents, name)
+				break
+			}
+		}
+	}
+	sort.Strings(agents)
+	return agents
+}
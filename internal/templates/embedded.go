@@ -7,13 +7,14 @@
 package templates
 
 import (
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 // CurrentVersion is the version of the embedded templates
@@ -34,6 +35,27 @@ type Agent struct {
 	Skills      []string
 	Tools       []string
 	Content     string // full markdown content
+	Source      string // where it was loaded from, see SourceEmbedded etc.
+
+	// Sections holds the body split on "## " headings, keyed by heading
+	// text. SectionOrder keeps the headings in document order so adapters
+	// can render a subset or reorder them instead of pasting Content.
+	Sections     map[string]string
+	SectionOrder []string
+}
+
+// Section returns the body of the named section, matching the heading
+// case-insensitively. Returns "" if the agent has no such section.
+func (a Agent) Section(name string) string {
+	if body, ok := a.Sections[name]; ok {
+		return body
+	}
+	for heading, body := range a.Sections {
+		if strings.EqualFold(heading, name) {
+			return body
+		}
+	}
+	return ""
 }
 
 // Skill represents a domain skill
@@ -42,6 +64,8 @@ type Skill struct {
 	Description string
 	Content     string
 	Scripts     []string // available scripts
+	Requires    []string // other skills this one depends on
+	Source      string
 }
 
 // Workflow represents a slash command workflow
@@ -49,8 +73,28 @@ type Workflow struct {
 	Name        string
 	Description string
 	Content     string
+	Args        []WorkflowArg // declared in frontmatter; nil if none
+	Source      string
 }
 
+// Template sources. Every loader records one on each agent, skill and
+// workflow it produces, so when sources are merged it's possible to tell
+// which copy of a template won.
+const (
+	SourceEmbedded = "embedded" // compiled into the binary
+	SourceCache    = "cache"    // local template cache
+	SourceNetwork  = "network"  // downloaded from GitHub
+)
+
+// DirSource is the source of templates read from a directory
+func DirSource(path string) string { return "dir:" + path }
+
+// PluginSource is the source of templates provided by a plugin
+func PluginSource(name string) string { return "plugin:" + name }
+
+// RemoteSource is the source of templates from a configured remote
+func RemoteSource(name string) string { return "remote:" + name }
+
 //go:embed all:data
 var embeddedFS embed.FS
 
@@ -113,6 +157,7 @@ func loadAgents(tmpl *Templates) error {
 		agent := parseAgentFile(string(content))
 		name := strings.TrimSuffix(entry.Name(), ".md")
 		agent.Name = name
+		agent.Source = SourceEmbedded
 		tmpl.Agents[name] = agent
 	}
 
@@ -141,6 +186,7 @@ func loadSkills(tmpl *Templates) error {
 
 		skill := parseSkillFile(string(content))
 		skill.Name = entry.Name()
+		skill.Source = SourceEmbedded
 		tmpl.Skills[entry.Name()] = skill
 	}
 
@@ -169,29 +215,45 @@ func loadWorkflows(tmpl *Templates) error {
 		workflow := parseWorkflowFile(string(content))
 		name := strings.TrimSuffix(entry.Name(), ".md")
 		workflow.Name = name
+		workflow.Source = SourceEmbedded
 		tmpl.Workflows[name] = workflow
 	}
 
 	return nil
 }
 
-// parseFrontmatter extracts YAML frontmatter from markdown
+// parseFrontmatter extracts YAML frontmatter from markdown. Broken
+// frontmatter is treated as none; see ParseFrontmatter for the error.
 func parseFrontmatter(content string) (map[string]interface{}, string) {
-	if !strings.HasPrefix(content, "---") {
+	frontmatter, body, err := ParseFrontmatter(content)
+	if err != nil {
 		return nil, content
 	}
+	return frontmatter, body
+}
 
-	parts := strings.SplitN(content[3:], "---", 2)
-	if len(parts) < 2 {
-		return nil, content
+// frontmatterList reads a frontmatter value written either as a
+// comma-separated string ("a, b") or as a YAML list
+func frontmatterList(value interface{}) []string {
+	var items []string
+	switch v := value.(type) {
+	case string:
+		items = strings.Split(v, ",")
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				items = append(items, s)
+			}
+		}
 	}
 
-	var frontmatter map[string]interface{}
-	if err := yaml.Unmarshal([]byte(parts[0]), &frontmatter); err != nil {
-		return nil, content
+	var list []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
 	}
-
-	return frontmatter, strings.TrimSpace(parts[1])
+	return list
 }
 
 func parseAgentFile(content string) Agent {
@@ -200,23 +262,14 @@ func parseAgentFile(content string) Agent {
 	agent := Agent{
 		Content: content,
 	}
+	agent.Sections, agent.SectionOrder = parseSections(body)
 
 	if fm != nil {
 		if desc, ok := fm["description"].(string); ok {
 			agent.Description = desc
 		}
-		if skills, ok := fm["skills"].(string); ok {
-			agent.Skills = strings.Split(skills, ",")
-			for i := range agent.Skills {
-				agent.Skills[i] = strings.TrimSpace(agent.Skills[i])
-			}
-		}
-		if tools, ok := fm["tools"].(string); ok {
-			agent.Tools = strings.Split(tools, ",")
-			for i := range agent.Tools {
-				agent.Tools[i] = strings.TrimSpace(agent.Tools[i])
-			}
-		}
+		agent.Skills = frontmatterList(fm["skills"])
+		agent.Tools = frontmatterList(fm["tools"])
 	}
 
 	// Extract description from first paragraph if not in frontmatter
@@ -234,6 +287,59 @@ func parseAgentFile(content string) Agent {
 	return agent
 }
 
+// parseSections splits a markdown body into level-2 ("## ") sections.
+//
+// Text before the first "## " heading (title, intro) isn't a section.
+// Deeper headings stay inside their parent section, and headings inside
+// fenced code blocks are ignored so example markdown doesn't split things.
+// Repeated headings are merged into one section.
+func parseSections(body string) (map[string]string, []string) {
+	sections := make(map[string]string)
+	var order []string
+
+	var current string
+	var buf []string
+	inSection := false
+	inFence := false
+
+	flush := func() {
+		if !inSection {
+			return
+		}
+		text := strings.TrimSpace(strings.Join(buf, "\n"))
+		if existing, ok := sections[current]; ok && existing != "" {
+			text = strings.TrimSpace(existing + "\n\n" + text)
+		}
+		sections[current] = text
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+
+		if !inFence && strings.HasPrefix(line, "## ") {
+			flush()
+			current = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			if _, seen := sections[current]; !seen {
+				order = append(order, current)
+				sections[current] = ""
+			}
+			buf = buf[:0]
+			inSection = true
+			continue
+		}
+
+		if inSection {
+			buf = append(buf, line)
+		}
+	}
+	flush()
+
+	return sections, order
+}
+
 func parseSkillFile(content string) Skill {
 	fm, _ := parseFrontmatter(content)
 
@@ -245,6 +351,7 @@ func parseSkillFile(content string) Skill {
 		if desc, ok := fm["description"].(string); ok {
 			skill.Description = desc
 		}
+		skill.Requires = frontmatterList(fm["requires"])
 	}
 
 	return skill
@@ -261,6 +368,7 @@ func parseWorkflowFile(content string) Workflow {
 		if desc, ok := fm["description"].(string); ok {
 			workflow.Description = desc
 		}
+		workflow.Args = parseWorkflowArgs(fm["args"])
 	}
 
 	return workflow
@@ -301,6 +409,69 @@ func (t *Templates) Filter(agents []string, skills []string) *Templates {
 	return filtered
 }
 
+// FilterWorkflows returns a copy of t keeping only the named workflows.
+// Filter always keeps every workflow; this narrows them down, e.g. to the
+// ones a profile recorded. An empty list keeps them all.
+func (t *Templates) FilterWorkflows(names []string) *Templates {
+	if len(names) == 0 {
+		return t
+	}
+
+	filtered := *t
+	filtered.Workflows = make(map[string]Workflow)
+	for _, name := range names {
+		if workflow, ok := t.Workflows[name]; ok {
+			filtered.Workflows[name] = workflow
+		}
+	}
+	return &filtered
+}
+
+// AgentNames returns the agent names in sorted order
+func (t *Templates) AgentNames() []string {
+	names := make([]string, 0, len(t.Agents))
+	for name := range t.Agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Sources counts the loaded agents, skills and workflows by source
+func (t *Templates) Sources() map[string]int {
+	counts := make(map[string]int)
+	for _, agent := range t.Agents {
+		counts[agent.Source]++
+	}
+	for _, skill := range t.Skills {
+		counts[skill.Source]++
+	}
+	for _, workflow := range t.Workflows {
+		counts[workflow.Source]++
+	}
+	return counts
+}
+
+// SkillNames returns the skill names in sorted order
+func (t *Templates) SkillNames() []string {
+	names := make([]string, 0, len(t.Skills))
+	for name := range t.Skills {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WorkflowNames returns the workflow names in sorted order
+func (t *Templates) WorkflowNames() []string {
+	names := make([]string, 0, len(t.Workflows))
+	for name := range t.Workflows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // InstallTo copies templates to the specified directory.
 // creates the directory structure and writes all files.
 func (t *Templates) InstallTo(targetDir string) error {
@@ -349,6 +520,14 @@ func (t *Templates) InstallTo(targetDir string) error {
 	return nil
 }
 
+// Hash returns the hex-encoded SHA-256 of content.
+// Every content comparison (diff, drift detection) should go through this so
+// they all agree on the algorithm.
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
 // GetLatestVersion returns the current embedded version
 func GetLatestVersion() string {
 	return CurrentVersion
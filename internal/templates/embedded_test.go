@@ -7,6 +7,7 @@
 package templates
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -33,6 +34,11 @@ func TestLoadEmbedded(t *testing.T) {
 		t.Error("No workflows loaded")
 	}
 
+	total := len(tmpl.Agents) + len(tmpl.Skills) + len(tmpl.Workflows)
+	if sources := tmpl.Sources(); sources[SourceEmbedded] != total {
+		t.Errorf("Sources() = %v, want all %d embedded", sources, total)
+	}
+
 	t.Logf("Loaded %d agents, %d skills, %d workflows",
 		len(tmpl.Agents), len(tmpl.Skills), len(tmpl.Workflows))
 }
@@ -133,6 +139,32 @@ func TestTemplatesFilter(t *testing.T) {
 	}
 }
 
+func TestTemplatesFilterWorkflows(t *testing.T) {
+	tmpl := &Templates{
+		Agents: map[string]Agent{"frontend": {Name: "frontend"}},
+		Workflows: map[string]Workflow{
+			"create": {Name: "create"},
+			"debug":  {Name: "debug"},
+			"deploy": {Name: "deploy"},
+		},
+	}
+
+	filtered := tmpl.FilterWorkflows([]string{"debug", "missing"})
+	if len(filtered.Workflows) != 1 {
+		t.Errorf("Expected 1 workflow, got %d", len(filtered.Workflows))
+	}
+	if _, ok := filtered.Workflows["debug"]; !ok {
+		t.Error("debug workflow should be in filtered results")
+	}
+	if len(filtered.Agents) != 1 || len(tmpl.Workflows) != 3 {
+		t.Error("FilterWorkflows should keep agents and leave the original untouched")
+	}
+
+	if all := tmpl.FilterWorkflows(nil); len(all.Workflows) != 3 {
+		t.Errorf("empty list kept %d workflows, want all 3", len(all.Workflows))
+	}
+}
+
 func TestGetLatestVersion(t *testing.T) {
 	version := GetLatestVersion()
 	if version == "" {
@@ -144,3 +176,86 @@ func TestGetLatestVersion(t *testing.T) {
 		t.Errorf("GetLatestVersion() = %q, want %q", version, CurrentVersion)
 	}
 }
+
+func TestSortedNames(t *testing.T) {
+	tmpl := &Templates{
+		Agents: map[string]Agent{"zeta": {}, "alpha": {}, "mid": {}},
+		Skills: map[string]Skill{"b": {}, "a": {}},
+	}
+
+	agents := tmpl.AgentNames()
+	if len(agents) != 3 || agents[0] != "alpha" || agents[2] != "zeta" {
+		t.Errorf("AgentNames() = %v, want sorted [alpha mid zeta]", agents)
+	}
+
+	skills := tmpl.SkillNames()
+	if len(skills) != 2 || skills[0] != "a" || skills[1] != "b" {
+		t.Errorf("SkillNames() = %v, want sorted [a b]", skills)
+	}
+}
+
+func TestHash(t *testing.T) {
+	// sha256("hello")
+	want := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if got := Hash([]byte("hello")); got != want {
+		t.Errorf("Hash() = %s, want %s", got, want)
+	}
+
+	if Hash([]byte("a")) == Hash([]byte("b")) {
+		t.Error("Hash() should differ for different content")
+	}
+}
+
+func TestParseAgentSections(t *testing.T) {
+	content := `---
+description: Sectioned agent
+---
+
+# Sectioned Agent
+
+Intro text that belongs to no section.
+
+## Responsibilities
+
+- Do the thing
+
+### Details
+
+Nested heading stays in Responsibilities.
+
+## Constraints
+
+` + "```markdown\n## Not a heading\n```" + `
+
+Never do the other thing.
+`
+
+	agent := parseAgentFile(content)
+
+	want := []string{"Responsibilities", "Constraints"}
+	if len(agent.SectionOrder) != len(want) {
+		t.Fatalf("SectionOrder = %v, want %v", agent.SectionOrder, want)
+	}
+	for i, name := range want {
+		if agent.SectionOrder[i] != name {
+			t.Errorf("SectionOrder[%d] = %q, want %q", i, agent.SectionOrder[i], name)
+		}
+	}
+
+	resp := agent.Sections["Responsibilities"]
+	if !strings.HasPrefix(resp, "- Do the thing") || !strings.Contains(resp, "### Details") {
+		t.Errorf("Responsibilities = %q", resp)
+	}
+	if strings.Contains(resp, "Intro text") {
+		t.Error("intro text should not be part of any section")
+	}
+
+	constraints := agent.Section("constraints")
+	if !strings.Contains(constraints, "## Not a heading") || !strings.HasSuffix(constraints, "Never do the other thing.") {
+		t.Errorf("Section(constraints) = %q", constraints)
+	}
+
+	if agent.Section("missing") != "" {
+		t.Error("Section() should return empty string for unknown headings")
+	}
+}
@@ -0,0 +1,308 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// A cross-platform CLI tool for managing AI agent templates
+
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eshanized/agen/internal/httpclient"
+)
+
+// MaxIncrementalFiles is the most changed files we'll fetch one by one.
+// Past this a single ZIP download is cheaper than many small requests.
+const MaxIncrementalFiles = 50
+
+// compareFileLimit is the number of files GitHub's compare API returns
+// at most. A response this size may be truncated, so we can't trust it.
+const compareFileLimit = 300
+
+// templatesDataPath is where templates live inside the repository
+const templatesDataPath = "internal/templates/data/"
+
+// cacheStateFile records which commit (or zip ETag) the template cache
+// was built from
+const cacheStateFile = "templates-state.json"
+
+// cacheState is persisted next to the template cache
+type cacheState struct {
+	Branch string `json:"branch"`
+	Commit string `json:"commit"`
+	ETag   string `json:"etag,omitempty"` // of the zip, see FetchCached
+}
+
+// IncrementalResult describes how FetchIncremental got its templates
+type IncrementalResult struct {
+	Commit       string // commit the templates now match
+	Full         bool   // true if the whole ZIP was downloaded
+	Reason       string // why a full download was needed (empty if incremental)
+	ChangedFiles int    // template files fetched or removed incrementally
+}
+
+// githubCompareResponse is the subset of the compare API we use
+type githubCompareResponse struct {
+	Status string `json:"status"` // "ahead", "behind", "diverged", "identical"
+	Files  []struct {
+		Filename         string `json:"filename"`
+		Status           string `json:"status"` // "added", "removed", "modified", "renamed", ...
+		RawURL           string `json:"raw_url"`
+		PreviousFilename string `json:"previous_filename,omitempty"`
+	} `json:"files"`
+}
+
+// FetchIncremental brings the template cache up to date with branch while
+// downloading as little as possible.
+//
+// How it works:
+//  1. Ask GitHub for the latest commit SHA on the branch (a few bytes)
+//  2. If the cache was built from that commit, return the cache as is
+//  3. Otherwise ask the compare API which files changed since the cached commit
+//  4. Download only the changed template files and patch the cached set
+//  5. Fall back to a full ZIP download if there's no usable cache, the diff
+//     is too large, or the compare API isn't available
+//
+// The cache is rewritten and the new commit recorded either way.
+func FetchIncremental(branch, cacheDir string) (*Templates, *IncrementalResult, error) {
+	if branch == "" {
+		branch = defaultBranch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	head, err := fetchLatestCommit(ctx, branch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get latest commit: %w", err)
+	}
+
+	state := loadCacheState(cacheDir)
+	cached, cacheErr := LoadFromCache(cacheDir)
+
+	var reason string
+	switch {
+	case cacheErr != nil:
+		reason = "no cached templates"
+	case state == nil || state.Commit == "":
+		reason = "cached commit unknown"
+	case state.Branch != branch:
+		reason = "cache is from branch " + state.Branch
+	}
+
+	if reason == "" && state.Commit == head {
+		markCacheFetched(cacheDir)
+		return cached, &IncrementalResult{Commit: head}, nil
+	}
+
+	if reason == "" {
+		changed, err := applyCompare(ctx, cached, state.Commit, head)
+		if err == nil {
+			if err := saveCache(cached, cacheDir, cacheState{Branch: branch, Commit: head}); err != nil {
+				return nil, nil, err
+			}
+			return cached, &IncrementalResult{Commit: head, ChangedFiles: changed}, nil
+		}
+		reason = err.Error()
+	}
+
+	tmpl, err := FetchFromGitHub(branch)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := saveCache(tmpl, cacheDir, cacheState{Branch: branch, Commit: head}); err != nil {
+		return nil, nil, err
+	}
+
+	return tmpl, &IncrementalResult{Commit: head, Full: true, Reason: reason}, nil
+}
+
+// fetchLatestCommit returns the SHA at the tip of branch.
+// The "sha" media type makes GitHub answer with just the 40-char hash.
+func fetchLatestCommit(ctx context.Context, branch string) (string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/commits/%s",
+		apiBaseURL, defaultOwner, defaultRepo, branch)
+
+	req, err := httpclient.NewGitHubRequest(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github.sha")
+
+	resp, err := httpclient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err := httpclient.CheckRateLimit(resp); err != nil {
+		return "", err
+	}
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return "", err
+	}
+
+	sha := strings.TrimSpace(string(data))
+	if sha == "" {
+		return "", fmt.Errorf("empty commit SHA")
+	}
+	return sha, nil
+}
+
+// applyCompare patches tmpl with the template files that changed between
+// base and head. Returns the number of template files touched, or an error
+// if the diff can't be applied incrementally.
+func applyCompare(ctx context.Context, tmpl *Templates, base, head string) (int, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/compare/%s...%s",
+		apiBaseURL, defaultOwner, defaultRepo, base, head)
+
+	req, err := httpclient.NewGitHubRequest(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := httpclient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := httpclient.CheckRateLimit(resp); err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("compare API returned status %d", resp.StatusCode)
+	}
+
+	var compare githubCompareResponse
+	if err := json.NewDecoder(resp.Body).Decode(&compare); err != nil {
+		return 0, err
+	}
+
+	if compare.Status != "ahead" && compare.Status != "identical" {
+		return 0, fmt.Errorf("cached commit is %s of latest", compare.Status)
+	}
+	if len(compare.Files) >= compareFileLimit {
+		return 0, fmt.Errorf("diff too large to compare")
+	}
+
+	// only template files matter; count them before downloading anything
+	type change struct {
+		path, previous, status, rawURL string
+	}
+	var changes []change
+	for _, f := range compare.Files {
+		path, ok := strings.CutPrefix(f.Filename, templatesDataPath)
+		previous, wasTemplate := strings.CutPrefix(f.PreviousFilename, templatesDataPath)
+		if !ok && !wasTemplate {
+			continue
+		}
+		if !ok {
+			path = ""
+		}
+		if !wasTemplate {
+			previous = ""
+		}
+		changes = append(changes, change{path, previous, f.Status, f.RawURL})
+	}
+
+	if len(changes) > MaxIncrementalFiles {
+		return 0, fmt.Errorf("%d files changed", len(changes))
+	}
+
+	// download everything first so a failure leaves tmpl untouched
+	contents := make([]string, len(changes))
+	for i, c := range changes {
+		if c.status == "removed" || c.path == "" {
+			continue
+		}
+		content, err := downloadFile(ctx, c.rawURL)
+		if err != nil {
+			return 0, fmt.Errorf("failed to download %s: %w", c.path, err)
+		}
+		contents[i] = content
+	}
+
+	for i, c := range changes {
+		if c.previous != "" {
+			removeTemplateFile(tmpl, c.previous)
+		}
+		if c.status == "removed" {
+			removeTemplateFile(tmpl, c.path)
+			continue
+		}
+		if c.path != "" {
+			applyTemplateFile(tmpl, c.path, contents[i])
+		}
+	}
+
+	return len(changes), nil
+}
+
+// removeTemplateFile drops the template at relativePath (relative to
+// templates/data/) from tmpl. The inverse of applyTemplateFile.
+func removeTemplateFile(tmpl *Templates, relativePath string) {
+	parts := strings.Split(relativePath, "/")
+	if len(parts) < 2 {
+		return
+	}
+
+	switch parts[0] {
+	case "agents":
+		delete(tmpl.Agents, strings.TrimSuffix(parts[1], ".md"))
+	case "skills":
+		if parts[len(parts)-1] == "SKILL.md" {
+			delete(tmpl.Skills, parts[1])
+		}
+	case "workflows":
+		delete(tmpl.Workflows, strings.TrimSuffix(parts[1], ".md"))
+	}
+}
+
+// loadCacheState reads the recorded cache commit. Returns nil if unknown.
+func loadCacheState(cacheDir string) *cacheState {
+	data, err := os.ReadFile(filepath.Join(cacheDir, cacheStateFile))
+	if err != nil {
+		return nil
+	}
+
+	var state cacheState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil
+	}
+	return &state
+}
+
+// saveCache rewrites the template cache and records the state it matches.
+// The old cache is cleared first so removed templates don't linger.
+func saveCache(tmpl *Templates, cacheDir string, state cacheState) error {
+	// clearing isn't a write, so drop the size here; everything after
+	// goes through writeCacheFile
+	InvalidateCacheSize(cacheDir)
+	if err := os.RemoveAll(filepath.Join(cacheDir, "templates")); err != nil {
+		return err
+	}
+	if err := CacheTemplates(tmpl, cacheDir); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(cacheDir, cacheStateFile, data)
+}
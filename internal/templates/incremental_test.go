@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for incremental template fetching (no real network)
+
+package templates
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	oldCommit = "1111111111111111111111111111111111111111"
+	newCommit = "2222222222222222222222222222222222222222"
+)
+
+// seedCache writes a cached template set built from oldCommit
+func seedCache(t *testing.T, cacheDir string) {
+	t.Helper()
+	tmpl := &Templates{
+		Agents:    map[string]Agent{"fake-agent": parseAgentFile(testAgent), "old-agent": parseAgentFile(testAgent)},
+		Skills:    map[string]Skill{"fake-skill": parseSkillFile(testSkill)},
+		Workflows: map[string]Workflow{"fake-workflow": parseWorkflowFile(testWorkflow)},
+	}
+	if err := saveCache(tmpl, cacheDir, cacheState{Branch: "main", Commit: oldCommit}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFetchIncrementalAppliesDiff(t *testing.T) {
+	cacheDir := t.TempDir()
+	seedCache(t, cacheDir)
+
+	var zipHits int32
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/eshanized/agen/commits/main":
+			w.Write([]byte(newCommit))
+		case "/repos/eshanized/agen/compare/" + oldCommit + "..." + newCommit:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"status": "ahead",
+				"files": []map[string]string{
+					{"filename": "internal/templates/data/agents/new-agent.md", "status": "added", "raw_url": srv.URL + "/raw/new"},
+					{"filename": "internal/templates/data/agents/old-agent.md", "status": "removed"},
+					{"filename": "README.md", "status": "modified", "raw_url": srv.URL + "/raw/readme"},
+				},
+			})
+		case "/raw/new":
+			w.Write([]byte(testAgent))
+		case "/eshanized/agen/archive/main.zip":
+			atomic.AddInt32(&zipHits, 1)
+			http.NotFound(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	tmpl, result, err := FetchIncremental("main", cacheDir)
+	if err != nil {
+		t.Fatalf("FetchIncremental() failed: %v", err)
+	}
+
+	if result.Full {
+		t.Errorf("expected incremental fetch, got full (%s)", result.Reason)
+	}
+	if result.ChangedFiles != 2 {
+		t.Errorf("ChangedFiles = %d, want 2", result.ChangedFiles)
+	}
+	if agent, ok := tmpl.Agents["new-agent"]; !ok {
+		t.Error("new-agent should be added")
+	} else if agent.Source != SourceNetwork {
+		t.Errorf("new-agent source = %q, want %q", agent.Source, SourceNetwork)
+	}
+	if _, ok := tmpl.Agents["old-agent"]; ok {
+		t.Error("old-agent should be removed")
+	}
+	if agent, ok := tmpl.Agents["fake-agent"]; !ok {
+		t.Error("unchanged fake-agent should be kept from cache")
+	} else if agent.Source != SourceCache {
+		t.Errorf("fake-agent source = %q, want %q", agent.Source, SourceCache)
+	}
+	if atomic.LoadInt32(&zipHits) != 0 {
+		t.Error("ZIP should not be downloaded for an incremental update")
+	}
+
+	if state := loadCacheState(cacheDir); state == nil || state.Commit != newCommit {
+		t.Errorf("cache state = %+v, want commit %s", state, newCommit)
+	}
+}
+
+func TestFetchIncrementalUpToDate(t *testing.T) {
+	cacheDir := t.TempDir()
+	seedCache(t, cacheDir)
+
+	var otherHits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/eshanized/agen/commits/main" {
+			w.Write([]byte(oldCommit))
+			return
+		}
+		atomic.AddInt32(&otherHits, 1)
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	tmpl, result, err := FetchIncremental("main", cacheDir)
+	if err != nil {
+		t.Fatalf("FetchIncremental() failed: %v", err)
+	}
+	if result.Full || result.ChangedFiles != 0 {
+		t.Errorf("result = %+v, want no-op", result)
+	}
+	if len(tmpl.Agents) != 2 {
+		t.Errorf("expected cached agents, got %d", len(tmpl.Agents))
+	}
+	if atomic.LoadInt32(&otherHits) != 0 {
+		t.Error("nothing but the commit lookup should be requested")
+	}
+}
+
+func TestFetchIncrementalFallsBackToZip(t *testing.T) {
+	zipData := buildTestZip(t, "main")
+
+	tests := []struct {
+		name    string
+		seed    bool
+		compare http.HandlerFunc
+	}{
+		{name: "no cache", seed: false},
+		{
+			name: "compare unavailable",
+			seed: true,
+			compare: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "rate limited", http.StatusForbidden)
+			},
+		},
+		{
+			name: "diverged history",
+			seed: true,
+			compare: func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(map[string]interface{}{"status": "diverged"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			if tt.seed {
+				seedCache(t, cacheDir)
+			}
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/repos/eshanized/agen/commits/main":
+					w.Write([]byte(newCommit))
+				case "/repos/eshanized/agen/compare/" + oldCommit + "..." + newCommit:
+					tt.compare(w, r)
+				case "/eshanized/agen/archive/main.zip":
+					w.Write(zipData)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+			useTestServer(t, srv)
+
+			tmpl, result, err := FetchIncremental("main", cacheDir)
+			if err != nil {
+				t.Fatalf("FetchIncremental() failed: %v", err)
+			}
+			if !result.Full {
+				t.Error("expected a full ZIP fetch")
+			}
+			if _, ok := tmpl.Agents["fake-agent"]; !ok {
+				t.Error("fake-agent should come from the ZIP")
+			}
+			if _, ok := tmpl.Agents["old-agent"]; ok {
+				t.Error("old-agent from the stale cache should be gone")
+			}
+
+			cached, err := LoadFromCache(cacheDir)
+			if err != nil {
+				t.Fatalf("LoadFromCache() failed: %v", err)
+			}
+			if _, ok := cached.Agents["old-agent"]; ok {
+				t.Error("stale cache entries should be cleared")
+			}
+		})
+	}
+}
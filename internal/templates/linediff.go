@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Line-based diffs between installed and latest templates
+
+package templates
+
+import "strings"
+
+// DiffOp says what happened to a line between the old and new text
+type DiffOp int
+
+const (
+	DiffEqual  DiffOp = iota // unchanged, shown as context
+	DiffDelete               // only in the old text
+	DiffInsert               // only in the new text
+)
+
+// DiffLine is one line of a diff
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// Hunk is a run of changes plus the context lines around it, numbered the
+// way unified diffs are (1-based; a start of 0 means an empty side)
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []DiffLine
+}
+
+// SplitLines splits text into lines for diffing. CRLF and lone CR line
+// endings are treated as LF, and a final newline doesn't produce an extra
+// empty line.
+func SplitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// DiffLines compares old and new line by line and groups the result into
+// hunks with up to context unchanged lines on each side of a change.
+// Hunks whose context would overlap are merged. Returns nil when the
+// texts match (ignoring line endings).
+func DiffLines(old, new string, context int) []Hunk {
+	if context < 0 {
+		context = 0
+	}
+	return buildHunks(myersDiff(SplitLines(old), SplitLines(new)), context)
+}
+
+// myersDiff computes a shortest edit script between a and b.
+//
+// How it works (Myers, "An O(ND) Difference Algorithm", 1986):
+//  1. For each edit distance d, extend the furthest-reaching path on every
+//     diagonal k = x - y, following runs of equal lines ("snakes") for free
+//  2. Stop at the first d where a path reaches the end of both inputs
+//  3. Walk the saved frontiers backwards to recover which edits were taken
+//
+// Only the diagonals reachable at each d are saved, so memory is O(D²)
+// rather than O(D·(N+M)), which matters for long agents with few changes.
+func myersDiff(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+
+	var trace [][]int
+	found := false
+	for d := 0; d <= maxD && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // step down: insert from b
+			} else {
+				x = v[offset+k-1] + 1 // step right: delete from a
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	// Backtrack from the end, emitting lines in reverse
+	var script []DiffLine
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		frontier := trace[d]
+		at := func(k int) int { return frontier[k+d] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			script = append(script, DiffLine{Op: DiffEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			script = append(script, DiffLine{Op: DiffInsert, Text: b[y-1]})
+		} else {
+			script = append(script, DiffLine{Op: DiffDelete, Text: a[x-1]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		script = append(script, DiffLine{Op: DiffEqual, Text: a[x-1]})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(script)-1; i < j; i, j = i+1, j-1 {
+		script[i], script[j] = script[j], script[i]
+	}
+	return script
+}
+
+// buildHunks cuts an edit script into hunks with context lines around
+// each change
+func buildHunks(script []DiffLine, context int) []Hunk {
+	// oldBefore[i]/newBefore[i] count the lines of each side before script[i]
+	oldBefore := make([]int, len(script)+1)
+	newBefore := make([]int, len(script)+1)
+	for i, line := range script {
+		oldBefore[i+1], newBefore[i+1] = oldBefore[i], newBefore[i]
+		if line.Op != DiffInsert {
+			oldBefore[i+1]++
+		}
+		if line.Op != DiffDelete {
+			newBefore[i+1]++
+		}
+	}
+
+	nextChange := func(from int) int {
+		for i := from; i < len(script); i++ {
+			if script[i].Op != DiffEqual {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var hunks []Hunk
+	prevStop := 0
+	for change := nextChange(0); change >= 0; {
+		start := max(change-context, prevStop)
+
+		// extend over following changes whose context would touch ours
+		end := change
+		for {
+			for end < len(script) && script[end].Op != DiffEqual {
+				end++
+			}
+			next := nextChange(end)
+			if next < 0 || next-end > 2*context {
+				change = next
+				break
+			}
+			end = next
+		}
+		stop := min(end+context, len(script))
+
+		hunk := Hunk{
+			OldStart: oldBefore[start] + 1,
+			OldLines: oldBefore[stop] - oldBefore[start],
+			NewStart: newBefore[start] + 1,
+			NewLines: newBefore[stop] - newBefore[start],
+			Lines:    script[start:stop],
+		}
+		// unified diff convention: an empty side points at the line before
+		if hunk.OldLines == 0 {
+			hunk.OldStart--
+		}
+		if hunk.NewLines == 0 {
+			hunk.NewStart--
+		}
+		hunks = append(hunks, hunk)
+		prevStop = stop
+	}
+
+	return hunks
+}
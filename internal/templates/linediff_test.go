@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for line diffs
+
+package templates
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// renderHunks formats hunks like a unified diff body, for easy comparison
+func renderHunks(hunks []Hunk) string {
+	var b strings.Builder
+	for _, h := range hunks {
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		for _, line := range h.Lines {
+			prefix := " "
+			switch line.Op {
+			case DiffDelete:
+				prefix = "-"
+			case DiffInsert:
+				prefix = "+"
+			}
+			b.WriteString(prefix + line.Text + "\n")
+		}
+	}
+	return b.String()
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a\nb\n", []string{"a", "b"}},
+		{"a\r\nb\r\n", []string{"a", "b"}},
+		{"a\rb", []string{"a", "b"}},
+		{"a\n\nb", []string{"a", "", "b"}},
+	}
+
+	for _, tt := range tests {
+		if got := SplitLines(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitLines(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "identical",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "line endings only",
+			old:  "a\r\nb\r\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			old:  "a\nb\nc\n",
+			new:  "a\nB\nc\n",
+			want: "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name: "added to empty",
+			old:  "",
+			new:  "a\nb\n",
+			want: "@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "removed everything",
+			old:  "a\n",
+			new:  "",
+			want: "@@ -1,1 +0,0 @@\n-a\n",
+		},
+		{
+			name: "trailing whitespace",
+			old:  "a \nb\n",
+			new:  "a\nb\n",
+			want: "@@ -1,2 +1,2 @@\n-a \n+a\n b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderHunks(DiffLines(tt.old, tt.new, 1)); got != tt.want {
+				t.Errorf("DiffLines() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffLinesContext(t *testing.T) {
+	var old []string
+	for i := 1; i <= 20; i++ {
+		old = append(old, fmt.Sprintf("line %d", i))
+	}
+	changed := append([]string(nil), old...)
+	changed[1] = "changed 2"
+	changed[17] = "changed 18"
+
+	hunks := DiffLines(strings.Join(old, "\n"), strings.Join(changed, "\n"), 2)
+	if len(hunks) != 2 {
+		t.Fatalf("got %d hunks, want 2:\n%s", len(hunks), renderHunks(hunks))
+	}
+	if h := hunks[0]; h.OldStart != 1 || h.OldLines != 4 {
+		t.Errorf("first hunk = -%d,%d, want -1,4", h.OldStart, h.OldLines)
+	}
+	if h := hunks[1]; h.OldStart != 16 || h.OldLines != 5 {
+		t.Errorf("second hunk = -%d,%d, want -16,5", h.OldStart, h.OldLines)
+	}
+
+	// with enough context the two changes share one hunk
+	if hunks := DiffLines(strings.Join(old, "\n"), strings.Join(changed, "\n"), 8); len(hunks) != 1 {
+		t.Errorf("got %d hunks with context 8, want 1", len(hunks))
+	}
+}
+
+func TestMyersDiffIsMinimal(t *testing.T) {
+	a := strings.Split("a b c a b b a", " ")
+	b := strings.Split("c b a b a c", " ")
+
+	script := myersDiff(a, b)
+	edits := 0
+	var gotA, gotB []string
+	for _, line := range script {
+		switch line.Op {
+		case DiffEqual:
+			gotA = append(gotA, line.Text)
+			gotB = append(gotB, line.Text)
+		case DiffDelete:
+			gotA = append(gotA, line.Text)
+			edits++
+		case DiffInsert:
+			gotB = append(gotB, line.Text)
+			edits++
+		}
+	}
+
+	if !reflect.DeepEqual(gotA, a) || !reflect.DeepEqual(gotB, b) {
+		t.Fatalf("script does not reproduce inputs: %v / %v", gotA, gotB)
+	}
+	// the classic example from the Myers paper has edit distance 5
+	if edits != 5 {
+		t.Errorf("edit distance = %d, want 5", edits)
+	}
+}
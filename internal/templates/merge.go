@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Merging template sets from several sources
+
+package templates
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Collision is a template name offered by more than one source.
+// The copy already in the set is kept; Ignored is the source that lost.
+type Collision struct {
+	Kind    string // "agent", "skill" or "workflow"
+	Name    string
+	Kept    string // source of the template that stayed
+	Ignored string // source of the one that was dropped
+}
+
+func (c Collision) String() string {
+	return fmt.Sprintf("%s %q from %s ignored: already provided by %s", c.Kind, c.Name, c.Ignored, c.Kept)
+}
+
+// Merge adds other's templates to t. Names t already has are never
+// overwritten - each one is reported as a Collision instead, sorted by
+// kind and name, so the caller can tell the user.
+func (t *Templates) Merge(other *Templates) []Collision {
+	var collisions []Collision
+
+	for name, agent := range other.Agents {
+		if existing, ok := t.Agents[name]; ok {
+			collisions = append(collisions, Collision{"agent", name, existing.Source, agent.Source})
+			continue
+		}
+		t.Agents[name] = agent
+	}
+	for name, skill := range other.Skills {
+		if existing, ok := t.Skills[name]; ok {
+			collisions = append(collisions, Collision{"skill", name, existing.Source, skill.Source})
+			continue
+		}
+		t.Skills[name] = skill
+	}
+	for name, workflow := range other.Workflows {
+		if existing, ok := t.Workflows[name]; ok {
+			collisions = append(collisions, Collision{"workflow", name, existing.Source, workflow.Source})
+			continue
+		}
+		t.Workflows[name] = workflow
+	}
+
+	sort.Slice(collisions, func(i, j int) bool {
+		if collisions[i].Kind != collisions[j].Kind {
+			return collisions[i].Kind < collisions[j].Kind
+		}
+		return collisions[i].Name < collisions[j].Name
+	})
+	return collisions
+}
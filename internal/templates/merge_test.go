@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for merging template sets
+
+package templates
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeReportsCollisions(t *testing.T) {
+	base := &Templates{
+		Agents:    map[string]Agent{"debugger": {Name: "debugger", Source: SourceEmbedded}},
+		Skills:    map[string]Skill{"clean-code": {Name: "clean-code", Source: SourceEmbedded}},
+		Workflows: map[string]Workflow{},
+	}
+	plugin := &Templates{
+		Agents: map[string]Agent{
+			"debugger": {Name: "debugger", Description: "plugin copy", Source: PluginSource("pack")},
+			"auditor":  {Name: "auditor", Source: PluginSource("pack")},
+		},
+		Skills:    map[string]Skill{},
+		Workflows: map[string]Workflow{"ship": {Name: "ship", Source: PluginSource("pack")}},
+	}
+
+	collisions := base.Merge(plugin)
+
+	want := []Collision{{Kind: "agent", Name: "debugger", Kept: SourceEmbedded, Ignored: "plugin:pack"}}
+	if !reflect.DeepEqual(collisions, want) {
+		t.Errorf("Merge() collisions = %+v, want %+v", collisions, want)
+	}
+	if base.Agents["debugger"].Description == "plugin copy" {
+		t.Error("Merge() overwrote an existing agent")
+	}
+	if _, ok := base.Agents["auditor"]; !ok {
+		t.Error("Merge() did not add the new agent")
+	}
+	if _, ok := base.Workflows["ship"]; !ok {
+		t.Error("Merge() did not add the new workflow")
+	}
+}
@@ -10,13 +10,17 @@ import (
 	"archive/zip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/eshanized/agen/internal/httpclient"
 )
 
 // GitHubRepo holds the repository info for fetching templates
@@ -26,6 +30,28 @@ const (
 	defaultBranch = "main"
 )
 
+// Base URLs for GitHub. These are variables rather than constants so
+// tests can point them at an httptest.Server instead of the real network.
+var (
+	githubBaseURL = "https://github.com"
+	apiBaseURL    = "https://api.github.com"
+)
+
+// ErrNotModified is returned when GitHub answers 304 Not Modified,
+// meaning the caller's cached copy is still current.
+var ErrNotModified = errors.New("templates not modified")
+
+// StatusError is returned when a template archive download gets an HTTP
+// status other than 200 or 304, so callers can explain 401/403/404
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("download returned status %d", e.StatusCode)
+}
+
 // GitHubContentsResponse represents the GitHub API response for directory contents
 type GitHubContentsResponse struct {
 	Name        string `json:"name"`
@@ -50,59 +76,141 @@ func FetchFromGitHub(branch string) (*Templates, error) {
 	}
 
 	// Try ZIP download first
-	tmpl, err := fetchViaZip(branch)
+	tmpl, _, err := fetchViaZip(branch, "")
 	if err == nil {
 		return tmpl, nil
 	}
 
+	// Nothing changed upstream - no point hammering the API
+	if errors.Is(err, ErrNotModified) {
+		return nil, err
+	}
+
 	// Fall back to API
 	return fetchViaAPI(branch)
 }
 
+// FetchCached is FetchFromGitHub backed by the template cache in cacheDir.
+//
+// How it works:
+//  1. Send the ETag recorded with the cache (if it's for this branch) as
+//     If-None-Match on the zip download
+//  2. On 304 Not Modified, return the cache without downloading anything
+//  3. Otherwise fetch as FetchFromGitHub does, rewrite the cache and record
+//     the new ETag
+//
+// The bool result is true when the templates came from the cache.
+func FetchCached(branch, cacheDir string) (*Templates, bool, error) {
+	if branch == "" {
+		branch = defaultBranch
+	}
+
+	// only trust the ETag if the cache it describes is still there
+	var etag string
+	cached, cacheErr := LoadFromCache(cacheDir)
+	if state := loadCacheState(cacheDir); state != nil && state.Branch == branch && cacheErr == nil {
+		etag = state.ETag
+	}
+
+	tmpl, newETag, err := fetchViaZip(branch, etag)
+	if errors.Is(err, ErrNotModified) {
+		if etag == "" {
+			// a 304 we didn't ask for; there's no cache to fall back on
+			return nil, false, err
+		}
+		// upstream confirmed the cache is current, so it counts as fresh
+		markCacheFetched(cacheDir)
+		return cached, true, nil
+	}
+	if err != nil {
+		tmpl, err = fetchViaAPI(branch)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := saveCache(tmpl, cacheDir, cacheState{Branch: branch, ETag: newETag}); err != nil {
+		return nil, false, err
+	}
+	return tmpl, false, nil
+}
+
 // fetchViaZip downloads the repo as a ZIP and extracts templates.
 //
 // GitHub provides ZIP downloads at:
 // https://github.com/{owner}/{repo}/archive/{branch}.zip
-func fetchViaZip(branch string) (*Templates, error) {
+//
+// A non-empty etag is sent as If-None-Match, and a 304 answer returns
+// ErrNotModified. The response's ETag is returned alongside the templates.
+func fetchViaZip(branch, etag string) (*Templates, string, error) {
+	zipURL := fmt.Sprintf("%s/%s/%s/archive/%s.zip",
+		githubBaseURL, defaultOwner, defaultRepo, branch)
+	return fetchZip(zipURL, etag, templatesDataPath)
+}
+
+// fetchZip downloads the archive at zipURL and extracts the templates
+// under the first of dirs that has any (see extractTemplatesFromZip).
+// etag works as for fetchViaZip.
+func fetchZip(zipURL, etag string, dirs ...string) (*Templates, string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	zipURL := fmt.Sprintf("https://github.com/%s/%s/archive/%s.zip",
-		defaultOwner, defaultRepo, branch)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", zipURL, nil)
+	req, err := httpclient.NewRequest(ctx, zipURL)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download: %w", err)
+		return nil, "", fmt.Errorf("failed to download: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, "", ErrNotModified
+	}
+
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("download returned status %d", resp.StatusCode)
+		return nil, "", &StatusError{URL: zipURL, StatusCode: resp.StatusCode}
 	}
 
 	// Save to temp file
 	tmpFile, err := os.CreateTemp("", "agen-templates-*.zip")
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer os.Remove(tmpFile.Name())
 	defer tmpFile.Close()
 
 	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Extract and parse
-	return extractTemplatesFromZip(tmpFile.Name(), branch)
+	tmpl, err := extractTemplatesFromZip(tmpFile.Name(), dirs...)
+	if err != nil {
+		return nil, "", err
+	}
+	return tmpl, resp.Header.Get("ETag"), nil
 }
 
 // extractTemplatesFromZip reads templates from a downloaded ZIP file.
-func extractTemplatesFromZip(zipPath, branch string) (*Templates, error) {
+//
+// GitHub wraps the archive in one top-level directory named after the repo
+// and ref, but not verbatim: "feature/x" becomes "agen-feature-x", and tags
+// lose a leading "v". Rather than guess, the directory is read from the
+// first entry. Templates are read from the first of dirs (relative to that
+// directory, "" for the top; templatesDataPath by default) with agents, skills
+// or workflows in it. An
+// archive with no templates under any of them is an error, so the caller
+// can fall back instead of returning an empty set.
+func extractTemplatesFromZip(zipPath string, dirs ...string) (*Templates, error) {
+	if len(dirs) == 0 {
+		dirs = []string{templatesDataPath}
+	}
 	reader, err := zip.OpenReader(zipPath)
 	if err != nil {
 		return nil, err
@@ -116,10 +224,16 @@ func extractTemplatesFromZip(zipPath, branch string) (*Templates, error) {
 		Workflows: make(map[string]Workflow),
 	}
 
+	if len(reader.File) == 0 {
+		return nil, fmt.Errorf("archive is empty")
+	}
+
 	// The ZIP contains a top-level directory like "agen-main/"
-	// We need to look for templates/data/ inside that
-	prefix := fmt.Sprintf("%s-%s/internal/templates/data/", defaultRepo, branch)
+	// We need to look for templates/data/ (or another of dirs) inside that
+	root, _, _ := strings.Cut(reader.File[0].Name, "/")
+	prefix := root + "/" + templateDirIn(reader.File, root, dirs)
 
+	found := false
 	for _, file := range reader.File {
 		// skip directories
 		if file.FileInfo().IsDir() {
@@ -144,45 +258,128 @@ func extractTemplatesFromZip(zipPath, branch string) (*Templates, error) {
 			continue
 		}
 
-		// Parse based on path
-		parts := strings.Split(relativePath, "/")
-		if len(parts) < 2 {
-			continue
+		applyTemplateFile(tmpl, relativePath, string(content))
+		found = true
+	}
+
+	if !found {
+		if len(dirs) > 1 {
+			return nil, fmt.Errorf("no agents/, skills/ or workflows/ in archive (looked under %s)", describeDirs(dirs))
 		}
+		return nil, fmt.Errorf("no templates under %s in archive", prefix)
+	}
+	return tmpl, nil
+}
 
-		switch parts[0] {
-		case "agents":
-			if strings.HasSuffix(parts[1], ".md") {
-				name := strings.TrimSuffix(parts[1], ".md")
-				agent := parseAgentFile(string(content))
-				agent.Name = name
-				tmpl.Agents[name] = agent
+// templateDirIn returns the first of dirs that holds an agents/, skills/ or
+// workflows/ file under root in files. With none of them it returns the
+// first, so the caller's "no templates" error names the usual place.
+func templateDirIn(files []*zip.File, root string, dirs []string) string {
+	for _, dir := range dirs {
+		prefix := root + "/" + dir
+		for _, f := range files {
+			rel, ok := strings.CutPrefix(f.Name, prefix)
+			if !ok || f.FileInfo().IsDir() {
+				continue
 			}
-
-		case "skills":
-			// skills/skill-name/SKILL.md
-			if len(parts) >= 2 && parts[len(parts)-1] == "SKILL.md" {
-				name := parts[1]
-				skill := parseSkillFile(string(content))
-				skill.Name = name
-				tmpl.Skills[name] = skill
+			kind, _, _ := strings.Cut(rel, "/")
+			if kind == "agents" || kind == "skills" || kind == "workflows" {
+				return dir
 			}
+		}
+	}
+	return dirs[0]
+}
 
-		case "workflows":
-			if strings.HasSuffix(parts[1], ".md") {
-				name := strings.TrimSuffix(parts[1], ".md")
-				workflow := parseWorkflowFile(string(content))
-				workflow.Name = name
-				tmpl.Workflows[name] = workflow
-			}
+// describeDirs lists archive directories for an error, "" as the top
+func describeDirs(dirs []string) string {
+	names := make([]string, len(dirs))
+	for i, dir := range dirs {
+		if dir == "" {
+			dir = "the top"
 		}
+		names[i] = dir
 	}
+	return strings.Join(names, ", ")
+}
 
-	return tmpl, nil
+// applyTemplateFile parses content according to its path relative to
+// templates/data/ and stores it in tmpl. Paths that aren't agents, skills
+// or workflows are ignored.
+func applyTemplateFile(tmpl *Templates, relativePath, content string) {
+	parts := strings.Split(relativePath, "/")
+	if len(parts) < 2 {
+		return
+	}
+
+	switch parts[0] {
+	case "agents":
+		if strings.HasSuffix(parts[1], ".md") {
+			name := strings.TrimSuffix(parts[1], ".md")
+			agent := parseAgentFile(content)
+			agent.Name = name
+			agent.Source = SourceNetwork
+			tmpl.Agents[name] = agent
+		}
+
+	case "skills":
+		// skills/skill-name/SKILL.md
+		if len(parts) >= 2 && parts[len(parts)-1] == "SKILL.md" {
+			name := parts[1]
+			skill := parseSkillFile(content)
+			skill.Name = name
+			skill.Source = SourceNetwork
+			tmpl.Skills[name] = skill
+		}
+
+	case "workflows":
+		if strings.HasSuffix(parts[1], ".md") {
+			name := strings.TrimSuffix(parts[1], ".md")
+			workflow := parseWorkflowFile(content)
+			workflow.Name = name
+			workflow.Source = SourceNetwork
+			tmpl.Workflows[name] = workflow
+		}
+	}
+}
+
+// ListError is returned by the API fallback when whole template
+// categories couldn't be listed, so callers can tell a broken fetch from
+// an upstream that really has no templates. Each error names its category.
+type ListError struct {
+	Errs []error
+}
+
+func (e *ListError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return "failed to list templates via API: " + strings.Join(msgs, "; ")
 }
 
+// Unwrap lets errors.As find a cause such as *httpclient.RateLimitError
+func (e *ListError) Unwrap() []error {
+	return e.Errs
+}
+
+// apiFetchWorkers caps how many files fetchViaAPI downloads at once.
+// Enough to hide the round trips, few enough not to trip GitHub's
+// secondary rate limits.
+const apiFetchWorkers = 8
+
 // fetchViaAPI uses GitHub's API to download files individually.
 // this is slower but works if ZIP download fails.
+//
+// How it works:
+//  1. List the agents, workflows and skills directories (one call each)
+//  2. Download each file on a pool of apiFetchWorkers goroutines; a skill's
+//     job also lists its directory to find SKILL.md
+//  3. Parsed templates go into the maps under a mutex
+//
+// A file that fails to download is skipped rather than failing the fetch,
+// but a directory that can't be listed (rate limit, missing branch) is an
+// error, with every failed category named.
 func fetchViaAPI(branch string) (*Templates, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 	defer cancel()
@@ -194,87 +391,142 @@ func fetchViaAPI(branch string) (*Templates, error) {
 		Workflows: make(map[string]Workflow),
 	}
 
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, apiFetchWorkers)
+	)
+
+	// spawn runs job on the pool, blocking while all workers are busy
+	spawn := func(job func()) {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			job()
+		}()
+	}
+
+	// a category that can't be listed fails the fetch (after the others
+	// finish): an empty set would otherwise look like a successful one
+	var listErrs []error
+
 	// Fetch agents
 	agentFiles, err := listGitHubDir(ctx, "internal/templates/data/agents", branch)
-	if err == nil {
+	if err != nil {
+		listErrs = append(listErrs, fmt.Errorf("agents: %w", err))
+	} else {
 		for _, file := range agentFiles {
-			if file.Type == "file" && strings.HasSuffix(file.Name, ".md") {
+			if file.Type != "file" || !strings.HasSuffix(file.Name, ".md") {
+				continue
+			}
+			spawn(func() {
 				content, err := downloadFile(ctx, file.DownloadURL)
 				if err != nil {
-					continue
+					return
 				}
 				name := strings.TrimSuffix(file.Name, ".md")
 				agent := parseAgentFile(content)
 				agent.Name = name
+				agent.Source = SourceNetwork
+
+				mu.Lock()
 				tmpl.Agents[name] = agent
-			}
+				mu.Unlock()
+			})
 		}
 	}
 
 	// Fetch workflows
 	workflowFiles, err := listGitHubDir(ctx, "internal/templates/data/workflows", branch)
-	if err == nil {
+	if err != nil {
+		listErrs = append(listErrs, fmt.Errorf("workflows: %w", err))
+	} else {
 		for _, file := range workflowFiles {
-			if file.Type == "file" && strings.HasSuffix(file.Name, ".md") {
+			if file.Type != "file" || !strings.HasSuffix(file.Name, ".md") {
+				continue
+			}
+			spawn(func() {
 				content, err := downloadFile(ctx, file.DownloadURL)
 				if err != nil {
-					continue
+					return
 				}
 				name := strings.TrimSuffix(file.Name, ".md")
 				workflow := parseWorkflowFile(content)
 				workflow.Name = name
+				workflow.Source = SourceNetwork
+
+				mu.Lock()
 				tmpl.Workflows[name] = workflow
-			}
+				mu.Unlock()
+			})
 		}
 	}
 
 	// Fetch skills (need to list directories first)
 	skillDirs, err := listGitHubDir(ctx, "internal/templates/data/skills", branch)
-	if err == nil {
+	if err != nil {
+		listErrs = append(listErrs, fmt.Errorf("skills: %w", err))
+	} else {
 		for _, dir := range skillDirs {
-			if dir.Type == "dir" {
+			if dir.Type != "dir" {
+				continue
+			}
+			spawn(func() {
 				// Get SKILL.md from this directory
 				skillFiles, err := listGitHubDir(ctx, dir.Path, branch)
 				if err != nil {
-					continue
+					return
 				}
 				for _, file := range skillFiles {
-					if file.Name == "SKILL.md" {
-						content, err := downloadFile(ctx, file.DownloadURL)
-						if err != nil {
-							continue
-						}
-						skill := parseSkillFile(content)
-						skill.Name = dir.Name
-						tmpl.Skills[dir.Name] = skill
-						break
+					if file.Name != "SKILL.md" {
+						continue
+					}
+					content, err := downloadFile(ctx, file.DownloadURL)
+					if err != nil {
+						return
 					}
+					skill := parseSkillFile(content)
+					skill.Name = dir.Name
+					skill.Source = SourceNetwork
+
+					mu.Lock()
+					tmpl.Skills[dir.Name] = skill
+					mu.Unlock()
+					return
 				}
-			}
+			})
 		}
 	}
 
+	wg.Wait()
+
+	if len(listErrs) > 0 {
+		return nil, &ListError{Errs: listErrs}
+	}
 	return tmpl, nil
 }
 
 // listGitHubDir lists contents of a directory via GitHub API
 func listGitHubDir(ctx context.Context, path, branch string) ([]GitHubContentsResponse, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s?ref=%s",
-		defaultOwner, defaultRepo, path, branch)
+	url := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s",
+		apiBaseURL, defaultOwner, defaultRepo, path, branch)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	req, err := httpclient.NewGitHubRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "agen-cli")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpclient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if err := httpclient.CheckRateLimit(resp); err != nil {
+		return nil, err
+	}
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
@@ -289,17 +541,15 @@ func listGitHubDir(ctx context.Context, path, branch string) ([]GitHubContentsRe
 
 // downloadFile downloads a single file from a URL
 func downloadFile(ctx context.Context, url string) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	resp, err := httpclient.GetGitHub(ctx, url)
 	if err != nil {
 		return "", err
 	}
+	defer resp.Body.Close()
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
+	if err := httpclient.CheckRateLimit(resp); err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-
 	if resp.StatusCode != 200 {
 		return "", fmt.Errorf("download returned status %d", resp.StatusCode)
 	}
@@ -312,11 +562,83 @@ func downloadFile(ctx context.Context, url string) (string, error) {
 	return string(content), nil
 }
 
+// CacheSizeFile records the total size of the cache directory so
+// `agen stats` doesn't have to walk the whole cache on every run.
+// Anything that writes to the cache must remove it; in this package that
+// means going through writeCacheFile.
+const CacheSizeFile = "cache-size.json"
+
+// InvalidateCacheSize drops the recorded cache size after a cache write
+func InvalidateCacheSize(cacheDir string) {
+	os.Remove(filepath.Join(cacheDir, CacheSizeFile))
+}
+
+// writeCacheFile writes data to name (slash-separated, relative to
+// cacheDir), creating its parent directories, and drops the recorded cache
+// size. Every file agen puts in the cache is written through here.
+func writeCacheFile(cacheDir, name string, data []byte) error {
+	defer InvalidateCacheSize(cacheDir)
+
+	path := filepath.Join(cacheDir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// CacheTimestampFile records when the template cache was last known to
+// match upstream, for CacheAge
+const CacheTimestampFile = "templates-fetched-at"
+
+// markCacheFetched records now as the time the cache was last fetched or
+// confirmed current
+func markCacheFetched(cacheDir string) error {
+	stamp := time.Now().UTC().Format(time.RFC3339)
+	return writeCacheFile(cacheDir, CacheTimestampFile, []byte(stamp+"\n"))
+}
+
+// CacheAge returns how long ago the template cache was fetched.
+// Caches written before timestamps were recorded return an error, as if
+// there were no cache, so they're never considered fresh.
+func CacheAge(cacheDir string) (time.Duration, error) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, CacheTimestampFile))
+	if err != nil {
+		return 0, err
+	}
+	fetched, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache timestamp: %w", err)
+	}
+	return time.Since(fetched), nil
+}
+
+// LoadFreshCache returns the cached templates for branch if they were
+// fetched less than maxAge ago, and an error saying why not otherwise.
+// Lets `agen update` skip the network entirely when it fetched recently.
+func LoadFreshCache(cacheDir, branch string, maxAge time.Duration) (*Templates, error) {
+	if branch == "" {
+		branch = defaultBranch
+	}
+
+	age, err := CacheAge(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("no cache timestamp: %w", err)
+	}
+	if age >= maxAge {
+		return nil, fmt.Errorf("cache is %s old", age.Round(time.Second))
+	}
+	if state := loadCacheState(cacheDir); state == nil || state.Branch != branch {
+		return nil, fmt.Errorf("cache is not for branch %s", branch)
+	}
+	return LoadFromCache(cacheDir)
+}
+
 // CacheTemplates saves fetched templates to local cache for offline use.
+// The time is recorded for CacheAge.
 func CacheTemplates(tmpl *Templates, cacheDir string) error {
 	templatesDir := filepath.Join(cacheDir, "templates")
 
-	// create directories
+	// create directories, so an empty set still counts as a cache
 	dirs := []string{
 		filepath.Join(templatesDir, "agents"),
 		filepath.Join(templatesDir, "skills"),
@@ -331,33 +653,26 @@ func CacheTemplates(tmpl *Templates, cacheDir string) error {
 
 	// Write agents
 	for name, agent := range tmpl.Agents {
-		file := filepath.Join(templatesDir, "agents", name+".md")
-		if err := os.WriteFile(file, []byte(agent.Content), 0644); err != nil {
+		if err := writeCacheFile(cacheDir, "templates/agents/"+name+".md", []byte(agent.Content)); err != nil {
 			return err
 		}
 	}
 
 	// Write skills
 	for name, skill := range tmpl.Skills {
-		skillDir := filepath.Join(templatesDir, "skills", name)
-		if err := os.MkdirAll(skillDir, 0755); err != nil {
-			return err
-		}
-		file := filepath.Join(skillDir, "SKILL.md")
-		if err := os.WriteFile(file, []byte(skill.Content), 0644); err != nil {
+		if err := writeCacheFile(cacheDir, "templates/skills/"+name+"/SKILL.md", []byte(skill.Content)); err != nil {
 			return err
 		}
 	}
 
 	// Write workflows
 	for name, workflow := range tmpl.Workflows {
-		file := filepath.Join(templatesDir, "workflows", name+".md")
-		if err := os.WriteFile(file, []byte(workflow.Content), 0644); err != nil {
+		if err := writeCacheFile(cacheDir, "templates/workflows/"+name+".md", []byte(workflow.Content)); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return markCacheFetched(cacheDir)
 }
 
 // LoadFromCache loads templates from local cache.
@@ -368,6 +683,34 @@ func LoadFromCache(cacheDir string) (*Templates, error) {
 		return nil, fmt.Errorf("no cached templates found")
 	}
 
+	return loadDir(templatesDir, SourceCache), nil
+}
+
+// LoadOffline returns the templates last pulled into cacheDir by
+// `agen update`, or the embedded set when nothing has been cached yet.
+// Used wherever the network is unavailable or not worth a round trip.
+func LoadOffline(cacheDir string) (*Templates, error) {
+	if cached, err := LoadFromCache(cacheDir); err == nil {
+		return cached, nil
+	}
+	return LoadEmbedded()
+}
+
+// LoadFromDir loads templates laid out as agents/, skills/ and workflows/
+// under dir, e.g. a project's .agent directory. Missing subdirectories and
+// unreadable files are skipped.
+func LoadFromDir(templatesDir string) *Templates {
+	return loadDir(templatesDir, DirSource(templatesDir))
+}
+
+// LoadFromPlugin loads a plugin's templates from its directory, recording
+// PluginSource(name) as their source
+func LoadFromPlugin(dir, name string) *Templates {
+	return loadDir(dir, PluginSource(name))
+}
+
+// loadDir is LoadFromDir with the source to record on each template
+func loadDir(templatesDir, source string) *Templates {
 	tmpl := &Templates{
 		Version:   CurrentVersion,
 		Agents:    make(map[string]Agent),
@@ -387,6 +730,7 @@ func LoadFromCache(cacheDir string) (*Templates, error) {
 				name := strings.TrimSuffix(entry.Name(), ".md")
 				agent := parseAgentFile(string(content))
 				agent.Name = name
+				agent.Source = source
 				tmpl.Agents[name] = agent
 			}
 		}
@@ -404,6 +748,7 @@ func LoadFromCache(cacheDir string) (*Templates, error) {
 				}
 				skill := parseSkillFile(string(content))
 				skill.Name = entry.Name()
+				skill.Source = source
 				tmpl.Skills[entry.Name()] = skill
 			}
 		}
@@ -421,10 +766,11 @@ func LoadFromCache(cacheDir string) (*Templates, error) {
 				name := strings.TrimSuffix(entry.Name(), ".md")
 				workflow := parseWorkflowFile(string(content))
 				workflow.Name = name
+				workflow.Source = source
 				tmpl.Workflows[name] = workflow
 			}
 		}
 	}
 
-	return tmpl, nil
+	return tmpl
 }
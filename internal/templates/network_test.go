@@ -0,0 +1,507 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for network template fetching (no real network)
+
+package templates
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eshanized/agen/internal/httpclient"
+)
+
+const testAgent = `---
+description: Fake agent served by test server
+skills: clean-code
+---
+
+# Fake Agent
+`
+
+const testSkill = `---
+description: Fake skill served by test server
+---
+
+# Fake Skill
+`
+
+const testWorkflow = `---
+description: Fake workflow served by test server
+---
+
+# Fake Workflow
+`
+
+// useTestServer points the GitHub base URLs at srv for the duration of the test
+func useTestServer(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+	oldGitHub, oldAPI := githubBaseURL, apiBaseURL
+	githubBaseURL = srv.URL
+	apiBaseURL = srv.URL
+	t.Cleanup(func() {
+		githubBaseURL = oldGitHub
+		apiBaseURL = oldAPI
+	})
+}
+
+// buildTestZip creates an in-memory archive shaped like GitHub's branch ZIP
+func buildTestZip(t *testing.T, branch string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	prefix := "agen-" + branch + "/internal/templates/data/"
+
+	files := map[string]string{
+		prefix + "agents/fake-agent.md":        testAgent,
+		prefix + "skills/fake-skill/SKILL.md":  testSkill,
+		prefix + "workflows/fake-workflow.md":  testWorkflow,
+		"agen-" + branch + "/README.md":        "# not a template",
+		prefix + "skills/fake-skill/notes.txt": "ignored",
+	}
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetchFromGitHubZip(t *testing.T) {
+	zipData := buildTestZip(t, "main")
+	var apiHits int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/eshanized/agen/archive/main.zip":
+			w.Write(zipData)
+		case strings.HasPrefix(r.URL.Path, "/repos/"):
+			atomic.AddInt32(&apiHits, 1)
+			http.NotFound(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	tmpl, err := FetchFromGitHub("")
+	if err != nil {
+		t.Fatalf("FetchFromGitHub() failed: %v", err)
+	}
+
+	if agent, ok := tmpl.Agents["fake-agent"]; !ok {
+		t.Error("fake-agent should be loaded from ZIP")
+	} else if agent.Source != SourceNetwork {
+		t.Errorf("agent source = %q, want %q", agent.Source, SourceNetwork)
+	}
+	if skill, ok := tmpl.Skills["fake-skill"]; !ok {
+		t.Error("fake-skill should be loaded from ZIP")
+	} else if skill.Description != "Fake skill served by test server" {
+		t.Errorf("skill description = %q", skill.Description)
+	}
+	if _, ok := tmpl.Workflows["fake-workflow"]; !ok {
+		t.Error("fake-workflow should be loaded from ZIP")
+	}
+
+	if atomic.LoadInt32(&apiHits) != 0 {
+		t.Error("API should not be used when ZIP download succeeds")
+	}
+}
+
+func TestFetchFromGitHubZipSlashBranch(t *testing.T) {
+	// GitHub names the top-level directory agen-feature-x for feature/x
+	zipData := buildTestZip(t, "feature-x")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/eshanized/agen/archive/feature/x.zip" {
+			w.Write(zipData)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	tmpl, _, err := fetchViaZip("feature/x", "")
+	if err != nil {
+		t.Fatalf("fetchViaZip() failed: %v", err)
+	}
+	if _, ok := tmpl.Agents["fake-agent"]; !ok {
+		t.Error("fake-agent should be loaded from a slash-named branch's ZIP")
+	}
+}
+
+func TestExtractTemplatesFromZipNoTemplates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.zip")
+	f, _ := os.Create(path)
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("agen-main/README.md")
+	w.Write([]byte("# readme"))
+	zw.Close()
+	f.Close()
+
+	if _, err := extractTemplatesFromZip(path); err == nil {
+		t.Error("extractTemplatesFromZip() with no templates returned no error")
+	}
+}
+
+func TestFetchFromGitHubAPIFallback(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const base = "/repos/eshanized/agen/contents/internal/templates/data"
+
+		listing := func(entries []GitHubContentsResponse) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(entries)
+		}
+
+		switch r.URL.Path {
+		case "/eshanized/agen/archive/main.zip":
+			http.Error(w, "gone", http.StatusInternalServerError)
+		case base + "/agents":
+			listing([]GitHubContentsResponse{
+				{Name: "fake-agent.md", Type: "file", DownloadURL: srv.URL + "/raw/agent"},
+				{Name: "README.txt", Type: "file", DownloadURL: srv.URL + "/raw/readme"},
+			})
+		case base + "/workflows":
+			listing([]GitHubContentsResponse{
+				{Name: "fake-workflow.md", Type: "file", DownloadURL: srv.URL + "/raw/workflow"},
+			})
+		case base + "/skills":
+			listing([]GitHubContentsResponse{
+				{Name: "fake-skill", Path: "internal/templates/data/skills/fake-skill", Type: "dir"},
+			})
+		case base + "/skills/fake-skill":
+			listing([]GitHubContentsResponse{
+				{Name: "SKILL.md", Type: "file", DownloadURL: srv.URL + "/raw/skill"},
+			})
+		case "/raw/agent":
+			w.Write([]byte(testAgent))
+		case "/raw/workflow":
+			w.Write([]byte(testWorkflow))
+		case "/raw/skill":
+			w.Write([]byte(testSkill))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	tmpl, err := FetchFromGitHub("main")
+	if err != nil {
+		t.Fatalf("FetchFromGitHub() failed: %v", err)
+	}
+
+	if len(tmpl.Agents) != 1 {
+		t.Errorf("Expected 1 agent via API, got %d", len(tmpl.Agents))
+	}
+	if agent := tmpl.Agents["fake-agent"]; len(agent.Skills) != 1 || agent.Skills[0] != "clean-code" {
+		t.Errorf("agent skills = %v, want [clean-code]", agent.Skills)
+	}
+	if _, ok := tmpl.Skills["fake-skill"]; !ok {
+		t.Error("fake-skill should be loaded via API")
+	}
+	if _, ok := tmpl.Workflows["fake-workflow"]; !ok {
+		t.Error("fake-workflow should be loaded via API")
+	}
+}
+
+func TestFetchFromGitHubNotModified(t *testing.T) {
+	var apiHits int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/repos/") {
+			atomic.AddInt32(&apiHits, 1)
+		}
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	_, err := FetchFromGitHub("main")
+	if !errors.Is(err, ErrNotModified) {
+		t.Fatalf("FetchFromGitHub() error = %v, want ErrNotModified", err)
+	}
+
+	if atomic.LoadInt32(&apiHits) != 0 {
+		t.Error("API fallback should be skipped on 304")
+	}
+}
+
+func TestFetchCachedUsesETag(t *testing.T) {
+	zipData := buildTestZip(t, "main")
+	var downloads int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/eshanized/agen/archive/main.zip" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&downloads, 1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(zipData)
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	cacheDir := t.TempDir()
+
+	tmpl, fromCache, err := FetchCached("main", cacheDir)
+	if err != nil || fromCache || len(tmpl.Agents) != 1 {
+		t.Fatalf("first FetchCached() = %d agents, fromCache %v, %v", len(tmpl.Agents), fromCache, err)
+	}
+
+	tmpl, fromCache, err = FetchCached("main", cacheDir)
+	if err != nil || !fromCache {
+		t.Fatalf("second FetchCached() fromCache = %v, %v", fromCache, err)
+	}
+	if agent, ok := tmpl.Agents["fake-agent"]; !ok || agent.Source != SourceCache {
+		t.Errorf("cached agent = %+v, want it loaded from the cache", agent)
+	}
+	if n := atomic.LoadInt32(&downloads); n != 1 {
+		t.Errorf("zip downloaded %d times, want 1", n)
+	}
+
+	// an ETag recorded for another branch isn't sent
+	if _, fromCache, _ := FetchCached("dev", cacheDir); fromCache {
+		t.Error("FetchCached() for another branch used the main cache")
+	}
+}
+
+func TestLoadFreshCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	tmpl := &Templates{
+		Agents: map[string]Agent{"a": {Name: "a", Content: "# A\n"}},
+	}
+
+	if _, err := LoadFreshCache(cacheDir, "main", time.Hour); err == nil {
+		t.Fatal("LoadFreshCache() with no cache returned no error")
+	}
+
+	if err := saveCache(tmpl, cacheDir, cacheState{Branch: "main"}); err != nil {
+		t.Fatal(err)
+	}
+	if age, err := CacheAge(cacheDir); err != nil || age > time.Minute {
+		t.Fatalf("CacheAge() = %v, %v", age, err)
+	}
+
+	if cached, err := LoadFreshCache(cacheDir, "main", time.Hour); err != nil || len(cached.Agents) != 1 {
+		t.Errorf("LoadFreshCache() = %v, %v", cached, err)
+	}
+	if _, err := LoadFreshCache(cacheDir, "dev", time.Hour); err == nil {
+		t.Error("LoadFreshCache() used a cache from another branch")
+	}
+
+	old := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	os.WriteFile(filepath.Join(cacheDir, CacheTimestampFile), []byte(old), 0644)
+	if _, err := LoadFreshCache(cacheDir, "main", time.Hour); err == nil {
+		t.Error("LoadFreshCache() used a cache older than maxAge")
+	}
+}
+
+func TestLoadOffline(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	tmpl, err := LoadOffline(cacheDir)
+	if err != nil || tmpl.Sources()[SourceEmbedded] == 0 {
+		t.Fatalf("LoadOffline() without a cache = %v, %v; want embedded", tmpl.Sources(), err)
+	}
+
+	pulled := &Templates{Agents: map[string]Agent{"pulled": {Name: "pulled", Content: "# Pulled\n"}}}
+	if err := CacheTemplates(pulled, cacheDir); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err = LoadOffline(cacheDir)
+	if err != nil || len(tmpl.Agents) != 1 || tmpl.Agents["pulled"].Source != SourceCache {
+		t.Errorf("LoadOffline() with a cache = %v, %v; want the cached set", tmpl.Agents, err)
+	}
+}
+
+func TestCacheTemplatesInvalidatesSize(t *testing.T) {
+	cacheDir := t.TempDir()
+	sizeFile := filepath.Join(cacheDir, CacheSizeFile)
+	if err := os.WriteFile(sizeFile, []byte(`{"bytes": 42}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &Templates{
+		Agents:    map[string]Agent{"fake-agent": parseAgentFile(testAgent)},
+		Skills:    map[string]Skill{},
+		Workflows: map[string]Workflow{},
+	}
+	if err := CacheTemplates(tmpl, cacheDir); err != nil {
+		t.Fatalf("CacheTemplates() failed: %v", err)
+	}
+
+	if _, err := os.Stat(sizeFile); !os.IsNotExist(err) {
+		t.Error("recorded cache size should be removed when the cache changes")
+	}
+}
+
+func TestCacheMarkersInvalidateSize(t *testing.T) {
+	writes := map[string]func(cacheDir string) error{
+		"fetch timestamp": markCacheFetched,
+		"cache state": func(cacheDir string) error {
+			return saveCache(&Templates{}, cacheDir, cacheState{Branch: "main", Commit: "abc123"})
+		},
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			sizeFile := filepath.Join(cacheDir, CacheSizeFile)
+			if err := os.WriteFile(sizeFile, []byte(`{"bytes": 42}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := write(cacheDir); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(sizeFile); !os.IsNotExist(err) {
+				t.Errorf("writing the %s left the recorded cache size in place", name)
+			}
+		})
+	}
+}
+
+func TestFetchViaAPIBoundsConcurrency(t *testing.T) {
+	const files = 20
+	var inFlight, peak int32
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const base = "/repos/eshanized/agen/contents/internal/templates/data"
+
+		switch {
+		case r.URL.Path == base+"/agents":
+			var entries []GitHubContentsResponse
+			for i := 0; i < files; i++ {
+				entries = append(entries, GitHubContentsResponse{
+					Name: fmt.Sprintf("agent-%d.md", i), Type: "file",
+					DownloadURL: fmt.Sprintf("%s/raw/%d", srv.URL, i),
+				})
+			}
+			json.NewEncoder(w).Encode(entries)
+		case r.URL.Path == base+"/workflows", r.URL.Path == base+"/skills":
+			w.Write([]byte("[]"))
+		case r.URL.Path == "/raw/7":
+			// one broken file is skipped, not fatal
+			http.Error(w, "boom", http.StatusInternalServerError)
+		case strings.HasPrefix(r.URL.Path, "/raw/"):
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			w.Write([]byte(testAgent))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	tmpl, err := fetchViaAPI("main")
+	if err != nil {
+		t.Fatalf("fetchViaAPI() failed: %v", err)
+	}
+	if len(tmpl.Agents) != files-1 {
+		t.Errorf("got %d agents, want %d", len(tmpl.Agents), files-1)
+	}
+	if p := atomic.LoadInt32(&peak); p > apiFetchWorkers || p < 2 {
+		t.Errorf("peak concurrent downloads = %d, want 2..%d", p, apiFetchWorkers)
+	}
+}
+
+func TestFetchViaAPIListFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/eshanized/agen/contents/internal/templates/data/agents" {
+			w.Write([]byte("[]"))
+			return
+		}
+		http.Error(w, "broken", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	_, err := fetchViaAPI("main")
+	var listErr *ListError
+	if !errors.As(err, &listErr) || len(listErr.Errs) != 2 {
+		t.Fatalf("fetchViaAPI() error = %v, want a ListError for workflows and skills", err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "workflows:") || !strings.Contains(msg, "skills:") {
+		t.Errorf("error %q should name the failed categories", msg)
+	}
+}
+
+func TestFetchViaAPIRateLimited(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/repos/") {
+			gotAuth = r.Header.Get("Authorization")
+			w.Header().Set("X-RateLimit-Limit", "60")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "1900000000")
+			http.Error(w, "rate limited", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "gone", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	t.Setenv("AGEN_GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	_, err := FetchFromGitHub("main")
+	var rateErr *httpclient.RateLimitError
+	if !errors.As(err, &rateErr) || rateErr.Limit != 60 {
+		t.Fatalf("FetchFromGitHub() error = %v, want a RateLimitError", err)
+	}
+	if !strings.Contains(err.Error(), "set GITHUB_TOKEN") || gotAuth != "" {
+		t.Errorf("anonymous request: error = %q, Authorization = %q", err, gotAuth)
+	}
+
+	// AGEN_GITHUB_TOKEN wins over GITHUB_TOKEN
+	t.Setenv("AGEN_GITHUB_TOKEN", "agen-token")
+	t.Setenv("GITHUB_TOKEN", "ci-token")
+	_, err = FetchFromGitHub("main")
+	if gotAuth != "Bearer agen-token" {
+		t.Errorf("Authorization = %q, want Bearer agen-token", gotAuth)
+	}
+	if err == nil || strings.Contains(err.Error(), "set GITHUB_TOKEN") {
+		t.Errorf("authenticated request error = %v, want no token hint", err)
+	}
+}
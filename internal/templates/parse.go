@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Parsing single template files with error reporting
+
+package templates
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The loaders are forgiving: a template whose frontmatter doesn't parse is
+// still loaded, just without its metadata. That's right for installing,
+// but while editing a template you want to hear about it. These parse one
+// file the same way and return what went wrong.
+
+// ParseAgent parses one agent file, reporting broken frontmatter
+func ParseAgent(name, content string) (Agent, error) {
+	agent := parseAgentFile(content)
+	agent.Name = name
+	return agent, checkFrontmatter(content)
+}
+
+// ParseSkill parses one SKILL.md file, reporting broken frontmatter
+func ParseSkill(name, content string) (Skill, error) {
+	skill := parseSkillFile(content)
+	skill.Name = name
+	return skill, checkFrontmatter(content)
+}
+
+// ParseWorkflow parses one workflow file, reporting broken frontmatter
+func ParseWorkflow(name, content string) (Workflow, error) {
+	workflow := parseWorkflowFile(content)
+	workflow.Name = name
+	return workflow, checkFrontmatter(content)
+}
+
+// ParseFrontmatter splits markdown into its YAML frontmatter and body.
+// No frontmatter at all isn't an error: fields is nil and body is the
+// whole content. A block that's never closed or isn't valid YAML is.
+func ParseFrontmatter(content string) (map[string]interface{}, string, error) {
+	if !strings.HasPrefix(content, "---") {
+		return nil, content, nil
+	}
+
+	parts := strings.SplitN(content[3:], "---", 2)
+	if len(parts) < 2 {
+		return nil, content, fmt.Errorf("frontmatter is not closed with ---")
+	}
+
+	var frontmatter map[string]interface{}
+	if err := yaml.Unmarshal([]byte(parts[0]), &frontmatter); err != nil {
+		return nil, content, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+	return frontmatter, strings.TrimSpace(parts[1]), nil
+}
+
+// checkFrontmatter reports a frontmatter block that's never closed or isn't
+// valid YAML
+func checkFrontmatter(content string) error {
+	_, _, err := ParseFrontmatter(strings.ReplaceAll(content, "\r\n", "\n"))
+	return err
+}
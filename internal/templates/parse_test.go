@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for single-file template parsing
+
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAgent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid", "---\ndescription: Backend expert\n---\n\n# Backend\n", ""},
+		{"no frontmatter", "# Backend\n\nBuilds APIs.\n", ""},
+		{"crlf", "---\r\ndescription: Backend expert\r\n---\r\n\r\n# Backend\r\n", ""},
+		{"not closed", "---\ndescription: Backend expert\n\n# Backend\n", "not closed"},
+		{"bad yaml", "---\ndescription: [unclosed\n---\n\n# Backend\n", "invalid frontmatter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent, err := ParseAgent("backend", tt.content)
+			if agent.Name != "backend" {
+				t.Errorf("Name = %q, want backend", agent.Name)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseAgent() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseAgent() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseSkillAndWorkflow(t *testing.T) {
+	skill, err := ParseSkill("api", "---\ndescription: API design\nrequires: clean-code\n---\n# API\n")
+	if err != nil || skill.Description != "API design" || len(skill.Requires) != 1 {
+		t.Errorf("ParseSkill() = %+v, %v", skill, err)
+	}
+
+	if _, err := ParseWorkflow("deploy", "---\nargs: [\n---\n# Deploy\n"); err == nil {
+		t.Error("ParseWorkflow() with bad frontmatter returned no error")
+	}
+}
+
+func TestParseFrontmatterFields(t *testing.T) {
+	fields, body, err := ParseFrontmatter("---\nname: debugger\nskills:\n  - clean-code\n---\n\n# Debugger\n")
+	if err != nil {
+		t.Fatalf("ParseFrontmatter() error = %v", err)
+	}
+	if fields["name"] != "debugger" || body != "# Debugger" {
+		t.Errorf("ParseFrontmatter() = %v, %q", fields, body)
+	}
+
+	fields, body, err = ParseFrontmatter("# Plain\n")
+	if err != nil || fields != nil || body != "# Plain\n" {
+		t.Errorf("ParseFrontmatter() without frontmatter = %v, %q, %v", fields, body, err)
+	}
+
+	// list-style skills are read the same as comma-separated ones
+	agent, _ := ParseAgent("debugger", "---\nskills:\n  - clean-code\n  - testing\n---\n# Debugger\n")
+	if len(agent.Skills) != 2 || agent.Skills[1] != "testing" {
+		t.Errorf("Skills = %v, want [clean-code testing]", agent.Skills)
+	}
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Fetching templates from configured remotes
+
+package templates
+
+import (
+	"fmt"
+	"strings"
+)
+
+// remoteTemplateDirs are where a remote's archive may keep its templates,
+// tried in order: laid out like this repository, under templates/, or at
+// the top of the archive
+var remoteTemplateDirs = []string{templatesDataPath, "templates/", ""}
+
+// FetchFromRemote downloads the templates of the remote called name.
+//
+// url is either a GitHub repository (https://github.com/user/repo,
+// github.com/user/repo or git@github.com:user/repo.git), fetched as the
+// archive of branch, or a direct link to a .zip. Inside the archive,
+// templates may sit where this repository keeps them, under templates/,
+// or at the top: agents/, skills/ and workflows/.
+//
+// Every template is recorded with RemoteSource(name) as its source.
+func FetchFromRemote(name, url, branch string) (*Templates, error) {
+	zipURL, err := RemoteArchiveURL(url, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, _, err := fetchZip(zipURL, "", remoteTemplateDirs...)
+	if err != nil {
+		return nil, fmt.Errorf("remote %s: %w", name, err)
+	}
+	tmpl.setSource(RemoteSource(name))
+	return tmpl, nil
+}
+
+// RemoteArchiveURL returns the archive FetchFromRemote downloads for a
+// remote URL and branch
+func RemoteArchiveURL(url, branch string) (string, error) {
+	if branch == "" {
+		branch = defaultBranch
+	}
+	if strings.HasSuffix(strings.ToLower(url), ".zip") {
+		return url, nil
+	}
+
+	owner, repo, ok := parseGitHubRepo(url)
+	if !ok {
+		return "", fmt.Errorf("unsupported remote URL %q: use a GitHub repository or a .zip URL", url)
+	}
+	return fmt.Sprintf("%s/%s/%s/archive/%s.zip", githubBaseURL, owner, repo, branch), nil
+}
+
+// IsGitHubRemote reports whether url is a GitHub repository, as opposed to
+// a .zip link or an unsupported host
+func IsGitHubRemote(url string) bool {
+	_, _, ok := parseGitHubRepo(url)
+	return ok && !strings.HasSuffix(strings.ToLower(url), ".zip")
+}
+
+// parseGitHubRepo extracts owner and repo from the usual ways of writing a
+// GitHub repository URL
+func parseGitHubRepo(url string) (owner, repo string, ok bool) {
+	path := url
+	for _, prefix := range []string{"https://", "http://", "git@", "ssh://git@"} {
+		path = strings.TrimPrefix(path, prefix)
+	}
+	path, ok = strings.CutPrefix(path, "github.com")
+	if !ok || path == "" || (path[0] != '/' && path[0] != ':') {
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(path[1:], "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], strings.TrimSuffix(parts[1], ".git"), true
+}
+
+// setSource records source on every template in t
+func (t *Templates) setSource(source string) {
+	for name, agent := range t.Agents {
+		agent.Source = source
+		t.Agents[name] = agent
+	}
+	for name, skill := range t.Skills {
+		skill.Source = source
+		t.Skills[name] = skill
+	}
+	for name, workflow := range t.Workflows {
+		workflow.Source = source
+		t.Workflows[name] = workflow
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for fetching templates from configured remotes
+
+package templates
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// buildFlatZip creates an archive with agents/ at the top of its root
+// directory, the way a team's own template repository is usually laid out
+func buildFlatZip(t *testing.T, root string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		root + "/README.md":                  "# team templates",
+		root + "/agents/team-agent.md":       testAgent,
+		root + "/skills/team-skill/SKILL.md": testSkill,
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetchFromRemote(t *testing.T) {
+	flat := buildFlatZip(t, "agents-dev")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/acme/agents/archive/dev.zip":
+			w.Write(flat)
+		case "/downloads/templates.zip":
+			w.Write(buildTestZip(t, "main"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	useTestServer(t, srv)
+
+	tmpl, err := FetchFromRemote("acme", "git@github.com:acme/agents.git", "dev")
+	if err != nil {
+		t.Fatalf("FetchFromRemote() failed: %v", err)
+	}
+	if agent, ok := tmpl.Agents["team-agent"]; !ok || agent.Source != RemoteSource("acme") {
+		t.Errorf("team-agent = %+v, %v; want it from %s", agent, ok, RemoteSource("acme"))
+	}
+	if skill := tmpl.Skills["team-skill"]; skill.Source != RemoteSource("acme") {
+		t.Errorf("team-skill source = %q", skill.Source)
+	}
+
+	tmpl, err = FetchFromRemote("mirror", srv.URL+"/downloads/templates.zip", "")
+	if err != nil {
+		t.Fatalf("FetchFromRemote() of a .zip URL failed: %v", err)
+	}
+	if _, ok := tmpl.Agents["fake-agent"]; !ok || len(tmpl.Agents) != 1 {
+		t.Errorf("agents from the .zip = %v", tmpl.AgentNames())
+	}
+
+	if _, err := FetchFromRemote("gone", "https://github.com/acme/missing", ""); err == nil {
+		t.Error("FetchFromRemote() of a missing repository should fail")
+	}
+	if _, err := FetchFromRemote("other", "https://gitlab.com/acme/agents", ""); err == nil {
+		t.Error("FetchFromRemote() of an unsupported host should fail")
+	}
+}
+
+func TestParseGitHubRepo(t *testing.T) {
+	tests := []struct {
+		url         string
+		owner, repo string
+		ok          bool
+	}{
+		{"https://github.com/acme/agents", "acme", "agents", true},
+		{"https://github.com/acme/agents.git/", "acme", "agents", true},
+		{"github.com/acme/agents", "acme", "agents", true},
+		{"git@github.com:acme/agents.git", "acme", "agents", true},
+		{"https://github.com/acme", "", "", false},
+		{"https://github.community/acme/agents", "", "", false},
+		{"https://gitlab.com/acme/agents", "", "", false},
+	}
+	for _, tt := range tests {
+		owner, repo, ok := parseGitHubRepo(tt.url)
+		if owner != tt.owner || repo != tt.repo || ok != tt.ok {
+			t.Errorf("parseGitHubRepo(%q) = %q, %q, %v", tt.url, owner, repo, ok)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Saved wizard progress for 'agen init --resume'
+
+package tui
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Checkpoint is the wizard's progress, saved on every step so the
+// selections survive a crash, either in the wizard or in the install
+// after it
+type Checkpoint struct {
+	Step      int       `json:"step"`      // the wizard step reached, 0 (IDE) to 3 (confirm)
+	Confirmed bool      `json:"confirmed"` // the user confirmed; only the install is left
+	IDE       string    `json:"ide"`
+	Agents    []string  `json:"agents"`
+	Skills    []string  `json:"skills"`
+	SavedAt   time.Time `json:"saved_at"`
+}
+
+// CheckpointPath returns where the wizard for projectDir saves its
+// progress: a file in the temp directory, named after a hash of the
+// absolute project path so two projects don't share one
+func CheckpointPath(projectDir string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(projectDir)))
+	return filepath.Join(os.TempDir(), "agen-wizard-"+hex.EncodeToString(sum[:6])+".json")
+}
+
+// LoadCheckpoint reads a checkpoint saved by the wizard.
+// Returns nil (and no error) when there isn't one.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &Checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// RemoveCheckpoint deletes a checkpoint, if there is one
+func RemoveCheckpoint(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// saveCheckpoint writes the wizard's progress to m.checkpoint.
+// Errors are ignored: a missing checkpoint only costs the resume.
+func (m Model) saveCheckpoint() {
+	if m.checkpoint == "" {
+		return
+	}
+
+	cp := Checkpoint{
+		Step:      int(m.state),
+		Confirmed: m.confirmed,
+		IDE:       m.selectedIDE,
+		Agents:    selectedNames(m.selectedAgents),
+		Skills:    selectedNames(m.selectedSkills),
+		SavedAt:   time.Now().UTC(),
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return
+	}
+
+	tmp := m.checkpoint + ".tmp"
+	if os.WriteFile(tmp, append(data, '\n'), 0600) == nil {
+		os.Rename(tmp, m.checkpoint)
+	}
+}
+
+// selectedNames returns the names set in selected, sorted
+func selectedNames(selected map[string]bool) []string {
+	var names []string
+	for name, on := range selected {
+		if on {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for saving and resuming wizard progress
+
+package tui
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestWizardSavesCheckpointOnEachStep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wizard.json")
+	m := NewWizard(testTemplates(), "cursor")
+	m.checkpoint = path
+
+	m = send(t, m, tea.WindowSizeMsg{Width: 100, Height: 40}, enter)
+	cp, err := LoadCheckpoint(path)
+	if err != nil || cp == nil {
+		t.Fatalf("LoadCheckpoint() after the IDE step = %v, %v", cp, err)
+	}
+	if cp.Step != int(stateAgentSelect) || cp.IDE != "cursor" || cp.Confirmed {
+		t.Errorf("checkpoint = %+v, want the agent step with cursor", cp)
+	}
+
+	m = send(t, m, typed("/frontend")...)
+	m = send(t, m, enter, space, enter, enter, enter)
+	if !m.confirmed {
+		t.Fatal("wizard not confirmed")
+	}
+	cp, _ = LoadCheckpoint(path)
+	if !cp.Confirmed || !reflect.DeepEqual(cp.Agents, []string{"frontend-specialist"}) || !reflect.DeepEqual(cp.Skills, []string{"clean-code"}) {
+		t.Errorf("checkpoint = %+v, want the confirmed selection", cp)
+	}
+}
+
+func TestWizardResume(t *testing.T) {
+	m := NewWizard(testTemplates(), "antigravity").resume(&Checkpoint{
+		Step:   int(stateSkillSelect),
+		IDE:    "zed",
+		Agents: []string{"frontend-specialist"},
+		Skills: []string{"testing-patterns"},
+	})
+
+	if m.state != stateSkillSelect || m.selectedIDE != "zed" {
+		t.Fatalf("resumed at %v with %q, want the skill step with zed", m.state, m.selectedIDE)
+	}
+	if got := chosen(m.selectedAgents); !reflect.DeepEqual(got, []string{"frontend-specialist"}) {
+		t.Errorf("agents = %v", got)
+	}
+	// clean-code was deselected before the checkpoint; it's flagged, not re-selected
+	if i := skillItem(t, m, "clean-code"); i.selected || len(i.requiredBy) != 1 {
+		t.Errorf("clean-code = %+v, want flagged but not selected", i)
+	}
+	if i := skillItem(t, m, "testing-patterns"); !i.selected {
+		t.Error("testing-patterns not restored")
+	}
+}
+
+func TestLoadCheckpointMissing(t *testing.T) {
+	cp, err := LoadCheckpoint(filepath.Join(t.TempDir(), "none.json"))
+	if cp != nil || err != nil {
+		t.Errorf("LoadCheckpoint() of a missing file = %v, %v; want nil, nil", cp, err)
+	}
+	if err := RemoveCheckpoint(filepath.Join(t.TempDir(), "none.json")); err != nil {
+		t.Errorf("RemoveCheckpoint() of a missing file = %v", err)
+	}
+	if CheckpointPath("/src/a") == CheckpointPath("/src/b") {
+		t.Error("two projects share a checkpoint path")
+	}
+}
@@ -12,9 +12,27 @@ import (
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/eshanized/agen/internal/templates"
 )
 
+const (
+	// previewMinWidth is the narrowest terminal that gets the skill
+	// preview beside the list; below it the list takes the full width
+	previewMinWidth = 80
+
+	// creatorChrome is the height taken by the title, subtitle and help
+	// around the skill list
+	creatorChrome = 10
+)
+
+// previewStyle frames the skill preview beside the creator's skill list
+var previewStyle = lipgloss.NewStyle().
+	BorderStyle(lipgloss.NormalBorder()).
+	BorderLeft(true).
+	BorderForeground(lipgloss.Color("241")).
+	PaddingLeft(2)
+
 // CreatorResult contains the data collected by the creator wizard
 type CreatorResult struct {
 	Name        string
@@ -44,6 +62,11 @@ type CreatorModel struct {
 	selectedSkills map[string]bool
 	templates      *templates.Templates
 
+	// validateName, when set, vets the name before the next step; its
+	// error is shown under the input until the next key press
+	validateName func(string) error
+	nameErr      string
+
 	width     int
 	height    int
 	quitting  bool
@@ -99,6 +122,11 @@ func (m CreatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.nameErr = ""
+		// keys typed into the skill filter belong to the filter
+		if m.state == stateSkills && m.skillList.SettingFilter() && msg.String() != "ctrl+c" {
+			break
+		}
 		switch msg.String() {
 		case "ctrl+c":
 			m.quitting = true
@@ -107,7 +135,15 @@ func (m CreatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			switch m.state {
 			case stateNameInput:
-				if m.nameInput.Value() != "" {
+				name := strings.TrimSpace(m.nameInput.Value())
+				if name != "" && m.validateName != nil {
+					if err := m.validateName(name); err != nil {
+						m.nameErr = err.Error()
+						return m, nil
+					}
+				}
+				if name != "" {
+					m.nameInput.SetValue(name)
 					m.state = stateDescInput
 					m.descInput.Focus()
 					return m, textinput.Blink
@@ -126,19 +162,10 @@ func (m CreatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 
-		case "space":
+		case " ":
 			if m.state == stateSkills {
 				if i, ok := m.skillList.SelectedItem().(item); ok {
-					m.selectedSkills[i.name] = !m.selectedSkills[i.name]
-					// Update view status
-					idx := m.skillList.Index()
-					items := m.skillList.Items()
-					items[idx] = item{
-						name:        i.name,
-						description: i.description,
-						selected:    m.selectedSkills[i.name],
-					}
-					m.skillList.SetItems(items)
+					return m, setSelected(&m.skillList, m.selectedSkills, []string{i.name}, !m.selectedSkills[i.name])
 				}
 				return m, nil
 			}
@@ -162,7 +189,7 @@ func (m CreatorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.skillList.SetSize(msg.Width-4, msg.Height-10)
+		m.skillList.SetSize(m.skillListWidth(), msg.Height-creatorChrome)
 	}
 
 	// Route events to components
@@ -194,7 +221,9 @@ func (m CreatorModel) View() string {
 		b.WriteString(subtitleStyle.Render("Step 1/4: Name your agent"))
 		b.WriteString("\n")
 		b.WriteString(m.nameInput.View())
-		b.WriteString("\n\n")
+		b.WriteString("\n")
+		b.WriteString(warningStyle.Render(m.nameErr))
+		b.WriteString("\n")
 		b.WriteString(helpStyle.Render("enter: next • esc: cancel"))
 
 	case stateDescInput:
@@ -207,7 +236,11 @@ func (m CreatorModel) View() string {
 	case stateSkills:
 		b.WriteString(subtitleStyle.Render("Step 3/4: Add skills"))
 		b.WriteString("\n")
-		b.WriteString(m.skillList.View())
+		if preview := m.renderPreview(); preview != "" {
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, m.skillList.View(), preview))
+		} else {
+			b.WriteString(m.skillList.View())
+		}
 		b.WriteString(helpStyle.Render("space: toggle • enter: next • esc: back"))
 
 	case stateCreatorConfirm:
@@ -242,6 +275,81 @@ func (m CreatorModel) View() string {
 	return b.String()
 }
 
+// skillListWidth is the skill list's share of the terminal: half of it
+// when there's room for the preview, all of it otherwise
+func (m CreatorModel) skillListWidth() int {
+	if m.width < previewMinWidth {
+		return m.width - 4
+	}
+	return (m.width - 4) / 2
+}
+
+// renderPreview shows the highlighted skill beside the list, or nothing
+// when the terminal is too narrow for it
+func (m CreatorModel) renderPreview() string {
+	if m.width < previewMinWidth {
+		return ""
+	}
+	i, ok := m.skillList.SelectedItem().(item)
+	if !ok {
+		return ""
+	}
+
+	// border and padding take 3 columns
+	width := m.width - 4 - m.skillListWidth() - 3
+	height := m.height - creatorChrome
+	return previewStyle.Render(skillPreview(i.name, m.templates.Skills[i.name].Content, width, height))
+}
+
+// skillPreview summarizes a skill in at most height lines of width
+// columns: its first paragraph, then the headings of its "## " sections.
+// Code fences are skipped, so a "## " inside an example isn't a section.
+func skillPreview(name, content string, width, height int) string {
+	_, body, _ := templates.ParseFrontmatter(content)
+
+	var paragraph, sections []string
+	inFence, paragraphDone := false, false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			paragraphDone = paragraphDone || len(paragraph) > 0
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "## "):
+			sections = append(sections, "• "+strings.TrimSpace(trimmed[3:]))
+			paragraphDone = paragraphDone || len(paragraph) > 0
+		case trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---":
+			paragraphDone = paragraphDone || len(paragraph) > 0
+		case !paragraphDone:
+			paragraph = append(paragraph, strings.TrimLeft(trimmed, "> "))
+		}
+	}
+
+	text := selectedStyle.Render(name)
+	if len(paragraph) > 0 {
+		text += "\n\n" + strings.Join(paragraph, " ")
+	}
+	if len(sections) > 0 {
+		text += "\n\nSections:\n" + strings.Join(sections, "\n")
+	}
+
+	lines := strings.Split(lipgloss.NewStyle().Width(width).Render(text), "\n")
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+		lines[height-1] = "…"
+	}
+	for idx, line := range lines {
+		lines[idx] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
 // GetResult returns the collected data
 func (m CreatorModel) GetResult() CreatorResult {
 	if m.quitting || !m.confirmed {
@@ -263,9 +371,12 @@ func (m CreatorModel) GetResult() CreatorResult {
 	}
 }
 
-// RunCreator launches the wizard
-func RunCreator(tmpl *templates.Templates) (CreatorResult, error) {
+// RunCreator launches the wizard. validateName, if not nil, is checked
+// when the name is entered, so a taken or invalid name is caught before
+// the rest is filled in.
+func RunCreator(tmpl *templates.Templates, validateName func(string) error) (CreatorResult, error) {
 	m := NewCreator(tmpl)
+	m.validateName = validateName
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	finalModel, err := p.Run()
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for the agent creator
+
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eshanized/agen/internal/templates"
+)
+
+// sendCreator feeds msgs to the creator, dropping the commands it returns
+func sendCreator(m CreatorModel, msgs ...tea.Msg) CreatorModel {
+	for _, msg := range msgs {
+		next, _ := m.Update(msg)
+		m = next.(CreatorModel)
+	}
+	return m
+}
+
+func TestCreatorRejectsTakenName(t *testing.T) {
+	m := NewCreator(testTemplates())
+	m.validateName = func(name string) error {
+		if name == "debugger" {
+			return fmt.Errorf("agent %s already exists", name)
+		}
+		return nil
+	}
+
+	m = sendCreator(m, typed("debugger")...)
+	m = sendCreator(m, enter)
+	if m.state != stateNameInput || m.nameErr == "" {
+		t.Fatalf("state %v, error %q; want to stay on the name with an error", m.state, m.nameErr)
+	}
+
+	m = sendCreator(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.nameErr != "" {
+		t.Errorf("error %q outlived the next key", m.nameErr)
+	}
+	m = sendCreator(m, typed("x")...)
+	if m = sendCreator(m, enter); m.state != stateDescInput {
+		t.Errorf("state = %v, want the description step for a free name", m.state)
+	}
+}
+
+func TestCreatorResult(t *testing.T) {
+	m := NewCreator(testTemplates())
+	m = sendCreator(m, tea.WindowSizeMsg{Width: 100, Height: 40})
+	m = sendCreator(m, typed("api-reviewer")...)
+	m = sendCreator(m, enter)
+	m = sendCreator(m, typed("Reviews APIs")...)
+	m = sendCreator(m, enter, space, enter, enter)
+
+	got := m.GetResult()
+	want := CreatorResult{Name: "api-reviewer", Description: "Reviews APIs", Skills: []string{m.skillList.Items()[0].(item).name}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetResult() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSkillPreview(t *testing.T) {
+	content := "---\nname: clean-code\ndescription: x\n---\n\n# Clean Code\n\n" +
+		"Pragmatic coding standards\nfor every language.\n\nSecond paragraph.\n\n" +
+		"## Principles\n\n```md\n## not a section\n```\n\n## Anti-Patterns\n"
+
+	got := skillPreview("clean-code", content, 60, 0)
+	for _, want := range []string{"Pragmatic coding standards for every language.", "• Principles", "• Anti-Patterns"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("preview missing %q:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"Second paragraph", "not a section", "description:"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("preview has %q:\n%s", unwanted, got)
+		}
+	}
+
+	short := skillPreview("clean-code", content, 60, 3)
+	if lines := strings.Split(short, "\n"); len(lines) != 3 || lines[2] != "…" {
+		t.Errorf("preview in 3 lines = %q", lines)
+	}
+}
+
+func TestCreatorPreviewFollowsWidth(t *testing.T) {
+	tmpl := testTemplates()
+	tmpl.Skills["clean-code"] = templates.Skill{Name: "clean-code", Content: "# Clean Code\n\nWrite readable code.\n"}
+	tmpl.Skills["testing-patterns"] = templates.Skill{Name: "testing-patterns", Content: "# Testing\n\nWrite readable code.\n"}
+
+	m := NewCreator(tmpl)
+	m = sendCreator(m, typed("api-reviewer")...)
+	m = sendCreator(m, enter)
+	m = sendCreator(m, typed("Reviews APIs")...)
+	m = sendCreator(m, enter)
+
+	if m = sendCreator(m, tea.WindowSizeMsg{Width: 120, Height: 40}); !strings.Contains(m.View(), "Write readable code.") {
+		t.Errorf("no preview at 120 columns:\n%s", m.View())
+	}
+	if m = sendCreator(m, tea.WindowSizeMsg{Width: 60, Height: 40}); strings.Contains(m.View(), "Write readable code.") {
+		t.Errorf("preview shown at 60 columns:\n%s", m.View())
+	}
+}
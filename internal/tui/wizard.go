@@ -47,6 +47,14 @@ type Model struct {
 	selectedAgents map[string]bool
 	selectedSkills map[string]bool
 
+	// autoSkills are skills the wizard marked because a selected agent
+	// lists them, and the user hasn't touched since. They're unmarked
+	// again if those agents are deselected.
+	autoSkills map[string]bool
+	warning    string // shown until the next key press
+
+	checkpoint string // file progress is saved to, see Checkpoint
+
 	templates *templates.Templates
 	width     int
 	height    int
@@ -59,13 +67,18 @@ type item struct {
 	name        string
 	description string
 	selected    bool
+	requiredBy  []string // selected agents that list this skill
 }
 
 func (i item) Title() string {
+	title := "  " + i.name
 	if i.selected {
-		return "✓ " + i.name
+		title = "✓ " + i.name
 	}
-	return "  " + i.name
+	if len(i.requiredBy) > 0 {
+		title += " (required)"
+	}
+	return title
 }
 func (i item) Description() string { return i.description }
 func (i item) FilterValue() string { return i.name }
@@ -88,16 +101,49 @@ var (
 	helpStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241")).
 			MarginTop(2)
+
+	detailStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			PaddingLeft(2)
+
+	warningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214"))
+)
+
+const (
+	// detailLines is how many lines the highlighted item's description may
+	// wrap to in the detail pane under the list. Fixed so the layout (and
+	// the list's page size) doesn't jump around while scrolling.
+	detailLines = 4
+
+	// defaultListHeight is used until the terminal reports its size
+	defaultListHeight = 15
+
+	// minListHeight keeps a couple of items visible in tiny terminals
+	minListHeight = 6
 )
 
-// NewWizard creates a new interactive wizard model
-func NewWizard(tmpl *templates.Templates) Model {
+// selectHelp is the key help under the agent and skill lists
+const selectHelp = "space: toggle • /: filter • a: all (shown) • n: none (shown) • enter: continue • esc: back"
+
+// NewWizard creates a new interactive wizard model.
+// defaultIDE pre-selects that entry in the IDE list (ignored if unknown);
+// it's a registry key, as ide.ResolveAdapterName returns.
+func NewWizard(tmpl *templates.Templates, defaultIDE string) Model {
 	// IDE options
 	ideItems := []list.Item{
-		item{name: "antigravity", description: "Claude Code / Antigravity (full .agent/ folder)"},
+		item{name: "antigravity", description: "Antigravity (full .agent/ folder)"},
 		item{name: "cursor", description: "Cursor IDE (.cursorrules file)"},
 		item{name: "windsurf", description: "Windsurf IDE (.windsurfrules file)"},
 		item{name: "zed", description: "Zed Editor (.zed/ folder with prompts)"},
+		item{name: "claudecode", description: "Claude Code (CLAUDE.md file)"},
+		item{name: "cline", description: "Cline for VS Code (.clinerules file)"},
+		item{name: "continue", description: "Continue for VS Code (.continuerules file)"},
+		item{name: "copilotworkspace", description: "GitHub Copilot (.github/copilot-instructions.md)"},
+		item{name: "jetbrains", description: "JetBrains IDEs (.jbrules.md file)"},
+		item{name: "neovim", description: "Neovim (.nvim/ folder)"},
+		item{name: "emacs", description: "Emacs (.emacs-project/ folder)"},
+		item{name: "aider", description: "Aider (.aider-context.md file)"},
 	}
 
 	ideDelegate := list.NewDefaultDelegate()
@@ -105,6 +151,12 @@ func NewWizard(tmpl *templates.Templates) Model {
 	ideList.Title = "Select IDE"
 	ideList.SetShowStatusBar(false)
 	ideList.SetFilteringEnabled(false)
+	for idx, itm := range ideItems {
+		if itm.(item).name == defaultIDE {
+			ideList.Select(idx)
+			break
+		}
+	}
 
 	// Agent options
 	var agentItems []list.Item
@@ -115,8 +167,8 @@ func NewWizard(tmpl *templates.Templates) Model {
 		})
 	}
 	agentDelegate := list.NewDefaultDelegate()
-	agentList := list.New(agentItems, agentDelegate, 60, 15)
-	agentList.Title = "Select Agents (space to toggle, enter to continue)"
+	agentList := list.New(agentItems, agentDelegate, 60, defaultListHeight)
+	agentList.Title = "Select Agents (space to toggle, / to filter, enter to continue)"
 	agentList.SetShowStatusBar(false)
 
 	// Skill options
@@ -128,8 +180,8 @@ func NewWizard(tmpl *templates.Templates) Model {
 		})
 	}
 	skillDelegate := list.NewDefaultDelegate()
-	skillList := list.New(skillItems, skillDelegate, 60, 15)
-	skillList.Title = "Select Skills (space to toggle, enter to continue)"
+	skillList := list.New(skillItems, skillDelegate, 60, defaultListHeight)
+	skillList.Title = "Select Skills (space to toggle, / to filter, enter to continue)"
 	skillList.SetShowStatusBar(false)
 
 	return Model{
@@ -139,6 +191,7 @@ func NewWizard(tmpl *templates.Templates) Model {
 		skillList:      skillList,
 		selectedAgents: make(map[string]bool),
 		selectedSkills: make(map[string]bool),
+		autoSkills:     make(map[string]bool),
 		templates:      tmpl,
 	}
 }
@@ -148,10 +201,27 @@ func (m Model) Init() tea.Cmd {
 	return nil
 }
 
-// Update implements tea.Model
+// Update implements tea.Model. Moving to another step (or confirming)
+// saves a checkpoint.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	state, confirmed := m.state, m.confirmed
+	next, cmd := m.update(msg)
+	if next.state != state || next.confirmed != confirmed {
+		next.saveCheckpoint()
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// while a filter is being typed, every key but ctrl+c belongs to
+		// the filter input, so "a", "q" or space can be part of the query
+		if l, _ := m.selection(); l != nil && l.SettingFilter() && msg.String() != "ctrl+c" {
+			break
+		}
+		m.warning = ""
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.quitting = true
@@ -167,6 +237,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			case stateAgentSelect:
 				m.state = stateSkillSelect
+				return m, m.markRequiredSkills()
 
 			case stateSkillSelect:
 				m.state = stateConfirm
@@ -178,89 +249,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case " ":
-			// Toggle selection
-			switch m.state {
-			case stateAgentSelect:
-				if i, ok := m.agentList.SelectedItem().(item); ok {
-					m.selectedAgents[i.name] = !m.selectedAgents[i.name]
-					// Update list item
-					idx := m.agentList.Index()
-					items := m.agentList.Items()
-					items[idx] = item{
-						name:        i.name,
-						description: i.description,
-						selected:    m.selectedAgents[i.name],
-					}
-					m.agentList.SetItems(items)
-				}
-
-			case stateSkillSelect:
-				if i, ok := m.skillList.SelectedItem().(item); ok {
-					m.selectedSkills[i.name] = !m.selectedSkills[i.name]
-					idx := m.skillList.Index()
-					items := m.skillList.Items()
-					items[idx] = item{
-						name:        i.name,
-						description: i.description,
-						selected:    m.selectedSkills[i.name],
-					}
-					m.skillList.SetItems(items)
-				}
-			}
-			return m, nil
-
-		case "a":
-			// Select all
-			switch m.state {
-			case stateAgentSelect:
-				items := m.agentList.Items()
-				for idx, itm := range items {
-					if i, ok := itm.(item); ok {
-						m.selectedAgents[i.name] = true
-						items[idx] = item{name: i.name, description: i.description, selected: true}
-					}
-				}
-				m.agentList.SetItems(items)
-
-			case stateSkillSelect:
-				items := m.skillList.Items()
-				for idx, itm := range items {
-					if i, ok := itm.(item); ok {
-						m.selectedSkills[i.name] = true
-						items[idx] = item{name: i.name, description: i.description, selected: true}
-					}
+			// Toggle selection of the highlighted item
+			if l, selected := m.selection(); l != nil {
+				if i, ok := l.SelectedItem().(item); ok {
+					m.skillsTouched([]string{i.name}, !selected[i.name])
+					return m, setSelected(l, selected, []string{i.name}, !selected[i.name])
 				}
-				m.skillList.SetItems(items)
 			}
 			return m, nil
 
-		case "n":
-			// Select none
-			switch m.state {
-			case stateAgentSelect:
-				items := m.agentList.Items()
-				for idx, itm := range items {
-					if i, ok := itm.(item); ok {
-						m.selectedAgents[i.name] = false
-						items[idx] = item{name: i.name, description: i.description, selected: false}
-					}
-				}
-				m.agentList.SetItems(items)
-
-			case stateSkillSelect:
-				items := m.skillList.Items()
-				for idx, itm := range items {
+		case "a", "n":
+			// Select all / none of the items shown, so with a filter
+			// applied only the matches change
+			if l, selected := m.selection(); l != nil {
+				var names []string
+				for _, itm := range l.VisibleItems() {
 					if i, ok := itm.(item); ok {
-						m.selectedSkills[i.name] = false
-						items[idx] = item{name: i.name, description: i.description, selected: false}
+						names = append(names, i.name)
 					}
 				}
-				m.skillList.SetItems(items)
+				m.skillsTouched(names, msg.String() == "a")
+				return m, setSelected(l, selected, names, msg.String() == "a")
 			}
 			return m, nil
 
 		case "esc":
-			// go back
+			// the first esc clears an applied filter (handled by the
+			// list below), the next one goes back
+			if l, _ := m.selection(); l != nil && l.IsFiltered() {
+				break
+			}
 			if m.state > stateIDESelect {
 				m.state--
 			}
@@ -270,9 +288,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.ideList.SetSize(msg.Width-4, msg.Height-10)
-		m.agentList.SetSize(msg.Width-4, msg.Height-10)
-		m.skillList.SetSize(msg.Width-4, msg.Height-10)
+		height := m.listHeight()
+		m.ideList.SetSize(msg.Width-4, height)
+		m.agentList.SetSize(msg.Width-4, height)
+		m.skillList.SetSize(msg.Width-4, height)
 	}
 
 	// Update the current list
@@ -289,6 +308,132 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// selection returns the list and selection set of the agent or skill
+// step, or nil on the other steps
+func (m *Model) selection() (*list.Model, map[string]bool) {
+	switch m.state {
+	case stateAgentSelect:
+		return &m.agentList, m.selectedAgents
+	case stateSkillSelect:
+		return &m.skillList, m.selectedSkills
+	}
+	return nil, nil
+}
+
+// skillsTouched records that the user set the named skills on or off
+// themselves, so markRequiredSkills leaves them alone from now on.
+// Turning off a skill a selected agent needs is allowed, with a warning.
+// Does nothing outside the skill step.
+func (m *Model) skillsTouched(names []string, on bool) {
+	if m.state != stateSkillSelect {
+		return
+	}
+
+	var required []string
+	for _, name := range names {
+		delete(m.autoSkills, name)
+		if !on && m.selectedSkills[name] {
+			if agents := m.requiredBy(name); len(agents) > 0 {
+				required = append(required, fmt.Sprintf("%s (required by %s)", name, strings.Join(agents, ", ")))
+			}
+		}
+	}
+
+	if len(required) > 0 {
+		m.warning = "⚠ deselected " + strings.Join(required, "; ")
+	}
+}
+
+// requiredBy returns the selected agents whose skills list includes skill
+func (m *Model) requiredBy(skill string) []string {
+	for _, itm := range m.skillList.Items() {
+		if i, ok := itm.(item); ok && i.name == skill {
+			return i.requiredBy
+		}
+	}
+	return nil
+}
+
+// markRequiredSkills flags and selects the skills the selected agents
+// list in their frontmatter. Run on entering the skill step, so going
+// back and changing agents is picked up.
+//
+// Skills it marked on an earlier pass that no agent needs any more are
+// unmarked again, unless the user has toggled them since.
+func (m *Model) markRequiredSkills() tea.Cmd {
+	required := m.requiredSkills()
+	items := m.skillList.Items()
+	for idx, itm := range items {
+		i, ok := itm.(item)
+		if !ok {
+			continue
+		}
+		i.requiredBy = required[i.name]
+		switch {
+		case len(i.requiredBy) > 0 && !i.selected:
+			i.selected = true
+			m.autoSkills[i.name] = true
+		case len(i.requiredBy) == 0 && m.autoSkills[i.name]:
+			i.selected = false
+			delete(m.autoSkills, i.name)
+		}
+		m.selectedSkills[i.name] = i.selected
+		items[idx] = i
+	}
+	return m.skillList.SetItems(items)
+}
+
+// requiredSkills maps each skill a selected agent lists to those agents
+func (m *Model) requiredSkills() map[string][]string {
+	required := make(map[string][]string)
+	for _, agent := range selectedNames(m.selectedAgents) {
+		for _, skill := range m.templates.Agents[agent].Skills {
+			required[skill] = append(required[skill], agent)
+		}
+	}
+	return required
+}
+
+// selectionCounts is the running tally shown on the agent and skill steps
+func (m Model) selectionCounts() string {
+	agents, skills := 0, 0
+	for _, on := range m.selectedAgents {
+		if on {
+			agents++
+		}
+	}
+	for _, on := range m.selectedSkills {
+		if on {
+			skills++
+		}
+	}
+	return fmt.Sprintf("Selected: %d agents, %d skills", agents, skills)
+}
+
+// setSelected marks the named items in l as selected or not.
+//
+// Items are looked up by name in the full, unfiltered item list: with a
+// filter applied, the highlighted row's index is its position among the
+// matches, not in the list. Because the ✓ lives on the full list, it's
+// still there when the filter is cleared. The returned command refilters
+// the list so the view picks up the change.
+func setSelected(l *list.Model, selected map[string]bool, names []string, on bool) tea.Cmd {
+	changed := make(map[string]bool, len(names))
+	for _, name := range names {
+		selected[name] = on
+		changed[name] = true
+	}
+
+	items := l.Items()
+	for idx, itm := range items {
+		if i, ok := itm.(item); ok && changed[i.name] {
+			i.selected = on
+			items[idx] = i
+		}
+	}
+	return l.SetItems(items)
+}
+
 // View implements tea.Model
 func (m Model) View() string {
 	if m.quitting {
@@ -305,18 +450,23 @@ func (m Model) View() string {
 		b.WriteString(subtitleStyle.Render("Step 1/4: Choose your IDE"))
 		b.WriteString("\n")
 		b.WriteString(m.ideList.View())
+		b.WriteString(m.renderDetail(m.ideList))
 
 	case stateAgentSelect:
-		b.WriteString(subtitleStyle.Render(fmt.Sprintf("Step 2/4: Select Agents (IDE: %s)", m.selectedIDE)))
+		b.WriteString(subtitleStyle.Render(fmt.Sprintf("Step 2/4: Select Agents (IDE: %s) • %s", m.selectedIDE, m.selectionCounts())))
 		b.WriteString("\n")
 		b.WriteString(m.agentList.View())
-		b.WriteString(helpStyle.Render("space: toggle • a: all • n: none • enter: continue • esc: back"))
+		b.WriteString(m.renderDetail(m.agentList))
+		b.WriteString(m.renderWarning())
+		b.WriteString(helpStyle.Render(selectHelp))
 
 	case stateSkillSelect:
-		b.WriteString(subtitleStyle.Render("Step 3/4: Select Skills"))
+		b.WriteString(subtitleStyle.Render("Step 3/4: Select Skills • " + m.selectionCounts()))
 		b.WriteString("\n")
 		b.WriteString(m.skillList.View())
-		b.WriteString(helpStyle.Render("space: toggle • a: all • n: none • enter: continue • esc: back"))
+		b.WriteString(m.renderDetail(m.skillList))
+		b.WriteString(m.renderWarning())
+		b.WriteString(helpStyle.Render(selectHelp))
 
 	case stateConfirm:
 		b.WriteString(subtitleStyle.Render("Step 4/4: Confirm Selection"))
@@ -360,6 +510,68 @@ func (m Model) View() string {
 	return b.String()
 }
 
+// listHeight sizes the lists to the terminal, leaving room for the
+// title, subtitle, detail pane and help line around them
+func (m Model) listHeight() int {
+	chrome := lipgloss.Height(titleStyle.Render("")) +
+		lipgloss.Height(subtitleStyle.Render("")) +
+		lipgloss.Height(helpStyle.Render("")) +
+		detailLines + 3 // detail name line, warning line and spacing
+
+	if height := m.height - chrome; height > minListHeight {
+		return height
+	}
+	return minListHeight
+}
+
+// renderDetail shows the highlighted item's full description, word-wrapped.
+//
+// The list delegate truncates descriptions to one line, which cuts off
+// most agent descriptions. Rendering them here instead keeps every list
+// row the same height, so paging and the ✓ marks still line up.
+func (m Model) renderDetail(l list.Model) string {
+	i, ok := l.SelectedItem().(item)
+	if !ok {
+		return ""
+	}
+
+	width := m.width - 6
+	if width < 20 {
+		width = 56
+	}
+
+	name := i.name
+	if i.selected {
+		name = selectedStyle.Render("✓ " + i.name)
+	}
+	if len(i.requiredBy) > 0 {
+		name += warningStyle.Render("  required by " + strings.Join(i.requiredBy, ", "))
+	}
+
+	var lines []string
+	if i.description != "" {
+		wrapped := lipgloss.NewStyle().Width(width).Render(i.description)
+		for _, line := range strings.Split(wrapped, "\n") {
+			lines = append(lines, strings.TrimRight(line, " "))
+		}
+	}
+	if len(lines) > detailLines {
+		lines = lines[:detailLines]
+		lines[detailLines-1] += "…"
+	}
+	for len(lines) < detailLines {
+		lines = append(lines, "")
+	}
+
+	return "\n" + detailStyle.Render(name+"\n"+strings.Join(lines, "\n"))
+}
+
+// renderWarning shows the last warning on a line of its own. The line is
+// there even when empty, so the help line doesn't move when one appears.
+func (m Model) renderWarning() string {
+	return "\n" + warningStyle.Render(m.warning)
+}
+
 // GetResult returns the wizard result after it completes
 func (m Model) GetResult() WizardResult {
 	if m.quitting || !m.confirmed {
@@ -386,17 +598,73 @@ func (m Model) GetResult() WizardResult {
 	}
 }
 
+// resume restores the step and selections of cp. Required skills are
+// flagged again, but not re-selected: the user may have turned them off.
+//
+// The lists aren't filtered yet, so the commands that would refilter
+// them after SetItems are nil and can be dropped.
+func (m Model) resume(cp *Checkpoint) Model {
+	m.selectedIDE = cp.IDE
+	for idx, itm := range m.ideList.Items() {
+		if itm.(item).name == cp.IDE {
+			m.ideList.Select(idx)
+		}
+	}
+
+	setSelected(&m.agentList, m.selectedAgents, cp.Agents, true)
+	setSelected(&m.skillList, m.selectedSkills, cp.Skills, true)
+
+	required := m.requiredSkills()
+	items := m.skillList.Items()
+	for idx, itm := range items {
+		if i, ok := itm.(item); ok {
+			i.requiredBy = required[i.name]
+			items[idx] = i
+		}
+	}
+	m.skillList.SetItems(items)
+
+	// a confirmed checkpoint is never resumed into the wizard, so the
+	// furthest it goes is the summary, waiting for enter
+	m.state = wizardState(cp.Step)
+	if m.state < stateIDESelect || m.state > stateConfirm || cp.IDE == "" {
+		m.state = stateIDESelect
+	}
+	return m
+}
+
+// WizardOptions are the optional settings for RunWizard
+type WizardOptions struct {
+	// Checkpoint is a file to save progress to on every step, usually
+	// CheckpointPath for the project. Cancelling the wizard removes it.
+	Checkpoint string
+
+	// Resume starts the wizard from an earlier checkpoint's step and
+	// selections instead of from scratch
+	Resume *Checkpoint
+}
+
 // RunWizard runs the interactive wizard and returns the result
-func RunWizard(tmpl *templates.Templates) (WizardResult, error) {
-	m := NewWizard(tmpl)
+func RunWizard(tmpl *templates.Templates, defaultIDE string, opts WizardOptions) (WizardResult, error) {
+	m := NewWizard(tmpl, defaultIDE)
+	m.checkpoint = opts.Checkpoint
+
+	if opts.Resume != nil {
+		m = m.resume(opts.Resume)
+	}
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
 	finalModel, err := p.Run()
 	if err != nil {
+		// the checkpoint stays, so 'agen init --resume' can pick it up
 		return WizardResult{Cancelled: true}, err
 	}
 
-	return finalModel.(Model).GetResult(), nil
+	final := finalModel.(Model)
+	if final.quitting && opts.Checkpoint != "" {
+		RemoveCheckpoint(opts.Checkpoint)
+	}
+	return final.GetResult(), nil
 }
 
 // Keymap bindings that are used by the wizard
@@ -0,0 +1,250 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for filtering and selection in the setup wizard
+
+package tui
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eshanized/agen/internal/templates"
+)
+
+func testTemplates() *templates.Templates {
+	return &templates.Templates{
+		Agents: map[string]templates.Agent{
+			"frontend-specialist": {Name: "frontend-specialist", Skills: []string{"clean-code"}},
+			"backend-specialist":  {Name: "backend-specialist"},
+			"security-auditor":    {Name: "security-auditor"},
+			"debugger":            {Name: "debugger"},
+		},
+		Skills: map[string]templates.Skill{
+			"clean-code":       {Name: "clean-code"},
+			"testing-patterns": {Name: "testing-patterns"},
+		},
+	}
+}
+
+// send feeds msgs to m. Of the commands that come back, only filter
+// results are fed in again; cursor blinks and status timers would never
+// settle.
+func send(t *testing.T, m Model, msgs ...tea.Msg) Model {
+	t.Helper()
+	for _, msg := range msgs {
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		for _, out := range runCmd(cmd) {
+			next, _ = m.Update(out)
+			m = next.(Model)
+		}
+	}
+	return m
+}
+
+// runCmd runs cmd and returns the filter results it produced. Timers
+// (cursor blink, status messages) don't answer right away and are
+// dropped rather than waited on.
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+
+	var result tea.Msg
+	select {
+	case result = <-done:
+	case <-time.After(50 * time.Millisecond):
+		return nil
+	}
+
+	switch msg := result.(type) {
+	case list.FilterMatchesMsg:
+		return []tea.Msg{msg}
+	case tea.BatchMsg:
+		var out []tea.Msg
+		for _, c := range msg {
+			out = append(out, runCmd(c)...)
+		}
+		return out
+	}
+	return nil
+}
+
+func typed(s string) []tea.Msg {
+	var msgs []tea.Msg
+	for _, r := range s {
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return msgs
+}
+
+var (
+	enter = tea.KeyMsg{Type: tea.KeyEnter}
+	esc   = tea.KeyMsg{Type: tea.KeyEsc}
+	space = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+)
+
+// atAgents returns a wizard on the agent step with "/query" applied
+func atAgents(t *testing.T, query string) Model {
+	t.Helper()
+	m := send(t, NewWizard(testTemplates(), "cursor"), tea.WindowSizeMsg{Width: 100, Height: 40}, enter)
+	if m.state != stateAgentSelect {
+		t.Fatalf("state = %v, want the agent step", m.state)
+	}
+	m = send(t, m, typed("/"+query)...)
+	return send(t, m, enter)
+}
+
+func chosen(selected map[string]bool) []string {
+	var names []string
+	for name, on := range selected {
+		if on {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestWizardFilterTakesTypedKeys(t *testing.T) {
+	m := send(t, NewWizard(testTemplates(), "cursor"), tea.WindowSizeMsg{Width: 100, Height: 40}, enter)
+	m = send(t, m, typed("/spec a")...)
+
+	if m.quitting || m.state != stateAgentSelect {
+		t.Fatalf("keys typed into the filter were handled by the wizard (state %v, quitting %v)", m.state, m.quitting)
+	}
+	if !m.agentList.SettingFilter() || m.agentList.FilterValue() != "spec a" {
+		t.Errorf("filter = %q (setting %v), want \"spec a\" being typed", m.agentList.FilterValue(), m.agentList.SettingFilter())
+	}
+	if got := chosen(m.selectedAgents); len(got) != 0 {
+		t.Errorf("typing selected %v", got)
+	}
+}
+
+func TestWizardToggleOnFilteredView(t *testing.T) {
+	m := atAgents(t, "debug")
+	if !m.agentList.IsFiltered() || len(m.agentList.VisibleItems()) != 1 {
+		t.Fatalf("visible = %d, want only debugger", len(m.agentList.VisibleItems()))
+	}
+
+	// the highlighted row is index 0 of the matches, but not of the list
+	m = send(t, m, space)
+	if got := chosen(m.selectedAgents); len(got) != 1 || got[0] != "debugger" {
+		t.Fatalf("selected = %v, want [debugger]", got)
+	}
+
+	// esc clears the filter first and keeps the ✓
+	m = send(t, m, esc)
+	if m.state != stateAgentSelect || m.agentList.IsFiltered() {
+		t.Fatalf("after esc: state %v, filtered %v; want the full agent list", m.state, m.agentList.IsFiltered())
+	}
+	for _, itm := range m.agentList.Items() {
+		i := itm.(item)
+		if i.selected != (i.name == "debugger") {
+			t.Errorf("%s marked %v after clearing the filter", i.name, i.selected)
+		}
+	}
+
+	// the next esc goes back a step
+	if m = send(t, m, esc); m.state != stateIDESelect {
+		t.Errorf("second esc: state = %v, want the IDE step", m.state)
+	}
+}
+
+func TestWizardSelectAllOnlyShown(t *testing.T) {
+	m := atAgents(t, "specialist")
+	m = send(t, m, typed("a")...)
+
+	want := []string{"backend-specialist", "frontend-specialist"}
+	if got := chosen(m.selectedAgents); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("a selected %v, want %v", got, want)
+	}
+
+	m = send(t, m, esc, typed("n")[0])
+	if got := chosen(m.selectedAgents); len(got) != 0 {
+		t.Errorf("n on the full list left %v selected", got)
+	}
+}
+
+// skillItem returns the entry for name in the skill list
+func skillItem(t *testing.T, m Model, name string) item {
+	t.Helper()
+	for _, itm := range m.skillList.Items() {
+		if i := itm.(item); i.name == name {
+			return i
+		}
+	}
+	t.Fatalf("no %s in the skill list", name)
+	return item{}
+}
+
+func TestWizardMarksRequiredSkills(t *testing.T) {
+	m := atAgents(t, "frontend")
+	m = send(t, m, space, enter)
+	if m.state != stateSkillSelect {
+		t.Fatalf("state = %v, want the skill step", m.state)
+	}
+
+	if got := chosen(m.selectedSkills); len(got) != 1 || got[0] != "clean-code" {
+		t.Fatalf("selected skills = %v, want [clean-code]", got)
+	}
+	if i := skillItem(t, m, "clean-code"); !i.selected || len(i.requiredBy) != 1 || !strings.Contains(i.Title(), "required") {
+		t.Errorf("clean-code = %+v (%q), want selected and flagged", i, i.Title())
+	}
+	if view := m.View(); !strings.Contains(view, "Selected: 1 agents, 1 skills") {
+		t.Errorf("view has no selection count:\n%s", view)
+	}
+
+	// deselecting it is allowed, but warned about
+	m = send(t, m, typed("/clean")...)
+	m = send(t, m, enter, space)
+	if m.selectedSkills["clean-code"] {
+		t.Error("clean-code still selected after space")
+	}
+	if !strings.Contains(m.warning, "required by frontend-specialist") {
+		t.Errorf("warning = %q, want it to name the agent", m.warning)
+	}
+	if m = send(t, m, space); m.warning != "" {
+		t.Errorf("warning %q outlived the next key", m.warning)
+	}
+}
+
+func TestWizardUnmarksSkillsNoLongerRequired(t *testing.T) {
+	m := atAgents(t, "frontend")
+	m = send(t, m, space, enter)
+	if !m.selectedSkills["clean-code"] {
+		t.Fatal("clean-code not marked for frontend-specialist")
+	}
+
+	// back to the agents, drop frontend-specialist and come forward again
+	m = send(t, m, esc, space, enter)
+	if m.state != stateSkillSelect {
+		t.Fatalf("state = %v, want the skill step", m.state)
+	}
+	if i := skillItem(t, m, "clean-code"); i.selected || len(i.requiredBy) != 0 {
+		t.Errorf("clean-code = %+v, want unmarked once no agent needs it", i)
+	}
+}
+
+func TestWizardPreselectsDefaultIDE(t *testing.T) {
+	for _, name := range []string{"cursor", "claudecode", "aider"} {
+		m := NewWizard(testTemplates(), name)
+		if got := m.ideList.SelectedItem().(item).name; got != name {
+			t.Errorf("NewWizard(%q) selected %q", name, got)
+		}
+	}
+
+	// unknown names leave the first entry selected
+	m := NewWizard(testTemplates(), "Cursor")
+	if got := m.ideList.SelectedItem().(item).name; got != "antigravity" {
+		t.Errorf("NewWizard(\"Cursor\") selected %q, want the first entry", got)
+	}
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Checksum verification for downloaded release binaries
+
+package updater
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/eshanized/agen/internal/httpclient"
+)
+
+// checksumsAsset is the release asset goreleaser writes the sha256 of
+// every other asset to, one "<hash>  <name>" line each
+const checksumsAsset = "checksums.txt"
+
+// maxChecksumsSize caps how much of checksums.txt we read; the real file
+// is a few lines per platform
+const maxChecksumsSize = 1 << 20
+
+// findChecksumsAsset returns the download URL of the release's
+// checksums.txt, or "" if it has none
+func findChecksumsAsset(assets []struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}) string {
+	for _, asset := range assets {
+		if asset.Name == checksumsAsset {
+			return asset.BrowserDownloadURL
+		}
+	}
+	return ""
+}
+
+// expectedChecksum downloads checksumsURL and returns the sha256 it lists
+// for the asset downloadURL points at
+func expectedChecksum(ctx context.Context, checksumsURL, downloadURL string) (string, error) {
+	name, err := assetName(downloadURL)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := httpclient.Get(ctx, checksumsURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", checksumsAsset, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: status %d", checksumsAsset, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxChecksumsSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", checksumsAsset, err)
+	}
+
+	return parseChecksums(string(data), name)
+}
+
+// parseChecksums finds name in sha256sum-style output. A "*" before the
+// name (binary mode) is allowed.
+func parseChecksums(data, name string) (string, error) {
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+
+		sum := strings.ToLower(fields[0])
+		if _, err := hex.DecodeString(sum); err != nil || len(sum) != 64 {
+			return "", fmt.Errorf("%s has an invalid sha256 for %s", checksumsAsset, name)
+		}
+		return sum, nil
+	}
+	return "", fmt.Errorf("%s has no entry for %s", checksumsAsset, name)
+}
+
+// assetName returns the file name at the end of a download URL
+func assetName(downloadURL string) (string, error) {
+	u, err := url.Parse(downloadURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid download URL %q: %w", downloadURL, err)
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return "", fmt.Errorf("download URL %q does not name a file", downloadURL)
+	}
+	return name, nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// GitHub API reachability and rate-limit check for doctor
+
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/eshanized/agen/internal/httpclient"
+)
+
+// Connectivity is the outcome of a request to the GitHub API
+type Connectivity struct {
+	Host          string        // API host that answered, e.g. api.github.com
+	Latency       time.Duration // time until the response arrived
+	Limit         int           // API requests allowed per hour
+	Remaining     int           // requests left before Reset
+	Reset         time.Time     // when Remaining refills
+	Authenticated bool          // whether a GitHub token was sent
+}
+
+// CheckConnectivity makes one request to the GitHub API that
+// CheckForUpdate uses and reports whether it answered, how fast, and how
+// much of the rate limit is left.
+//
+// How it works:
+//  1. GET /rate_limit, which GitHub doesn't count against the limit, so
+//     the check itself never uses up a request
+//  2. Any response at all means the API is reachable; Latency is
+//     measured up to its headers
+//  3. A used-up limit returns the Connectivity together with a
+//     *httpclient.RateLimitError, since update calls will fail until
+//     Reset even though the network is fine
+//
+// A transport error (DNS, proxy, timeout) returns a nil Connectivity.
+func CheckConnectivity(timeout time.Duration) (*Connectivity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := httpclient.NewGitHubRequest(ctx, apiBase+"/rate_limit")
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	start := time.Now()
+	resp, err := httpclient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	conn := &Connectivity{
+		Host:          req.URL.Host,
+		Latency:       time.Since(start),
+		Authenticated: req.Header.Get("Authorization") != "",
+	}
+
+	if err := httpclient.CheckRateLimit(resp); err != nil {
+		return conn, err
+	}
+	if resp.StatusCode != 200 {
+		return conn, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var status struct {
+		Resources struct {
+			Core struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Reset     int64 `json:"reset"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return conn, fmt.Errorf("failed to parse rate limit: %w", err)
+	}
+
+	core := status.Resources.Core
+	conn.Limit = core.Limit
+	conn.Remaining = core.Remaining
+	if core.Reset > 0 {
+		conn.Reset = time.Unix(core.Reset, 0)
+	}
+
+	// /rate_limit itself still answers 200 once the limit is gone
+	if core.Limit > 0 && core.Remaining == 0 {
+		return conn, &httpclient.RateLimitError{
+			Limit:         core.Limit,
+			Reset:         conn.Reset,
+			Authenticated: conn.Authenticated,
+		}
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Hiding the console window of the Windows update script
+
+//go:build !windows
+
+package updater
+
+import "os/exec"
+
+// hideWindow is a no-op: only Windows opens a console for the update script
+func hideWindow(cmd *exec.Cmd) {}
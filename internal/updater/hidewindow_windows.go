@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Hiding the console window of the Windows update script
+
+//go:build windows
+
+package updater
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// hideWindow keeps cmd from flashing a console window while it runs
+func hideWindow(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Rolling back the last self-update
+
+package updater
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/eshanized/agen/internal/config"
+)
+
+// UpdateState records the last self-update, so Rollback knows which
+// binary to restore and can say which version it's going back to
+type UpdateState struct {
+	PreviousVersion string    `json:"previous_version"`
+	Version         string    `json:"version"`
+	Binary          string    `json:"binary"`
+	Backup          string    `json:"backup"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// ErrNoRollback is returned by Rollback when there's no update to undo
+var ErrNoRollback = errors.New("no self-update to roll back")
+
+// GetStatePath returns where the last self-update is recorded,
+// update-state.json in the config directory
+func GetStatePath() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "update-state.json"), nil
+}
+
+// LoadState returns the last self-update, or nil if none is recorded
+func LoadState() (*UpdateState, error) {
+	path, err := GetStatePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state UpdateState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid update state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// saveState records state as the last self-update
+func saveState(state *UpdateState) error {
+	path, err := GetStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// clearState forgets the last self-update
+func clearState() error {
+	path, err := GetStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Rollback restores the binary the last self-update replaced and returns
+// what was recorded about that update. currentVersion is the running
+// binary's version.
+//
+// On Windows the swap happens in a script after agen exits, and the
+// script may give up or put the old binary back, so the record alone
+// doesn't prove the update happened. Unless currentVersion is the version
+// the update installed, the record is dropped and nothing is touched.
+// Returns ErrNoRollback if nothing usable was recorded or the backup is
+// gone.
+func Rollback(currentVersion string) (*UpdateState, error) {
+	state, err := LoadState()
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, ErrNoRollback
+	}
+
+	if compareVersions(currentVersion, state.Version) != 0 {
+		clearState()
+		return nil, fmt.Errorf("%w: the upgrade to %s recorded on %s didn't complete (this is %s)",
+			ErrNoRollback, state.Version, state.UpdatedAt.Format("2006-01-02"), currentVersion)
+	}
+
+	execPath, err := currentExecutable()
+	if err != nil {
+		return nil, err
+	}
+	if state.Binary != execPath {
+		return nil, fmt.Errorf("the last update replaced %s, not this binary (%s)", state.Binary, execPath)
+	}
+
+	if err := restoreBackup(execPath, state.Backup); err != nil {
+		return nil, err
+	}
+	return state, clearState()
+}
+
+// restoreBackup moves backup over execPath.
+//
+// The current binary is renamed aside first rather than overwritten:
+// Windows lets a running executable be renamed but not replaced. The
+// renamed copy is removed afterwards, which on Windows only works once
+// this process has exited, so a failure there is ignored.
+func restoreBackup(execPath, backup string) error {
+	if _, err := os.Stat(backup); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: backup %s is gone", ErrNoRollback, backup)
+		}
+		return err
+	}
+
+	aside := execPath + ".rollback"
+	os.Remove(aside) // left over from an earlier rollback on Windows
+
+	if err := os.Rename(execPath, aside); err != nil {
+		return fmt.Errorf("failed to move current binary aside: %w", err)
+	}
+	if err := os.Rename(backup, execPath); err != nil {
+		os.Rename(aside, execPath)
+		return fmt.Errorf("failed to restore %s: %w", backup, err)
+	}
+
+	os.Remove(aside)
+	return nil
+}
@@ -8,22 +8,28 @@ package updater
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/eshanized/agen/internal/httpclient"
 )
 
 // Release represents a GitHub release
 type Release struct {
-	Version      string
-	DownloadURL  string
+	Version     string
+	DownloadURL string
+	// ChecksumsURL is the release's checksums.txt, which DownloadAndReplace
+	// verifies the download against
+	ChecksumsURL string
 	ReleaseNotes string
 	PublishedAt  time.Time
 }
@@ -34,6 +40,8 @@ type GitHubRelease struct {
 	Name        string `json:"name"`
 	Body        string `json:"body"`
 	PublishedAt string `json:"published_at"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
 	Assets      []struct {
 		Name               string `json:"name"`
 		BrowserDownloadURL string `json:"browser_download_url"`
@@ -43,56 +51,43 @@ type GitHubRelease struct {
 const (
 	repoOwner = "eshanized"
 	repoName  = "agen"
-	apiBase   = "https://api.github.com"
 )
 
-// CheckForUpdate checks if a newer version is available.
+// apiBase is a variable so tests can redirect it to an httptest.Server
+var apiBase = "https://api.github.com"
+
+// Update channels: which releases CheckForUpdate considers
+const (
+	// ChannelStable offers only the latest full release
+	ChannelStable = "stable"
+	// ChannelPrerelease also offers prereleases, for early adopters
+	ChannelPrerelease = "prerelease"
+)
+
+// releasesPageSize is how many releases the prerelease channel looks at;
+// GitHub lists the newest first, so the highest version is among them
+const releasesPageSize = 30
+
+// CheckForUpdate checks if a newer version is available on channel.
 //
 // How it works:
-// 1. Query GitHub API for latest release
+// 1. Query GitHub API for the newest release on the channel
 // 2. Compare version strings (semantic versioning)
 // 3. Return release info if newer, nil if current is latest
 //
 // Why GitHub API? It's the standard way to distribute Go binaries.
-// We also support prerelease versions for early adopters.
-func CheckForUpdate(currentVersion string) (*Release, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	url := fmt.Sprintf("%s/repos/%s/%s/releases/latest", apiBase, repoOwner, repoName)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
+// GitHub leaves prereleases out of /releases/latest, so the prerelease
+// channel lists /releases instead and picks the highest version.
+// An empty channel means ChannelStable.
+func CheckForUpdate(currentVersion, channel string) (*Release, error) {
+	ghRelease, err := fetchNewestRelease(channel)
+	if err != nil || ghRelease == nil {
 		return nil, err
 	}
 
-	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	req.Header.Set("User-Agent", "agen-updater")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check for updates: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == 404 {
-		// No releases yet
-		return nil, nil
-	}
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
-	}
-
-	var ghRelease GitHubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&ghRelease); err != nil {
-		return nil, fmt.Errorf("failed to parse release info: %w", err)
-	}
-
 	latestVersion := strings.TrimPrefix(ghRelease.TagName, "v")
 	current := strings.TrimPrefix(currentVersion, "v")
 
-	// simple version comparison (assumes semver)
 	if compareVersions(current, latestVersion) >= 0 {
 		return nil, nil // already on latest
 	}
@@ -107,25 +102,112 @@ func CheckForUpdate(currentVersion string) (*Release, error) {
 	return &Release{
 		Version:      latestVersion,
 		DownloadURL:  downloadURL,
+		ChecksumsURL: findChecksumsAsset(ghRelease.Assets),
 		ReleaseNotes: ghRelease.Body,
 	}, nil
 }
 
+// fetchNewestRelease returns the newest release on channel, or nil if the
+// repository has none
+func fetchNewestRelease(channel string) (*GitHubRelease, error) {
+	switch channel {
+	case "", ChannelStable:
+		var release GitHubRelease
+		found, err := getReleaseAPI(fmt.Sprintf("%s/repos/%s/%s/releases/latest", apiBase, repoOwner, repoName), &release)
+		if err != nil || !found {
+			return nil, err
+		}
+		return &release, nil
+
+	case ChannelPrerelease, "beta":
+		var releases []GitHubRelease
+		url := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=%d", apiBase, repoOwner, repoName, releasesPageSize)
+		found, err := getReleaseAPI(url, &releases)
+		if err != nil || !found {
+			return nil, err
+		}
+		return highestRelease(releases), nil
+
+	default:
+		return nil, fmt.Errorf("unknown update channel %q: use %q or %q", channel, ChannelStable, ChannelPrerelease)
+	}
+}
+
+// highestRelease picks the release with the highest version, skipping
+// drafts. Returns nil if there is none.
+func highestRelease(releases []GitHubRelease) *GitHubRelease {
+	var best *GitHubRelease
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft || r.TagName == "" {
+			continue
+		}
+		if best == nil || compareVersions(r.TagName, best.TagName) > 0 {
+			best = r
+		}
+	}
+	return best
+}
+
+// getReleaseAPI decodes the GitHub API response at url into v. found is
+// false on a 404, which means the repository has no releases yet.
+func getReleaseAPI(url string, v interface{}) (found bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := httpclient.NewGitHubRequest(ctx, url)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := httpclient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := httpclient.CheckRateLimit(resp); err != nil {
+		return false, err
+	}
+
+	if resp.StatusCode == 404 {
+		// No releases yet
+		return false, nil
+	}
+
+	if resp.StatusCode != 200 {
+		return false, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("failed to parse release info: %w", err)
+	}
+	return true, nil
+}
+
 // DownloadAndReplace downloads the new binary and replaces the current one.
 //
 // How it works (the tricky part):
-// 1. Download new binary to temp file
-// 2. Verify it's executable
+// 1. Download new binary to temp file, hashing it on the way
+// 2. Check the sha256 against the release's checksums.txt
 // 3. Get path of current binary
 // 4. On Unix: rename current to .old, rename new to current
 // 5. On Windows: create a batch script to do the swap after exit
+// 6. Record currentVersion and the .old backup for Rollback
 //
 // Why so complex? You can't replace a running binary on Windows.
 // On Unix it technically works but we do atomic swap for safety.
-func DownloadAndReplace(release *Release) error {
+// A download that doesn't match its checksum, or a release without one,
+// is refused before the current binary is touched.
+func DownloadAndReplace(release *Release, currentVersion string) error {
 	if release.DownloadURL == "" {
 		return fmt.Errorf("no download URL provided")
 	}
+	if release.ChecksumsURL == "" {
+		return fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.Version, checksumsAsset)
+	}
 
 	// Download to temp file
 	tmpFile, err := os.CreateTemp("", "agen-update-*")
@@ -133,8 +215,17 @@ func DownloadAndReplace(release *Release) error {
 		return fmt.Errorf("failed to create temp file: %w", err)
 	}
 	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
 
-	resp, err := http.Get(release.DownloadURL)
+	ctx, cancel := context.WithTimeout(context.Background(), httpclient.DefaultTimeout)
+	defer cancel()
+
+	want, err := expectedChecksum(ctx, release.ChecksumsURL, release.DownloadURL)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpclient.Get(ctx, release.DownloadURL)
 	if err != nil {
 		return fmt.Errorf("failed to download update: %w", err)
 	}
@@ -144,84 +235,170 @@ func DownloadAndReplace(release *Release) error {
 		return fmt.Errorf("download failed with status %d", resp.StatusCode)
 	}
 
-	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmpFile, hash), resp.Body); err != nil {
 		return fmt.Errorf("failed to save update: %w", err)
 	}
 	tmpFile.Close()
 
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch for %s: expected sha256 %s, got %s; the download may be corrupted or tampered with, so the current binary was kept", release.DownloadURL, want, got)
+	}
+
 	// Make executable
 	if err := os.Chmod(tmpFile.Name(), 0755); err != nil {
 		return fmt.Errorf("failed to make binary executable: %w", err)
 	}
 
-	// Get current binary path
-	execPath, err := os.Executable()
+	execPath, err := currentExecutable()
 	if err != nil {
-		return fmt.Errorf("failed to get current executable path: %w", err)
+		return err
 	}
 
-	execPath, err = filepath.EvalSymlinks(execPath)
+	if runtime.GOOS == "windows" {
+		err = windowsUpdate(tmpFile.Name(), execPath)
+	} else {
+		err = unixUpdate(tmpFile.Name(), execPath)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to resolve executable path: %w", err)
+		return err
 	}
 
-	if runtime.GOOS == "windows" {
-		return windowsUpdate(tmpFile.Name(), execPath)
+	return saveState(&UpdateState{
+		PreviousVersion: strings.TrimPrefix(currentVersion, "v"),
+		Version:         release.Version,
+		Binary:          execPath,
+		Backup:          backupPath(execPath),
+		UpdatedAt:       time.Now(),
+	})
+}
+
+// currentExecutable returns the path of the running binary, with
+// symlinks resolved
+func currentExecutable() (string, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current executable path: %w", err)
+	}
+
+	execPath, err = filepath.EvalSymlinks(execPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve executable path: %w", err)
 	}
+	return execPath, nil
+}
 
-	return unixUpdate(tmpFile.Name(), execPath)
+// backupPath is where an update keeps the binary it replaced
+func backupPath(execPath string) string {
+	return execPath + ".old"
 }
 
-// unixUpdate does atomic replacement on Unix systems
+// unixUpdate does atomic replacement on Unix systems.
+// The replaced binary stays at .old, so Rollback can restore it; the
+// next update replaces it.
 func unixUpdate(newPath, currentPath string) error {
 	// Backup current
-	backupPath := currentPath + ".old"
-	os.Remove(backupPath) // ignore error if doesn't exist
+	backup := backupPath(currentPath)
+	os.Remove(backup) // ignore error if doesn't exist
 
 	// Rename current to backup
-	if err := os.Rename(currentPath, backupPath); err != nil {
+	if err := os.Rename(currentPath, backup); err != nil {
 		return fmt.Errorf("failed to backup current binary: %w", err)
 	}
 
 	// Move new to current
 	if err := os.Rename(newPath, currentPath); err != nil {
 		// try to restore backup
-		os.Rename(backupPath, currentPath)
+		os.Rename(backup, currentPath)
 		return fmt.Errorf("failed to install new binary: %w", err)
 	}
 
-	// Clean up backup
-	os.Remove(backupPath)
-
 	return nil
 }
 
-// windowsUpdate creates a batch script to replace the binary after exit
+// windowsUpdate creates a batch script to replace the binary after exit.
+//
+// The new binary is first copied next to the current one, since the
+// download's temp file is removed as soon as we return. The script then
+// runs hidden and:
+//  1. Retries moving the current binary to .old until agen has exited
+//     and released it, giving up after updateRetries attempts
+//  2. Moves the new binary into place and runs "agen --version" to check
+//     it starts; if it doesn't, the .old backup is restored
+//  3. Deletes itself, keeping the backup for Rollback
 func windowsUpdate(newPath, currentPath string) error {
-	// Windows can't replace running executables, so we create a batch script
-	// that runs after this process exits
-
-	batchScript := fmt.Sprintf(`@echo off
-ping 127.0.0.1 -n 2 > nul
-move /y "%s" "%s"
-del "%s"
-`, newPath, currentPath, "%~f0")
+	stagedPath := currentPath + ".new"
+	if err := copyFile(newPath, stagedPath); err != nil {
+		return fmt.Errorf("failed to stage new binary: %w", err)
+	}
 
 	batchPath := filepath.Join(os.TempDir(), "agen-update.bat")
-	if err := os.WriteFile(batchPath, []byte(batchScript), 0755); err != nil {
+	if err := os.WriteFile(batchPath, []byte(windowsUpdateScript(stagedPath, currentPath)), 0755); err != nil {
+		os.Remove(stagedPath)
 		return fmt.Errorf("failed to create update script: %w", err)
 	}
 
-	// Start the batch script detached
+	// Start the batch script detached, without a console window
 	cmd := exec.Command("cmd.exe", "/C", batchPath)
-	// cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true} // Windows specific, breaks on Linux
+	hideWindow(cmd)
 	if err := cmd.Start(); err != nil {
+		os.Remove(stagedPath)
 		return fmt.Errorf("failed to start update script: %w", err)
 	}
 
 	return nil
 }
 
+// VersionCheckArg is what the Windows update script runs the new binary
+// with to check it starts; it has to exit 0 on a working agen
+const VersionCheckArg = "--version"
+
+// updateRetries is how many times the Windows update script tries to move
+// the running binary aside, a second apart, before giving up
+const updateRetries = 10
+
+// windowsUpdateScript returns the batch script windowsUpdate runs, with
+// CRLF line endings since cmd.exe can misread labels without them.
+//
+// The last line deletes the script: "(goto) 2>nul" ends the batch before
+// del runs, so cmd.exe never reads from the deleted file.
+func windowsUpdateScript(newPath, currentPath string) string {
+	script := fmt.Sprintf(`@echo off
+setlocal
+set "NEW=%s"
+set "TARGET=%s"
+set "BACKUP=%s.old"
+set /a tries=0
+
+:wait
+ping 127.0.0.1 -n 2 > nul
+move /y "%%TARGET%%" "%%BACKUP%%" > nul 2>&1
+if not errorlevel 1 goto install
+set /a tries+=1
+if %%tries%% lss %d goto wait
+del "%%NEW%%" > nul 2>&1
+goto done
+
+:install
+move /y "%%NEW%%" "%%TARGET%%" > nul 2>&1
+if errorlevel 1 goto restore
+"%%TARGET%%" %s > nul 2>&1
+if errorlevel 1 goto restore
+goto done
+
+:restore
+del "%%TARGET%%" > nul 2>&1
+move /y "%%BACKUP%%" "%%TARGET%%" > nul 2>&1
+del "%%NEW%%" > nul 2>&1
+
+:done
+(goto) 2>nul & del "%%~f0"
+`, newPath, currentPath, currentPath, updateRetries, VersionCheckArg)
+
+	return strings.ReplaceAll(script, "\n", "\r\n")
+}
+
+// copyFile copies src to dst, replacing dst
 func copyFile(src, dst string) error {
 	sourceFile, err := os.Open(src)
 	if err != nil {
@@ -233,10 +410,12 @@ func copyFile(src, dst string) error {
 	if err != nil {
 		return err
 	}
-	defer destFile.Close()
 
-	_, err = io.Copy(destFile, sourceFile)
-	return err
+	if _, err := io.Copy(destFile, sourceFile); err != nil {
+		destFile.Close()
+		return err
+	}
+	return destFile.Close()
 }
 
 // findAssetForPlatform finds the right binary for the current OS/arch
@@ -261,29 +440,3 @@ func findAssetForPlatform(assets []struct {
 
 	return ""
 }
-
-// compareVersions compares two semantic versions.
-// Returns: -1 if a < b, 0 if a == b, 1 if a > b
-func compareVersions(a, b string) int {
-	aParts := strings.Split(a, ".")
-	bParts := strings.Split(b, ".")
-
-	for i := 0; i < 3; i++ {
-		var aNum, bNum int
-		if i < len(aParts) {
-			fmt.Sscanf(aParts[i], "%d", &aNum)
-		}
-		if i < len(bParts) {
-			fmt.Sscanf(bParts[i], "%d", &bNum)
-		}
-
-		if aNum < bNum {
-			return -1
-		}
-		if aNum > bNum {
-			return 1
-		}
-	}
-
-	return 0
-}
@@ -7,7 +7,21 @@
 package updater
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/eshanized/agen/internal/httpclient"
 )
 
 func TestCompareVersions(t *testing.T) {
@@ -23,6 +37,14 @@ func TestCompareVersions(t *testing.T) {
 		{"0.9.0", "1.0.0", -1},
 		{"v1.0.0", "v1.0.0", 0}, // handles 'v' prefix
 		{"1.0", "1.0.0", 0},     // handles missing patch
+		{"1.10.0", "1.9.0", 1},
+		{"1.2.10", "1.2.9", 1},
+		{"1.2.0-beta", "1.2.0", -1},
+		{"1.2.0", "1.2.0-rc.1", 1},
+		{"1.2.1-beta", "1.2.0", 1}, // a prerelease of a later version is still later
+		{"1.2.0+build.5", "1.2.0", 0},
+		{"1.2.0-rc.1+sha.abc", "1.2.0-rc.1+sha.def", 0},
+		{"dev", "0.0.1", -1},
 	}
 
 	for _, tt := range tests {
@@ -35,6 +57,35 @@ func TestCompareVersions(t *testing.T) {
 	}
 }
 
+// TestComparePrereleaseOrder checks the ordering example from the semver
+// spec (section 11), each version lower than the next
+func TestComparePrereleaseOrder(t *testing.T) {
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := compareVersions(ordered[i], ordered[j]); got != want {
+				t.Errorf("compareVersions(%q, %q) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+}
+
 func TestFindAssetForPlatform(t *testing.T) {
 	assets := []struct {
 		Name               string `json:"name"`
@@ -72,3 +123,453 @@ func TestReleaseStruct(t *testing.T) {
 		t.Error("ReleaseNotes should not be empty")
 	}
 }
+
+// useTestAPI points the updater at srv for the duration of the test
+func useTestAPI(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+	old := apiBase
+	apiBase = srv.URL
+	t.Cleanup(func() { apiBase = old })
+}
+
+// releaseServer serves a fake "latest release" response for tag
+func releaseServer(t *testing.T, tag string) *httptest.Server {
+	t.Helper()
+
+	assetName := fmt.Sprintf("agen_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		assetName += ".exe"
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/eshanized/agen/releases/latest" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"tag_name": tag,
+			"body":     "notes",
+			"assets": []map[string]string{
+				{"name": assetName, "browser_download_url": "https://example.com/" + assetName},
+				{"name": "checksums.txt", "browser_download_url": "https://example.com/checksums.txt"},
+			},
+		})
+	}))
+}
+
+func TestCheckForUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		tag     string
+		want    string // expected release version, "" for no update
+	}{
+		{"newer available", "1.0.0", "v1.1.0", "1.1.0"},
+		{"already latest", "1.1.0", "v1.1.0", ""},
+		{"ahead of latest", "2.0.0", "v1.1.0", ""},
+		{"v prefix on current", "v1.0.0", "v1.0.1", "1.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := releaseServer(t, tt.tag)
+			defer srv.Close()
+			useTestAPI(t, srv)
+
+			release, err := CheckForUpdate(tt.current, ChannelStable)
+			if err != nil {
+				t.Fatalf("CheckForUpdate() failed: %v", err)
+			}
+
+			if tt.want == "" {
+				if release != nil {
+					t.Errorf("expected no update, got %s", release.Version)
+				}
+				return
+			}
+
+			if release == nil {
+				t.Fatalf("expected update to %s, got none", tt.want)
+			}
+			if release.Version != tt.want {
+				t.Errorf("Version = %q, want %q", release.Version, tt.want)
+			}
+			if release.DownloadURL == "" {
+				t.Error("DownloadURL should be set for current platform")
+			}
+			if release.ChecksumsURL != "https://example.com/checksums.txt" {
+				t.Errorf("ChecksumsURL = %q, want the checksums.txt asset", release.ChecksumsURL)
+			}
+		})
+	}
+}
+
+func TestCheckForUpdateNoReleases(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	useTestAPI(t, srv)
+
+	release, err := CheckForUpdate("1.0.0", ChannelStable)
+	if err != nil {
+		t.Fatalf("CheckForUpdate() failed: %v", err)
+	}
+	if release != nil {
+		t.Error("expected nil release when repo has no releases")
+	}
+}
+
+func TestCheckForUpdateServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	useTestAPI(t, srv)
+
+	if _, err := CheckForUpdate("1.0.0", ChannelStable); err == nil {
+		t.Error("expected error on 500 response")
+	}
+}
+
+// channelServer serves a repository whose latest full release is v1.1.0,
+// with a newer prerelease and an even newer draft
+func channelServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	assetName := fmt.Sprintf("agen_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		assetName += ".exe"
+	}
+	release := func(tag string, draft, prerelease bool) map[string]interface{} {
+		return map[string]interface{}{
+			"tag_name":   tag,
+			"draft":      draft,
+			"prerelease": prerelease,
+			"assets": []map[string]string{
+				{"name": assetName, "browser_download_url": "https://example.com/" + tag + "/" + assetName},
+			},
+		}
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/eshanized/agen/releases/latest":
+			json.NewEncoder(w).Encode(release("v1.1.0", false, false))
+		case "/repos/eshanized/agen/releases":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				release("v1.3.0", true, false),
+				release("v1.2.0-beta.1", false, true),
+				release("v1.1.0", false, false),
+				release("v1.1.0-rc.1", false, true),
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestCheckForUpdateChannel(t *testing.T) {
+	srv := channelServer(t)
+	defer srv.Close()
+	useTestAPI(t, srv)
+
+	tests := []struct {
+		name    string
+		current string
+		channel string
+		want    string // expected release version, "" for no update
+	}{
+		{"stable skips prereleases", "1.0.0", ChannelStable, "1.1.0"},
+		{"empty channel is stable", "1.0.0", "", "1.1.0"},
+		{"prerelease picks highest", "1.0.0", ChannelPrerelease, "1.2.0-beta.1"},
+		{"prerelease ignores drafts", "1.2.0-beta.1", ChannelPrerelease, ""},
+		{"prerelease after release", "1.1.0", ChannelPrerelease, "1.2.0-beta.1"},
+		{"stable never downgrades a prerelease", "1.2.0-beta.1", ChannelStable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release, err := CheckForUpdate(tt.current, tt.channel)
+			if err != nil {
+				t.Fatalf("CheckForUpdate() failed: %v", err)
+			}
+			got := ""
+			if release != nil {
+				got = release.Version
+			}
+			if got != tt.want {
+				t.Errorf("CheckForUpdate(%q, %q) = %q, want %q", tt.current, tt.channel, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := CheckForUpdate("1.0.0", "nightly"); err == nil {
+		t.Error("expected error for unknown channel")
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	data := "0000000000000000000000000000000000000000000000000000000000000000  agen_linux_arm64\n" +
+		sum + "  agen_linux_amd64\n" +
+		strings.Repeat("cd", 32) + " *agen_windows_amd64.exe\n"
+
+	tests := []struct {
+		name    string
+		asset   string
+		want    string
+		wantErr bool
+	}{
+		{"matching entry", "agen_linux_amd64", sum, false},
+		{"binary mode marker", "agen_windows_amd64.exe", strings.Repeat("cd", 32), false},
+		{"no prefix match", "agen_linux", "", true},
+		{"missing entry", "agen_darwin_arm64", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseChecksums(data, tt.asset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseChecksums() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseChecksums() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseChecksums("nothex  agen_linux_amd64\n", "agen_linux_amd64"); err == nil {
+		t.Error("expected error for an invalid hash")
+	}
+}
+
+func TestDownloadAndReplaceVerifiesChecksum(t *testing.T) {
+	binary := []byte("new agen binary")
+	good := sha256.Sum256(binary)
+
+	tests := []struct {
+		name      string
+		checksums string
+		wantErr   string
+	}{
+		{"mismatch", strings.Repeat("0", 64) + "  agen_test\n", "checksum mismatch"},
+		{"no entry", hex.EncodeToString(good[:]) + "  agen_other\n", "no entry for agen_test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/agen_test":
+					w.Write(binary)
+				case "/checksums.txt":
+					fmt.Fprint(w, tt.checksums)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			err := DownloadAndReplace(&Release{
+				Version:      "9.9.9",
+				DownloadURL:  srv.URL + "/agen_test",
+				ChecksumsURL: srv.URL + "/checksums.txt",
+			}, "1.0.0")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DownloadAndReplace() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	err := DownloadAndReplace(&Release{Version: "9.9.9", DownloadURL: "https://example.com/agen_test"}, "1.0.0")
+	if err == nil || !strings.Contains(err.Error(), "unverified") {
+		t.Errorf("DownloadAndReplace() without checksums.txt error = %v, want refusal", err)
+	}
+}
+
+func TestWindowsUpdateScript(t *testing.T) {
+	script := windowsUpdateScript(`C:\bin\agen.exe.new`, `C:\bin\agen.exe`)
+
+	if strings.Contains(strings.ReplaceAll(script, "\r\n", ""), "\n") {
+		t.Error("script should use CRLF line endings throughout")
+	}
+	if strings.Contains(script, "%!") {
+		t.Errorf("script has a formatting error:\n%s", script)
+	}
+
+	for _, want := range []string{
+		`set "NEW=C:\bin\agen.exe.new"`,
+		`set "TARGET=C:\bin\agen.exe"`,
+		`set "BACKUP=C:\bin\agen.exe.old"`,
+		fmt.Sprintf("if %%tries%% lss %d goto wait", updateRetries),
+		`(goto) 2>nul & del "%~f0"`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+
+	if !strings.Contains(script, `"%TARGET%" --version > nul`) {
+		t.Errorf("script should check the new binary starts:\n%s", script)
+	}
+	// the backup is kept for 'agen upgrade --rollback'
+	if strings.Contains(script, `del "%BACKUP%"`) {
+		t.Errorf("script should keep the backup:\n%s", script)
+	}
+}
+
+func TestUnixUpdateKeepsBackup(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "agen")
+	newBin := filepath.Join(dir, "agen-new")
+	os.WriteFile(current, []byte("v1"), 0755)
+	os.WriteFile(newBin, []byte("v2"), 0755)
+
+	if err := unixUpdate(newBin, current); err != nil {
+		t.Fatalf("unixUpdate() failed: %v", err)
+	}
+	if data, _ := os.ReadFile(current); string(data) != "v2" {
+		t.Errorf("current binary = %q, want v2", data)
+	}
+	if data, _ := os.ReadFile(backupPath(current)); string(data) != "v1" {
+		t.Errorf("backup = %q, want v1", data)
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "agen")
+	os.WriteFile(current, []byte("v2"), 0755)
+	os.WriteFile(backupPath(current), []byte("v1"), 0755)
+
+	if err := restoreBackup(current, backupPath(current)); err != nil {
+		t.Fatalf("restoreBackup() failed: %v", err)
+	}
+	if data, _ := os.ReadFile(current); string(data) != "v1" {
+		t.Errorf("current binary = %q, want v1", data)
+	}
+	for _, leftover := range []string{backupPath(current), current + ".rollback"} {
+		if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+			t.Errorf("%s should be gone", leftover)
+		}
+	}
+
+	err := restoreBackup(current, backupPath(current))
+	if !errors.Is(err, ErrNoRollback) {
+		t.Errorf("restoreBackup() without a backup error = %v, want ErrNoRollback", err)
+	}
+	if data, _ := os.ReadFile(current); string(data) != "v1" {
+		t.Errorf("failed rollback changed the binary to %q", data)
+	}
+}
+
+func TestUpdateState(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	if state, err := LoadState(); err != nil || state != nil {
+		t.Fatalf("LoadState() with no update = %v, %v; want nil, nil", state, err)
+	}
+	if _, err := Rollback("1.1.0"); !errors.Is(err, ErrNoRollback) {
+		t.Errorf("Rollback() with no update error = %v, want ErrNoRollback", err)
+	}
+
+	want := &UpdateState{PreviousVersion: "1.0.0", Version: "1.1.0", Binary: "/bin/agen", Backup: "/bin/agen.old"}
+	if err := saveState(want); err != nil {
+		t.Fatalf("saveState() failed: %v", err)
+	}
+	got, err := LoadState()
+	if err != nil || got == nil {
+		t.Fatalf("LoadState() = %v, %v", got, err)
+	}
+	if got.PreviousVersion != want.PreviousVersion || got.Version != want.Version || got.Backup != want.Backup {
+		t.Errorf("LoadState() = %+v, want %+v", got, want)
+	}
+
+	// the recorded binary isn't the test binary, so nothing is touched
+	if _, err := Rollback("v1.1.0"); err == nil || !strings.Contains(err.Error(), "not this binary") {
+		t.Errorf("Rollback() of another binary error = %v", err)
+	}
+
+	// still on the old version: the update never completed, as when the
+	// Windows script gives up, so the record is dropped
+	if _, err := Rollback("1.0.0"); !errors.Is(err, ErrNoRollback) {
+		t.Errorf("Rollback() of an incomplete update error = %v, want ErrNoRollback", err)
+	}
+	if state, err := LoadState(); err != nil || state != nil {
+		t.Errorf("LoadState() after an incomplete update = %v, %v; want nil, nil", state, err)
+	}
+}
+
+// rateLimitServer serves /rate_limit with remaining requests left
+func rateLimitServer(t *testing.T, remaining int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rate_limit" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"resources":{"core":{"limit":60,"remaining":%d,"reset":1700000000}}}`, remaining)
+	}))
+}
+
+func TestCheckConnectivity(t *testing.T) {
+	t.Setenv("AGEN_GITHUB_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+
+	srv := rateLimitServer(t, 42)
+	defer srv.Close()
+	useTestAPI(t, srv)
+
+	conn, err := CheckConnectivity(5 * time.Second)
+	if err != nil {
+		t.Fatalf("CheckConnectivity() failed: %v", err)
+	}
+	if conn.Limit != 60 || conn.Remaining != 42 || conn.Authenticated {
+		t.Errorf("conn = %+v, want 42/60 unauthenticated", conn)
+	}
+	if conn.Host == "" || conn.Latency <= 0 {
+		t.Errorf("conn = %+v, want host and latency set", conn)
+	}
+}
+
+func TestCheckConnectivityRateLimited(t *testing.T) {
+	t.Run("exhausted in body", func(t *testing.T) {
+		srv := rateLimitServer(t, 0)
+		defer srv.Close()
+		useTestAPI(t, srv)
+
+		conn, err := CheckConnectivity(5 * time.Second)
+		var rateErr *httpclient.RateLimitError
+		if !errors.As(err, &rateErr) || rateErr.Limit != 60 {
+			t.Fatalf("err = %v, want a RateLimitError for 60 requests", err)
+		}
+		if conn == nil {
+			t.Error("conn should be set: the API answered")
+		}
+	})
+
+	t.Run("refused with 403", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+		useTestAPI(t, srv)
+
+		var rateErr *httpclient.RateLimitError
+		if _, err := CheckConnectivity(5 * time.Second); !errors.As(err, &rateErr) {
+			t.Errorf("err = %v, want a RateLimitError", err)
+		}
+	})
+}
+
+func TestCheckConnectivityUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	useTestAPI(t, srv)
+	srv.Close() // nothing listens there any more
+
+	conn, err := CheckConnectivity(5 * time.Second)
+	if err == nil || conn != nil {
+		t.Errorf("CheckConnectivity() = %+v, %v; want nil and an error", conn, err)
+	}
+}
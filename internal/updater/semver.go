@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Semantic version comparison for release tags
+
+package updater
+
+import (
+	"strconv"
+	"strings"
+)
+
+// compareVersions compares two semantic versions by semver precedence.
+// Returns: -1 if a < b, 0 if a == b, 1 if a > b
+//
+// A leading "v" is ignored, as is build metadata ("+..."). Missing
+// minor or patch numbers count as 0, so "1.0" == "1.0.0". A prerelease
+// sorts before its release (1.2.0-beta < 1.2.0), and prereleases compare
+// identifier by identifier: numbers numerically, below any word, and a
+// shorter list first when the rest is equal (alpha < alpha.1 < beta).
+//
+// Core parts that aren't numbers count as 0, so a development build
+// ("dev") is older than every release.
+func compareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	for i := 0; i < len(aCore) || i < len(bCore); i++ {
+		if c := compareInts(versionPart(aCore, i), versionPart(bCore, i)); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == "" && bPre == "":
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return comparePrerelease(strings.Split(aPre, "."), strings.Split(bPre, "."))
+}
+
+// splitVersion returns the dot-separated core numbers of v and its
+// prerelease, without any "v" prefix or build metadata
+func splitVersion(v string) (core []string, prerelease string) {
+	v = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(v), "v"), "V")
+	v, _, _ = strings.Cut(v, "+")
+	v, prerelease, _ = strings.Cut(v, "-")
+	return strings.Split(v, "."), prerelease
+}
+
+// versionPart returns core[i] as a number, 0 if it's missing or not one
+func versionPart(core []string, i int) int {
+	if i >= len(core) {
+		return 0
+	}
+	n, err := strconv.Atoi(core[i])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// comparePrerelease compares prerelease identifiers per semver 11.4
+func comparePrerelease(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		aNum, aErr := strconv.Atoi(a[i])
+		bNum, bErr := strconv.Atoi(b[i])
+
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareInts(aNum, bNum)
+		case aErr == nil:
+			c = -1 // numeric identifiers have lower precedence
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(a[i], b[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(a), len(b))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
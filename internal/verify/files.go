@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Shared project walk feeding every verification check
+
+package verify
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxFileSize is the largest file the checks read. Anything bigger is
+// almost always generated or binary, and reading it is what makes
+// verify slow on big repos.
+var MaxFileSize int64 = 1 << 20
+
+// File is a project file loaded once and shared by all checks
+type File struct {
+	Path    string // relative to the project, what issues report
+	Ext     string // lowercased extension, e.g. ".tsx"
+	Content string
+}
+
+// fileStat is the path and size of any project file, scanned extension
+// or not, for checks that look at sizes rather than content
+type fileStat struct {
+	Path string
+	Ext  string
+	Size int64
+}
+
+// skipDirs are never walked: dependencies, VCS data and build output
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	".git":         true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+// scannedExts is every extension at least one check looks at. Files
+// with other extensions aren't read at all.
+var scannedExts = map[string]bool{
+	".js": true, ".ts": true, ".jsx": true, ".tsx": true,
+	".py": true, ".go": true, ".java": true, ".rb": true,
+	".env": true, ".yml": true, ".yaml": true, ".json": true,
+	".md": true, ".txt": true, ".sh": true, ".bash": true,
+	".html": true, ".htm": true,
+}
+
+// files returns the project's files, walking and reading them on the
+// first call only, so running several checks costs one walk
+func (r *Runner) files() []File {
+	r.load()
+	return r.fileCache
+}
+
+// stats returns the size of every project file from the same walk as
+// files, large and unscanned ones included
+func (r *Runner) stats() []fileStat {
+	r.load()
+	return r.statCache
+}
+
+// load walks the project the first time it's called
+func (r *Runner) load() {
+	r.filesOnce.Do(func() {
+		r.fileCache, r.statCache = loadFiles(r.projectPath, !r.options.NoGitignore)
+	})
+}
+
+// loadFiles walks root once, recording every file's size and reading
+// every file with a scanned extension up to MaxFileSize. With
+// useGitignore set, paths ignored by the project's .gitignore files
+// (nested ones included) are skipped too. Unreadable files and
+// directories are skipped rather than failing the whole run.
+func loadFiles(root string, useGitignore bool) ([]File, []fileStat) {
+	var files []File
+	var stats []fileStat
+	var ignore gitignore
+
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(root, path)
+
+		if d.IsDir() {
+			if path != root && (skipDirs[d.Name()] || (useGitignore && ignore.ignored(relPath, true))) {
+				return filepath.SkipDir
+			}
+			// this directory's rules apply to everything below it
+			if useGitignore {
+				ignore.load(root, relPath)
+			}
+			return nil
+		}
+
+		if useGitignore && ignore.ignored(relPath, false) {
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		stats = append(stats, fileStat{Path: relPath, Ext: ext, Size: info.Size()})
+		if !scannedExts[ext] || info.Size() > MaxFileSize {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		files = append(files, File{
+			Path:    relPath,
+			Ext:     ext,
+			Content: string(content),
+		})
+		return nil
+	})
+
+	return files, stats
+}
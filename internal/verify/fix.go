@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Safe automatic fixes offered by the verification checks
+
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Fix is a mechanical change a check knows how to make safely. Checks
+// return them in Result.Fixes alongside the issues they resolve; nothing
+// is written until ApplyFixes is called.
+type Fix struct {
+	File    string `json:"file"`    // relative to the project
+	Rule    string `json:"rule"`    // rule of the issues it resolves
+	Message string `json:"message"` // what it changes, e.g. "remove console.log() lines"
+
+	// apply returns the file's new content. The file may not exist yet
+	// (content is then ""), and returning content unchanged means
+	// there was nothing to do.
+	apply func(content string) string
+}
+
+// AppliedFix is a fix that changed a file
+type AppliedFix struct {
+	Fix
+	Backup string `json:"backup,omitempty"` // copy of the file from before this run's first change, "" if it was new
+}
+
+// ApplyFixes applies fixes in order and returns the ones that changed
+// something.
+//
+// Each file is read fresh before every fix, so two fixes to the same
+// file (a lint and a UX fix on one .jsx, say) both land. The first time
+// a run changes an existing file, it's copied to file.bak first.
+func (r *Runner) ApplyFixes(fixes []Fix) ([]AppliedFix, error) {
+	var applied []AppliedFix
+	backups := make(map[string]string)
+
+	for _, fix := range fixes {
+		path := filepath.Join(r.projectPath, fix.File)
+
+		content, err := os.ReadFile(path)
+		exists := err == nil
+		if err != nil && !os.IsNotExist(err) {
+			return applied, err
+		}
+
+		updated := fix.apply(string(content))
+		if updated == string(content) {
+			continue
+		}
+
+		if _, done := backups[path]; !done {
+			backups[path] = ""
+			if exists {
+				backup := path + ".bak"
+				if err := os.WriteFile(backup, content, 0644); err != nil {
+					return applied, err
+				}
+				backups[path] = backup
+			}
+		}
+
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := os.WriteFile(path, []byte(updated), mode); err != nil {
+			return applied, err
+		}
+
+		backup := backups[path]
+		if backup != "" {
+			backup, _ = filepath.Rel(r.projectPath, backup)
+		}
+		applied = append(applied, AppliedFix{Fix: fix, Backup: backup})
+	}
+
+	return applied, nil
+}
+
+// removeConsoleLogs drops lines that are a single console.log() call.
+// A console.log inside a larger expression is left alone, since cutting
+// it out could change what the code does.
+func removeConsoleLogs(content string) string {
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !isConsoleLogLine(line) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// isConsoleLogLine reports whether line is exactly one console.log(...)
+// call, optionally followed by a semicolon. The call's parentheses must
+// balance on the line, so "console.log(a); cleanup();" and
+// "console.log(x) || launch()" don't count: deleting them would delete
+// the code after the call too. Parentheses inside string literals are
+// ignored.
+func isConsoleLogLine(line string) bool {
+	rest := strings.TrimSpace(line)
+	if !strings.HasPrefix(rest, "console.log(") {
+		return false
+	}
+
+	depth := 0
+	var quote byte
+	for i := len("console.log"); i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				tail := strings.TrimSpace(rest[i+1:])
+				return tail == "" || tail == ";"
+			}
+		}
+	}
+	// the call continues on the next line
+	return false
+}
+
+// addGitignoreEntry returns a fix function appending entry to a
+// .gitignore, unless a line already ignores it
+func addGitignoreEntry(entry string) func(string) string {
+	return func(content string) string {
+		for _, line := range strings.Split(content, "\n") {
+			if strings.Trim(strings.TrimSpace(line), "/") == entry {
+				return content
+			}
+		}
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		return content + entry + "\n"
+	}
+}
+
+// addMissingAlt gives every <img> tag without an alt attribute an empty
+// one, which marks the image as decorative until someone writes a real
+// description
+func addMissingAlt(content string, jsx bool) string {
+	var b strings.Builder
+	last := 0
+
+	for _, t := range htmlTokens(content, jsx) {
+		if !t.isStart("img") || t.has("alt") {
+			continue
+		}
+		at := t.Offset + len("<img")
+		b.WriteString(content[last:at])
+		b.WriteString(` alt=""`)
+		last = at
+	}
+
+	b.WriteString(content[last:])
+	return b.String()
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for verification auto-fixes
+
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyFixes(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"App.jsx":    "export function App() {\n  console.log(\"render\");\n  const x = f(console.log(1));\n  return <img src=\"a.png\" />;\n}\n",
+		".env":       "KEY=1\n",
+		".gitignore": "node_modules",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runner := NewRunner(tmpDir, RunnerOptions{})
+	var fixes []Fix
+	for _, result := range runner.RunAll() {
+		fixes = append(fixes, result.Fixes...)
+	}
+	if len(fixes) != 3 {
+		t.Fatalf("got %d fixes, want env-gitignore, no-console and img-alt: %+v", len(fixes), fixes)
+	}
+
+	applied, err := runner.ApplyFixes(fixes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 3 {
+		t.Errorf("ApplyFixes() applied %d fixes, want 3", len(applied))
+	}
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	want := "export function App() {\n  const x = f(console.log(1));\n  return <img alt=\"\" src=\"a.png\" />;\n}\n"
+	if got := read("App.jsx"); got != want {
+		t.Errorf("App.jsx =\n%s\nwant\n%s", got, want)
+	}
+	if got := read(".gitignore"); got != "node_modules\n.env\n" {
+		t.Errorf(".gitignore = %q", got)
+	}
+
+	// one backup per file, holding the content from before the run
+	if got := read("App.jsx.bak"); got != files["App.jsx"] {
+		t.Errorf("App.jsx.bak = %q, want the original", got)
+	}
+	if got := read(".gitignore.bak"); got != files[".gitignore"] {
+		t.Errorf(".gitignore.bak = %q, want the original", got)
+	}
+
+	// a second run has nothing left to do
+	again, err := NewRunner(tmpDir, RunnerOptions{}).ApplyFixes(fixes)
+	if err != nil || len(again) != 0 {
+		t.Errorf("second ApplyFixes() = %+v, %v; want nothing", again, err)
+	}
+}
+
+func TestFixesFollowRuleFilters(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.js"), []byte("console.log(1)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := NewRunner(tmpDir, RunnerOptions{DisabledRules: []string{"lint/*"}}).RunLint()
+	if len(result.Fixes) != 0 {
+		t.Errorf("disabled rule still offers fixes: %+v", result.Fixes)
+	}
+}
+
+func TestRemoveConsoleLogsKeepsCodeOnTheLine(t *testing.T) {
+	content := `console.log("start");
+  console.log("a (b", x)
+console.log(a); cleanup();
+console.log(x) || launch()
+console.log(fmt(x)) // note
+console.log(
+  "multi-line"
+);
+logger.info(console.log(1));
+`
+	want := `console.log(a); cleanup();
+console.log(x) || launch()
+console.log(fmt(x)) // note
+console.log(
+  "multi-line"
+);
+logger.info(console.log(1));
+`
+	if got := removeConsoleLogs(content); got != want {
+		t.Errorf("removeConsoleLogs() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// .gitignore matching for the verification walk
+
+package verify
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule is one pattern line from a .gitignore
+type ignoreRule struct {
+	base     string   // directory of the .gitignore, slash separated, "" for the root
+	segments []string // pattern split on "/"
+	negate   bool     // "!pattern" re-includes
+	dirOnly  bool     // "pattern/" only matches directories
+	anchored bool     // contains a "/", so matched from base rather than any depth
+}
+
+// gitignore collects the rules of every .gitignore seen so far in a walk.
+// Rules are kept in the order they were loaded, and the last one
+// matching a path decides, as in git.
+type gitignore struct {
+	rules []ignoreRule
+}
+
+// load reads the .gitignore in relDir (relative to root), if any.
+// Call it for each directory as the walk enters it, so deeper files
+// override shallower ones.
+func (g *gitignore) load(root, relDir string) {
+	content, err := os.ReadFile(filepath.Join(root, relDir, ".gitignore"))
+	if err != nil {
+		return
+	}
+
+	base := filepath.ToSlash(relDir)
+	if base == "." {
+		base = ""
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:] // "\#" and "\!" are literal
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		rule.segments = strings.Split(line, "/")
+		g.rules = append(g.rules, rule)
+	}
+}
+
+// ignored reports whether relPath (relative to the walk root) is ignored
+func (g *gitignore) ignored(relPath string, isDir bool) bool {
+	relPath = filepath.ToSlash(relPath)
+	ignored := false
+
+	for _, rule := range g.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		p := relPath
+		if rule.base != "" {
+			var ok bool
+			if p, ok = strings.CutPrefix(relPath, rule.base+"/"); !ok {
+				continue // the rule's .gitignore is in another directory
+			}
+		}
+
+		var matched bool
+		if rule.anchored {
+			matched = matchSegments(rule.segments, strings.Split(p, "/"))
+		} else {
+			matched, _ = path.Match(rule.segments[0], path.Base(p))
+		}
+		if matched {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+// matchSegments matches a path against pattern segments, where a "**"
+// segment stands for any number of directories, including none
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// HTML/JSX tokenizing for the UX and SEO checks
+
+package verify
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// htmlToken is one token of an HTML or JSX file. Tag and attribute
+// names are lowercased by the tokenizer, so JSX's className and htmlFor
+// show up as "classname" and "htmlfor". The exception is a JSX component
+// such as <Img> or <Button>: its Tag keeps its case and Component is set,
+// so it never passes for the native element of the same name.
+type htmlToken struct {
+	Type      html.TokenType
+	Tag       string            // element name, "" for text and comments
+	Attrs     map[string]string // start and self-closing tags only
+	Text      string            // text tokens only
+	Spread    bool              // a JSX {...props} spread, which may set any attribute
+	Component bool              // a capitalised JSX tag, a component rather than an element
+	Offset    int               // byte offset of the token in the file
+	Line      int
+}
+
+// isStart reports whether t opens an element named tag, <img> and
+// <img /> alike
+func (t htmlToken) isStart(tag string) bool {
+	return t.Tag == tag && (t.Type == html.StartTagToken || t.Type == html.SelfClosingTagToken)
+}
+
+// has reports whether t sets attribute name (possibly through a spread)
+func (t htmlToken) has(name string) bool {
+	_, ok := t.Attrs[name]
+	return ok || t.Spread
+}
+
+// htmlTokens tokenizes content with golang.org/x/net/html, so tags split
+// over several lines, quoted values containing ">" and self-closing tags
+// are all read the way a browser would. JSX expressions in tags are
+// masked first (see maskJSXExpressions) so their contents can't end a
+// tag early.
+//
+// In JSX (jsx true), a capitalised tag name is a component, not an HTML
+// element; its token keeps the name as written and sets Component. In
+// HTML, <IMG> is just <img>.
+func htmlTokens(content string, jsx bool) []htmlToken {
+	var tokens []htmlToken
+
+	z := html.NewTokenizer(strings.NewReader(maskJSXExpressions(content)))
+	offset, line := 0, 1
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break // io.EOF; a strings.Reader has no other errors
+		}
+
+		raw := string(z.Raw())
+		token := htmlToken{Type: tt, Offset: offset, Line: line}
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			name, hasAttr := z.TagName()
+			token.Tag = string(name)
+			if jsx {
+				if written := rawTagName(raw); written != "" && 'A' <= written[0] && written[0] <= 'Z' {
+					token.Tag = written
+					token.Component = true
+				}
+			}
+			if tt != html.EndTagToken {
+				token.Attrs = make(map[string]string)
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					if strings.HasPrefix(string(key), "{") {
+						token.Spread = true
+						continue
+					}
+					token.Attrs[string(key)] = string(val)
+				}
+			}
+		case html.TextToken:
+			token.Text = string(z.Text())
+		}
+
+		tokens = append(tokens, token)
+		offset += len(raw)
+		line += strings.Count(content[token.Offset:offset], "\n")
+	}
+
+	return tokens
+}
+
+// isJSXExt reports whether files with ext are JSX, where capitalised tags
+// are components
+func isJSXExt(ext string) bool {
+	return ext == ".jsx" || ext == ".tsx"
+}
+
+// rawTagName returns a tag's name as written in raw, "<Img src=...>" or
+// "</Img>", before the tokenizer lowercases it
+func rawTagName(raw string) string {
+	i := 1
+	if i < len(raw) && raw[i] == '/' {
+		i++
+	}
+	end := i
+	for end < len(raw) && isTagNameChar(raw[end]) {
+		end++
+	}
+	return raw[i:end]
+}
+
+// maskJSXExpressions prepares content for the tokenizer, keeping every
+// byte offset the same.
+//
+// How it works:
+//  1. A "<" that doesn't start a well-formed tag, as in for (i<n; ...),
+//     becomes a space, so the tokenizer can't read code up to the next
+//     ">" as one long tag and swallow real elements with it
+//  2. In tags, an expression used as a value, alt={desc}, becomes a
+//     quoted placeholder, alt="____", and a spread, {...props}, an
+//     attribute named {____}, which htmlTokens records as Spread
+//
+// Braces in text between tags are left alone, and plain HTML has none in
+// its tags, so this is safe to run on every file.
+func maskJSXExpressions(content string) string {
+	b := []byte(content)
+	for i := 0; i < len(b); i++ {
+		if b[i] != '<' || i+1 >= len(b) || !isTagStart(b[i+1]) {
+			continue
+		}
+
+		end := i + 1
+		if b[end] == '/' {
+			end++
+		}
+		for end < len(b) && isTagNameChar(b[end]) {
+			end++
+		}
+		if end < len(b) && !strings.ContainsRune(" \t\r\n/>", rune(b[end])) {
+			b[i] = ' '
+			continue
+		}
+		i = maskTag(b, end)
+	}
+	return string(b)
+}
+
+// maskTag masks the expressions of a tag from start, just past its
+// name, and returns the index of its closing ">"
+func maskTag(b []byte, start int) int {
+	var quote byte
+
+	for i := start; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			end := matchingBrace(b, i)
+			value := lastNonSpace(b[:i]) == '='
+			for j := i; j <= end; j++ {
+				b[j] = '_'
+			}
+			if value {
+				b[i], b[end] = '"', '"'
+			} else {
+				b[i], b[end] = '{', '}'
+			}
+			i = end
+		case c == '>':
+			return i
+		}
+	}
+	return len(b)
+}
+
+// isTagStart reports whether c can follow "<" in an element tag
+func isTagStart(c byte) bool {
+	return c == '/' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// isTagNameChar reports whether c can appear in an HTML or JSX element
+// name, <my-widget> and <Layout.Header> included
+func isTagNameChar(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || strings.IndexByte("-_.:", c) >= 0
+}
+
+// matchingBrace returns the index of the "}" closing the "{" at open,
+// skipping nested braces and string literals, or the last index if the
+// expression never closes
+func matchingBrace(b []byte, open int) int {
+	depth := 0
+	var quote byte
+
+	for i := open; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(b) - 1
+}
+
+// lastNonSpace returns the last byte of b that isn't whitespace, or 0
+func lastNonSpace(b []byte) byte {
+	for i := len(b) - 1; i >= 0; i-- {
+		switch b[i] {
+		case ' ', '\t', '\n', '\r':
+		default:
+			return b[i]
+		}
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for HTML/JSX tokenizing in the UX and SEO checks
+
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// issuesByRule runs check on a project holding files and returns the
+// line numbers it reported per rule
+func issuesByRule(t *testing.T, files map[string]string, check func(*Runner) Result) map[string][]int {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := make(map[string][]int)
+	for _, issue := range check(NewRunner(dir, RunnerOptions{})).Issues {
+		lines[issue.Rule] = append(lines[issue.Rule], issue.Line)
+	}
+	return lines
+}
+
+func TestUXParsesTags(t *testing.T) {
+	page := `<html><body>
+<img
+  src="hero.png"
+  data-note="a > b">
+<img src="logo.png" alt="Logo">
+<label>Name <input type="text"></label>
+<label for="email">Email</label>
+<input id="email" type="email">
+<input type="search" aria-label="Search">
+<input type="hidden" name="csrf">
+<input type="text" name="nickname">
+<!-- <img src="commented-out.png"> -->
+</body></html>
+`
+	component := `export function Card({ user, ...props }) {
+  for (let i = 0; i<user.tags.length; i++) {}
+  return (
+    <div className="card" onClick={() => user.count > 0 && open()}>
+      <img src={user.avatar} />
+      <img src={user.cover} alt={user.name} />
+      <img {...props} />
+      <label htmlFor="bio">Bio</label>
+      <input id="bio" className="field" />
+    </div>
+  );
+}
+`
+
+	lines := issuesByRule(t, map[string]string{"index.html": page, "Card.tsx": component}, (*Runner).RunUX)
+
+	// hero.png on line 2 (its ">" in a value doesn't end the tag) and
+	// the avatar, line 5 of Card.tsx; the spread one may have an alt
+	if got := lines["ux/img-alt"]; len(got) != 2 || !containsLine(got, 2) || !containsLine(got, 5) {
+		t.Errorf("ux/img-alt lines = %v, want 2 and 5", got)
+	}
+	// only nickname is unlabelled
+	if got := lines["ux/input-label"]; len(got) != 1 || got[0] != 11 {
+		t.Errorf("ux/input-label lines = %v, want [11]", got)
+	}
+}
+
+func TestUXSkipsJSXComponents(t *testing.T) {
+	component := `export function Gallery({ photo }) {
+  return (
+    <Card>
+      <Img src={photo.url} />
+      <Input name="q" />
+      <Label><Input name="r" /></Label>
+      <img src={photo.thumb} />
+      <Button type="button" />
+    </Card>
+  );
+}
+`
+	page := `<html><body><IMG src="a.png"></body></html>`
+
+	lines := issuesByRule(t, map[string]string{"Gallery.jsx": component, "index.html": page}, (*Runner).RunUX)
+
+	// only the native <img> on line 7 and, in HTML, <IMG> on line 1
+	if got := lines["ux/img-alt"]; len(got) != 2 || !containsLine(got, 1) || !containsLine(got, 7) {
+		t.Errorf("ux/img-alt lines = %v, want 1 and 7", got)
+	}
+	if got := lines["ux/input-label"]; len(got) != 0 {
+		t.Errorf("ux/input-label lines = %v, want none for <Input> components", got)
+	}
+
+	fixed := addMissingAlt(component, true)
+	if !strings.Contains(fixed, `<Img src={photo.url} />`) || !strings.Contains(fixed, `<img alt="" src={photo.thumb} />`) {
+		t.Errorf("addMissingAlt() touched the component or missed the element:\n%s", fixed)
+	}
+}
+
+func TestSEOParsesDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want map[string]int // rule -> number of issues
+	}{
+		{
+			name: "complete page",
+			page: `<html><head>
+<TITLE>Home</TITLE>
+<meta content="All about us" name="Description">
+</head><body><h1 class="hero">Welcome</h1></body></html>`,
+			want: map[string]int{},
+		},
+		{
+			name: "empty title and description",
+			page: `<html><head><title> </title><meta name="description" content=""></head><body><h1>Hi</h1></body></html>`,
+			want: map[string]int{"seo/title": 1, "seo/meta-description": 1},
+		},
+		{
+			name: "svg title and commented h1 don't count",
+			page: `<html><head><meta name="description" content="x"></head><body>
+<svg><title>icon</title></svg>
+<!-- <h1>Old</h1> -->
+</body></html>`,
+			want: map[string]int{"seo/title": 1, "seo/h1": 1},
+		},
+		{
+			name: "duplicate h1",
+			page: `<html><head><title>Docs</title><meta name="description" content="x"></head>
+<body><h1>One</h1>
+<h1>Two</h1></body></html>`,
+			want: map[string]int{"seo/h1": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := issuesByRule(t, map[string]string{"page.html": tt.page}, (*Runner).RunSEO)
+			if len(lines) != len(tt.want) {
+				t.Errorf("rules = %v, want %v", lines, tt.want)
+			}
+			for rule, n := range tt.want {
+				if len(lines[rule]) != n {
+					t.Errorf("%s reported %d times, want %d", rule, len(lines[rule]), n)
+				}
+			}
+		})
+	}
+}
+
+func TestAddMissingAltMultiline(t *testing.T) {
+	content := "<img\n  src=\"a.png\"\n  title=\"x > y\"><img src=\"b.png\" alt=\"B\"><IMG src=c.png/>"
+	want := "<img alt=\"\"\n  src=\"a.png\"\n  title=\"x > y\"><img src=\"b.png\" alt=\"B\"><IMG alt=\"\" src=c.png/>"
+
+	if got := addMissingAlt(content, false); got != want {
+		t.Errorf("addMissingAlt() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func containsLine(lines []int, line int) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// External linter detection and output parsing for the lint check
+
+package verify
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// linter is a language's external lint step. RunLint runs every linter
+// whose detect matches, using the first of its tools that's installed.
+type linter struct {
+	language string
+	detect   func(dir string) bool
+	tools    []lintTool
+}
+
+// lintTool is one way to lint a language. Each is run so that it prints
+// one "file:line[:col]: message" line per finding, which parseLintOutput
+// reads; name is also the rule id suffix (lint/<name>).
+type lintTool struct {
+	name string
+	args []string
+}
+
+// linters lists the supported languages. JavaScript comes first and
+// depends on package.json: a "lint" script is run through npm, since it
+// is how the project wants to be linted; otherwise eslint is run directly
+// when the project has an eslint config.
+var linters = []linter{
+	{
+		language: "JavaScript",
+		detect:   hasNpmLintScript,
+		// reported as lint/eslint, the rule id npm lint output has always had
+		tools: []lintTool{{"eslint", []string{"npm", "run", "lint", "--silent"}}},
+	},
+	{
+		language: "JavaScript",
+		detect: func(dir string) bool {
+			return !hasNpmLintScript(dir) && hasAnyFile(dir,
+				"eslint.config.js", "eslint.config.mjs", "eslint.config.cjs", "eslint.config.ts",
+				".eslintrc", ".eslintrc.js", ".eslintrc.cjs", ".eslintrc.json", ".eslintrc.yml", ".eslintrc.yaml")
+		},
+		tools: []lintTool{{"eslint", []string{"eslint", "--format", "unix", "."}}},
+	},
+	{
+		language: "Go",
+		detect:   func(dir string) bool { return hasAnyFile(dir, "go.mod") },
+		tools:    []lintTool{{"golangci", []string{"golangci-lint", "run", "--out-format=line-number"}}},
+	},
+	{
+		language: "Python",
+		detect: func(dir string) bool {
+			return hasAnyFile(dir, "pyproject.toml", "setup.py", "setup.cfg", "requirements.txt", "ruff.toml", ".ruff.toml", ".flake8")
+		},
+		tools: []lintTool{
+			{"ruff", []string{"ruff", "check", "--output-format", "concise", "."}},
+			{"flake8", []string{"flake8", "."}},
+		},
+	},
+	{
+		language: "Rust",
+		detect:   func(dir string) bool { return hasAnyFile(dir, "Cargo.toml") },
+		tools:    []lintTool{{"clippy", []string{"cargo", "clippy", "--quiet", "--message-format", "short"}}},
+	},
+	{
+		language: "Ruby",
+		detect:   func(dir string) bool { return hasAnyFile(dir, "Gemfile", ".rubocop.yml") },
+		tools:    []lintTool{{"rubocop", []string{"rubocop", "--format", "emacs"}}},
+	},
+}
+
+// runLinters runs the detected linters in dir. A language whose tools
+// aren't installed gets an info issue saying so instead of silence.
+func runLinters(dir string, verbose bool) []Issue {
+	var issues []Issue
+
+	for _, l := range linters {
+		if !l.detect(dir) {
+			continue
+		}
+
+		tool, path, ok := findLintTool(dir, l.tools)
+		if !ok {
+			var names []string
+			for _, t := range l.tools {
+				names = append(names, t.args[0])
+			}
+			issues = append(issues, Issue{
+				Severity: "info",
+				Message:  l.language + " project detected but " + strings.Join(names, " or ") + " isn't installed; skipped",
+				Rule:     "lint/" + l.tools[0].name,
+			})
+			continue
+		}
+
+		if verbose {
+			issues = append(issues, Issue{
+				Severity: "info",
+				Message:  "Running " + strings.Join(tool.args, " ") + "...",
+				Rule:     "lint/" + tool.name,
+			})
+		}
+
+		cmd := exec.Command(path, tool.args[1:]...)
+		cmd.Dir = dir
+		// linters exit non-zero when they find something (clippy doesn't
+		// for warnings), so the error only says whether it failed
+		output, err := cmd.CombinedOutput()
+		issues = append(issues, parseLintOutput(string(output), dir, "lint/"+tool.name, err != nil)...)
+	}
+
+	return issues
+}
+
+// findLintTool returns the first installed tool and its path. A copy in
+// the project's node_modules/.bin wins over PATH, as npx would pick it.
+func findLintTool(dir string, tools []lintTool) (lintTool, string, bool) {
+	for _, t := range tools {
+		local := filepath.Join(dir, "node_modules", ".bin", t.args[0])
+		if info, err := os.Stat(local); err == nil && !info.IsDir() {
+			return t, local, true
+		}
+		if path, err := exec.LookPath(t.args[0]); err == nil {
+			return t, path, true
+		}
+	}
+	return lintTool{}, "", false
+}
+
+// lintLocation matches "file:line: message" and "file:line:col: message",
+// the format every supported tool can print
+var lintLocation = regexp.MustCompile(`^(.+?):(\d+):(?:\d+:)?\s*(.+)$`)
+
+// parseLintOutput turns linter output into warnings. Lines with a file
+// and line number are reported with them (paths made relative to dir).
+// When the tool failed, other lines mentioning an error are kept as
+// plain messages so unstructured output (an npm script, say) isn't lost;
+// after a clean run they're just summaries like "0 errors".
+func parseLintOutput(output, dir, rule string, failed bool) []Issue {
+	var issues []Issue
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "level=") {
+			continue
+		}
+
+		if m := lintLocation.FindStringSubmatch(line); m != nil && !strings.Contains(m[1], " ") {
+			file := m[1]
+			if filepath.IsAbs(file) {
+				if rel, err := filepath.Rel(dir, file); err == nil {
+					file = rel
+				}
+			}
+			lineNum, _ := strconv.Atoi(m[2])
+			issues = append(issues, Issue{
+				Severity: "warning",
+				File:     filepath.ToSlash(file),
+				Line:     lineNum,
+				Message:  m[3],
+				Rule:     rule,
+			})
+			continue
+		}
+
+		if failed && strings.Contains(strings.ToLower(line), "error") {
+			issues = append(issues, Issue{
+				Severity: "warning",
+				Message:  line,
+				Rule:     rule,
+			})
+		}
+	}
+
+	return issues
+}
+
+// hasNpmLintScript reports whether dir's package.json defines a "lint" script
+func hasNpmLintScript(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return false
+	}
+	_, ok := pkg.Scripts["lint"]
+	return ok
+}
+
+// hasAnyFile reports whether any of names exists in dir
+func hasAnyFile(dir string, names ...string) bool {
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
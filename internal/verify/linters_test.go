@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for external linter detection and parsing
+
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestParseLintOutput(t *testing.T) {
+	dir := filepath.FromSlash("/work/app")
+	output := `app/models.py:12:5: F401 'os' imported but unused
+/work/app/src/index.js:3:1: Unexpected console statement [Error/no-console]
+src/main.rs:2:9: warning: unused variable: ` + "`x`" + `
+lib/user.rb:7: C: Style/StringLiterals: Prefer single quotes
+level=warning msg="[runner] skipped"
+Found 4 errors.
+`
+
+	got := parseLintOutput(output, dir, "lint/test", false)
+	want := []Issue{
+		{Severity: "warning", File: "app/models.py", Line: 12, Message: "F401 'os' imported but unused", Rule: "lint/test"},
+		{Severity: "warning", File: "src/index.js", Line: 3, Message: "Unexpected console statement [Error/no-console]", Rule: "lint/test"},
+		{Severity: "warning", File: "src/main.rs", Line: 2, Message: "warning: unused variable: `x`", Rule: "lint/test"},
+		{Severity: "warning", File: "lib/user.rb", Line: 7, Message: "C: Style/StringLiterals: Prefer single quotes", Rule: "lint/test"},
+	}
+	if runtime.GOOS == "windows" {
+		want = append(want[:1], want[2:]...) // the absolute path is a unix one
+		got = append(got[:1], got[2:]...)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseLintOutput() = %+v, want %d issues", got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("issue %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// unstructured error lines only count when the tool failed
+	if failed := parseLintOutput("npm error Lifecycle script `lint` failed\n", dir, "lint/eslint", true); len(failed) != 1 || failed[0].File != "" {
+		t.Errorf("failed run = %+v, want one plain issue", failed)
+	}
+}
+
+func TestRunLintDetectsTools(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake linters are shell scripts")
+	}
+
+	project := t.TempDir()
+	for _, name := range []string{"pyproject.toml", "Gemfile"} {
+		if err := os.WriteFile(filepath.Join(project, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// only flake8 is "installed": ruff is missing, so it's the fallback,
+	// and rubocop gets a not-installed note
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho 'app.py:4:1: E302 expected 2 blank lines'\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(bin, "flake8"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	result := NewRunner(project, RunnerOptions{}).RunLint()
+
+	var flake8, rubocop *Issue
+	for i, issue := range result.Issues {
+		switch issue.Rule {
+		case "lint/flake8":
+			flake8 = &result.Issues[i]
+		case "lint/rubocop":
+			rubocop = &result.Issues[i]
+		}
+	}
+	if flake8 == nil || flake8.File != "app.py" || flake8.Line != 4 || flake8.Severity != "warning" {
+		t.Errorf("flake8 issue = %+v, want app.py:4 warning", flake8)
+	}
+	if rubocop == nil || rubocop.Severity != "info" {
+		t.Errorf("rubocop issue = %+v, want an info note that it isn't installed", rubocop)
+	}
+	if result.WarningCount != 1 {
+		t.Errorf("WarningCount = %d, want 1", result.WarningCount)
+	}
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Performance budgets: asset sizes, file lengths and lazy loading
+
+package verify
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Performance budgets checked by RunPerformance. Sizes are in bytes.
+var (
+	MaxImageSize   int64 = 200 << 10
+	MaxBundleSize  int64 = 500 << 10
+	MaxSourceLines       = 1000
+)
+
+// imageExts are the committed image formats the size budget applies to
+var imageExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true,
+	".webp": true, ".avif": true, ".bmp": true, ".tiff": true, ".svg": true,
+}
+
+// bundleExts are the formats a vendored library or build output ships in
+var bundleExts = map[string]bool{
+	".js": true, ".mjs": true, ".cjs": true, ".css": true,
+}
+
+// sourceExts are the files the line budget applies to
+var sourceExts = map[string]bool{
+	".js": true, ".ts": true, ".jsx": true, ".tsx": true,
+	".py": true, ".go": true, ".java": true, ".rb": true,
+}
+
+// RunPerformance checks frontend performance budgets.
+//
+// Checks for:
+//   - Images over MaxImageSize
+//   - Committed .js/.css files over MaxBundleSize, usually a vendored
+//     library or build output that belongs in a dependency or a CDN
+//     (node_modules, dist and build aren't walked at all)
+//   - Source files over MaxSourceLines
+//   - <img> and <iframe> elements without a loading attribute. The first
+//     one in a file is skipped, since it's likely above the fold, where
+//     lazy loading would slow it down
+//
+// Size findings carry the file's size in Issue.Size.
+func (r *Runner) RunPerformance() Result {
+	start := time.Now()
+	result := Result{
+		Name:   "Performance",
+		Passed: true,
+	}
+
+	bundles := make(map[string]bool)
+	for _, stat := range r.stats() {
+		switch {
+		case imageExts[stat.Ext] && stat.Size > MaxImageSize:
+			result.Issues = append(result.Issues, Issue{
+				Severity: "warning",
+				File:     stat.Path,
+				Size:     stat.Size,
+				Message:  fmt.Sprintf("Image exceeds the %d KB budget", MaxImageSize>>10),
+				Rule:     "perf/image-size",
+			})
+		case bundleExts[stat.Ext] && stat.Size > MaxBundleSize:
+			result.Issues = append(result.Issues, Issue{
+				Severity: "warning",
+				File:     stat.Path,
+				Size:     stat.Size,
+				Message:  fmt.Sprintf("Committed bundle exceeds the %d KB budget", MaxBundleSize>>10),
+				Rule:     "perf/bundle-size",
+			})
+			bundles[stat.Path] = true
+		}
+	}
+
+	htmlExts := map[string]bool{
+		".html": true, ".htm": true, ".jsx": true, ".tsx": true,
+	}
+
+	for _, file := range r.files() {
+		// a bundle's length is already covered by its size, and minified
+		// code is one long line anyway
+		if sourceExts[file.Ext] && !bundles[file.Path] && !strings.Contains(filepath.Base(file.Path), ".min.") {
+			if lines := strings.Count(file.Content, "\n") + 1; lines > MaxSourceLines {
+				result.Issues = append(result.Issues, Issue{
+					Severity: "warning",
+					File:     file.Path,
+					Message:  fmt.Sprintf("File has %d lines, over the %d line budget", lines, MaxSourceLines),
+					Rule:     "perf/file-lines",
+				})
+			}
+		}
+
+		if !htmlExts[file.Ext] {
+			continue
+		}
+
+		first := true
+		for _, t := range htmlTokens(file.Content, isJSXExt(file.Ext)) {
+			if !t.isStart("img") && !t.isStart("iframe") {
+				continue
+			}
+			if first {
+				first = false
+				continue
+			}
+			if t.has("loading") || strings.EqualFold(t.Attrs["fetchpriority"], "high") {
+				continue
+			}
+			result.Issues = append(result.Issues, Issue{
+				Severity: "warning",
+				File:     file.Path,
+				Line:     t.Line,
+				Message:  fmt.Sprintf(`<%s> without loading="lazy"`, t.Tag),
+				Rule:     "perf/lazy-loading",
+			})
+		}
+	}
+
+	return r.finish(result, start)
+}
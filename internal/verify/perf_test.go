@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Unit tests for the performance check
+
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunPerformance(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(rel string, size int, content string) {
+		path := filepath.Join(tmpDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if size > len(content) {
+			content += strings.Repeat("x", size-len(content))
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldImage, oldBundle, oldLines := MaxImageSize, MaxBundleSize, MaxSourceLines
+	MaxImageSize, MaxBundleSize, MaxSourceLines = 100, 200, 10
+	defer func() { MaxImageSize, MaxBundleSize, MaxSourceLines = oldImage, oldBundle, oldLines }()
+
+	write("public/hero.png", 150, "")
+	write("public/icon.png", 50, "")
+	write("public/lib/jquery.min.js", 300, "")
+	write("node_modules/big/index.js", 300, "")
+	write("src/long.ts", 0, strings.Repeat("x\n", 20))
+	write("src/short.ts", 0, "export {}\n")
+	write("index.html", 0, `<html><body>
+<img src="hero.png">
+<img src="a.png">
+<img src="b.png" loading="lazy">
+<iframe src="map.html"></iframe>
+</body></html>`)
+
+	result := NewRunner(tmpDir, RunnerOptions{}).RunPerformance()
+
+	got := make(map[string]Issue)
+	for _, issue := range result.Issues {
+		got[fmt.Sprintf("%s %s:%d", issue.Rule, filepath.ToSlash(issue.File), issue.Line)] = issue
+	}
+
+	want := []string{
+		"perf/image-size public/hero.png:0",
+		"perf/bundle-size public/lib/jquery.min.js:0",
+		"perf/file-lines src/long.ts:0",
+		"perf/lazy-loading index.html:3",
+		"perf/lazy-loading index.html:5",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d issues, want %d: %+v", len(got), len(want), result.Issues)
+	}
+	for _, key := range want {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing issue %q", key)
+		}
+	}
+	if issue := got["perf/image-size public/hero.png:0"]; issue.Size != 150 {
+		t.Errorf("image issue Size = %d, want 150", issue.Size)
+	}
+	if result.WarningCount != len(want) {
+		t.Errorf("WarningCount = %d, want %d", result.WarningCount, len(want))
+	}
+}
+
+func TestRunAllPerformanceOptIn(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	names := func(results []Result) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Name)
+		}
+		return out
+	}
+
+	if got := names(NewRunner(tmpDir, RunnerOptions{}).RunAll()); strings.Contains(strings.Join(got, ","), "Performance") {
+		t.Errorf("RunAll() = %v, want no performance check by default", got)
+	}
+	got := names(NewRunner(tmpDir, RunnerOptions{Performance: true}).RunAll())
+	if len(got) != 5 || got[2] != "Performance" {
+		t.Errorf("RunAll() with Performance = %v, want it third, after lint", got)
+	}
+}
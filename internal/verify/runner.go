@@ -7,42 +7,84 @@
 package verify
 
 import (
+	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
 )
 
 // Result represents the outcome of a verification check
 type Result struct {
-	Name          string
-	Passed        bool
-	HasCritical   bool
-	CriticalCount int
-	WarningCount  int
-	Issues        []Issue
-	Duration      float64
+	Name          string  `json:"name"`
+	Passed        bool    `json:"passed"`
+	HasCritical   bool    `json:"has_critical"`
+	CriticalCount int     `json:"critical_count"`
+	WarningCount  int     `json:"warning_count"`
+	Issues        []Issue `json:"issues"`
+	Fixes         []Fix   `json:"fixes,omitempty"` // safe fixes for some of the issues, see ApplyFixes
+	Duration      float64 `json:"duration_seconds"`
 }
 
 // Issue represents a single verification issue
 type Issue struct {
-	Severity string // "critical", "warning", "info"
-	File     string
-	Line     int
-	Message  string
-	Rule     string
+	Severity string `json:"severity"` // "critical", "warning", "info"
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Size     int64  `json:"size,omitempty"` // bytes, for findings about a file's size
+	Message  string `json:"message"`
+	Rule     string `json:"rule"`
 }
 
 // RunnerOptions configures the verification runner
 type RunnerOptions struct {
 	Verbose bool
+
+	// NoGitignore scans files the project's .gitignore files ignore
+	NoGitignore bool
+
+	// Performance adds the opt-in RunPerformance check to RunAll
+	Performance bool
+
+	// DisabledRules drops issues with these rule ids (e.g. "lint/no-console").
+	// A "category/*" entry disables every rule in that category.
+	DisabledRules []string
+
+	// OnlyRules, if set, keeps only issues matching one of these rule ids.
+	// Same matching as DisabledRules; DisabledRules still applies on top.
+	OnlyRules []string
+
+	// SeverityOverrides changes the severity of matching issues, keyed by
+	// rule id or "category/*". An exact rule id beats a wildcard.
+	SeverityOverrides map[string]string
 }
 
-// Runner orchestrates verification checks
+// Severities lists the valid issue severities, most severe first
+var Severities = []string{"critical", "warning", "info"}
+
+// ValidSeverity reports whether s is one of Severities
+func ValidSeverity(s string) bool {
+	for _, severity := range Severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// Runner orchestrates verification checks.
+// The project is walked once, on the first check, and every check after
+// that reads the same files (see files).
 type Runner struct {
 	projectPath string
 	options     RunnerOptions
+
+	filesOnce sync.Once
+	fileCache []File
+	statCache []fileStat
 }
 
 // NewRunner creates a new verification runner
@@ -53,95 +95,91 @@ func NewRunner(projectPath string, options RunnerOptions) *Runner {
 	}
 }
 
+// RunAll runs every check concurrently over the shared file list and
+// returns the results in priority order: security, lint, performance
+// (only with RunnerOptions.Performance), UX, SEO.
+func (r *Runner) RunAll() []Result {
+	checks := []func() Result{r.RunSecurity, r.RunLint}
+	if r.options.Performance {
+		checks = append(checks, r.RunPerformance)
+	}
+	checks = append(checks, r.RunUX, r.RunSEO)
+
+	// load the files up front so the checks don't all wait on the walk
+	r.load()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = check()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
 // RunSecurity performs security scanning.
 //
 // How it works:
-// 1. Scan for hardcoded secrets (API keys, passwords, tokens)
-// 2. Check for common security anti-patterns
-// 3. Look for vulnerable package patterns
-// 4. Check .env files aren't committed
+//  1. Scan for hardcoded secrets (API keys, passwords, tokens). Values
+//     assigned to password/api_key/secret/token are only reported when
+//     they look real (see likelyRealSecret); lines marked with
+//     AllowSecretComment are skipped
+//  2. Check for common security anti-patterns
+//  3. Look for vulnerable package patterns
+//  4. Check .env files aren't committed
 //
 // This is a simplified version of the original Python script.
 // For full security auditing, consider using dedicated tools like
 // trufflehog, gitleaks, or snyk.
 func (r *Runner) RunSecurity() Result {
+	start := time.Now()
 	result := Result{
 		Name:   "Security Scan",
 		Passed: true,
 	}
 
-	// Secret patterns to look for
-	secretPatterns := map[string]*regexp.Regexp{
-		"AWS Key":      regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
-		"GitHub Token": regexp.MustCompile(`ghp_[a-zA-Z0-9]{36}`),
-		"Private Key":  regexp.MustCompile(`-----BEGIN (RSA |EC |DSA |OPENSSH )?PRIVATE KEY-----`),
-		"Password":     regexp.MustCompile(`(?i)(password|passwd|pwd)\s*[=:]\s*["'][^"']+["']`),
-		"API Key":      regexp.MustCompile(`(?i)(api[_-]?key|apikey)\s*[=:]\s*["'][^"']+["']`),
-		"Secret":       regexp.MustCompile(`(?i)(secret|token)\s*[=:]\s*["'][^"']+["']`),
+	// secretExts are the files worth scanning for secrets
+	secretExts := map[string]bool{
+		".js": true, ".ts": true, ".jsx": true, ".tsx": true,
+		".py": true, ".go": true, ".java": true, ".rb": true,
+		".env": true, ".yml": true, ".yaml": true, ".json": true,
+		".md": true, ".txt": true, ".sh": true, ".bash": true,
 	}
 
-	// Files to skip
-	skipDirs := map[string]bool{
-		"node_modules": true,
-		".git":         true,
-		"vendor":       true,
-		"dist":         true,
-		"build":        true,
-	}
-
-	// Walk the project
-	filepath.Walk(r.projectPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		// skip directories
-		if info.IsDir() {
-			if skipDirs[info.Name()] {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		// Only check text files
-		ext := strings.ToLower(filepath.Ext(path))
-		textExts := map[string]bool{
-			".js": true, ".ts": true, ".jsx": true, ".tsx": true,
-			".py": true, ".go": true, ".java": true, ".rb": true,
-			".env": true, ".yml": true, ".yaml": true, ".json": true,
-			".md": true, ".txt": true, ".sh": true, ".bash": true,
-		}
-
-		if !textExts[ext] && filepath.Base(path) != ".env" {
-			return nil
-		}
-
-		// Read and scan
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
+	for _, file := range r.files() {
+		if !secretExts[file.Ext] {
+			continue
 		}
 
-		relPath, _ := filepath.Rel(r.projectPath, path)
-
-		for patternName, pattern := range secretPatterns {
-			if matches := pattern.FindAllStringIndex(string(content), -1); len(matches) > 0 {
-				for _, match := range matches {
-					lineNum := strings.Count(string(content[:match[0]]), "\n") + 1
-					result.Issues = append(result.Issues, Issue{
-						Severity: "critical",
-						File:     relPath,
-						Line:     lineNum,
-						Message:  "Potential " + patternName + " detected",
-						Rule:     "security/no-secrets",
-					})
-					result.CriticalCount++
+		for _, pattern := range secretPatterns {
+			for _, match := range pattern.re.FindAllStringSubmatchIndex(file.Content, -1) {
+				line, lineNum := lineAt(file.Content, match[0])
+				if strings.Contains(line, AllowSecretComment) {
+					continue
+				}
+				if pattern.generic {
+					value := file.Content[match[len(match)-2]:match[len(match)-1]]
+					if !likelyRealSecret(value) {
+						continue
+					}
 				}
+
+				result.Issues = append(result.Issues, Issue{
+					Severity: "critical",
+					File:     file.Path,
+					Line:     lineNum,
+					Message:  "Potential " + pattern.name + " detected",
+					Rule:     "security/no-secrets",
+				})
+				result.CriticalCount++
 			}
 		}
-
-		return nil
-	})
+	}
 
 	// Check for .env file without .gitignore
 	envFile := filepath.Join(r.projectPath, ".env")
@@ -155,123 +193,83 @@ func (r *Runner) RunSecurity() Result {
 				Rule:     "security/env-gitignore",
 			})
 			result.WarningCount++
+			result.Fixes = append(result.Fixes, Fix{
+				File:    ".gitignore",
+				Rule:    "security/env-gitignore",
+				Message: "add .env to .gitignore",
+				apply:   addGitignoreEntry(".env"),
+			})
 		}
 	}
 
-	result.Passed = result.CriticalCount == 0
-	result.HasCritical = result.CriticalCount > 0
-
-	return result
+	return r.finish(result, start)
 }
 
 // RunLint performs basic linting checks.
 //
-// For JavaScript/TypeScript projects, we try to run npm run lint.
-// For Go projects, we try golangci-lint.
-// Falls back to basic file checks if no linter is available.
+// Each language the project uses gets its usual linter (see linters):
+// npm run lint or eslint, golangci-lint, ruff or flake8, cargo clippy,
+// rubocop. A linter that isn't installed is noted as an info issue.
+// The basic file checks run either way.
 func (r *Runner) RunLint() Result {
+	start := time.Now()
 	result := Result{
 		Name:   "Lint Check",
 		Passed: true,
 	}
 
-	// Check for linter configs
-	packageJSON := filepath.Join(r.projectPath, "package.json")
-	goMod := filepath.Join(r.projectPath, "go.mod")
-
-	if _, err := os.Stat(packageJSON); err == nil {
-		// Try to run npm run lint
-		if r.options.Verbose {
-			result.Issues = append(result.Issues, Issue{
-				Severity: "info",
-				Message:  "Running npm run lint...",
-				Rule:     "lint/npm",
-			})
-		}
-		cmd := exec.Command("npm", "run", "lint", "--silent")
-		cmd.Dir = r.projectPath
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			// Parse npm lint output for issues
-			lines := strings.Split(string(output), "\n")
-			for _, line := range lines {
-				if strings.Contains(line, "error") || strings.Contains(line, "Error") {
-					result.Issues = append(result.Issues, Issue{
-						Severity: "warning",
-						Message:  strings.TrimSpace(line),
-						Rule:     "lint/eslint",
-					})
-					result.WarningCount++
-				}
-			}
-		}
-	} else if _, err := os.Stat(goMod); err == nil {
-		// Try to run golangci-lint
-		cmd := exec.Command("golangci-lint", "run", "--out-format=line-number")
-		cmd.Dir = r.projectPath
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			// Parse golangci-lint output
-			lines := strings.Split(string(output), "\n")
-			for _, line := range lines {
-				if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "level=") {
-					result.Issues = append(result.Issues, Issue{
-						Severity: "warning",
-						Message:  strings.TrimSpace(line),
-						Rule:     "lint/golangci",
-					})
-					result.WarningCount++
-				}
-			}
+	// Run the external linters the project is set up for
+	for _, issue := range runLinters(r.projectPath, r.options.Verbose) {
+		result.Issues = append(result.Issues, issue)
+		if issue.Severity == "warning" {
+			result.WarningCount++
 		}
 	}
 
 	// Basic file checks
-	filepath.Walk(r.projectPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return nil
-		}
-
+	for _, file := range r.files() {
 		// Check for console.log in production code
-		ext := filepath.Ext(path)
-		if ext == ".js" || ext == ".ts" || ext == ".jsx" || ext == ".tsx" {
-			content, err := os.ReadFile(path)
-			if err != nil {
-				return nil
-			}
-
-			relPath, _ := filepath.Rel(r.projectPath, path)
-
-			if strings.Contains(string(content), "console.log(") {
-				lineNum := strings.Count(strings.Split(string(content), "console.log(")[0], "\n") + 1
-				result.Issues = append(result.Issues, Issue{
-					Severity: "warning",
-					File:     relPath,
-					Line:     lineNum,
-					Message:  "console.log() found - remove before production",
-					Rule:     "lint/no-console",
-				})
-				result.WarningCount++
-			}
+		switch file.Ext {
+		case ".js", ".ts", ".jsx", ".tsx":
+		default:
+			continue
 		}
 
-		return nil
-	})
-
-	result.Passed = result.CriticalCount == 0
-	result.HasCritical = result.CriticalCount > 0
+		if idx := strings.Index(file.Content, "console.log("); idx >= 0 {
+			lineNum := strings.Count(file.Content[:idx], "\n") + 1
+			result.Issues = append(result.Issues, Issue{
+				Severity: "warning",
+				File:     file.Path,
+				Line:     lineNum,
+				Message:  "console.log() found - remove before production",
+				Rule:     "lint/no-console",
+			})
+			result.WarningCount++
+			result.Fixes = append(result.Fixes, Fix{
+				File:    file.Path,
+				Rule:    "lint/no-console",
+				Message: "remove console.log() lines",
+				apply:   removeConsoleLogs,
+			})
+		}
+	}
 
-	return result
+	return r.finish(result, start)
 }
 
 // RunUX performs basic UX auditing on HTML/JSX files.
 //
 // Checks for:
-// - Missing alt text on images
-// - Buttons without accessible labels
-// - Touch target sizes (for buttons/links)
-// - Color contrast issues (basic)
+//   - Images without an alt attribute
+//   - Form inputs without a label (aria-label, aria-labelledby, title,
+//     an enclosing <label> or a <label for/htmlFor> naming their id)
+//
+// Files are tokenized rather than matched with regexes (see htmlTokens),
+// so multi-line tags, self-closing JSX tags and JSX expressions are read
+// correctly. Elements with a {...props} spread are skipped, since the
+// spread may supply the attribute.
 func (r *Runner) RunUX() Result {
+	start := time.Now()
 	result := Result{
 		Name:   "UX Audit",
 		Passed: true,
@@ -281,135 +279,248 @@ func (r *Runner) RunUX() Result {
 		".html": true, ".htm": true, ".jsx": true, ".tsx": true,
 	}
 
-	filepath.Walk(r.projectPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return nil
-		}
-
-		ext := strings.ToLower(filepath.Ext(path))
-		if !htmlExts[ext] {
-			return nil
+	for _, file := range r.files() {
+		if !htmlExts[file.Ext] {
+			continue
 		}
 
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return nil
+		jsx := isJSXExt(file.Ext)
+		tokens := htmlTokens(file.Content, jsx)
+
+		// ids that some <label for="..."> (htmlFor in JSX) points at
+		labelled := make(map[string]bool)
+		for _, t := range tokens {
+			if t.isStart("label") {
+				for _, id := range []string{t.Attrs["for"], t.Attrs["htmlfor"]} {
+					if id != "" {
+						labelled[id] = true
+					}
+				}
+			}
 		}
 
-		relPath, _ := filepath.Rel(r.projectPath, path)
-		contentStr := string(content)
+		missingAlt := false
+		labelDepth := 0
+		for _, t := range tokens {
+			switch {
+			case t.Tag == "label" && t.Type == html.StartTagToken:
+				labelDepth++
+			case t.Tag == "label" && t.Type == html.EndTagToken && labelDepth > 0:
+				labelDepth--
 
-		// Check for images without alt
-		imgPattern := regexp.MustCompile(`<img[^>]*>`)
-		altPattern := regexp.MustCompile(`alt\s*=`)
-		for _, match := range imgPattern.FindAllString(contentStr, -1) {
-			if !altPattern.MatchString(match) {
+			case t.isStart("img") && !t.has("alt"):
 				result.Issues = append(result.Issues, Issue{
 					Severity: "warning",
-					File:     relPath,
+					File:     file.Path,
+					Line:     t.Line,
 					Message:  "Image missing alt attribute",
 					Rule:     "ux/img-alt",
 				})
-				result.WarningCount++
-			}
-		}
+				missingAlt = true
 
-		// check for form inputs without labels
-		inputPattern := regexp.MustCompile(`<input[^>]*>`)
-		for _, match := range inputPattern.FindAllString(contentStr, -1) {
-			if !strings.Contains(match, "aria-label") && !strings.Contains(match, "id=") {
+			case t.isStart("input") && needsLabel(t) && labelDepth == 0 && !labelled[t.Attrs["id"]]:
 				result.Issues = append(result.Issues, Issue{
 					Severity: "warning",
-					File:     relPath,
+					File:     file.Path,
+					Line:     t.Line,
 					Message:  "Input may be missing associated label",
 					Rule:     "ux/input-label",
 				})
-				result.WarningCount++
 			}
 		}
+		if missingAlt {
+			result.Fixes = append(result.Fixes, Fix{
+				File:    file.Path,
+				Rule:    "ux/img-alt",
+				Message: `add alt="" to images without alt text`,
+				apply: func(content string) string {
+					return addMissingAlt(content, jsx)
+				},
+			})
+		}
+	}
 
-		return nil
-	})
-
-	result.Passed = result.CriticalCount == 0
-	result.HasCritical = result.CriticalCount > 0
+	return r.finish(result, start)
+}
 
-	return result
+// needsLabel reports whether an <input> needs a label and doesn't label
+// itself. Buttons carry their own text and hidden inputs aren't shown.
+func needsLabel(input htmlToken) bool {
+	switch strings.ToLower(input.Attrs["type"]) {
+	case "hidden", "submit", "reset", "button", "image":
+		return false
+	}
+	return !input.has("aria-label") && !input.has("aria-labelledby") && !input.has("title")
 }
 
 // RunSEO performs basic SEO checks on HTML files.
 //
 // Checks for:
-// - Title tag
-// - Meta description
-// - OG tags
-// - Canonical URL
-// - H1 usage
+// - A non-empty <title> (one inside an inline <svg> doesn't count)
+// - A meta description with content
+// - Exactly one H1: none is an info note, several a warning
 func (r *Runner) RunSEO() Result {
+	start := time.Now()
 	result := Result{
 		Name:   "SEO Check",
 		Passed: true,
 	}
 
-	// look for HTML files
-	htmlFiles := []string{}
-
-	filepath.Walk(r.projectPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return nil
-		}
-
-		if strings.HasSuffix(path, ".html") || strings.HasSuffix(path, ".htm") {
-			htmlFiles = append(htmlFiles, path)
-		}
-
-		return nil
-	})
-
-	for _, htmlFile := range htmlFiles {
-		content, err := os.ReadFile(htmlFile)
-		if err != nil {
+	for _, file := range r.files() {
+		if file.Ext != ".html" && file.Ext != ".htm" {
 			continue
 		}
 
-		relPath, _ := filepath.Rel(r.projectPath, htmlFile)
-		contentStr := string(content)
+		relPath := file.Path
+		tokens := htmlTokens(file.Content, false)
+
+		var title *strings.Builder
+		hasTitle, hasDescription := false, false
+		var h1s []htmlToken
+		svgDepth := 0
+
+		for _, t := range tokens {
+			switch {
+			case t.Tag == "svg" && t.Type == html.StartTagToken:
+				svgDepth++
+			case t.Tag == "svg" && t.Type == html.EndTagToken && svgDepth > 0:
+				svgDepth--
+
+			case t.Tag == "title" && t.Type == html.StartTagToken && svgDepth == 0:
+				title = &strings.Builder{}
+			case t.Tag == "title" && t.Type == html.EndTagToken && title != nil:
+				hasTitle = hasTitle || strings.TrimSpace(title.String()) != ""
+				title = nil
+			case t.Type == html.TextToken && title != nil:
+				title.WriteString(t.Text)
+
+			case t.isStart("meta") && strings.EqualFold(t.Attrs["name"], "description"):
+				hasDescription = hasDescription || strings.TrimSpace(t.Attrs["content"]) != ""
+
+			case t.isStart("h1"):
+				h1s = append(h1s, t)
+			}
+		}
 
 		// check for title
-		if !strings.Contains(contentStr, "<title>") {
+		if !hasTitle {
 			result.Issues = append(result.Issues, Issue{
 				Severity: "warning",
 				File:     relPath,
 				Message:  "Missing <title> tag",
 				Rule:     "seo/title",
 			})
-			result.WarningCount++
 		}
 
 		// check for meta description
-		if !strings.Contains(contentStr, `name="description"`) {
+		if !hasDescription {
 			result.Issues = append(result.Issues, Issue{
 				Severity: "warning",
 				File:     relPath,
 				Message:  "Missing meta description",
 				Rule:     "seo/meta-description",
 			})
-			result.WarningCount++
 		}
 
 		// check for H1
-		if !strings.Contains(contentStr, "<h1") {
+		switch {
+		case len(h1s) == 0:
 			result.Issues = append(result.Issues, Issue{
 				Severity: "info",
 				File:     relPath,
 				Message:  "Missing H1 heading",
 				Rule:     "seo/h1",
 			})
+		case len(h1s) > 1:
+			result.Issues = append(result.Issues, Issue{
+				Severity: "warning",
+				File:     relPath,
+				Line:     h1s[1].Line,
+				Message:  fmt.Sprintf("Page has %d H1 headings; use one", len(h1s)),
+				Rule:     "seo/h1",
+			})
 		}
 	}
 
+	return r.finish(result, start)
+}
+
+// finish applies the rule filters and severity overrides to a check's
+// issues (the filters to its fixes too), recomputes the counts and
+// pass/fail status from what's left, and records how long the check
+// took since start.
+func (r *Runner) finish(result Result, start time.Time) Result {
+	kept := result.Issues[:0]
+	result.CriticalCount = 0
+	result.WarningCount = 0
+
+	for _, issue := range result.Issues {
+		if len(r.options.OnlyRules) > 0 && !matchesRule(issue.Rule, r.options.OnlyRules) {
+			continue
+		}
+		if matchesRule(issue.Rule, r.options.DisabledRules) {
+			continue
+		}
+		if severity, ok := r.severityFor(issue.Rule); ok {
+			issue.Severity = severity
+		}
+
+		switch issue.Severity {
+		case "critical":
+			result.CriticalCount++
+		case "warning":
+			result.WarningCount++
+		}
+		kept = append(kept, issue)
+	}
+
+	result.Issues = kept
+	if result.Issues == nil {
+		result.Issues = []Issue{} // so JSON reports an empty list, not null
+	}
+
+	// a fix for a filtered-out rule must not run either
+	fixes := result.Fixes[:0]
+	for _, fix := range result.Fixes {
+		if len(r.options.OnlyRules) > 0 && !matchesRule(fix.Rule, r.options.OnlyRules) {
+			continue
+		}
+		if matchesRule(fix.Rule, r.options.DisabledRules) {
+			continue
+		}
+		fixes = append(fixes, fix)
+	}
+	result.Fixes = fixes
+
 	result.Passed = result.CriticalCount == 0
 	result.HasCritical = result.CriticalCount > 0
+	result.Duration = time.Since(start).Seconds()
 
 	return result
 }
+
+// severityFor returns the configured severity override for rule, if any
+func (r *Runner) severityFor(rule string) (string, bool) {
+	if severity, ok := r.options.SeverityOverrides[rule]; ok {
+		return severity, true
+	}
+	if category, _, found := strings.Cut(rule, "/"); found {
+		severity, ok := r.options.SeverityOverrides[category+"/*"]
+		return severity, ok
+	}
+	return "", false
+}
+
+// matchesRule reports whether rule is listed in patterns, either exactly
+// or through a "category/*" wildcard.
+func matchesRule(rule string, patterns []string) bool {
+	for _, p := range patterns {
+		if p == rule {
+			return true
+		}
+		if category, ok := strings.CutSuffix(p, "/*"); ok && strings.HasPrefix(rule, category+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2026 Eshan Roy <eshanized@proton.me>
+//
+// AGEN - AI Agent Template Manager
+// Secret patterns and false-positive filtering for the security scan
+
+package verify
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// AllowSecretComment on a line suppresses secret findings on that line,
+// e.g. for a test fixture: token := "ghp_..." // agen:allow-secret
+const AllowSecretComment = "agen:allow-secret"
+
+// MinSecretEntropy is the Shannon entropy, in bits per character, a
+// value assigned to password/api_key/secret/token needs before it's
+// reported. Placeholders like "changeme" or "password123" fall below it;
+// generated keys are well above.
+var MinSecretEntropy = 3.0
+
+// secretPattern is something the security scan looks for. Generic
+// patterns match any assignment to a suspicious name, so their captured
+// value (the last submatch) is checked with likelyRealSecret first.
+// The rest have a shape nothing else has and are always reported.
+type secretPattern struct {
+	name    string
+	re      *regexp.Regexp
+	generic bool
+}
+
+var secretPatterns = []secretPattern{
+	{"AWS Key", regexp.MustCompile(`AKIA[0-9A-Z]{16}`), false},
+	{"GitHub Token", regexp.MustCompile(`ghp_[a-zA-Z0-9]{36}`), false},
+	{"Private Key", regexp.MustCompile(`-----BEGIN (RSA |EC |DSA |OPENSSH )?PRIVATE KEY-----`), false},
+	{"Password", regexp.MustCompile(`(?i)(password|passwd|pwd)\s*[=:]\s*["']([^"']+)["']`), true},
+	{"API Key", regexp.MustCompile(`(?i)(api[_-]?key|apikey)\s*[=:]\s*["']([^"']+)["']`), true},
+	{"Secret", regexp.MustCompile(`(?i)(secret|token)\s*[=:]\s*["']([^"']+)["']`), true},
+}
+
+// dummySecrets are values that show up in docs, examples and tests,
+// compared lowercased
+var dummySecrets = map[string]bool{
+	"changeme": true, "change-me": true, "change_me": true,
+	"password": true, "password123": true, "passw0rd": true,
+	"secret": true, "mysecret": true, "supersecret": true,
+	"token": true, "apikey": true, "api_key": true, "api-key": true,
+	"example": true, "sample": true, "dummy": true, "test": true,
+	"placeholder": true, "redacted": true, "none": true, "null": true,
+	"your-api-key": true, "your_api_key": true, "your-secret": true,
+	"your-password": true, "your_password": true, "your-token": true,
+	"foo": true, "bar": true, "foobar": true, "admin": true,
+	"123456": true, "12345678": true, "qwerty": true, "letmein": true,
+}
+
+// likelyRealSecret reports whether value, assigned to a secret-looking
+// name, is worth flagging: not a known dummy, not a template reference
+// or masked value, and random enough (see MinSecretEntropy)
+func likelyRealSecret(value string) bool {
+	v := strings.TrimSpace(value)
+	lower := strings.ToLower(v)
+
+	if dummySecrets[lower] {
+		return false
+	}
+	// references and placeholders: ${DB_PASS}, {{ .Token }}, <your key>,
+	// %(password)s, and masks like ******** or xxxxxxxx
+	for _, prefix := range []string{"${", "{{", "<", "%(", "$("} {
+		if strings.HasPrefix(v, prefix) {
+			return false
+		}
+	}
+	if strings.Trim(lower, "*x.-_") == "" {
+		return false
+	}
+
+	return shannonEntropy(v) >= MinSecretEntropy
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	var entropy float64
+	for _, n := range counts {
+		p := float64(n) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// lineAt returns the line of content containing offset, and its number
+func lineAt(content string, offset int) (string, int) {
+	start := strings.LastIndex(content[:offset], "\n") + 1
+	end := strings.Index(content[offset:], "\n")
+	if end < 0 {
+		end = len(content)
+	} else {
+		end += offset
+	}
+	return content[start:end], strings.Count(content[:start], "\n") + 1
+}